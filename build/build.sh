@@ -41,6 +41,7 @@ fi
 export CGO_ENABLED=0
 export GOARCH="${ARCH}"
 export GOCACHE=/go/.cache
+export GO111MODULE=off
 
 go install                                                         \
     -installsuffix "static"                                        \
@@ -23,14 +23,11 @@ set -o pipefail
 
 export CGO_ENABLED=0
 export GOCACHE=/go/.cache
+export GO111MODULE=off
 
 FLAGS="-installsuffix"
 TARGETS=$(for d in "$@"; do go list ./$d/... | grep -v /vendor/; done)
 
-echo "Running tests:"
-go test -i ${FLAGS} "static" ${TARGETS}
-
-echo
 echo "Code coverage"
 go test -cover -covermode=count ${FLAGS} "static" ${TARGETS}
 
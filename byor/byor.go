@@ -0,0 +1,337 @@
+// Package byor reads and writes the radars in the format of the ThoughtWorks
+// Build Your Own Radar visualizer: a CSV with a header row, or a JSON array,
+// with the name, ring, quadrant, isNew and description of every blip.
+package byor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/render"
+)
+
+// Formats of the radars.
+const (
+	CSV  = "csv"
+	JSON = "json"
+)
+
+// columns of the CSV format, in the order they are written.
+var columns = []string{"name", "ring", "quadrant", "isNew", "description"}
+
+// ErrFormat raised when the format of the radar is unknown.
+var ErrFormat = i18n.NewError(&goi18n.Message{
+	ID:    "BYORUnknownFormat",
+	Other: "The format must be csv or json",
+})
+
+// ErrHeader raised when the CSV header misses a required column.
+var ErrHeader = i18n.NewError(&goi18n.Message{
+	ID:    "BYORHeader",
+	Other: "The CSV header must have the name, ring and quadrant columns",
+})
+
+// ErrName raised when a blip has no name.
+var ErrName = i18n.NewError(&goi18n.Message{
+	ID:    "BYORNameEmpty",
+	Other: "The name is empty",
+})
+
+// ErrRing raised when the ring of a blip is unknown.
+var ErrRing = i18n.NewError(&goi18n.Message{
+	ID:    "BYORUnknownRing",
+	Other: "The ring must be adopt, trial, assess or hold",
+})
+
+// ErrQuadrant raised when the quadrant of a blip is unknown.
+var ErrQuadrant = i18n.NewError(&goi18n.Message{
+	ID:    "BYORUnknownQuadrant",
+	Other: "The quadrant must be techniques, tools, platforms or languages & frameworks",
+})
+
+// ErrIsNew raised when isNew is not a boolean.
+var ErrIsNew = i18n.NewError(&goi18n.Message{
+	ID:    "BYORIsNew",
+	Other: "isNew must be true or false",
+})
+
+// ErrDuplicated raised when a blip is in more than one row.
+var ErrDuplicated = i18n.NewError(&goi18n.Message{
+	ID:    "BYORDuplicated",
+	Other: "The blip is already in a previous row",
+})
+
+// Blip represents a technology placed in the radar, with the quadrant and the
+// ring named as in the render package.
+type Blip struct {
+	Name        string
+	Ring        string
+	Quadrant    string
+	IsNew       bool
+	Description string
+}
+
+// RowError represents a row that couldn't be read. The rows are numbered from
+// 1, without the CSV header.
+type RowError struct {
+	Row  int
+	Name string
+	Err  error
+}
+
+// row represents a blip as it's read, before being validated.
+type row struct {
+	Name        string      `json:"name"`
+	Ring        string      `json:"ring"`
+	Quadrant    string      `json:"quadrant"`
+	IsNew       interface{} `json:"isNew"`
+	Description string      `json:"description"`
+}
+
+// record represents a blip as it's written in JSON.
+type record struct {
+	Name        string `json:"name"`
+	Ring        string `json:"ring"`
+	Quadrant    string `json:"quadrant"`
+	IsNew       string `json:"isNew"`
+	Description string `json:"description"`
+}
+
+// Read reads the blips of a radar in format, returning the valid ones and the
+// errors of the rest of the rows. The error is only returned when the radar
+// can't be read at all.
+func Read(r io.Reader, format string) ([]Blip, []RowError, error) {
+	var rows []row
+	var err error
+	switch radar.CleanString(format) {
+	case CSV, "":
+		rows, err = readCSV(r)
+	case JSON:
+		err = json.NewDecoder(r).Decode(&rows)
+	default:
+		return nil, nil, errors.Wrap(ErrFormat, format)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blips := make([]Blip, 0, len(rows))
+	rowErrors := make([]RowError, 0)
+	seen := make(map[string]bool)
+	for i, rw := range rows {
+		blip, err := rw.blip()
+		if err == nil && seen[radar.CleanString(blip.Name)] {
+			err = ErrDuplicated
+		}
+
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Row: i + 1, Name: rw.Name, Err: err})
+			continue
+		}
+
+		seen[radar.CleanString(blip.Name)] = true
+		blips = append(blips, blip)
+	}
+
+	return blips, rowErrors, nil
+}
+
+// readCSV reads the rows of a radar in CSV format. The columns are found by
+// the names in the header, in any order, and the unknown ones are ignored.
+func readCSV(r io.Reader) ([]row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, ErrHeader
+	} else if err != nil {
+		return nil, err
+	}
+
+	position := make(map[string]int)
+	for i, name := range header {
+		position[radar.CleanString(name)] = i
+	}
+
+	for _, name := range columns[:3] {
+		if _, ok := position[radar.CleanString(name)]; !ok {
+			return nil, ErrHeader
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := position[radar.CleanString(name)]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return record[i]
+	}
+
+	rows := make([]row, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		rows = append(rows, row{
+			Name:        field(record, "name"),
+			Ring:        field(record, "ring"),
+			Quadrant:    field(record, "quadrant"),
+			IsNew:       field(record, "isNew"),
+			Description: field(record, "description"),
+		})
+	}
+}
+
+// blip returns the blip of the row, or the error making it invalid.
+func (rw row) blip() (Blip, error) {
+	blip := Blip{
+		Name:        strings.TrimSpace(rw.Name),
+		Description: strings.TrimSpace(rw.Description),
+	}
+	if blip.Name == "" {
+		return blip, ErrName
+	}
+
+	for _, ring := range render.Rings {
+		if radar.CleanString(ring) == radar.CleanString(rw.Ring) {
+			blip.Ring = ring
+		}
+	}
+
+	if blip.Ring == "" {
+		return blip, errors.Wrap(ErrRing, rw.Ring)
+	}
+
+	quadrant, ok := render.Quadrant(rw.Quadrant)
+	if !ok {
+		return blip, errors.Wrap(ErrQuadrant, rw.Quadrant)
+	}
+
+	blip.Quadrant = quadrant
+	switch isNew := rw.IsNew.(type) {
+	case nil:
+	case bool:
+		blip.IsNew = isNew
+	case string:
+		switch radar.CleanString(isNew) {
+		case "true", "yes", "1":
+			blip.IsNew = true
+		case "false", "no", "0", "":
+		default:
+			return blip, errors.Wrap(ErrIsNew, isNew)
+		}
+	default:
+		return blip, ErrIsNew
+	}
+
+	return blip, nil
+}
+
+// Write writes the blips in format. The visualizer takes the order of the
+// rings from the order they appear, so the blips are written sorted by ring,
+// quadrant and name.
+func Write(w io.Writer, format string, blips []Blip) error {
+	sorted := append([]Blip(nil), blips...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := index(render.Rings, sorted[i].Ring), index(render.Rings, sorted[j].Ring)
+		if ri != rj {
+			return ri < rj
+		}
+
+		qi, qj := index(render.Quadrants, sorted[i].Quadrant), index(render.Quadrants, sorted[j].Quadrant)
+		if qi != qj {
+			return qi < qj
+		}
+
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	records := make([]record, 0, len(sorted))
+	for _, blip := range sorted {
+		isNew := "FALSE"
+		if blip.IsNew {
+			isNew = "TRUE"
+		}
+
+		records = append(records, record{
+			Name:        blip.Name,
+			Ring:        strings.ToLower(blip.Ring),
+			Quadrant:    strings.ToLower(blip.Quadrant),
+			IsNew:       isNew,
+			Description: blip.Description,
+		})
+	}
+
+	switch radar.CleanString(format) {
+	case CSV, "":
+		return writeCSV(w, records)
+	case JSON:
+		return json.NewEncoder(w).Encode(records)
+	}
+
+	return errors.Wrap(ErrFormat, format)
+}
+
+// writeCSV writes the records in CSV format with the header row.
+func writeCSV(w io.Writer, records []record) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(columns)
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		err = writer.Write([]string{rec.Name, rec.Ring, rec.Quadrant, rec.IsNew, rec.Description})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// index returns the position of value in list or -1 if it's not present.
+func index(list []string, value string) int {
+	for i, elem := range list {
+		if elem == value {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,132 @@
+package byor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestReadCSV(t *testing.T) {
+	data := `name,ring,quadrant,isNew,description
+Go,adopt,languages & frameworks,TRUE,"A language, with goroutines"
+Docker, Trial ,platforms,false,
+,hold,tools,FALSE,No name
+Cobol,retire,languages,FALSE,
+Jenkins,hold,ci,FALSE,
+Kafka,assess,platforms,maybe,
+go,hold,languages,FALSE,Duplicated
+`
+	blips, rowErrors, err := Read(strings.NewReader(data), CSV)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(blips) != 2 {
+		t.Fatalf("Expected 2 blips, Got %+v", blips)
+	}
+
+	expected := Blip{Name: "Go", Ring: "Adopt", Quadrant: "Languages & Frameworks", IsNew: true,
+		Description: "A language, with goroutines"}
+	if blips[0] != expected || blips[1].Ring != "Trial" || blips[1].IsNew {
+		t.Errorf("Unexpected blips %+v", blips)
+	}
+
+	expectedErrors := []struct {
+		row int
+		err error
+	}{{3, ErrName}, {4, ErrRing}, {5, ErrQuadrant}, {6, ErrIsNew}, {7, ErrDuplicated}}
+	if len(rowErrors) != len(expectedErrors) {
+		t.Fatalf("Expected %d row errors, Got %+v", len(expectedErrors), rowErrors)
+	}
+
+	for i, expected := range expectedErrors {
+		if rowErrors[i].Row != expected.row || errors.Cause(rowErrors[i].Err) != expected.err {
+			t.Errorf("Expected %s in the row %d, Got %+v", expected.err, expected.row, rowErrors[i])
+		}
+	}
+
+	_, _, err = Read(strings.NewReader("name,ring\nGo,adopt\n"), CSV)
+	if err != ErrHeader {
+		t.Errorf("Expected %s, Got %v", ErrHeader, err)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	data := `[{"name": "Go", "ring": "Adopt", "quadrant": "Languages & Frameworks", "isNew": "TRUE"},
+		{"name": "Docker", "ring": "trial", "quadrant": "platforms", "isNew": false},
+		{"name": "Kafka", "ring": "assess", "quadrant": "platforms", "isNew": 1}]`
+	blips, rowErrors, err := Read(strings.NewReader(data), JSON)
+	if err != nil || len(blips) != 2 || !blips[0].IsNew || blips[1].IsNew {
+		t.Errorf("Unexpected blips %+v: %v", blips, err)
+	}
+
+	if len(rowErrors) != 1 || rowErrors[0].Row != 3 || rowErrors[0].Err != ErrIsNew {
+		t.Errorf("Expected the isNew error in the row 3, Got %+v", rowErrors)
+	}
+
+	_, _, err = Read(strings.NewReader(`{"name": "Go"}`), JSON)
+	if err == nil {
+		t.Error("Expected error reading a JSON object")
+	}
+
+	_, _, err = Read(strings.NewReader(data), "xml")
+	if errors.Cause(err) != ErrFormat {
+		t.Errorf("Expected %s, Got %v", ErrFormat, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	blips := []Blip{
+		{Name: "Jenkins", Ring: "Hold", Quadrant: "Tools"},
+		{Name: "Go", Ring: "Adopt", Quadrant: "Languages & Frameworks", IsNew: true, Description: "A language, fast"},
+		{Name: "Docker", Ring: "Adopt", Quadrant: "Platforms"},
+	}
+
+	out := &bytes.Buffer{}
+	err := Write(out, CSV, blips)
+	expected := `name,ring,quadrant,isNew,description
+Docker,adopt,platforms,FALSE,
+Go,adopt,languages & frameworks,TRUE,"A language, fast"
+Jenkins,hold,tools,FALSE,
+`
+	if err != nil || out.String() != expected {
+		t.Errorf("Expected %s, Got %s: %v", expected, out, err)
+	}
+
+	read, _, err := Read(out, CSV)
+	if err != nil || len(read) != 3 || read[1] != blips[1] {
+		t.Errorf("Expected the blips written to be read back, Got %+v: %v", read, err)
+	}
+
+	out.Reset()
+	err = Write(out, JSON, blips[:1])
+	expected = `[{"name":"Jenkins","ring":"hold","quadrant":"tools","isNew":"FALSE","description":""}]` + "\n"
+	if err != nil || out.String() != expected {
+		t.Errorf("Expected %s, Got %s: %v", expected, out, err)
+	}
+
+	err = Write(out, "xml", blips)
+	if errors.Cause(err) != ErrFormat {
+		t.Errorf("Expected %s, Got %v", ErrFormat, err)
+	}
+}
@@ -22,20 +22,60 @@ package account
 import (
 	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/casesprovider/cases/account/activate"
+	"github.com/radar-go/radar/casesprovider/cases/account/confirmenroll"
+	"github.com/radar-go/radar/casesprovider/cases/account/confirmreset"
+	"github.com/radar-go/radar/casesprovider/cases/account/createapikey"
 	"github.com/radar-go/radar/casesprovider/cases/account/deactivate"
+	"github.com/radar-go/radar/casesprovider/cases/account/disabletwofactor"
 	"github.com/radar-go/radar/casesprovider/cases/account/edit"
+	"github.com/radar-go/radar/casesprovider/cases/account/enroll"
+	"github.com/radar-go/radar/casesprovider/cases/account/erase"
+	"github.com/radar-go/radar/casesprovider/cases/account/export"
+	"github.com/radar-go/radar/casesprovider/cases/account/get"
+	"github.com/radar-go/radar/casesprovider/cases/account/ldapsync"
+	"github.com/radar-go/radar/casesprovider/cases/account/list"
+	"github.com/radar-go/radar/casesprovider/cases/account/listapikeys"
 	"github.com/radar-go/radar/casesprovider/cases/account/login"
 	"github.com/radar-go/radar/casesprovider/cases/account/logout"
+	"github.com/radar-go/radar/casesprovider/cases/account/oidclogin"
+	"github.com/radar-go/radar/casesprovider/cases/account/oidcstart"
 	"github.com/radar-go/radar/casesprovider/cases/account/register"
 	"github.com/radar-go/radar/casesprovider/cases/account/remove"
+	"github.com/radar-go/radar/casesprovider/cases/account/requestreset"
+	"github.com/radar-go/radar/casesprovider/cases/account/resend"
+	"github.com/radar-go/radar/casesprovider/cases/account/restore"
+	"github.com/radar-go/radar/casesprovider/cases/account/revokeapikey"
+	"github.com/radar-go/radar/casesprovider/cases/account/twofactorpolicy"
+	"github.com/radar-go/radar/casesprovider/cases/account/unlock"
+	"github.com/radar-go/radar/casesprovider/cases/account/verifylogin"
 )
 
 func init() {
 	casesprovider.Register(activate.New())
+	casesprovider.Register(confirmenroll.New())
+	casesprovider.Register(confirmreset.New())
+	casesprovider.Register(createapikey.New())
 	casesprovider.Register(deactivate.New())
+	casesprovider.Register(disabletwofactor.New())
 	casesprovider.Register(edit.New())
+	casesprovider.Register(enroll.New())
+	casesprovider.Register(erase.New())
+	casesprovider.Register(export.New())
+	casesprovider.Register(get.New())
+	casesprovider.Register(ldapsync.New())
+	casesprovider.Register(list.New())
+	casesprovider.Register(listapikeys.New())
 	casesprovider.Register(login.New())
 	casesprovider.Register(logout.New())
+	casesprovider.Register(oidclogin.New())
+	casesprovider.Register(oidcstart.New())
 	casesprovider.Register(register.New())
 	casesprovider.Register(remove.New())
+	casesprovider.Register(requestreset.New())
+	casesprovider.Register(resend.New())
+	casesprovider.Register(restore.New())
+	casesprovider.Register(revokeapikey.New())
+	casesprovider.Register(twofactorpolicy.New())
+	casesprovider.Register(unlock.New())
+	casesprovider.Register(verifylogin.New())
 }
@@ -20,14 +20,28 @@ package activate
 */
 
 import (
-	"fmt"
+	"context"
 
-	"github.com/pkg/errors"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 
 	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
 	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
 )
 
+// msgSuccess is the result of a successful activation.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountActivateSuccess",
+	Other: "Account activated successfully",
+}
+
+// msgError is the result of a failed activation.
+var msgError = &goi18n.Message{
+	ID:    "AccountActivateError",
+	Other: "Error activating the account",
+}
+
 // UseCase for the account activation.
 type UseCase struct {
 	usecase.UseCase
@@ -44,9 +58,9 @@ func New() *UseCase {
 		usecase.UseCase{
 			Name: "AccountActivate",
 			Params: map[string]interface{}{
-				"id":    0,
-				"token": "",
+				"code": "",
 			},
+			Audit: true,
 		},
 	}
 
@@ -58,26 +72,26 @@ func (uc *UseCase) New() casesprovider.UseCase {
 	return New()
 }
 
-// Run tries to activate an account from the system.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+// AuditTarget returns the account of the verification code.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	acc, _ := verification.Account(ctx, uc.Datastore, uc.Params["code"].(string))
+	return acc
+}
+
+// Run activates the account of a verification code sent to its email.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	res := usecase.NewResult()
 
-	token := uc.Params["token"].(string)
-	account, err := uc.Datastore.GetAccountBySession(token)
+	acc, err := verification.Account(ctx, uc.Datastore, uc.Params["code"].(string))
 	if err != nil {
 		return res, err
 	}
 
-	if account.ID() != uc.Params["id"].(int) {
-		return res, errors.New("The account id doesn't match with the session information")
-	}
-
-	if uc.Datastore.ActivateAccount(account.ID()) {
-		res.Res["result"] = "Account activated successfully"
-		res.Res["id"] = account.ID()
+	if acc.IsActive() || uc.Datastore.ActivateAccount(ctx, acc.ID()) {
+		res.Res["result"] = msgSuccess
+		res.Res["id"] = acc.UUID()
 	} else {
-		res.Res["result"] = "Error activating the account"
-		res.Res["error"] = fmt.Sprintf("%s", err)
+		res.Res["result"] = msgError
 	}
 
 	return res, nil
@@ -19,9 +19,13 @@ package activate
 */
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
 	"github.com/radar-go/radar/casesprovider/helper"
 	"github.com/radar-go/radar/datastore"
 )
@@ -35,31 +39,23 @@ func TestCaseParams(t *testing.T) {
 	uc.SetDatastore(datastore.New())
 
 	testCases := map[string]helper.ParamsTestCases{
-		"UnknownTokens": {
+		"UnknownToken": {
 			Params: map[string]interface{}{
-				"tokens": "00000000-0000-0000-0000-000000000000",
+				"token": "00000000-0000-0000-0000-000000000000",
 			},
 			Expected:      "key doesn't exists: Unknown parameter for the use case",
 			ExpectedError: true,
 		},
-		"IdFormatError": {
-			Params: map[string]interface{}{
-				"id": "00000000-0000-0000-0000-000000000000",
-			},
-			Expected:      "id: Param is not from the right type",
-			ExpectedError: true,
-		},
-		"TokenFormatError": {
+		"CodeFormatError": {
 			Params: map[string]interface{}{
-				"token": 1,
+				"code": 1,
 			},
-			Expected:      "token: Param is not from the right type",
+			Expected:      "code: Param is not from the right type",
 			ExpectedError: true,
 		},
 		"AddParamsSuccessfully": {
 			Params: map[string]interface{}{
-				"id":    1,
-				"token": "00000000-0000-0000-0000-000000000000",
+				"code": "abc.def",
 			},
 			ExpectedError: false,
 		},
@@ -68,87 +64,69 @@ func TestCaseParams(t *testing.T) {
 }
 
 func TestAccountActivation(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 
 	testCases := map[string]struct {
-		params        map[string]interface{}
+		code          string
+		ttl           time.Duration
 		expected      string
 		expectedError bool
-
-		/* Data for user registration. */
-		username string
-		name     string
-		email    string
-		password string
-		register bool
-
-		/* Data for user login. */
-		token string
-		login bool
 	}{
-		"UserNotRegistered": {
-			params: map[string]interface{}{
-				"id":    1,
-				"token": "00000000-0000-0000-0000-000000000000",
-			},
-			expected:      "00000000-0000-0000-0000-000000000000: User not logged in",
+		"InvalidCode": {
+			code:          "abc.def",
+			expected:      "The link is not valid",
 			expectedError: true,
 		},
-		"UserNotLogged": {
-			params: map[string]interface{}{
-				"token": "00000000-0000-0000-0000-000000000000",
-			},
-			expected:      "00000000-0000-0000-0000-000000000000: User not logged in",
+		"ExpiredCode": {
+			ttl:           -time.Hour,
+			expected:      "The link has expired, ask for a new one",
 			expectedError: true,
-			username:      "ritho",
-			name:          "ritho",
-			email:         "palvarez@ritho.net",
-			password:      "121212",
-			register:      true,
 		},
 		"ActivationSuccess": {
-			params:        make(map[string]interface{}),
+			ttl:           time.Hour,
 			expected:      "Account activated successfully",
 			expectedError: false,
-			username:      "ritho",
-			name:          "ritho",
-			email:         "palvarez@ritho.net",
-			password:      "121212",
-			register:      true,
-			token:         "00000000-0000-0000-0000-000000000000",
-			login:         true,
 		},
 	}
 
 	for testName, tc := range testCases {
 		t.Run(testName, func(t *testing.T) {
-			var id int
-			var err error
-
-			helper.SetupUseCase(t, uc, tc.params)
-			if tc.register {
-				id = helper.RegisterUser(t, uc.Datastore, tc.username, tc.name,
-					tc.email, tc.password)
-				helper.AddParam(t, uc, "id", id)
-			}
+			helper.SetupUseCase(t, uc, map[string]interface{}{})
+			id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho",
+				"palvarez@ritho.net", "121212")
+
+			code := tc.code
+			if code == "" {
+				acc, err := uc.Datastore.GetAccountByID(ctx, id)
+				helper.UnexpectedError(t, err)
 
-			if tc.login {
-				helper.LoginUser(t, uc.Datastore, tc.token, tc.username)
-				helper.AddParam(t, uc, "token", tc.token)
+				ttl := casesprovider.Config().VerificationTTL
+				casesprovider.Config().VerificationTTL = tc.ttl
+				code = verification.Code(acc)
+				casesprovider.Config().VerificationTTL = ttl
 			}
+			helper.AddParam(t, uc, "code", code)
 
-			res, err := uc.Run()
+			res, err := uc.Run(ctx)
 			if tc.expectedError {
 				if err == nil {
 					t.Error("Expected error running the use case")
 				}
 
 				helper.Contains(t, fmt.Sprint(err), tc.expected)
-			} else {
-				helper.UnexpectedError(t, err)
-				plainResult := helper.GetResultString(t, res)
-				helper.Contains(t, plainResult, fmt.Sprintf(`"id":%d`, id))
-				helper.Contains(t, plainResult, tc.expected)
+				return
+			}
+
+			helper.UnexpectedError(t, err)
+			plainResult := helper.GetResultString(t, res)
+			helper.Contains(t, plainResult, fmt.Sprintf(`"id":%q`, helper.AccountUUID(t, uc.Datastore, id)))
+			helper.Contains(t, plainResult, tc.expected)
+
+			acc, err := uc.Datastore.GetAccountByID(ctx, id)
+			helper.UnexpectedError(t, err)
+			if !acc.IsActive() {
+				t.Error("Expected the account to be active")
 			}
 		})
 	}
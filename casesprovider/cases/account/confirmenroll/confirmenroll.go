@@ -0,0 +1,110 @@
+// Package confirmenroll implements the use case confirming the enrollment of
+// the two-factor authentication of an account.
+package confirmenroll
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/totp"
+)
+
+// recoveryCodes is the number of recovery codes given to the account.
+const recoveryCodes = 10
+
+// msgSuccess is the result of a successful enrollment confirmation.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountTwoFactorConfirmSuccess",
+	Other: "Two-factor authentication enabled, keep the recovery codes in a safe place",
+}
+
+// UseCase for the two-factor enrollment confirmation.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the two-factor enrollment confirmation.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new confirm enroll use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountTwoFactorConfirm",
+			Params: map[string]interface{}{
+				"token": "",
+				"code":  "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new confirm enroll use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// TwoFactorExempt returns true, the confirmation completes the enrollment the
+// accounts required to use the two-factor authentication have to do first.
+func (uc *UseCase) TwoFactorExempt() bool {
+	return true
+}
+
+// Run enables the two-factor authentication of the account of the session
+// with a code of the enrolled secret, returning the recovery codes to use when
+// the authenticator app is lost. The recovery codes are shown only this time.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	codes, err := totp.RecoveryCodes(recoveryCodes)
+	if err != nil {
+		return res, err
+	}
+
+	err = acc.EnableTwoFactor(uc.Params["code"].(string), time.Now(), codes)
+	if err != nil {
+		return res, err
+	}
+
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["recovery_codes"] = codes
+
+	return res, nil
+}
@@ -0,0 +1,110 @@
+// Package confirmreset implements the use case setting a new password with the
+// link sent to the email of the account.
+package confirmreset
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/passwordreset"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful password reset.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountPasswordResetConfirmSuccess",
+	Other: "Password changed successfully, log in with the new one",
+}
+
+// UseCase for the password reset confirmation.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the password reset confirmation.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new confirm reset use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountPasswordResetConfirm",
+			Params: map[string]interface{}{
+				"code":     "",
+				"password": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new confirm reset use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// AuditTarget returns the account of the reset code.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	acc, _ := passwordreset.Account(ctx, uc.Datastore, uc.Params["code"].(string))
+	return acc
+}
+
+// Run sets the new password of the account of the reset code and closes all
+// its sessions. The new password must be different from the current one.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := passwordreset.Account(ctx, uc.Datastore, uc.Params["code"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	if acc.CheckPassword(uc.Params["password"].(string)) {
+		return res, account.ErrPasswordUnchanged
+	}
+
+	err = acc.SetPassword(uc.Params["password"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	_, err = uc.Datastore.DeleteSessions(ctx, acc.ID())
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["id"] = acc.UUID()
+
+	return res, nil
+}
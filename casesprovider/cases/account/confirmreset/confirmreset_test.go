@@ -0,0 +1,81 @@
+package confirmreset
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/cases/account/passwordreset"
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountPasswordResetConfirm")
+}
+
+func TestConfirmReset(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "121212")
+	helper.LoginUser(t, uc.Datastore, "00000000-0000-0000-0000-000000000000", "ritho")
+
+	acc, err := uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	code := passwordreset.Code(acc)
+
+	testCases := []struct {
+		name     string
+		code     string
+		password string
+		expected string
+	}{
+		{"InvalidCode", "abc.def", "newpassword", "The link is not valid"},
+		{"PasswordTooShort", code, "new", "Password too short"},
+		{"PasswordUnchanged", code, "121212", "The new password must be different from the current one"},
+		{"Success", code, "newpassword", "Password changed successfully"},
+		{"AlreadyUsed", code, "otherpassword", "The link is not valid"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			helper.AddParam(t, uc, "code", tc.code)
+			helper.AddParam(t, uc, "password", tc.password)
+			res, err := uc.Run(ctx)
+			if err != nil {
+				helper.Contains(t, fmt.Sprint(err), tc.expected)
+				return
+			}
+
+			helper.Contains(t, helper.GetResultString(t, res), tc.expected)
+		})
+	}
+
+	acc, err = uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	if !acc.CheckPassword("newpassword") {
+		t.Errorf("Expected the new password, Got %s", acc.Password())
+	}
+
+	if uc.Datastore.DoesAccountHaveSessionByID(ctx, id) {
+		t.Error("Expected the sessions of the account to be closed")
+	}
+}
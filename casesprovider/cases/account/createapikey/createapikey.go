@@ -0,0 +1,141 @@
+package createapikey
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// DefaultLifetime is the time the API keys can be used when the expiration is
+// not given.
+const DefaultLifetime = 90 * 24 * time.Hour
+
+// msgSuccess is the result of a successful creation.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountAPIKeyCreateSuccess",
+	Other: "Store the API key now, it can't be shown again",
+}
+
+// UseCase for the API key creation.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the API key creation.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new create API key use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountAPIKeyCreate",
+			Params: map[string]interface{}{
+				"token":   "",
+				"name":    "",
+				"scopes":  "",
+				"expires": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new create API key use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run creates a new API key for the account of the session with the scopes
+// separated by commas, returning the key only this time.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	now := time.Now()
+	expires := now.Add(DefaultLifetime)
+	if value := uc.Params["expires"].(string); value != "" {
+		expires, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return res, errWrap.Wrap(errors.ErrParamType, "expires")
+		}
+	}
+
+	var scopes []string
+	for _, scope := range strings.Split(uc.Params["scopes"].(string), ",") {
+		if scope = strings.ToLower(strings.TrimSpace(scope)); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	id, key, err := generate()
+	if err != nil {
+		return res, err
+	}
+
+	err = acc.AddAPIKey(id, uc.Params["name"].(string), key, scopes, now, expires)
+	if err != nil {
+		return res, err
+	}
+
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	apiKey := acc.APIKeys()[len(acc.APIKeys())-1]
+	res.Res["result"] = msgSuccess
+	res.Res["id"] = apiKey.ID
+	res.Res["key"] = key
+	res.Res["name"] = apiKey.Name
+	res.Res["scopes"] = apiKey.Scopes
+	res.Res["expires"] = apiKey.Expires
+
+	return res, nil
+}
+
+// generate returns the id and the secret of a new API key.
+func generate() (string, string, error) {
+	buf := make([]byte, 40)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(buf[:8]),
+		account.APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf[8:]), nil
+}
@@ -0,0 +1,82 @@
+package createapikey
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountAPIKeyCreate")
+}
+
+func TestCreateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{
+		"token":  token,
+		"name":   "ci",
+		"scopes": "read, Write",
+	})
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "User not logged in")
+
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	helper.LoginUser(t, uc.Datastore, token, "ritho")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, "Store the API key now")
+	helper.Contains(t, plainResult, `"scopes":["read","write"]`)
+	acc, _ := uc.Datastore.GetAccountByID(ctx, id)
+	keys := acc.APIKeys()
+	if len(keys) != 1 || keys[0].Name != "ci" ||
+		keys[0].Expires.Sub(keys[0].Created).Round(time.Hour) != DefaultLifetime {
+		t.Errorf("Expected the key stored, Got %+v", keys)
+	}
+
+	helper.Contains(t, plainResult, `"key":"radar_`)
+	if strings.Contains(plainResult, `"hash"`) {
+		t.Errorf("Expected the hash of the key not in the result, Got %s", plainResult)
+	}
+
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The API key needs a name not used by other keys")
+
+	helper.AddParams(t, uc, map[string]interface{}{"name": "deploy", "scopes": "delete"})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Unknown API key scope")
+
+	helper.AddParams(t, uc, map[string]interface{}{"scopes": "admin", "expires": "tomorrow"})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "expires")
+
+	expires := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	helper.AddParam(t, uc, "expires", expires)
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), fmt.Sprintf(`"expires":"%s"`, expires))
+}
@@ -20,14 +20,28 @@ package deactivate
 */
 
 import (
-	"fmt"
+	"context"
 
-	"github.com/pkg/errors"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 
+	"github.com/radar-go/radar"
 	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
 )
 
+// msgSuccess is the result of a successful deactivation.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountDeactivateSuccess",
+	Other: "Account deactivated successfully",
+}
+
+// msgError is the result of a failed deactivation.
+var msgError = &goi18n.Message{
+	ID:    "AccountDeactivateError",
+	Other: "Error deactivating the account",
+}
+
 // UseCase for the account deactivation.
 type UseCase struct {
 	usecase.UseCase
@@ -44,9 +58,10 @@ func New() *UseCase {
 		usecase.UseCase{
 			Name: "AccountDeactivate",
 			Params: map[string]interface{}{
-				"id":    0,
+				"id":    "",
 				"token": "",
 			},
+			Audit: true,
 		},
 	}
 
@@ -59,30 +74,29 @@ func (uc *UseCase) New() casesprovider.UseCase {
 }
 
 // Run tries to deactivate an account from the system.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	res := usecase.NewResult()
 
-	token := uc.Params["token"].(string)
-	account, err := uc.Datastore.GetAccountBySession(token)
+	acc, err := uc.SessionAccount(ctx)
 	if err != nil {
 		return res, err
 	}
 
-	if account.ID() != uc.Params["id"].(int) {
-		return res, errors.New("The account id doesn't match with the session information")
+	if acc.UUID() != radar.CleanString(uc.Params["id"].(string)) {
+		return res, account.ErrSessionMismatch
 	}
 
-	err = uc.Datastore.DeleteSession(token, account.Username())
+	err = uc.Datastore.DeleteSession(ctx, uc.Params["token"].(string), acc.Username())
 	if err != nil {
 		return res, err
 	}
 
-	if uc.Datastore.DeactivateAccount(account.ID()) {
-		res.Res["result"] = "Account deactivated successfully"
-		res.Res["id"] = account.ID()
+	if uc.Datastore.DeactivateAccount(ctx, acc.ID()) {
+		res.Res["result"] = msgSuccess
+		res.Res["id"] = acc.UUID()
 	} else {
-		res.Res["result"] = "Error deactivating the account"
-		res.Res["error"] = fmt.Sprintf("%s", err)
+		res.Res["result"] = msgError
+		res.Res["error"] = err
 	}
 
 	return res, nil
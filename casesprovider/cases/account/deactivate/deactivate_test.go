@@ -19,6 +19,7 @@ package deactivate
 */
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -44,7 +45,7 @@ func TestCaseParams(t *testing.T) {
 		},
 		"IdFormatError": {
 			Params: map[string]interface{}{
-				"id": "00000000-0000-0000-0000-000000000000",
+				"id": 1,
 			},
 			Expected:      "id: Param is not from the right type",
 			ExpectedError: true,
@@ -58,7 +59,7 @@ func TestCaseParams(t *testing.T) {
 		},
 		"AddParamsSuccessfully": {
 			Params: map[string]interface{}{
-				"id":    1,
+				"id":    "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c",
 				"token": "00000000-0000-0000-0000-000000000000",
 			},
 			ExpectedError: false,
@@ -68,6 +69,7 @@ func TestCaseParams(t *testing.T) {
 }
 
 func TestAccountDeactivation(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 
 	testCases := map[string]struct {
@@ -88,7 +90,7 @@ func TestAccountDeactivation(t *testing.T) {
 	}{
 		"UserNotRegistered": {
 			params: map[string]interface{}{
-				"id":    1,
+				"id":    "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c",
 				"token": "00000000-0000-0000-0000-000000000000",
 			},
 			expected:      "00000000-0000-0000-0000-000000000000: User not logged in",
@@ -129,7 +131,7 @@ func TestAccountDeactivation(t *testing.T) {
 			if tc.register {
 				id = helper.RegisterUser(t, uc.Datastore, tc.username, tc.name,
 					tc.email, tc.password)
-				helper.AddParam(t, uc, "id", id)
+				helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
 			}
 
 			if tc.login {
@@ -137,7 +139,7 @@ func TestAccountDeactivation(t *testing.T) {
 				helper.AddParam(t, uc, "token", tc.token)
 			}
 
-			res, err := uc.Run()
+			res, err := uc.Run(ctx)
 			if tc.expectedError {
 				if err == nil {
 					t.Error("Expected error running the use case")
@@ -147,7 +149,7 @@ func TestAccountDeactivation(t *testing.T) {
 			} else {
 				helper.UnexpectedError(t, err)
 				plainResult := helper.GetResultString(t, res)
-				helper.Contains(t, plainResult, fmt.Sprintf(`"id":%d`, id))
+				helper.Contains(t, plainResult, fmt.Sprintf(`"id":%q`, helper.AccountUUID(t, uc.Datastore, id)))
 				helper.Contains(t, plainResult, tc.expected)
 			}
 		})
@@ -0,0 +1,304 @@
+// Package directory keeps the accounts in sync with the users of the LDAP
+// directory.
+package directory
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/ldap"
+	"github.com/radar-go/radar/logging"
+)
+
+// prefix of the external ids of the accounts of the directory users.
+const prefix = "ldap|"
+
+// ErrConflict raised when the username of a directory user is taken by a local
+// account with another email.
+var ErrConflict = i18n.NewError(&goi18n.Message{
+	ID:    "LDAPConflict",
+	Other: "The username is taken by an account that is not in the directory",
+})
+
+// ErrNoUsers raised when the directory has no users, most likely because of a
+// wrong configuration, so the accounts are not deactivated.
+var ErrNoUsers = i18n.NewError(&goi18n.Message{
+	ID:    "LDAPNoUsers",
+	Other: "The directory has no users, check the base DN and the user filter",
+})
+
+// usernameChars matches the characters not allowed in the usernames created
+// from the directory.
+var usernameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// Report represents the result of a synchronization with the directory.
+type Report struct {
+	Created     int
+	Linked      int
+	Updated     int
+	Deactivated int
+	Conflicts   []string
+}
+
+// ExternalID returns the external id of the account of the directory user.
+func ExternalID(username string) string {
+	return prefix + radar.CleanString(username)
+}
+
+// Username returns the username in the directory of the account, or an empty
+// string if it's a local account.
+func Username(acc *account.Account) string {
+	if !strings.HasPrefix(acc.ExternalID(), prefix) {
+		return ""
+	}
+
+	return strings.TrimPrefix(acc.ExternalID(), prefix)
+}
+
+// Statuses of the accounts imported from the directory.
+const (
+	Unchanged = iota
+	Created
+	Linked
+	Updated
+)
+
+// Import creates or updates the account of the directory user, returning it
+// and what has been done to it. Only the accounts created or linked are
+// activated, the ones deactivated are kept as they are.
+func Import(ctx context.Context, ds *datastore.Datastore, user *ldap.User) (*account.Account, int, error) {
+	status := Unchanged
+	acc, err := ds.GetAccountByExternalID(ctx, ExternalID(user.Username))
+	if err != nil {
+		status = Linked
+		acc, err = link(ctx, ds, user)
+	}
+
+	if errors.Cause(err) == account.ErrAccountNotExists {
+		status = Created
+		acc, err = create(ctx, ds, user)
+	}
+
+	if err != nil {
+		return nil, Unchanged, err
+	}
+
+	if update(acc, user) && status == Unchanged {
+		status = Updated
+	}
+
+	if status == Created || status == Linked {
+		acc.Activate()
+	}
+
+	if status != Unchanged {
+		err = ds.UpdateAccountData(ctx, acc)
+	}
+
+	return acc, status, err
+}
+
+// link links the local account with the username of the directory user, if it
+// has the same email and it's not deactivated.
+func link(ctx context.Context, ds *datastore.Datastore, user *ldap.User) (*account.Account, error) {
+	acc, err := ds.GetAccountByUsername(ctx, user.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if acc.ExternalID() != "" || !strings.EqualFold(acc.Email(), user.Email) {
+		return nil, errors.Wrap(ErrConflict, user.Username)
+	} else if acc.IsDeactivated() {
+		return nil, errors.Wrap(account.ErrAccountInactive, user.Username)
+	}
+
+	acc.SetExternalID(ExternalID(user.Username))
+	if !acc.IsActive() {
+		/* The email of the account was never verified, so it may have been
+		registered by someone else waiting for the directory user. */
+		acc.DisableTwoFactor()
+		if _, err = ds.DeleteSessions(ctx, acc.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	/* The directory checks the passwords from now on. */
+	return acc, randomPassword(acc)
+}
+
+// create registers the account of the directory user, with a random password
+// as the directory checks them.
+func create(ctx context.Context, ds *datastore.Datastore, user *ldap.User) (*account.Account, error) {
+	password, err := random()
+	if err != nil {
+		return nil, err
+	}
+
+	username := usernameChars.ReplaceAllString(radar.CleanString(user.Username), "")
+	if len(username) < 5 {
+		username += "-ldap"
+	}
+
+	name := user.Name
+	if name == "" {
+		name = user.Username
+	}
+
+	id, err := ds.AccountRegistration(ctx, username, name, user.Email, password)
+	if err != nil {
+		return nil, errors.Wrap(err, user.Username)
+	}
+
+	acc, err := ds.GetAccountByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	acc.SetExternalID(ExternalID(user.Username))
+
+	return acc, nil
+}
+
+// update sets the data of the directory user in the account, returning true if
+// any has changed.
+func update(acc *account.Account, user *ldap.User) bool {
+	changed := false
+	if user.Name != "" && user.Name != acc.Name() {
+		acc.SetName(user.Name)
+		changed = true
+	}
+
+	if user.Email != "" && !strings.EqualFold(user.Email, acc.Email()) {
+		if err := acc.SetEmail(user.Email); err != nil {
+			logging.Logger.Warn("wrong email of the directory user", "username", user.Username,
+				"error", err.Error())
+		} else {
+			changed = true
+		}
+	}
+
+	/* The title is the current role, the previous one is finished when it
+	changes so the history of the member is kept. */
+	titled, err := acc.SetTitle(user.Title, time.Now())
+	if err != nil {
+		logging.Logger.Warn("wrong title of the directory user", "username", user.Username,
+			"error", err.Error())
+	}
+
+	return changed || titled
+}
+
+// randomPassword sets a random password to the account.
+func randomPassword(acc *account.Account) error {
+	password, err := random()
+	if err != nil {
+		return err
+	}
+
+	return acc.SetPassword(password)
+}
+
+// random returns a new random password.
+func random() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Sync imports or updates the accounts of all the users of the directory, and
+// deactivates the accounts of the users that are not in it anymore, closing
+// their sessions. The accounts deactivated are not activated again when their
+// users come back, an administrator activates them.
+func Sync(ctx context.Context, ds *datastore.Datastore, dir *ldap.Directory) (*Report, error) {
+	if !dir.Configured() {
+		return nil, ldap.ErrNotConfigured
+	}
+
+	users, err := dir.Users(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, ErrNoUsers
+	}
+
+	report := &Report{}
+	present := make(map[string]bool)
+	for _, user := range users {
+		present[ExternalID(user.Username)] = true
+		_, status, err := Import(ctx, ds, user)
+		if err != nil {
+			logging.Logger.Warn("directory user not imported", "request_id", logging.ContextRequestID(ctx),
+				"username", user.Username, "error", err.Error())
+			report.Conflicts = append(report.Conflicts, user.Username)
+			continue
+		}
+
+		switch status {
+		case Created:
+			report.Created++
+		case Linked:
+			report.Linked++
+		case Updated:
+			report.Updated++
+		}
+	}
+
+	accounts, err := ds.Accounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acc := range accounts {
+		if Username(acc) == "" || present[acc.ExternalID()] || !acc.IsActive() {
+			continue
+		}
+
+		logging.Logger.Info("deactivating account not in the directory anymore",
+			"request_id", logging.ContextRequestID(ctx), "username", acc.Username())
+		if !ds.DeactivateAccount(ctx, acc.ID()) {
+			return nil, errors.Errorf("Error deactivating the account %s", acc.Username())
+		}
+
+		if _, err = ds.DeleteSessions(ctx, acc.ID()); err != nil {
+			return nil, err
+		}
+
+		report.Deactivated++
+	}
+
+	return report, nil
+}
@@ -0,0 +1,121 @@
+// Package disabletwofactor implements the use case removing the two-factor
+// authentication of an account.
+package disabletwofactor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/lockout"
+	"github.com/radar-go/radar/casesprovider/cases/account/twofactor"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful two-factor removal.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountTwoFactorDisableSuccess",
+	Other: "Two-factor authentication disabled",
+}
+
+// UseCase for the two-factor removal.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the two-factor removal.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new disable two-factor use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountTwoFactorDisable",
+			Params: map[string]interface{}{
+				"token": "",
+				"code":  "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new disable two-factor use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run removes the two-factor authentication of the account of the session,
+// with a code of the authenticator app or a recovery code, unless the policy
+// requires it to the account.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !acc.HasTwoFactor() {
+		return res, account.ErrTwoFactorNotEnrolled
+	}
+
+	policy, err := uc.Datastore.Policy(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if policy.RequiresTwoFactor(acc) {
+		return res, account.ErrTwoFactorRequired
+	}
+
+	/* The codes are throttled as in the logins, so a stolen session can't
+	guess them. */
+	if lockout.Wait(ctx, acc.Username()) > 0 {
+		return res, errors.ErrTooManyRequests
+	}
+
+	err = twofactor.Check(ctx, uc.Datastore, acc, uc.Params["code"].(string))
+	if errWrap.Cause(err) == account.ErrTwoFactorCode {
+		lockout.Fail(ctx, acc.Username())
+		return res, err
+	} else if err != nil {
+		return res, err
+	}
+
+	acc.DisableTwoFactor()
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+
+	return res, nil
+}
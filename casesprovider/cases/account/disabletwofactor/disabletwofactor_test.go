@@ -0,0 +1,67 @@
+package disabletwofactor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/totp"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountTwoFactorDisable")
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token, "code": "000000"})
+	id := helper.RegisterUser(t, uc.Datastore, "disable", "ritho", "palvarez@ritho.net", "ritho")
+	helper.LoginUser(t, uc.Datastore, token, "disable")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Two-factor authentication is not enrolled")
+
+	secret := helper.EnableTwoFactor(t, uc.Datastore, "disable")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong two-factor authentication code")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	uc.Datastore.SetPolicy(ctx, datastore.Policy{TwoFactor: datastore.TwoFactorAdmins})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Two-factor authentication is required")
+
+	uc.Datastore.SetPolicy(ctx, datastore.Policy{TwoFactor: datastore.TwoFactorOptional})
+	code, _ := totp.Code(secret, time.Now())
+	helper.AddParam(t, uc, "code", code)
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), "Two-factor authentication disabled")
+
+	acc, _ := uc.Datastore.GetAccountByID(ctx, id)
+	if acc.HasTwoFactor() || acc.TwoFactorSecret() != "" {
+		t.Error("Expected the two-factor authentication removed")
+	}
+}
@@ -20,14 +20,28 @@ package edit
 */
 
 import (
-	"fmt"
+	"context"
 
-	"github.com/pkg/errors"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 
+	"github.com/radar-go/radar"
 	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
 )
 
+// msgSuccess is the result of a successful edition.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountEditSuccess",
+	Other: "Account data updated successfully",
+}
+
+// msgError is the result of a failed edition.
+var msgError = &goi18n.Message{
+	ID:    "AccountEditError",
+	Other: "Error updating the account data",
+}
+
 // UseCase for the account edition.
 type UseCase struct {
 	usecase.UseCase
@@ -44,13 +58,14 @@ func New() *UseCase {
 		usecase.UseCase{
 			Name: "AccountEdit",
 			Params: map[string]interface{}{
-				"id":       0,
+				"id":       "",
 				"token":    "",
 				"username": "",
 				"name":     "",
 				"email":    "",
 				"password": "",
 			},
+			Audit: true,
 		},
 	}
 
@@ -62,43 +77,51 @@ func (uc *UseCase) New() casesprovider.UseCase {
 	return New()
 }
 
+// APIKeyScope returns the write scope, the API keys need it to edit the
+// account.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeWrite
+}
+
 // Run tries to edit an account from the system.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	res := usecase.NewResult()
 
-	token := uc.Params["token"].(string)
-	account, err := uc.Datastore.GetAccountBySession(token)
+	acc, err := uc.SessionAccount(ctx)
 	if err != nil {
 		return res, err
 	}
 
-	if account.ID() != uc.Params["id"].(int) {
-		return res, errors.New("The account id doesn't match with the session information")
+	if acc.UUID() != radar.CleanString(uc.Params["id"].(string)) {
+		return res, account.ErrSessionMismatch
 	}
 
-	account.SetName(uc.Params["name"].(string))
-	err = account.SetEmail(uc.Params["email"].(string))
+	/* The fields are set in a copy of the account, so it doesn't change until
+	all of them are valid and the datastore stores them at once. */
+	acc = acc.Clone()
+	acc.SetName(uc.Params["name"].(string))
+	err = acc.SetEmail(uc.Params["email"].(string))
 	if err != nil {
 		return res, err
 	}
 
-	err = account.SetUsername(uc.Params["username"].(string))
+	err = acc.SetUsername(uc.Params["username"].(string))
 	if err != nil {
 		return res, err
 	}
 
-	err = account.SetPassword(uc.Params["password"].(string))
+	err = acc.SetPassword(uc.Params["password"].(string))
 	if err != nil {
 		return res, err
 	}
 
-	err = uc.Datastore.UpdateAccountData(account, token)
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
 	if err != nil {
-		res.Res["result"] = "Error updating the account data"
-		res.Res["error"] = fmt.Sprintf("%s", err)
+		res.Res["result"] = msgError
+		res.Res["error"] = err
 	} else {
-		res.Res["result"] = "Account data updated successfully"
-		res.Res["id"] = account.ID()
+		res.Res["result"] = msgSuccess
+		res.Res["id"] = acc.UUID()
 	}
 
 	return res, nil
@@ -19,6 +19,7 @@ package edit
 */
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -32,11 +33,12 @@ import (
 )
 
 func TestEditCaseCreation(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 	helper.TestCaseName(t, uc, "AccountEdit")
 
 	uc.SetDatastore(datastore.New())
-	_, err := uc.Run()
+	_, err := uc.Run(ctx)
 	if !strings.Contains(fmt.Sprintf("%s", err), "User not logged in") {
 		t.Error(err)
 	}
@@ -56,7 +58,7 @@ func TestCaseParams(t *testing.T) {
 		},
 		"IdFormatError": {
 			Params: map[string]interface{}{
-				"id": "00000000-0000-0000-0000-000000000000",
+				"id": 1,
 			},
 			Expected:      "id: Param is not from the right type",
 			ExpectedError: true,
@@ -98,7 +100,7 @@ func TestCaseParams(t *testing.T) {
 		},
 		"AddParamsSuccessfully": {
 			Params: map[string]interface{}{
-				"id":       1,
+				"id":       "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c",
 				"token":    "00000000-0000-0000-0000-000000000000",
 				"username": "ritho",
 				"name":     "ritho",
@@ -112,6 +114,7 @@ func TestCaseParams(t *testing.T) {
 }
 
 func TestEdit(t *testing.T) {
+	ctx := context.Background()
 	/* Test initialization. */
 	session := "00000000-0000-0000-0000-000000000000"
 	uc, id := initializeTests(t, session)
@@ -132,7 +135,7 @@ func TestEdit(t *testing.T) {
 				"password": "212121",
 			},
 			nil,
-			fmt.Sprintf(`"id":%d`, id),
+			fmt.Sprintf(`"id":%q`, helper.AccountUUID(t, uc.Datastore, id)),
 			true,
 		},
 		{
@@ -187,12 +190,12 @@ func TestEdit(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.testName, func(t *testing.T) {
-			helper.AddParam(t, uc, "id", id)
+			helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
 			helper.AddParam(t, uc, "token", session)
 			helper.AddParams(t, uc, tc.params)
 
 			/* Edit the account. */
-			res, err := uc.Run()
+			res, err := uc.Run(ctx)
 			if errors.Cause(err) != tc.err {
 				t.Errorf("Unexpected error: %s", err)
 			}
@@ -209,7 +212,7 @@ func TestEdit(t *testing.T) {
 
 			if tc.compare {
 				/* Get the account from the session. */
-				accountData, err := uc.Datastore.GetAccountBySession(session)
+				accountData, err := uc.Datastore.GetAccountBySession(ctx, session)
 				if err != nil {
 					t.Errorf("User %s doesn't have session in the datastore", tc.params["username"])
 				}
@@ -232,13 +235,13 @@ func TestEdit(t *testing.T) {
 					t.Errorf("Expected %s, Got %s", tc.params["email"], accountData.Email())
 				}
 
-				if accountData.Password() != tc.params["password"] {
-					t.Errorf("Expected %s, Got %s", tc.params["password"],
+				if !accountData.CheckPassword(tc.params["password"].(string)) {
+					t.Errorf("Expected the password %s, Got the hash %s", tc.params["password"],
 						accountData.Password())
 				}
 
 				/* Get the user from the data stored. */
-				accountDatastore, err := uc.Datastore.GetAccountByUsername(tc.params["username"].(string))
+				accountDatastore, err := uc.Datastore.GetAccountByUsername(ctx, tc.params["username"].(string))
 				if err != nil {
 					t.Errorf("User %s is not registered in the datastore", tc.params["username"])
 				}
@@ -251,16 +254,53 @@ func TestEdit(t *testing.T) {
 	}
 }
 
+func TestEditInvalidField(t *testing.T) {
+	ctx := context.Background()
+	session := "00000000-0000-0000-0000-000000000000"
+	uc, id := initializeTests(t, session)
+
+	helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
+	helper.AddParam(t, uc, "token", session)
+	helper.AddParam(t, uc, "username", "renamed")
+	helper.AddParam(t, uc, "name", "Renamed")
+	helper.AddParam(t, uc, "email", "renamed@ritho.net")
+	helper.AddParam(t, uc, "password", "121")
+
+	_, err := uc.Run(ctx)
+	if errors.Cause(err) != account.ErrPasswordTooShort {
+		t.Errorf("Expected %s, Got %v", account.ErrPasswordTooShort, err)
+	}
+
+	/* No field changes when one of them is not valid. */
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, "ritho")
+	helper.UnexpectedError(t, err)
+	if acc.Username() != "ritho" || acc.Name() != "ritho" || acc.Email() != "palvarez@ritho.net" ||
+		!acc.CheckPassword("121212") {
+		t.Errorf("Expected the account unchanged, Got %s %s %s", acc.Username(), acc.Name(), acc.Email())
+	}
+
+	_, err = uc.Datastore.GetAccountByUsername(ctx, "renamed")
+	if errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotExists, err)
+	}
+
+	_, err = uc.Datastore.AccountRegistration(ctx, "ritho", "Other", "palvarez@ritho.net", "other-secret")
+	if errors.Cause(err) != account.ErrAccountExists {
+		t.Errorf("Expected the username and the email still taken, Got %v", err)
+	}
+}
+
 func TestEditLogoutError(t *testing.T) {
+	ctx := context.Background()
 	/* Test initialization. */
 	session := "00000000-0000-0000-0000-000000000000"
 	uc, id := initializeTests(t, session)
 
 	/* Logout the user. */
-	err := uc.Datastore.DeleteSession(session, "ritho")
+	err := uc.Datastore.DeleteSession(ctx, session, "ritho")
 	helper.UnexpectedError(t, err)
 
-	helper.AddParam(t, uc, "id", id)
+	helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
 	helper.AddParam(t, uc, "token", session)
 	helper.AddParam(t, uc, "username", "senoritho")
 	helper.AddParam(t, uc, "name", "senoritho")
@@ -268,7 +308,7 @@ func TestEditLogoutError(t *testing.T) {
 	helper.AddParam(t, uc, "password", "212121")
 
 	/* Edit the account. */
-	res, err := uc.Run()
+	res, err := uc.Run(ctx)
 	helper.Contains(t, fmt.Sprintf("%s", err), "User not logged in")
 
 	ucRes, err := res.String()
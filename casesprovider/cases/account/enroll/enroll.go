@@ -0,0 +1,105 @@
+// Package enroll implements the use case starting the enrollment of the
+// two-factor authentication of an account.
+package enroll
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/twofactor"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/totp"
+)
+
+// msgSuccess is the result of a successful enrollment.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountTwoFactorEnrollSuccess",
+	Other: "Add the secret to your authenticator app and confirm it with a code",
+}
+
+// UseCase for the two-factor enrollment.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the two-factor enrollment.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new enroll use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountTwoFactorEnroll",
+			Params: map[string]interface{}{
+				"token": "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new enroll use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// TwoFactorExempt returns true, the enrollment is what the accounts required
+// to use the two-factor authentication have to do first.
+func (uc *UseCase) TwoFactorExempt() bool {
+	return true
+}
+
+// Run generates a new secret for the account of the session, returning it
+// with its otpauth uri to add it to the authenticator app.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return res, err
+	}
+
+	err = acc.SetTwoFactorSecret(secret)
+	if err != nil {
+		return res, err
+	}
+
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["secret"] = secret
+	res.Res["uri"] = totp.URI(twofactor.Issuer, acc.Username(), secret)
+
+	return res, nil
+}
@@ -0,0 +1,58 @@
+package enroll
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountTwoFactorEnroll")
+}
+
+func TestEnroll(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "User not logged in")
+
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	helper.LoginUser(t, uc.Datastore, token, "ritho")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	acc, _ := uc.Datastore.GetAccountByID(ctx, id)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, "Add the secret to your authenticator app")
+	helper.Contains(t, plainResult, fmt.Sprintf(`"secret":"%s"`, acc.TwoFactorSecret()))
+	helper.Contains(t, plainResult, `"uri":"otpauth://totp/Radar:ritho?`)
+	if acc.HasTwoFactor() {
+		t.Error("Expected the two-factor authentication pending of confirmation")
+	}
+
+	helper.EnableTwoFactor(t, uc.Datastore, "ritho")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Two-factor authentication is already enabled")
+}
@@ -0,0 +1,121 @@
+// Package erase implements the use case erasing an account and its personal
+// data for good.
+package erase
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful erasure.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountEraseSuccess",
+	Other: "Account erased successfully",
+}
+
+// UseCase for the account erasure.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the account erasure.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new erase use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountErase",
+			Params: map[string]interface{}{
+				"id":    "",
+				"token": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new erase use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// AuditTarget returns the account erased.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	acc, _ := uc.target(ctx)
+	return acc
+}
+
+// target returns the account to erase: the account of the session or, for
+// the administrators, any other account even if it's removed.
+func (uc *UseCase) target(ctx context.Context) (*account.Account, error) {
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := radar.CleanString(uc.Params["id"].(string))
+	if acc.UUID() == id {
+		return acc, nil
+	}
+
+	if !acc.IsAdmin() {
+		return nil, account.ErrUserMismatch
+	}
+
+	target, err := uc.Datastore.GetAccountByUUID(ctx, id)
+	if err != nil {
+		return uc.Datastore.GetRemovedAccount(ctx, id)
+	}
+
+	return target, nil
+}
+
+// Run erases an account for good, anonymizing it everywhere it's referenced.
+// The accounts can erase themselves, and the administrators any account.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.target(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	err = uc.Datastore.EraseAccount(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["id"] = acc.UUID()
+
+	return res, nil
+}
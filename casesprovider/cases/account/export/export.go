@@ -0,0 +1,146 @@
+// Package export implements the use case exporting all the personal data of
+// the account of the session.
+package export
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"time"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/profile"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// sessionPrefix is the length of the start of the sessions exported, enough
+// to tell them apart without exporting them as valid tokens.
+const sessionPrefix = 8
+
+// Account represents the account exported.
+type Account struct {
+	profile.Profile
+	ExternalID string           `json:"external_id,omitempty"`
+	APIKeys    []account.APIKey `json:"api_keys"`
+}
+
+// Role represents a role the member has had.
+type Role struct {
+	Title    string    `json:"title"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// Technology represents a technology known by the member and its level.
+type Technology struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Level int    `json:"level"`
+}
+
+// UseCase for the personal data export.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the personal data export.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new export use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountExport",
+			Params: map[string]interface{}{
+				"token": "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new export use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run exports all the personal data of the account of the session: the
+// account, the member profile with its roles and technologies, the sessions
+// and the audit records done by or on the account.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	exported := Account{
+		Profile:    profile.New(acc, acc),
+		ExternalID: acc.ExternalID(),
+		APIKeys:    append(make([]account.APIKey, 0), acc.APIKeys()...),
+	}
+
+	roles := make([]Role, 0, len(acc.Roles()))
+	for _, r := range acc.Roles() {
+		roles = append(roles, Role{
+			Title:    r.Title(),
+			Started:  r.Started(),
+			Finished: r.Finished(),
+		})
+	}
+
+	technologies := make([]Technology, 0, len(acc.Technologies()))
+	for _, tech := range acc.Technologies() {
+		technologies = append(technologies, Technology{
+			Name:  tech.Name(),
+			Type:  tech.Type(),
+			Level: tech.Level(),
+		})
+	}
+
+	sessions, err := uc.Datastore.AccountSessions(ctx, acc.ID())
+	if err != nil {
+		return res, err
+	}
+
+	for i, session := range sessions {
+		if len(session) > sessionPrefix {
+			sessions[i] = session[:sessionPrefix] + "..."
+		}
+	}
+
+	records, err := uc.Datastore.AccountAuditRecords(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["exported"] = time.Now().UTC()
+	res.Res["account"] = exported
+	res.Res["roles"] = roles
+	res.Res["technologies"] = technologies
+	res.Res["sessions"] = sessions
+	res.Res["audit"] = records
+
+	return res, nil
+}
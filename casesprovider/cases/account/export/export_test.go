@@ -0,0 +1,76 @@
+package export
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore/audit"
+	technology "github.com/radar-go/radar/entities/technology/api"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountExport")
+}
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "User not logged in")
+
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	uuid := helper.AccountUUID(t, uc.Datastore, id)
+	helper.LoginUser(t, uc.Datastore, token, "ritho")
+	acc, err := uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	acc.AddTechnology(technology.New("Go", "Language", 4))
+	helper.UnexpectedError(t, uc.Datastore.UpdateAccountData(ctx, acc))
+	uc.Datastore.AddAuditRecord(ctx, audit.Record{Action: "AccountEdit", Actor: "admin",
+		Target: "ritho", TargetID: uuid})
+	uc.Datastore.AddAuditRecord(ctx, audit.Record{Action: "AccountLogin", Actor: "admin",
+		Target: "admin", TargetID: "other"})
+
+	/* The records done with the usernames it had before are exported too. */
+	uc.Datastore.AddAuditRecord(ctx, audit.Record{Action: "AccountRename", Actor: "admin",
+		Target: "ritho", TargetID: uuid, Changes: map[string]audit.Change{
+			"username": {Before: "palvarez", After: "ritho"}}})
+	uc.Datastore.AddAuditRecord(ctx, audit.Record{Action: "TechnologyAdd", Actor: "palvarez",
+		Target: "Go", TargetID: "technology"})
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, fmt.Sprintf(`"id":%q`, uuid))
+	helper.Contains(t, plainResult, `"email":"palvarez@ritho.net"`)
+	helper.Contains(t, plainResult, `"technologies":[{"name":"Go","type":"Language","level":4}]`)
+	helper.Contains(t, plainResult, `"sessions":["00000000..."]`)
+	helper.Contains(t, plainResult, `"action":"AccountEdit"`)
+	helper.Contains(t, plainResult, `"action":"TechnologyAdd"`)
+	if strings.Contains(plainResult, token) || strings.Contains(plainResult, `"AccountLogin"`) {
+		t.Errorf("Expected only the data of the account, Got %s", plainResult)
+	}
+}
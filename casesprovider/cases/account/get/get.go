@@ -0,0 +1,101 @@
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/profile"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// UseCase for the account profile retrieval.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the account profile retrieval.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new account get use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountGet",
+			Params: map[string]interface{}{
+				"token":    "",
+				"id":       "",
+				"username": "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new account get use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the read scope, the API keys need it to get the
+// profiles.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeRead
+}
+
+// Run obtains the profile of the account with the id or the username. Only
+// the administrators can get the inactive accounts.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	caller, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	var acc *account.Account
+	id, username := uc.Params["id"].(string), uc.Params["username"].(string)
+	switch {
+	case id != "":
+		acc, err = uc.Datastore.GetAccountByUUID(ctx, id)
+	case username != "":
+		acc, err = uc.Datastore.GetAccountByUsername(ctx, username)
+	default:
+		return res, errWrap.Wrap(errors.ErrParamEmpty, "id")
+	}
+
+	if err != nil {
+		return res, err
+	}
+
+	if !acc.IsActive() && !profile.Private(acc, caller) {
+		return res, account.ErrAccountNotExists
+	}
+
+	res.Res["account"] = profile.New(acc, caller)
+
+	return res, nil
+}
@@ -0,0 +1,71 @@
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountGet")
+}
+
+func TestGet(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "User not logged in")
+
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	other := helper.RegisterUser(t, uc.Datastore, "jdoe-", "John Doe", "jdoe@ritho.net", "12345")
+	helper.LoginUser(t, uc.Datastore, token, "ritho")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "id: Param is not present or empty")
+
+	helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"email":"palvarez@ritho.net"`)
+
+	uc.Params["id"] = ""
+	helper.AddParam(t, uc, "username", "jdoe-")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Account doesn't exists")
+
+	uc.Datastore.ActivateAccount(ctx, other)
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"username":"jdoe-","name":"John Doe"`)
+	if strings.Contains(plainResult, "email") || strings.Contains(plainResult, "admin") {
+		t.Errorf("Expected the private fields hidden, Got %s", plainResult)
+	}
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"email":"jdoe@ritho.net","active":true,"admin":false`)
+}
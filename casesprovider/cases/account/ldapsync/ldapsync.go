@@ -0,0 +1,106 @@
+package ldapsync
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/directory"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful directory synchronization.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountLDAPSyncSuccess",
+	Other: "Accounts synchronized with the directory successfully",
+}
+
+// UseCase for the synchronization of the accounts with the LDAP directory.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the synchronization.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new LDAP sync use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountLDAPSync",
+			Params: map[string]interface{}{
+				"token": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new LDAP sync use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the admin scope, the API keys need it to synchronize
+// the directory.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeAdmin
+}
+
+// Run imports the users of the directory and deactivates the accounts of the
+// users removed from it, only the administrators can synchronize the accounts.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	admin, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !admin.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	report, err := directory.Sync(ctx, uc.Datastore, casesprovider.LDAP())
+	if err != nil {
+		return res, err
+	}
+
+	conflicts := report.Conflicts
+	if conflicts == nil {
+		conflicts = []string{}
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["created"] = report.Created
+	res.Res["linked"] = report.Linked
+	res.Res["updated"] = report.Updated
+	res.Res["deactivated"] = report.Deactivated
+	res.Res["conflicts"] = conflicts
+
+	return res, nil
+}
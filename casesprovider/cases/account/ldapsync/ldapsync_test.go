@@ -0,0 +1,111 @@
+package ldapsync
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountLDAPSync")
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+	id := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "admin@ritho.net", "admin")
+	helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	helper.LoginUser(t, uc.Datastore, token, "admin")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Administration privileges required")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The directory is not configured")
+
+	s := helper.LDAPDirectory(t)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The directory has no users")
+
+	s.AddUser("ritho", "Pablo Álvarez", "palvarez@ritho.net", "Developer", "ritho-secret")
+	s.AddUser("jdoe", "John Doe", "jdoe@ritho.net", "Manager", "jdoe-secret")
+	s.AddUser("admin", "Admin", "other@ritho.net", "", "admin-secret")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, "Accounts synchronized with the directory successfully")
+	helper.Contains(t, plainResult, `"created":1`)
+	helper.Contains(t, plainResult, `"linked":1`)
+	helper.Contains(t, plainResult, `"conflicts":["admin"]`)
+
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, "ritho")
+	helper.UnexpectedError(t, err)
+	if acc.Name() != "Pablo Álvarez" || acc.CurrentRole() == nil || acc.CurrentRole().Title() != "Developer" {
+		t.Errorf("Expected the data of the directory, Got %s %v", acc.Name(), acc.Roles())
+	}
+
+	/* The title changes finish the previous role. */
+	s.AddUser("ritho", "Pablo Álvarez", "palvarez@ritho.net", "Architect", "ritho-secret")
+	s.RemoveUser("jdoe")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult = helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"updated":1`)
+	helper.Contains(t, plainResult, `"deactivated":1`)
+
+	acc, err = uc.Datastore.GetAccountByUsername(ctx, "ritho")
+	helper.UnexpectedError(t, err)
+	if len(acc.Roles()) != 2 || acc.CurrentRole().Title() != "Architect" || acc.Roles()[0].IsActive() {
+		t.Errorf("Expected the role history to be kept, Got %v", acc.Roles())
+	}
+
+	acc, err = uc.Datastore.GetAccountByUsername(ctx, "jdoe-ldap")
+	helper.UnexpectedError(t, err)
+	if acc.IsActive() {
+		t.Error("Expected the account removed from the directory to be deactivated")
+	}
+
+	/* The accounts deactivated are not activated again by the directory. */
+	ritho, err := uc.Datastore.GetAccountByUsername(ctx, "ritho")
+	helper.UnexpectedError(t, err)
+	uc.Datastore.DeactivateAccount(ctx, ritho.ID())
+	s.AddUser("jdoe", "John Doe", "jdoe@ritho.net", "Manager", "jdoe-secret")
+	_, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	for _, username := range []string{"ritho", "jdoe-ldap"} {
+		acc, err = uc.Datastore.GetAccountByUsername(ctx, username)
+		helper.UnexpectedError(t, err)
+		if acc.IsActive() {
+			t.Errorf("Expected the account %s to be kept deactivated", username)
+		}
+	}
+
+	admin, err := uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	if !admin.IsAdmin() || admin.ExternalID() != "" {
+		t.Error("Expected the local accounts to be kept")
+	}
+}
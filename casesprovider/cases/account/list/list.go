@@ -0,0 +1,128 @@
+package list
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+	"strings"
+
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/profile"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// DefaultPerPage is the number of accounts of every page when it's not given.
+const DefaultPerPage = 20
+
+// MaxPerPage is the maximum number of accounts of every page.
+const MaxPerPage = 100
+
+// UseCase for the accounts listing.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the accounts listing.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new account list use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountList",
+			Params: map[string]interface{}{
+				"token":    "",
+				"search":   "",
+				"sort":     "",
+				"page":     0,
+				"per_page": 0,
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new account list use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the read scope, the API keys need it to list the
+// accounts.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeRead
+}
+
+// Run obtains a page of the profiles of the accounts matching the search,
+// sorted by the field of the sort param, descending if it starts with -. Only
+// the administrators see the inactive accounts and can search and sort the
+// accounts by their email.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	caller, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	query := account.Query{
+		Search:      uc.Params["search"].(string),
+		SearchEmail: caller.IsAdmin(),
+		Inactive:    caller.IsAdmin(),
+	}
+
+	sort := uc.Params["sort"].(string)
+	query.Desc = strings.HasPrefix(sort, "-")
+	query.Sort = strings.ToLower(strings.TrimPrefix(sort, "-"))
+	if query.Sort != "" && !account.ValidSort(query.Sort) {
+		return res, errWrap.Wrap(account.ErrSortUnknown, query.Sort)
+	}
+
+	if query.Sort == account.SortEmail && !caller.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	page, perPage, err := uc.Page(DefaultPerPage, MaxPerPage)
+	if err != nil {
+		return res, err
+	}
+
+	query.Offset, query.Limit = (page-1)*perPage, perPage
+	accounts, total, err := uc.Datastore.QueryAccounts(ctx, query)
+	if err != nil {
+		return res, err
+	}
+
+	profiles := make([]profile.Profile, 0, len(accounts))
+	for _, acc := range accounts {
+		profiles = append(profiles, profile.New(acc, caller))
+	}
+
+	res.Res["accounts"] = profiles
+	res.Res["total"] = total
+	res.Res["page"] = page
+	res.Res["per_page"] = perPage
+
+	return res, nil
+}
@@ -0,0 +1,107 @@
+package list
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountList")
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "User not logged in")
+
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "Pablo", "palvarez@ritho.net", "ritho")
+	uc.Datastore.ActivateAccount(ctx, id)
+	for i, username := range []string{"jdoe-", "asmith", "bjones"} {
+		other := helper.RegisterUser(t, uc.Datastore, username, username, username+"@example.com", "12345")
+		if i < 2 {
+			uc.Datastore.ActivateAccount(ctx, other)
+		}
+	}
+
+	helper.LoginUser(t, uc.Datastore, token, "ritho")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"page":1,"per_page":20,"total":3`)
+	helper.Contains(t, plainResult, `"email":"palvarez@ritho.net"`)
+	if strings.Contains(plainResult, "bjones") || strings.Contains(plainResult, "@example.com") {
+		t.Errorf("Expected the inactive accounts and the emails of the others hidden, Got %s", plainResult)
+	}
+
+	helper.AddParams(t, uc, map[string]interface{}{"sort": "-username", "per_page": 2, "page": 2})
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	plainResult = helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"page":2,"per_page":2,"total":3`)
+	helper.Contains(t, plainResult, `"username":"asmith"`)
+
+	uc.Params["page"], uc.Params["per_page"] = 0, 0
+	helper.AddParams(t, uc, map[string]interface{}{"search": "example", "sort": "name"})
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"total":0`)
+
+	testCases := map[string]struct {
+		params   map[string]interface{}
+		expected string
+	}{
+		"SortUnknown":  {map[string]interface{}{"sort": "password"}, "password: Unknown sort field"},
+		"SortEmail":    {map[string]interface{}{"sort": "-email"}, "Administration privileges required"},
+		"PerPage":      {map[string]interface{}{"sort": "id", "per_page": 1000}, "per_page: Param is not from the right type"},
+		"Page":         {map[string]interface{}{"per_page": 10, "page": -1}, "page: Param is not from the right type"},
+		"PageOverflow": {map[string]interface{}{"per_page": 10, "page": math.MaxInt}, "page: Param is not from the right type"},
+	}
+
+	for name, tc := range testCases {
+		helper.AddParams(t, uc, tc.params)
+		_, err = uc.Run(ctx)
+		if !strings.Contains(fmt.Sprint(err), tc.expected) {
+			t.Errorf("%s: Expected %s, Got %v", name, tc.expected, err)
+		}
+
+		uc.Params["sort"], uc.Params["page"], uc.Params["per_page"] = "", 0, 0
+	}
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	helper.AddParam(t, uc, "sort", "email")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	/* The administrators search the emails and see the inactive accounts. */
+	plainResult = helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"total":3`)
+	helper.Contains(t, plainResult, `"username":"asmith","name":"asmith","role":"","email":"asmith@example.com"`)
+	helper.Contains(t, plainResult, `"username":"bjones"`)
+}
@@ -0,0 +1,69 @@
+package listapikeys
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+)
+
+// UseCase for the API keys listing.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the API keys listing.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new list API keys use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountAPIKeyList",
+			Params: map[string]interface{}{
+				"token": "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new list API keys use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run obtains the API keys of the account of the session, with their scopes,
+// expiration and the last time they were used, but not the keys themselves.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["keys"] = acc.APIKeys()
+
+	return res, nil
+}
@@ -0,0 +1,57 @@
+package listapikeys
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountAPIKeyList")
+}
+
+func TestListAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "User not logged in")
+
+	helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	helper.LoginUser(t, uc.Datastore, token, "ritho")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"keys":null`)
+
+	helper.AddAPIKey(t, uc.Datastore, "ritho", "ci", "radar_secret", "read")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"id":"ci","name":"ci","scopes":["read"]`)
+	helper.Contains(t, plainResult, `"last_used":"0001-01-01T00:00:00Z"`)
+	if strings.Contains(plainResult, "radar_secret") || strings.Contains(plainResult, "hash") {
+		t.Errorf("Expected the keys not in the result, Got %s", plainResult)
+	}
+}
@@ -0,0 +1,79 @@
+// Package lockout implements the tracking of the failed logins, making the
+// accounts and the clients wait more after every failure and locking them out
+// after too many.
+package lockout
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"time"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/ratelimit"
+)
+
+// Failed logins allowed before waiting, by account and by client. Every
+// failure after them doubles the wait, up to 15 minutes of lockout, so a
+// client can try a few hundred passwords a day at most.
+var (
+	accounts = ratelimit.NewBackoff(3, time.Second, 15*time.Minute)
+	clients  = ratelimit.NewBackoff(10, time.Second, 15*time.Minute)
+)
+
+// Wait returns the time the client has to wait before trying to log in the
+// account again, zero if it can try now.
+func Wait(ctx context.Context, username string) time.Duration {
+	wait := accounts.Wait(radar.CleanString(username))
+	if ip := logging.ContextClientIP(ctx); ip != "" {
+		if clientWait := clients.Wait(ip); clientWait > wait {
+			wait = clientWait
+		}
+	}
+
+	return wait
+}
+
+// Fail records a failed login of the client on the account, whether it exists
+// or not.
+func Fail(ctx context.Context, username string) {
+	username = radar.CleanString(username)
+	accounts.Fail(username)
+	if ip := logging.ContextClientIP(ctx); ip != "" {
+		clients.Fail(ip)
+	}
+
+	if accounts.Locked(username) {
+		logging.Logger.Warn("account locked out", "request_id", logging.ContextRequestID(ctx),
+			"username", username, "remote_ip", logging.ContextClientIP(ctx))
+	}
+}
+
+// Locked returns true if the account is locked out.
+func Locked(username string) bool {
+	return accounts.Locked(radar.CleanString(username))
+}
+
+// Reset forgets the failed logins of the account, after logging in or when an
+// administrator unlocks it. The failures of the client are kept, so it can't
+// clean them logging in its own account.
+func Reset(username string) {
+	accounts.Reset(radar.CleanString(username))
+}
@@ -20,14 +20,36 @@ package login
 */
 
 import (
-	"errors"
+	"context"
 
 	"github.com/golang-plus/uuid"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	errWrap "github.com/pkg/errors"
 
 	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/directory"
+	"github.com/radar-go/radar/casesprovider/cases/account/lockout"
+	"github.com/radar-go/radar/casesprovider/cases/account/twofactor"
 	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/ldap"
 )
 
+// msgSuccess is the result of a successful login.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountLoginSuccess",
+	Other: "User login successfully",
+}
+
+// msgChallenge is the result of a login that needs the two-factor
+// authentication code.
+var msgChallenge = &goi18n.Message{
+	ID:    "AccountLoginChallenge",
+	Other: "Type the code of your authenticator app to log in",
+}
+
 // UseCase for the user login.
 type UseCase struct {
 	usecase.UseCase
@@ -47,6 +69,7 @@ func New() *UseCase {
 				"login":    "",
 				"password": "",
 			},
+			Audit: true,
 		},
 	}
 
@@ -59,41 +82,122 @@ func (uc *UseCase) New() casesprovider.UseCase {
 }
 
 // Run tries to log in an user into the system.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	var err error
 	res := usecase.NewResult()
 
 	login := uc.Params["login"].(string)
 	password := uc.Params["password"].(string)
-	acc, err := uc.Datastore.GetAccountByUsername(login)
-	if err != nil {
+	if lockout.Wait(ctx, login) > 0 {
+		return res, errors.ErrTooManyRequests
+	}
+
+	/* The unknown accounts and the wrong passwords fail the same way, so the
+	usernames can't be found out. */
+	acc, err := uc.authenticate(ctx, login, password)
+	if errWrap.Cause(err) == account.ErrInvalidCredentials {
+		lockout.Fail(ctx, login)
+		return res, account.ErrInvalidCredentials
+	} else if err != nil {
 		return res, err
 	}
 
-	if acc.Password() != password {
-		return res, errors.New("Password missmatch")
+	if acc.HasTwoFactor() {
+		/* The failed logins are forgotten once the code is verified, so the
+		password can't be used to try the codes without waiting. */
+		Challenge(acc, res)
+		return res, nil
 	}
 
-	if uc.Datastore.DoesAccountHaveSessionByUsername(login) {
-		return res, errors.New("User already logged in")
+	lockout.Reset(login)
+	err = Open(ctx, uc.Datastore, acc, res)
+
+	return res, err
+}
+
+// authenticate returns the account of the login if the password is right and
+// the account is active. The accounts of the directory users are checked
+// against the directory, which imports the users logging in for the first
+// time.
+func (uc *UseCase) authenticate(ctx context.Context, login, password string) (*account.Account, error) {
+	dir := casesprovider.LDAP()
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, login)
+	switch {
+	case err == nil && directory.Username(acc) == "":
+		if !acc.CheckPassword(password) {
+			return nil, account.ErrInvalidCredentials
+		}
+
+		/* The inactive accounts are only told apart once the password is
+		right, so it doesn't reveal which accounts exist. */
+		if !acc.IsActive() {
+			return nil, account.ErrAccountInactive
+		}
+
+		return acc, nil
+	case !dir.Configured():
+		account.CheckUnknownPassword(password)
+		return nil, account.ErrInvalidCredentials
+	case err == nil:
+		login = directory.Username(acc)
+	}
+
+	user, err := dir.Authenticate(ctx, login, password)
+	if err == ldap.ErrUserNotFound {
+		return nil, account.ErrInvalidCredentials
+	} else if err != nil {
+		return nil, err
+	}
+
+	acc, _, err = directory.Import(ctx, uc.Datastore, user)
+	if err != nil {
+		return nil, err
+	}
+
+	/* The directory doesn't know the accounts deactivated in the radar. */
+	if !acc.IsActive() {
+		return nil, account.ErrAccountInactive
+	}
+
+	return acc, nil
+}
+
+// Challenge adds to the result of the login the challenge to verify with the
+// code of the authenticator app of the account.
+func Challenge(acc *account.Account, res *usecase.Result) {
+	res.Res["result"] = msgChallenge
+	res.Res["challenge"] = twofactor.Challenge(acc)
+}
+
+// Open opens a new session for the account, adding its token and the data of
+// the account to the result of the login, and whether it has to enroll the
+// two-factor authentication.
+func Open(ctx context.Context, ds *datastore.Datastore, acc *account.Account, res *usecase.Result) error {
+	if ds.DoesAccountHaveSessionByUsername(ctx, acc.Username()) {
+		return account.ErrUserAlreadyLogin
 	}
 
 	uuid, err := uuid.NewTimeBased()
 	if err != nil {
-		return res, err
+		return err
 	}
 
-	err = uc.Datastore.AddSession(uuid.String(), login)
+	err = ds.AddSession(ctx, uuid.String(), acc.Username())
 	if err != nil {
-		return res, err
+		return err
 	}
 
-	res.Res["result"] = "User login successfully"
-	res.Res["id"] = acc.ID()
+	res.Res["result"] = msgSuccess
+	res.Res["id"] = acc.UUID()
 	res.Res["username"] = acc.Username()
 	res.Res["name"] = acc.Name()
 	res.Res["email"] = acc.Email()
 	res.Res["token"] = uuid.String()
 
-	return res, err
+	policy, err := ds.Policy(ctx)
+	if err == nil && policy.RequiresTwoFactor(acc) {
+		res.Res["two_factor_required"] = true
+	}
+
+	return nil
 }
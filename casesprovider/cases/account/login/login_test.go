@@ -19,26 +19,31 @@ package login
 */
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/radar-go/radar/casesprovider/cases/account/lockout"
 	"github.com/radar-go/radar/casesprovider/helper"
 	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/logging"
 )
 
 func TestLogin(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 	helper.TestCaseName(t, uc, "AccountLogin")
 
 	uc.SetDatastore(datastore.New())
 	helper.AddParam(t, uc, "login", "ritho")
 	helper.AddParam(t, uc, "password", "12345")
-	_, err := uc.Run()
+	_, err := uc.Run(ctx)
 	if err == nil {
 		t.Error("Expected error running the use case.")
 	}
 
-	helper.Contains(t, fmt.Sprintf("%s", err), "ritho: Account doesn't exists")
+	helper.Contains(t, fmt.Sprintf("%s", err), "Wrong username or password")
 	err = uc.AddParam("passwoed", "12345")
 	if err == nil {
 		t.Error("Expected error running the use case.")
@@ -48,12 +53,172 @@ func TestLogin(t *testing.T) {
 	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
 
 	helper.AddParam(t, uc, "password", "123456")
-	_, err = uc.Run()
-	helper.Contains(t, fmt.Sprintf("%s", err), "Password missmatch")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprintf("%s", err), "Wrong username or password")
 	helper.AddParam(t, uc, "password", "12345")
-	res, err := uc.Run()
+	helper.ActivateUser(t, uc.Datastore, id)
+	res, err := uc.Run(ctx)
 	helper.UnexpectedError(t, err)
 	plainResult := helper.GetResultString(t, res)
-	helper.Contains(t, plainResult, fmt.Sprintf(`"id":%d`, id))
+	helper.Contains(t, plainResult, fmt.Sprintf(`"id":%q`, helper.AccountUUID(t, uc.Datastore, id)))
 	helper.Contains(t, plainResult, `"token":`)
 }
+
+func TestLoginInactive(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"login": "ritho", "password": "12345"})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+
+	/* The accounts with the email not verified can't log in, and neither the
+	ones deactivated later. */
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The account is not active")
+	if uc.Datastore.DoesAccountHaveSessionByID(ctx, id) {
+		t.Error("Expected no session of the unverified account")
+	}
+
+	helper.ActivateUser(t, uc.Datastore, id)
+	_, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	_, err = uc.Datastore.DeleteSessions(ctx, id)
+	helper.UnexpectedError(t, err)
+	uc.Datastore.DeactivateAccount(ctx, id)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The account is not active")
+
+	/* The wrong passwords still fail as for any other account. */
+	helper.AddParam(t, uc, "password", "wrong")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong username or password")
+}
+
+func TestLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{})
+	id := helper.RegisterUser(t, uc.Datastore, "lockme", "ritho", "palvarez@ritho.net", "12345")
+	helper.ActivateUser(t, uc.Datastore, id)
+
+	/* The unknown accounts are locked the same way as the existing ones. */
+	for _, username := range []string{"lockme", "unknown"} {
+		helper.AddParam(t, uc, "login", username)
+		helper.AddParam(t, uc, "password", "wrong")
+		for i := 0; i < 4; i++ {
+			_, err := uc.Run(ctx)
+			helper.Contains(t, fmt.Sprint(err), "Wrong username or password")
+		}
+
+		_, err := uc.Run(ctx)
+		helper.Contains(t, fmt.Sprint(err), "Too many requests, try again later")
+	}
+
+	helper.AddParams(t, uc, map[string]interface{}{"login": "lockme", "password": "12345"})
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Too many requests, try again later")
+
+	lockout.Reset("lockme")
+	_, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+}
+
+func TestLoginClientLockout(t *testing.T) {
+	ctx := logging.WithClientIP(context.Background(), "192.0.2.1")
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"password": "wrong"})
+
+	/* Trying one password on many accounts locks the client. */
+	for i := 0; i < 11; i++ {
+		helper.AddParam(t, uc, "login", fmt.Sprintf("user%d", i))
+		_, err := uc.Run(ctx)
+		helper.Contains(t, fmt.Sprint(err), "Wrong username or password")
+	}
+
+	helper.AddParam(t, uc, "login", "another")
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Too many requests, try again later")
+
+	_, err = uc.Run(logging.WithClientIP(context.Background(), "192.0.2.2"))
+	helper.Contains(t, fmt.Sprint(err), "Wrong username or password")
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"login": "ritho", "password": "12345"})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	helper.ActivateUser(t, uc.Datastore, id)
+	uc.Datastore.SetAdmin(ctx, id, true)
+	uc.Datastore.SetPolicy(ctx, datastore.Policy{TwoFactor: datastore.TwoFactorAdmins})
+
+	/* The admins without two-factor authentication are told to enroll it. */
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"two_factor_required":true`)
+
+	_, err = uc.Datastore.DeleteSessions(ctx, id)
+	helper.UnexpectedError(t, err)
+	helper.EnableTwoFactor(t, uc.Datastore, "ritho")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, "Type the code of your authenticator app to log in")
+	helper.Contains(t, plainResult, `"challenge":`)
+	if strings.Contains(plainResult, `"token"`) || uc.Datastore.DoesAccountHaveSessionByID(ctx, id) {
+		t.Errorf("Expected no session before the code, Got %s", plainResult)
+	}
+}
+
+func TestLoginLDAP(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"login": "jdoe", "password": "jdoe-secret"})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	helper.ActivateUser(t, uc.Datastore, id)
+	s := helper.LDAPDirectory(t)
+	s.AddUser("jdoe", "John Doe", "jdoe@ritho.net", "Developer", "jdoe-secret")
+	s.AddUser("ritho", "Pablo Álvarez", "other@ritho.net", "", "ritho-secret")
+
+	/* The first login of a directory user imports the account. */
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"token":`)
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, "jdoe-ldap")
+	helper.UnexpectedError(t, err)
+	if acc.Email() != "jdoe@ritho.net" || acc.ExternalID() != "ldap|jdoe" {
+		t.Errorf("Expected the account of the directory, Got %s %s", acc.Email(), acc.ExternalID())
+	}
+
+	_, err = uc.Datastore.DeleteSessions(ctx, acc.ID())
+	helper.UnexpectedError(t, err)
+	helper.AddParam(t, uc, "password", "wrong")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong username or password")
+
+	/* The accounts deactivated in the radar are not activated by the
+	directory. */
+	uc.Datastore.DeactivateAccount(ctx, acc.ID())
+	helper.AddParam(t, uc, "password", "jdoe-secret")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The account is not active")
+	acc, err = uc.Datastore.GetAccountByID(ctx, acc.ID())
+	helper.UnexpectedError(t, err)
+	if acc.IsActive() {
+		t.Error("Expected the account to be kept deactivated")
+	}
+
+	/* The local accounts keep their password, even with the same username. */
+	helper.AddParams(t, uc, map[string]interface{}{"login": "ritho", "password": "ritho-secret"})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong username or password")
+	helper.AddParam(t, uc, "password", "12345")
+	_, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	/* The directory accounts can't log in while it's not available. */
+	s.Close()
+	helper.AddParams(t, uc, map[string]interface{}{"login": "jdoe-ldap", "password": "jdoe-secret"})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The directory is not available, try again later")
+}
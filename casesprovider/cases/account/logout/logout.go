@@ -20,10 +20,20 @@ package logout
 */
 
 import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
 	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/casesprovider/cases/usecase"
 )
 
+// msgSuccess is the result of a successful logout.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountLogoutSuccess",
+	Other: "User logout successfully",
+}
+
 // UseCase for the user logout.
 type UseCase struct {
 	usecase.UseCase
@@ -43,6 +53,7 @@ func New() *UseCase {
 				"username": "",
 				"token":    "",
 			},
+			Audit: true,
 		},
 	}
 
@@ -54,25 +65,31 @@ func (uc *UseCase) New() casesprovider.UseCase {
 	return New()
 }
 
+// TwoFactorExempt returns true, the accounts can close their sessions without
+// enrolling the two-factor authentication required to them.
+func (uc *UseCase) TwoFactorExempt() bool {
+	return true
+}
+
 // Run tries to log out an user from the system.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	var err error
 	res := usecase.NewResult()
 
 	username := uc.Params["username"].(string)
 	session := uc.Params["token"].(string)
-	acc, err := uc.Datastore.GetAccountByUsername(username)
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, username)
 	if err != nil {
 		return res, err
 	}
 
-	err = uc.Datastore.DeleteSession(session, username)
+	err = uc.Datastore.DeleteSession(ctx, session, username)
 	if err != nil {
 		return res, err
 	}
 
-	res.Res["result"] = "User logout successfully"
-	res.Res["id"] = acc.ID()
+	res.Res["result"] = msgSuccess
+	res.Res["id"] = acc.UUID()
 	res.Res["username"] = acc.Username()
 
 	return res, err
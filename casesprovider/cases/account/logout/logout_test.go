@@ -19,6 +19,7 @@ package logout
 */
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -27,12 +28,13 @@ import (
 )
 
 func TestLogout(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 	helper.TestCaseName(t, uc, "AccountLogout")
 	uc.SetDatastore(datastore.New())
 	helper.AddParam(t, uc, "username", "ritho")
 	helper.AddParam(t, uc, "token", "00000000-0000-0000-0000-000000000000")
-	_, err := uc.Run()
+	_, err := uc.Run(ctx)
 	if err == nil {
 		t.Error("Expected error running the use case.")
 	}
@@ -41,16 +43,16 @@ func TestLogout(t *testing.T) {
 	err = uc.AddParam("tokens", "12345")
 	helper.Contains(t, fmt.Sprintf("%s", err), "Error adding the param tokens")
 	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
-	_, err = uc.Run()
+	_, err = uc.Run(ctx)
 	if err == nil {
 		t.Error("Expected error running the use case.")
 	}
 
 	helper.Contains(t, fmt.Sprintf("%s", err), "ritho: User not logged in")
 	helper.LoginUser(t, uc.Datastore, "00000000-0000-0000-0000-000000000000", "ritho")
-	res, err := uc.Run()
+	res, err := uc.Run(ctx)
 	helper.UnexpectedError(t, err)
 	plainResult := helper.GetResultString(t, res)
-	helper.Contains(t, plainResult, fmt.Sprintf(`"id":%d`, id))
+	helper.Contains(t, plainResult, fmt.Sprintf(`"id":%q`, helper.AccountUUID(t, uc.Datastore, id)))
 	helper.Contains(t, plainResult, `User logout successfully`)
 }
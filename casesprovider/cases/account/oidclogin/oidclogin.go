@@ -0,0 +1,273 @@
+package oidclogin
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/login"
+	"github.com/radar-go/radar/casesprovider/cases/account/oidcstart"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/oidc"
+)
+
+// ErrEmailNotVerified raised when the identity provider doesn't vouch for the
+// email of the user, so it can't be used to find its account.
+var ErrEmailNotVerified = i18n.NewError(&goi18n.Message{
+	ID:    "OIDCEmailNotVerified",
+	Other: "The identity provider has not verified your email",
+})
+
+// ErrNotAllowed raised when none of the groups of the user is allowed to use
+// the radar.
+var ErrNotAllowed = i18n.NewError(&goi18n.Message{
+	ID:    "OIDCNotAllowed",
+	Other: "Your groups are not allowed to use the radar",
+})
+
+// ErrLinked raised when the account of the email is already linked with
+// another identity of the provider.
+var ErrLinked = i18n.NewError(&goi18n.Message{
+	ID:    "OIDCAccountLinked",
+	Other: "The account of your email is linked with another identity",
+})
+
+// usernameChars matches the characters not allowed in the usernames created
+// from the identity of the users.
+var usernameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// UseCase for the single sign-on login.
+type UseCase struct {
+	usecase.UseCase
+	account *account.Account
+}
+
+// Result stores the result of the single sign-on login.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new single sign-on login use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		UseCase: usecase.UseCase{
+			Name: "AccountOIDCLogin",
+			Params: map[string]interface{}{
+				"code":     "",
+				"state":    "",
+				"verifier": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new single sign-on login use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// AuditTarget returns the account logged in, known once the provider answers.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	return uc.account
+}
+
+// Run exchanges the code given by the identity provider for the identity of
+// the user and logs in its account. The account is found by the identity, then
+// linked by the verified email, and created if there is none.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	p := casesprovider.OIDC()
+	if !p.Configured() {
+		return res, oidc.ErrNotConfigured
+	}
+
+	nonce, err := oidcstart.Nonce(uc.Params["state"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	claims, err := p.Exchange(ctx, uc.Params["code"].(string), uc.Params["verifier"].(string), nonce)
+	if err != nil {
+		return res, err
+	}
+
+	role, allowed := p.Role(claims)
+	if !allowed {
+		return res, errWrap.Wrap(ErrNotAllowed, claims.Subject)
+	}
+
+	acc, err := uc.find(ctx, claims)
+	if err != nil {
+		return res, err
+	}
+
+	/* The roles follow the groups only when they are mapped, otherwise
+	they are managed in the radar. */
+	if len(p.Groups) > 0 {
+		acc.SetAdmin(role == oidc.RoleAdmin)
+	}
+
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	/* The second factor is asked as in the local logins, the provider may
+	not require it. */
+	uc.account = acc
+	if acc.HasTwoFactor() {
+		login.Challenge(acc, res)
+		return res, nil
+	}
+
+	err = login.Open(ctx, uc.Datastore, acc, res)
+
+	return res, err
+}
+
+// find returns the account of the identity, linking or creating it the first
+// time the user logs in, or an error if the account is deactivated. The
+// account is stored by the caller, so nothing changes if it fails.
+func (uc *UseCase) find(ctx context.Context, claims *oidc.Claims) (*account.Account, error) {
+	externalID := claims.Issuer + "|" + claims.Subject
+	acc, err := uc.Datastore.GetAccountByExternalID(ctx, externalID)
+	if err == nil && !acc.IsActive() {
+		/* The linked accounts are only inactive when they were deactivated. */
+		return nil, account.ErrAccountInactive
+	} else if err == nil {
+		return acc, nil
+	}
+
+	/* Only the emails verified by the provider are trusted, otherwise anyone
+	could take an account registering its email in the provider. */
+	if !claims.EmailVerified || claims.Email == "" {
+		return nil, errWrap.Wrap(ErrEmailNotVerified, claims.Email)
+	}
+
+	acc, err = uc.Datastore.GetAccountByEmail(ctx, claims.Email)
+	switch {
+	case errWrap.Cause(err) == account.ErrAccountNotExists:
+		return uc.provision(ctx, claims, externalID)
+	case err != nil:
+		return nil, err
+	case acc.ExternalID() != "":
+		return nil, errWrap.Wrap(ErrLinked, claims.Email)
+	case acc.IsDeactivated():
+		/* The identity doesn't activate the accounts deactivated. */
+		return nil, errWrap.Wrap(account.ErrAccountInactive, claims.Email)
+	}
+
+	if !acc.IsActive() {
+		/* The email of the account was never verified, so it may have been
+		registered by someone else waiting for its owner to log in. Nothing
+		set by them is kept: the password, the sessions nor the two-factor
+		authentication. */
+		password, err := oidc.Random()
+		if err != nil {
+			return nil, err
+		}
+
+		err = acc.SetPassword(password)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = uc.Datastore.DeleteSessions(ctx, acc.ID())
+		if err != nil {
+			return nil, err
+		}
+
+		acc.DisableTwoFactor()
+		acc.Activate()
+	}
+
+	acc.SetExternalID(externalID)
+
+	return acc, nil
+}
+
+// provision creates the account of the identity, active as its email is
+// verified and with a random password as the user logs in through the
+// provider.
+func (uc *UseCase) provision(ctx context.Context, claims *oidc.Claims, externalID string) (*account.Account, error) {
+	password, err := oidc.Random()
+	if err != nil {
+		return nil, err
+	}
+
+	username := uc.username(ctx, claims)
+	name := claims.Name
+	if name == "" {
+		name = username
+	}
+
+	id, err := uc.Datastore.AccountRegistration(ctx, username, name, claims.Email, password)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := uc.Datastore.GetAccountByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	acc.SetExternalID(externalID)
+	acc.Activate()
+
+	return acc, nil
+}
+
+// username returns a free username for the identity, from its preferred
+// username or the local part of its email.
+func (uc *UseCase) username(ctx context.Context, claims *oidc.Claims) string {
+	base := claims.Username
+	if base == "" {
+		base = strings.SplitN(claims.Email, "@", 2)[0]
+	}
+
+	base = usernameChars.ReplaceAllString(radar.CleanString(base), "")
+	if base == "" {
+		base = "user"
+	}
+
+	if len(base) < 5 {
+		base += "-sso"
+	}
+
+	username := base
+	for i := 2; uc.Datastore.IsAccountRegisteredByUsername(ctx, username); i++ {
+		username = base + strconv.Itoa(i)
+	}
+
+	return username
+}
@@ -0,0 +1,238 @@
+package oidclogin
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/oidcstart"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/oidc/oidctest"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountOIDCLogin")
+}
+
+// ssoLogin logs in the user of the mock provider, returning the result of the
+// single sign-on login.
+func ssoLogin(t *testing.T, ds *datastore.Datastore, s *oidctest.Server) (*usecase.Result, error) {
+	t.Helper()
+	start := oidcstart.New()
+	start.SetDatastore(ds)
+	res, err := start.Run(context.Background())
+	helper.UnexpectedError(t, err)
+
+	params := res.(*usecase.Result).Res
+	code, state, err := s.Authorize(params["url"].(string))
+	helper.UnexpectedError(t, err)
+
+	uc := New()
+	uc.SetDatastore(ds)
+	helper.AddParams(t, uc, map[string]interface{}{
+		"code":     code,
+		"state":    state,
+		"verifier": params["verifier"],
+	})
+	res, err = uc.Run(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return res.(*usecase.Result), nil
+}
+
+func TestOIDCLoginErrors(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{
+		"code":     "code",
+		"state":    "state",
+		"verifier": "verifier",
+	})
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Single sign-on is not configured")
+
+	s := helper.OIDCProvider(t, oidctest.User{Subject: "1234", Email: "palvarez@ritho.net"})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The single sign-on has expired, log in again")
+
+	_, err = ssoLogin(t, uc.Datastore, s)
+	helper.Contains(t, fmt.Sprint(err), "The identity provider has not verified your email")
+
+	casesprovider.OIDC().Groups = map[string]string{"radar-admins": "admin"}
+	_, err = ssoLogin(t, uc.Datastore, s)
+	helper.Contains(t, fmt.Sprint(err), "Your groups are not allowed to use the radar")
+}
+
+func TestOIDCLoginProvision(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.New()
+	helper.RegisterUser(t, ds, "ritho", "ritho", "ritho@ritho.net", "12345")
+	s := helper.OIDCProvider(t, oidctest.User{
+		Subject:       "1234",
+		Email:         "palvarez@ritho.net",
+		EmailVerified: true,
+		Name:          "Pablo Álvarez",
+		Username:      "Ritho",
+	})
+
+	res, err := ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"username":"ritho2"`)
+	helper.Contains(t, plainResult, `"token":`)
+
+	acc, err := ds.GetAccountByUsername(ctx, "ritho2")
+	helper.UnexpectedError(t, err)
+	if !acc.IsActive() || acc.IsAdmin() || acc.Name() != "Pablo Álvarez" ||
+		acc.ExternalID() != s.URL+"|1234" {
+		t.Errorf("Unexpected account provisioned %+v", acc)
+	}
+
+	/* The identity keeps linked to the account if the email changes. */
+	ds.DeleteSessions(ctx, acc.ID())
+	s.SetUser(oidctest.User{Subject: "1234", Email: "pablo@ritho.net", EmailVerified: true})
+	res, err = ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"username":"ritho2"`)
+
+	/* The deactivated accounts can't log in through the provider either. */
+	ds.DeleteSessions(ctx, acc.ID())
+	ds.DeactivateAccount(ctx, acc.ID())
+	_, err = ssoLogin(t, ds, s)
+	helper.Contains(t, fmt.Sprint(err), "The account is not active")
+	ds.ActivateAccount(ctx, acc.ID())
+
+	s.SetUser(oidctest.User{Subject: "5678", Email: "al@ritho.net", EmailVerified: true})
+	res, err = ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"username":"al-sso"`)
+
+	/* A second identity with the email of a linked account is refused. */
+	s.SetUser(oidctest.User{Subject: "9012", Email: "al@ritho.net", EmailVerified: true})
+	_, err = ssoLogin(t, ds, s)
+	helper.Contains(t, fmt.Sprint(err), "The account of your email is linked with another identity")
+}
+
+func TestOIDCLoginLink(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.New()
+	active := helper.RegisterUser(t, ds, "active", "ritho", "active@ritho.net", "12345")
+	inactive := helper.RegisterUser(t, ds, "inactive", "ritho", "inactive@ritho.net", "12345")
+	deactivated := helper.RegisterUser(t, ds, "deactivated", "ritho", "deactivated@ritho.net", "12345")
+	ds.ActivateAccount(ctx, active)
+	ds.DeactivateAccount(ctx, deactivated)
+	helper.LoginUser(t, ds, "00000000-0000-0000-0000-000000000000", "inactive")
+	helper.EnableTwoFactor(t, ds, "inactive")
+
+	s := helper.OIDCProvider(t, oidctest.User{
+		Subject:       "1234",
+		Email:         "Active@ritho.net",
+		EmailVerified: true,
+	})
+	res, err := ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"username":"active"`)
+
+	acc, _ := ds.GetAccountByID(ctx, active)
+	if !acc.CheckPassword("12345") || acc.ExternalID() != s.URL+"|1234" {
+		t.Errorf("Expected the account linked keeping its password, Got %+v", acc)
+	}
+
+	/* The accounts with the email not verified may have been registered by
+	anyone, so they lose their password, sessions and second factor. */
+	s.SetUser(oidctest.User{Subject: "5678", Email: "inactive@ritho.net", EmailVerified: true})
+	res, err = ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"username":"inactive"`)
+
+	acc, _ = ds.GetAccountByID(ctx, inactive)
+	if !acc.IsActive() || acc.CheckPassword("12345") || acc.HasTwoFactor() {
+		t.Errorf("Expected the account taken over by the identity, Got %+v", acc)
+	}
+
+	_, err = ds.GetAccountBySession(ctx, "00000000-0000-0000-0000-000000000000")
+	if err == nil {
+		t.Error("Expected the previous sessions closed")
+	}
+
+	/* The accounts deactivated are neither linked nor activated. */
+	s.SetUser(oidctest.User{Subject: "9012", Email: "deactivated@ritho.net", EmailVerified: true})
+	_, err = ssoLogin(t, ds, s)
+	helper.Contains(t, fmt.Sprint(err), "The account is not active")
+
+	acc, _ = ds.GetAccountByID(ctx, deactivated)
+	if acc.IsActive() || !acc.CheckPassword("12345") || acc.ExternalID() != "" {
+		t.Errorf("Expected the deactivated account unchanged, Got %+v", acc)
+	}
+}
+
+func TestOIDCLoginGroups(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.New()
+	s := helper.OIDCProvider(t, oidctest.User{
+		Subject:       "1234",
+		Email:         "palvarez@ritho.net",
+		EmailVerified: true,
+		Username:      "ritho",
+		Groups:        []string{"engineering", "radar-admins"},
+	})
+	casesprovider.OIDC().Groups = map[string]string{
+		"engineering":  "member",
+		"radar-admins": "admin",
+	}
+
+	_, err := ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	acc, _ := ds.GetAccountByUsername(ctx, "ritho")
+	if !acc.IsAdmin() {
+		t.Error("Expected the admin role mapped from the groups")
+	}
+
+	ds.DeleteSessions(ctx, acc.ID())
+	s.SetUser(oidctest.User{
+		Subject:       "1234",
+		Email:         "palvarez@ritho.net",
+		EmailVerified: true,
+		Groups:        []string{"engineering"},
+	})
+	_, err = ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	acc, _ = ds.GetAccountByUsername(ctx, "ritho")
+	if acc.IsAdmin() {
+		t.Error("Expected the admin role revoked with the group")
+	}
+
+	/* The accounts with two-factor authentication are asked the code. */
+	ds.DeleteSessions(ctx, acc.ID())
+	helper.EnableTwoFactor(t, ds, "ritho")
+	res, err := ssoLogin(t, ds, s)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"challenge":`)
+	if ds.DoesAccountHaveSessionByID(ctx, acc.ID()) {
+		t.Error("Expected no session before the two-factor code")
+	}
+}
@@ -0,0 +1,125 @@
+package oidcstart
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/oidc"
+)
+
+// purpose of the login states, so they can't be used as other tokens.
+const purpose = "oidc-state"
+
+// stateTTL is the time the user has to log in the identity provider.
+const stateTTL = 10 * time.Minute
+
+// msgSuccess is the result of a successful single sign-on start.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountOIDCStartSuccess",
+	Other: "Log in the identity provider to continue",
+}
+
+// ErrState raised when the state given back by the provider is not valid or
+// has expired.
+var ErrState = i18n.NewError(&goi18n.Message{
+	ID:    "OIDCStateInvalid",
+	Other: "The single sign-on has expired, log in again",
+})
+
+// UseCase for the single sign-on start.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the single sign-on start.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new single sign-on start use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name:   "AccountOIDCStart",
+			Params: map[string]interface{}{},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new single sign-on start use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run returns the url of the identity provider the user logs in, with the
+// state to check the answer of the provider belongs to this login and the
+// code verifier that has to be kept to finish it.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	p := casesprovider.OIDC()
+	if !p.Configured() {
+		return res, oidc.ErrNotConfigured
+	}
+
+	nonce, err := oidc.Random()
+	if err != nil {
+		return res, err
+	}
+
+	verifier, err := oidc.Random()
+	if err != nil {
+		return res, err
+	}
+
+	/* The nonce travels signed in the state, so the radar doesn't keep the
+	logins in progress. */
+	state := casesprovider.Signer().Sign(purpose, nonce, stateTTL)
+	authURL, err := p.AuthURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["url"] = authURL
+	res.Res["state"] = state
+	res.Res["verifier"] = verifier
+
+	return res, nil
+}
+
+// Nonce returns the nonce of the login of the state, or an error if the state
+// is not valid or has expired.
+func Nonce(state string) (string, error) {
+	nonce, err := casesprovider.Signer().Verify(purpose, state)
+	if err != nil {
+		return "", ErrState
+	}
+
+	return nonce, nil
+}
@@ -0,0 +1,61 @@
+package oidcstart
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/oidc"
+	"github.com/radar-go/radar/oidc/oidctest"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountOIDCStart")
+}
+
+func TestStart(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, nil)
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Single sign-on is not configured")
+
+	s := helper.OIDCProvider(t, oidctest.User{Subject: "1234"})
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	r := res.(*usecase.Result)
+	authURL, _ := r.Res["url"].(string)
+	helper.Contains(t, authURL, s.URL+"/authorize?")
+	helper.Contains(t, authURL, "code_challenge="+oidc.Challenge(r.Res["verifier"].(string)))
+	helper.Contains(t, authURL, "code_challenge_method=S256")
+
+	nonce, err := Nonce(r.Res["state"].(string))
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, authURL, "nonce="+nonce)
+
+	_, err = Nonce("wrong")
+	if err != ErrState {
+		t.Errorf("Expected %s, Got %v", ErrState, err)
+	}
+}
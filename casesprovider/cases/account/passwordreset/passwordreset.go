@@ -0,0 +1,101 @@
+// Package passwordreset implements the links sent to the users to reset their
+// passwords.
+package passwordreset
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/mailer"
+	"github.com/radar-go/radar/token"
+)
+
+// purpose of the reset codes, so they can't be used as other tokens.
+const purpose = "password-reset"
+
+// msgSubject is the subject of the password reset email.
+var msgSubject = &goi18n.Message{
+	ID:    "PasswordResetSubject",
+	Other: "Reset your Radar password",
+}
+
+// msgBody is the body of the password reset email.
+var msgBody = &goi18n.Message{
+	ID:    "PasswordResetBody",
+	Other: "Hi {{.Name}},\n\nSomeone asked to reset the password of your Radar account. Follow this link to choose a new one:\n\n{{.Link}}\n\nThe link expires in {{.Minutes}} minutes and can be used only once. If you didn't ask for it, ignore this email.",
+}
+
+// Code returns a new reset code for the account. The code is bound to the
+// current password, so it stops being valid once it's used to change it.
+func Code(acc *account.Account) string {
+	return casesprovider.Signer().Sign(purpose, subject(acc),
+		casesprovider.Config().ResetTTL)
+}
+
+// subject returns the subject of the reset codes of the account.
+func subject(acc *account.Account) string {
+	return fmt.Sprintf("%s:%s", acc.UUID(), casesprovider.Signer().Hash(acc.Password()))
+}
+
+// Link returns the link of the web interface to reset the password.
+func Link(acc *account.Account) string {
+	return strings.TrimRight(casesprovider.Config().PublicURL, "/") +
+		"/password/reset/confirm?code=" + url.QueryEscape(Code(acc))
+}
+
+// Send sends the reset link to the email of the account, in the language of
+// the request.
+func Send(ctx context.Context, acc *account.Account) error {
+	loc := i18n.ContextLocalizer(ctx)
+	return casesprovider.Mailer().Send(ctx, &mailer.Message{
+		To:      acc.Email(),
+		Subject: loc.Message(msgSubject),
+		Body: loc.Message(msgBody, map[string]interface{}{
+			"Name":    acc.Name(),
+			"Link":    Link(acc),
+			"Minutes": int(casesprovider.Config().ResetTTL.Minutes()),
+		}),
+	})
+}
+
+// Account returns the account of the reset code, or an error if the code is
+// not valid, has expired or has already been used.
+func Account(ctx context.Context, ds *datastore.Datastore, code string) (*account.Account, error) {
+	sub, err := casesprovider.Signer().Verify(purpose, code)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := ds.GetAccountByUUID(ctx, strings.SplitN(sub, ":", 2)[0])
+	if err != nil || subject(acc) != sub {
+		return nil, token.ErrInvalid
+	}
+
+	return acc, nil
+}
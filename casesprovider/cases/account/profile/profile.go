@@ -0,0 +1,61 @@
+package profile
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// Profile is the representation of an account in the directory. The private
+// fields are only shown to the account itself and to the administrators.
+type Profile struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	Email     string `json:"email,omitempty"`
+	Active    *bool  `json:"active,omitempty"`
+	Admin     *bool  `json:"admin,omitempty"`
+	TwoFactor *bool  `json:"two_factor,omitempty"`
+}
+
+// New returns the profile of the account as seen by the caller.
+func New(acc, caller *account.Account) Profile {
+	p := Profile{
+		ID:       acc.UUID(),
+		Username: acc.Username(),
+		Name:     acc.Name(),
+		Role:     acc.Title(),
+	}
+
+	if Private(acc, caller) {
+		active, admin, twoFactor := acc.IsActive(), acc.IsAdmin(), acc.HasTwoFactor()
+		p.Email = acc.Email()
+		p.Active = &active
+		p.Admin = &admin
+		p.TwoFactor = &twoFactor
+	}
+
+	return p
+}
+
+// Private returns true if the caller can see the private fields of the
+// account.
+func Private(acc, caller *account.Account) bool {
+	return caller != nil && (caller.IsAdmin() || caller.ID() == acc.ID())
+}
@@ -20,12 +20,35 @@ package register
 */
 
 import (
-	"fmt"
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 
 	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
 	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/logging"
 )
 
+// msgSuccess is the result of a successful registration.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountRegisterSuccess",
+	Other: "Account registered successfully, follow the link sent to your email to activate it",
+}
+
+// msgError is the result of a failed registration.
+var msgError = &goi18n.Message{
+	ID:    "AccountRegisterError",
+	Other: "Error registering the account",
+}
+
+// msgAlreadyRegistered is the error when the username is already registered.
+var msgAlreadyRegistered = &goi18n.Message{
+	ID:    "AccountAlreadyRegistered",
+	Other: "User {{.Username}} already registered",
+}
+
 // UseCase for the account registration.
 type UseCase struct {
 	usecase.UseCase
@@ -47,6 +70,7 @@ func New() *UseCase {
 				"email":    "",
 				"password": "",
 			},
+			Audit: true,
 		},
 	}
 
@@ -59,16 +83,19 @@ func (uc *UseCase) New() casesprovider.UseCase {
 }
 
 // Run tries to register a new account into the system.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	res := usecase.NewResult()
 
 	username := uc.Params["username"].(string)
-	_, err := uc.Datastore.GetAccountByUsername(username)
+	_, err := uc.Datastore.GetAccountByUsername(ctx, username)
 	if err == nil {
-		return res, fmt.Errorf("User %s already registered", username)
+		return res, i18n.NewError(msgAlreadyRegistered, map[string]interface{}{
+			"Username": username,
+		})
 	}
 
 	userID, err := uc.Datastore.AccountRegistration(
+		ctx,
 		username,
 		uc.Params["name"].(string),
 		uc.Params["email"].(string),
@@ -76,12 +103,26 @@ func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
 	)
 
 	if err != nil {
-		res.Res["result"] = "Error registering the account"
-		res.Res["error"] = fmt.Sprintf("%s", err)
-	} else {
-		res.Res["result"] = "Account registered successfully"
-		res.Res["id"] = userID
+		res.Res["result"] = msgError
+		res.Res["error"] = err
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+
+	/* The account is registered even if the email can't be sent, the user can
+	ask for a new link later. */
+	acc, err := uc.Datastore.GetAccountByID(ctx, userID)
+	if err == nil {
+		res.Res["id"] = acc.UUID()
+		err = verification.Send(ctx, acc)
+	}
+
+	if err != nil {
+		logging.Logger.Error("verification email not sent",
+			"request_id", logging.ContextRequestID(ctx), "username", username,
+			"error", err.Error())
 	}
 
-	return res, err
+	return res, nil
 }
@@ -19,17 +19,22 @@ package register
 */
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"testing"
 
 	"github.com/goware/emailx"
 
+	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/casesprovider/errors"
 	"github.com/radar-go/radar/casesprovider/helper"
 	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/mailer"
 )
 
 func TestRegister(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 	helper.TestCaseName(t, uc, "AccountRegister")
 	uc.SetDatastore(datastore.New())
@@ -37,7 +42,7 @@ func TestRegister(t *testing.T) {
 	helper.AddParam(t, uc, "name", "Ritho")
 	helper.AddParam(t, uc, "email", "palvarez@ritho.net")
 	helper.AddParam(t, uc, "password", "Ritho")
-	res, err := uc.Run()
+	res, err := uc.Run(ctx)
 	helper.UnexpectedError(t, err)
 	resultString := helper.GetResultString(t, res)
 	helper.Contains(t, resultString, `"id"`)
@@ -47,27 +52,49 @@ func TestRegister(t *testing.T) {
 	helper.ContainsBytes(t, resultBytes, []byte(`Account registered successfully`))
 }
 
+func TestRegisterVerificationEmail(t *testing.T) {
+	out := &bytes.Buffer{}
+	casesprovider.SetMailer(mailer.NewWriter(out, "radar@localhost"))
+	defer casesprovider.SetMailer(&mailer.Log{})
+
+	uc := New()
+	uc.SetDatastore(datastore.New())
+	helper.AddParams(t, uc, map[string]interface{}{
+		"username": "ritho",
+		"name":     "Ritho",
+		"email":    "palvarez@ritho.net",
+		"password": "Ritho",
+	})
+	_, err := uc.Run(context.Background())
+	helper.UnexpectedError(t, err)
+
+	helper.Contains(t, out.String(), "To: palvarez@ritho.net")
+	helper.Contains(t, out.String(), "Subject: Activate your Radar account")
+	helper.Contains(t, out.String(), "http://localhost:10080/account/verify?code=")
+}
+
 func TestRegisterError(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 	helper.TestCaseName(t, uc, "AccountRegister")
 	uc.Datastore = datastore.New()
 	helper.AddParam(t, uc, "name", "ritho")
-	_, err := uc.Run()
+	_, err := uc.Run(ctx)
 	helper.Contains(t, fmt.Sprintf("%s", err), "Username too short")
 	helper.AddParam(t, uc, "username", "ritho")
-	_, err = uc.Run()
+	_, err = uc.Run(ctx)
 	helper.Contains(t, fmt.Sprintf("%s", err), fmt.Sprintf("%s", emailx.ErrInvalidFormat))
 	helper.AddParam(t, uc, "email", "Ritho")
-	_, err = uc.Run()
+	_, err = uc.Run(ctx)
 	helper.Contains(t, fmt.Sprintf("%s", err), fmt.Sprintf("%s", emailx.ErrInvalidFormat))
 	helper.AddParam(t, uc, "email", "Ritho@invalid.es")
-	_, err = uc.Run()
+	_, err = uc.Run(ctx)
 	helper.Contains(t, fmt.Sprintf("%s", err), fmt.Sprintf("%s", emailx.ErrUnresolvableHost))
 	helper.AddParam(t, uc, "email", "palvarez@ritho.net")
-	_, err = uc.Run()
+	_, err = uc.Run(ctx)
 	helper.Contains(t, fmt.Sprintf("%s", err), "Password too short")
 	helper.AddParam(t, uc, "password", "Ritho")
-	_, err = uc.Run()
+	_, err = uc.Run(ctx)
 	helper.UnexpectedError(t, err)
 }
 
@@ -20,14 +20,28 @@ package remove
 */
 
 import (
-	"fmt"
+	"context"
 
-	"github.com/pkg/errors"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 
+	"github.com/radar-go/radar"
 	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
 )
 
+// msgSuccess is the result of a successful removal.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountRemoveSuccess",
+	Other: "Account removed successfully",
+}
+
+// msgError is the result of a failed removal.
+var msgError = &goi18n.Message{
+	ID:    "AccountRemoveError",
+	Other: "Error removing the account",
+}
+
 // UseCase for the account removal.
 type UseCase struct {
 	usecase.UseCase
@@ -44,9 +58,10 @@ func New() *UseCase {
 		usecase.UseCase{
 			Name: "AccountRemove",
 			Params: map[string]interface{}{
-				"id":    0,
+				"id":    "",
 				"token": "",
 			},
+			Audit: true,
 		},
 	}
 
@@ -59,31 +74,30 @@ func (uc *UseCase) New() casesprovider.UseCase {
 }
 
 // Run tries to remove an account from the system.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	res := usecase.NewResult()
 
-	token := uc.Params["token"].(string)
-	acc, err := uc.Datastore.GetAccountBySession(token)
+	acc, err := uc.SessionAccount(ctx)
 	if err != nil {
 		return res, err
 	}
 
-	if acc.ID() != uc.Params["id"].(int) {
-		return res, errors.New("The account id doesn't match with the user logged in")
+	if acc.UUID() != radar.CleanString(uc.Params["id"].(string)) {
+		return res, account.ErrUserMismatch
 	}
 
-	err = uc.Datastore.DeleteSession(token, acc.Username())
+	err = uc.Datastore.DeleteSession(ctx, uc.Params["token"].(string), acc.Username())
 	if err != nil {
 		return res, err
 	}
 
-	err = uc.Datastore.RemoveAccount(acc)
+	err = uc.Datastore.RemoveAccount(ctx, acc)
 	if err != nil {
-		res.Res["result"] = "Error removing the account"
-		res.Res["error"] = fmt.Sprintf("%s", err)
+		res.Res["result"] = msgError
+		res.Res["error"] = err
 	} else {
-		res.Res["result"] = "Account removed successfully"
-		res.Res["id"] = acc.ID()
+		res.Res["result"] = msgSuccess
+		res.Res["id"] = acc.UUID()
 	}
 
 	return res, nil
@@ -19,6 +19,8 @@ package remove
 */
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -29,20 +31,21 @@ import (
 )
 
 func TestRemoveAccountCreation(t *testing.T) {
+	ctx := context.Background()
 	uc := New()
 	helper.TestCaseName(t, New(), "AccountRemove")
 
 	uc.SetDatastore(datastore.New())
-	_, err := uc.Run()
+	_, err := uc.Run(ctx)
 	helper.SaveGoldenData(t, "RemoveAccountCreation", []byte(err.Error()))
 	expected := helper.GetGoldenData(t, "RemoveAccountCreation")
 	helper.ContainsBytes(t, []byte(err.Error()), expected)
 }
 
 func TestRemoveAccountError(t *testing.T) {
+	ctx := context.Background()
 	testCases := []struct {
 		testName     string
-		id           int
 		username     string
 		name         string
 		email        string
@@ -55,7 +58,6 @@ func TestRemoveAccountError(t *testing.T) {
 	}{
 		{
 			testName:     "AccountNotExists",
-			id:           1,
 			username:     "ritho",
 			name:         "ritho",
 			email:        "palvarez@ritho.net",
@@ -68,7 +70,6 @@ func TestRemoveAccountError(t *testing.T) {
 		},
 		{
 			testName:     "AccountNotLogin",
-			id:           1,
 			username:     "ritho",
 			name:         "ritho",
 			email:        "palvarez@ritho.net",
@@ -81,7 +82,6 @@ func TestRemoveAccountError(t *testing.T) {
 		},
 		{
 			testName:     "AccountRemoveSuccessful",
-			id:           2,
 			username:     "ritho",
 			name:         "ritho",
 			email:        "palvarez@ritho.net",
@@ -101,12 +101,10 @@ func TestRemoveAccountError(t *testing.T) {
 			uc := New()
 			uc.SetDatastore(datastore.New())
 
+			id := "00000000-0000-0000-0000-000000000001"
 			if tc.register {
-				id := helper.RegisterUser(t, uc.Datastore, tc.username, tc.name,
-					tc.email, tc.password)
-				if id != tc.id {
-					t.Errorf("Expected %d, Got %d", tc.id, id)
-				}
+				id = helper.AccountUUID(t, uc.Datastore, helper.RegisterUser(t,
+					uc.Datastore, tc.username, tc.name, tc.email, tc.password))
 			}
 
 			if tc.login {
@@ -114,8 +112,8 @@ func TestRemoveAccountError(t *testing.T) {
 			}
 
 			helper.AddParam(t, uc, "token", tc.session)
-			helper.AddParam(t, uc, "id", tc.id)
-			res, err := uc.Run()
+			helper.AddParam(t, uc, "id", id)
+			res, err := uc.Run(ctx)
 			if err != nil {
 				helper.SaveGoldenData(t, tc.testName+"_error", []byte(err.Error()))
 				expected := helper.GetGoldenData(t, tc.testName+"_error")
@@ -132,7 +130,8 @@ func TestRemoveAccountError(t *testing.T) {
 			expected := helper.GetGoldenData(t, tc.testName+"_result")
 			helper.ContainsBytes(t, actual, expected)
 			if tc.checkAccount {
-				_, err = uc.Datastore.GetAccountByUsername(tc.username)
+				helper.Contains(t, string(actual), fmt.Sprintf(`"id":%q`, id))
+				_, err = uc.Datastore.GetAccountByUsername(ctx, tc.username)
 				if err == nil {
 					t.Error("Expected error getting the account from the datastore")
 				} else if errors.Cause(err) != account.ErrAccountNotExists {
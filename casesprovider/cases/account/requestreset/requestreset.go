@@ -0,0 +1,107 @@
+// Package requestreset implements the use case sending the link to reset the
+// password of an account.
+package requestreset
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/passwordreset"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/ratelimit"
+)
+
+// msgSuccess is the result of the request, the same whether the account exists
+// or not so it can't be used to find out the registered usernames.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountPasswordResetRequestSuccess",
+	Other: "If the account exists, a link to reset its password has been sent to its email",
+}
+
+// Limits of the reset requests by account, so its owner doesn't get flooded
+// with emails, and by client, so it can't go through all the accounts.
+var (
+	accountLimiter = ratelimit.New(3, time.Hour)
+	clientLimiter  = ratelimit.New(10, time.Hour)
+)
+
+// UseCase for the password reset request.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the password reset request.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new request reset use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountPasswordResetRequest",
+			Params: map[string]interface{}{
+				"username": "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new request reset use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run sends a link to reset the password to the email of the account.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	ip := logging.ContextClientIP(ctx)
+	if ip != "" && !clientLimiter.Allow(ip) {
+		return res, errors.ErrTooManyRequests
+	}
+
+	res.Res["result"] = msgSuccess
+
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, uc.Params["username"].(string))
+	if err != nil || !accountLimiter.Allow(strconv.Itoa(acc.ID())) {
+		return res, nil
+	}
+
+	/* The failures are only logged, answering with an error would tell the
+	account exists. */
+	err = passwordreset.Send(ctx, acc)
+	if err != nil {
+		logging.Logger.Error("password reset email not sent",
+			"request_id", logging.ContextRequestID(ctx), "username", acc.Username(),
+			"error", err.Error())
+	}
+
+	return res, nil
+}
@@ -0,0 +1,78 @@
+package requestreset
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/mailer"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountPasswordResetRequest")
+}
+
+func TestRequestReset(t *testing.T) {
+	out := &bytes.Buffer{}
+	casesprovider.SetMailer(mailer.NewWriter(out, "radar@localhost"))
+	defer casesprovider.SetMailer(&mailer.Log{})
+
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{})
+	helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "121212")
+
+	for i, username := range []string{"unknown", "ritho", "ritho", "ritho", "ritho"} {
+		out.Reset()
+		helper.AddParam(t, uc, "username", username)
+		res, err := uc.Run(context.Background())
+		helper.UnexpectedError(t, err)
+		helper.Contains(t, helper.GetResultString(t, res),
+			"If the account exists, a link to reset its password has been sent to its email")
+
+		/* Only the first three requests for the account send an email. */
+		expected := username == "ritho" && i <= 3
+		sent := strings.Contains(out.String(), "http://localhost:10080/password/reset/confirm?code=")
+		if sent != expected {
+			t.Errorf("Expected email sent to be %t for the request %d, Got %s", expected, i, out)
+		}
+	}
+}
+
+func TestRequestResetClientLimit(t *testing.T) {
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"username": "unknown"})
+	ctx := logging.WithClientIP(context.Background(), "192.0.2.1")
+
+	var err error
+	for i := 0; i < 11 && err == nil; i++ {
+		_, err = uc.Run(ctx)
+	}
+
+	helper.Contains(t, fmt.Sprint(err), "Too many requests, try again later")
+
+	_, err = uc.Run(logging.WithClientIP(context.Background(), "192.0.2.2"))
+	helper.UnexpectedError(t, err)
+}
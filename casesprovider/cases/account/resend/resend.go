@@ -0,0 +1,86 @@
+// Package resend implements the use case sending a new verification link to an
+// account pending activation.
+package resend
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+)
+
+// msgSuccess is the result of the resend, the same whether the account exists
+// or not so it can't be used to find out the registered usernames.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountVerificationResendSuccess",
+	Other: "If the account is pending activation, a new link has been sent to its email",
+}
+
+// UseCase for sending a new verification link.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the verification link resend.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new resend use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountVerificationResend",
+			Params: map[string]interface{}{
+				"username": "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new resend use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run sends a new verification link to the email of the account if it's
+// pending activation, the accounts deactivated are not activated with it.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+	res.Res["result"] = msgSuccess
+
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, uc.Params["username"].(string))
+	if err != nil || acc.IsActive() || acc.IsDeactivated() {
+		return res, nil
+	}
+
+	err = verification.Send(ctx, acc)
+	if err != nil {
+		return usecase.NewResult(), err
+	}
+
+	return res, nil
+}
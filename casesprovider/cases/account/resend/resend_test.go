@@ -0,0 +1,76 @@
+package resend
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/mailer"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountVerificationResend")
+}
+
+func TestResend(t *testing.T) {
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+	casesprovider.SetMailer(mailer.NewWriter(out, "radar@localhost"))
+	defer casesprovider.SetMailer(&mailer.Log{})
+
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho",
+		"palvarez@ritho.net", "121212")
+
+	for _, username := range []string{"unknown", "ritho"} {
+		out.Reset()
+		helper.AddParam(t, uc, "username", username)
+		res, err := uc.Run(ctx)
+		helper.UnexpectedError(t, err)
+		helper.Contains(t, helper.GetResultString(t, res),
+			"If the account is pending activation, a new link has been sent to its email")
+
+		sent := strings.Contains(out.String(), "To: palvarez@ritho.net")
+		if sent != (username == "ritho") {
+			t.Errorf("Expected email sent to be %t for %s, Got %s", username == "ritho",
+				username, out)
+		}
+	}
+
+	uc.Datastore.ActivateAccount(ctx, id)
+	out.Reset()
+	_, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	if out.Len() > 0 {
+		t.Errorf("Expected no email for an active account, Got %s", out)
+	}
+
+	uc.Datastore.DeactivateAccount(ctx, id)
+	_, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	if out.Len() > 0 {
+		t.Errorf("Expected no email for a deactivated account, Got %s", out)
+	}
+}
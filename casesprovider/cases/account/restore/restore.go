@@ -0,0 +1,111 @@
+// Package restore implements the use case letting an administrator restore a
+// removed account before it's purged.
+package restore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful restore.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountRestoreSuccess",
+	Other: "Account restored successfully",
+}
+
+// UseCase for the account restore.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the account restore.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new restore use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountRestore",
+			Params: map[string]interface{}{
+				"id":    "",
+				"token": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new restore use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the admin scope, the API keys need it to restore the
+// accounts.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeAdmin
+}
+
+// AuditTarget returns the account restored.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	acc, _ := uc.Datastore.GetRemovedAccount(ctx, uc.Params["id"].(string))
+	return acc
+}
+
+// Run restores a removed account not purged yet, only the administrators can
+// restore the accounts.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	admin, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !admin.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	acc, err := uc.Datastore.GetRemovedAccount(ctx, uc.Params["id"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	err = uc.Datastore.RestoreAccount(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["id"] = acc.UUID()
+	res.Res["username"] = acc.Username()
+
+	return res, nil
+}
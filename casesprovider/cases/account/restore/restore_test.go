@@ -0,0 +1,69 @@
+package restore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountRestore")
+}
+
+func TestRestore(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token, "id": "00000000-0000-0000-0000-000000000001"})
+	id := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "admin@ritho.net", "admin")
+	userID := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	uuid := helper.AccountUUID(t, uc.Datastore, userID)
+	helper.LoginUser(t, uc.Datastore, token, "admin")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Administration privileges required")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Account doesn't exists")
+
+	helper.AddParam(t, uc, "id", uuid)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The account is not removed")
+
+	acc, err := uc.Datastore.GetAccountByID(ctx, userID)
+	helper.UnexpectedError(t, err)
+	helper.UnexpectedError(t, uc.Datastore.RemoveAccount(ctx, acc))
+	if uc.AuditTarget(ctx) == nil {
+		t.Error("Expected the removed account as the audit target")
+	}
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, "Account restored successfully")
+	helper.Contains(t, plainResult, `"username":"ritho"`)
+	if _, err = uc.Datastore.GetAccountByUsername(ctx, "ritho"); err != nil {
+		t.Errorf("Expected the account restored, Got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package revokeapikey
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+)
+
+// msgSuccess is the result of a successful revocation.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountAPIKeyRevokeSuccess",
+	Other: "API key revoked successfully",
+}
+
+// UseCase for the API key revocation.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the API key revocation.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new revoke API key use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountAPIKeyRevoke",
+			Params: map[string]interface{}{
+				"token": "",
+				"id":    "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new revoke API key use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run revokes the API key of the account of the session with the id.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	id := uc.Params["id"].(string)
+	if err = acc.RevokeAPIKey(id); err != nil {
+		return res, errors.Wrap(err, id)
+	}
+
+	err = uc.Datastore.UpdateAccountData(ctx, acc)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["id"] = id
+
+	return res, nil
+}
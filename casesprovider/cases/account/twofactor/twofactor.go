@@ -0,0 +1,88 @@
+// Package twofactor implements the challenges of the logins with the two-factor
+// authentication, given after the password to ask for the code.
+package twofactor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+)
+
+// purpose of the login challenges, so they can't be used as other tokens.
+const purpose = "login-challenge"
+
+// Issuer is the name of the radar in the authenticator apps.
+const Issuer = "Radar"
+
+// challengeTTL is the time the user has to type the code after the password.
+const challengeTTL = 5 * time.Minute
+
+// ErrChallenge raised when the challenge is not valid or has expired.
+var ErrChallenge = i18n.NewError(&goi18n.Message{
+	ID:    "TwoFactorChallengeInvalid",
+	Other: "The login has expired, log in again",
+})
+
+// Challenge returns a new login challenge for the account. The challenge is
+// bound to the current password, so changing it ends the pending logins.
+func Challenge(acc *account.Account) string {
+	return casesprovider.Signer().Sign(purpose, subject(acc), challengeTTL)
+}
+
+// subject returns the subject of the login challenges of the account.
+func subject(acc *account.Account) string {
+	return fmt.Sprintf("%s:%s", acc.UUID(), casesprovider.Signer().Hash(acc.Password()))
+}
+
+// Account returns the account of the login challenge, or an error if the
+// challenge is not valid or has expired.
+func Account(ctx context.Context, ds *datastore.Datastore, challenge string) (*account.Account, error) {
+	sub, err := casesprovider.Signer().Verify(purpose, challenge)
+	if err != nil {
+		return nil, ErrChallenge
+	}
+
+	acc, err := ds.GetAccountByUUID(ctx, strings.SplitN(sub, ":", 2)[0])
+	if err != nil || subject(acc) != sub || !acc.HasTwoFactor() {
+		return nil, ErrChallenge
+	}
+
+	return acc, nil
+}
+
+// Check returns an error if the code is neither a code of the authenticator
+// app nor a recovery code of the account. The code used is stored so it
+// can't be used again.
+func Check(ctx context.Context, ds *datastore.Datastore, acc *account.Account, code string) error {
+	if !acc.CheckTwoFactorCode(code, time.Now()) && !acc.UseRecoveryCode(code) {
+		return account.ErrTwoFactorCode
+	}
+
+	return ds.UpdateAccountData(ctx, acc)
+}
@@ -0,0 +1,113 @@
+// Package twofactorpolicy implements the use case letting an administrator
+// choose the accounts that must use the two-factor authentication.
+package twofactorpolicy
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful policy change.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountTwoFactorPolicySuccess",
+	Other: "Two-factor policy updated",
+}
+
+// UseCase for the two-factor policy change.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the two-factor policy change.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new two-factor policy use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountTwoFactorPolicy",
+			Params: map[string]interface{}{
+				"token":   "",
+				"require": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new two-factor policy use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the admin scope, the API keys need it to change the
+// two-factor policy.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeAdmin
+}
+
+// Run sets the accounts that must use the two-factor authentication, optional
+// for everyone or required to the admins. Only the administrators can change
+// the policy.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	admin, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !admin.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	/* The administrator enrolls first, so the policy doesn't lock it out. */
+	require := uc.Params["require"].(string)
+	if require == datastore.TwoFactorAdmins && !admin.HasTwoFactor() {
+		return res, account.ErrTwoFactorRequired
+	}
+
+	policy, err := uc.Datastore.Policy(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	policy.TwoFactor = require
+	err = uc.Datastore.SetPolicy(ctx, policy)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["require"] = policy.TwoFactor
+
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package twofactorpolicy
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountTwoFactorPolicy")
+}
+
+func TestTwoFactorPolicy(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token, "require": "admins"})
+	id := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "palvarez@ritho.net", "admin")
+	helper.LoginUser(t, uc.Datastore, token, "admin")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Administration privileges required")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Two-factor authentication is required, enroll it first")
+
+	helper.EnableTwoFactor(t, uc.Datastore, "admin")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, "Two-factor policy updated")
+	helper.Contains(t, plainResult, `"require":"admins"`)
+
+	policy, _ := uc.Datastore.Policy(ctx)
+	if policy.TwoFactor != datastore.TwoFactorAdmins {
+		t.Errorf("Expected the two-factor required to the admins, Got %+v", policy)
+	}
+
+	helper.AddParam(t, uc, "require", "everyone")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The two-factor policy must be optional or admins")
+}
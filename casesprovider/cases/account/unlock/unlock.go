@@ -0,0 +1,109 @@
+// Package unlock implements the use case letting an administrator unlock an
+// account locked out after too many failed logins.
+package unlock
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/lockout"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful unlock.
+var msgSuccess = &goi18n.Message{
+	ID:    "AccountUnlockSuccess",
+	Other: "Account unlocked successfully",
+}
+
+// UseCase for the account unlock.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the account unlock.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new unlock use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountUnlock",
+			Params: map[string]interface{}{
+				"token":    "",
+				"username": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new unlock use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the admin scope, the API keys need it to unlock the
+// accounts.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeAdmin
+}
+
+// AuditTarget returns the account unlocked.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	acc, _ := uc.Datastore.GetAccountByUsername(ctx, uc.Params["username"].(string))
+	return acc
+}
+
+// Run forgets the failed logins of an account, only the administrators can
+// unlock the accounts.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	admin, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !admin.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, uc.Params["username"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["locked"] = lockout.Locked(acc.Username())
+	lockout.Reset(acc.Username())
+
+	res.Res["result"] = msgSuccess
+	res.Res["username"] = acc.Username()
+
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package unlock
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/cases/account/lockout"
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountUnlock")
+}
+
+func TestUnlock(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token, "username": "ritho"})
+	id := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "admin@ritho.net", "admin")
+	helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	helper.LoginUser(t, uc.Datastore, token, "admin")
+
+	for i := 0; i < 20; i++ {
+		lockout.Fail(ctx, "ritho")
+	}
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Administration privileges required")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, "Account unlocked successfully")
+	helper.Contains(t, plainResult, `"locked":true`)
+	if lockout.Wait(ctx, "ritho") != 0 {
+		t.Error("Expected the account to be unlocked")
+	}
+
+	helper.AddParam(t, uc, "username", "unknown")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "unknown: Account doesn't exists")
+}
@@ -0,0 +1,101 @@
+// Package verification implements the links sent to verify the email of the
+// accounts and activate them.
+package verification
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/mailer"
+	"github.com/radar-go/radar/token"
+)
+
+// purpose of the verification codes, so they can't be used as other tokens.
+const purpose = "email-verification"
+
+// msgSubject is the subject of the verification email.
+var msgSubject = &goi18n.Message{
+	ID:    "VerificationSubject",
+	Other: "Activate your Radar account",
+}
+
+// msgBody is the body of the verification email.
+var msgBody = &goi18n.Message{
+	ID:    "VerificationBody",
+	Other: "Hi {{.Name}},\n\nFollow this link to verify your email and activate your Radar account:\n\n{{.Link}}\n\nThe link expires in {{.Hours}} hours.",
+}
+
+// Code returns a new verification code for the account, bound to its id and
+// email so it stops being valid if the email changes.
+func Code(acc *account.Account) string {
+	return casesprovider.Signer().Sign(purpose, subject(acc),
+		casesprovider.Config().VerificationTTL)
+}
+
+// subject returns the subject of the verification codes of the account.
+func subject(acc *account.Account) string {
+	return fmt.Sprintf("%s:%s", acc.UUID(), acc.Email())
+}
+
+// Link returns the link of the web interface to verify the account.
+func Link(acc *account.Account) string {
+	return strings.TrimRight(casesprovider.Config().PublicURL, "/") +
+		"/account/verify?code=" + url.QueryEscape(Code(acc))
+}
+
+// Send sends the verification link to the email of the account, in the
+// language of the request.
+func Send(ctx context.Context, acc *account.Account) error {
+	loc := i18n.ContextLocalizer(ctx)
+	return casesprovider.Mailer().Send(ctx, &mailer.Message{
+		To:      acc.Email(),
+		Subject: loc.Message(msgSubject),
+		Body: loc.Message(msgBody, map[string]interface{}{
+			"Name":  acc.Name(),
+			"Link":  Link(acc),
+			"Hours": int(casesprovider.Config().VerificationTTL.Hours()),
+		}),
+	})
+}
+
+// Account returns the account of the verification code, or an error if the
+// code is not valid, has expired or the account email has changed.
+func Account(ctx context.Context, ds *datastore.Datastore, code string) (*account.Account, error) {
+	sub, err := casesprovider.Signer().Verify(purpose, code)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := ds.GetAccountByUUID(ctx, strings.SplitN(sub, ":", 2)[0])
+	if err != nil || subject(acc) != sub {
+		return nil, token.ErrInvalid
+	}
+
+	return acc, nil
+}
@@ -0,0 +1,105 @@
+// Package verifylogin implements the second step of the logins with the
+// two-factor authentication.
+package verifylogin
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/lockout"
+	"github.com/radar-go/radar/casesprovider/cases/account/login"
+	"github.com/radar-go/radar/casesprovider/cases/account/twofactor"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// UseCase for the login verification.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the login verification.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new login verification use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AccountLoginVerify",
+			Params: map[string]interface{}{
+				"challenge": "",
+				"code":      "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new login verification use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// AuditTarget returns the account of the login challenge.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	acc, _ := twofactor.Account(ctx, uc.Datastore, uc.Params["challenge"].(string))
+	return acc
+}
+
+// Run checks the code of the authenticator app, or a recovery code, of the
+// account of the login challenge and opens its session.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := twofactor.Account(ctx, uc.Datastore, uc.Params["challenge"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	if lockout.Wait(ctx, acc.Username()) > 0 {
+		return res, errors.ErrTooManyRequests
+	}
+
+	err = twofactor.Check(ctx, uc.Datastore, acc, uc.Params["code"].(string))
+	if errWrap.Cause(err) == account.ErrTwoFactorCode {
+		lockout.Fail(ctx, acc.Username())
+		return res, err
+	} else if err != nil {
+		return res, err
+	}
+
+	lockout.Reset(acc.Username())
+	err = login.Open(ctx, uc.Datastore, acc, res)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["recovery_codes_left"] = acc.RecoveryCodesLeft()
+
+	return res, nil
+}
@@ -0,0 +1,109 @@
+package verifylogin
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/radar-go/radar/casesprovider/cases/account/lockout"
+	"github.com/radar-go/radar/casesprovider/cases/account/twofactor"
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/totp"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AccountLoginVerify")
+}
+
+func TestLoginVerify(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"challenge": "wrong", "code": "000000"})
+	id := helper.RegisterUser(t, uc.Datastore, "verify", "ritho", "palvarez@ritho.net", "12345")
+	secret := helper.EnableTwoFactor(t, uc.Datastore, "verify", "abcd-efgh")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The login has expired, log in again")
+
+	acc, _ := uc.Datastore.GetAccountByID(ctx, id)
+	helper.AddParam(t, uc, "challenge", twofactor.Challenge(acc))
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong two-factor authentication code")
+
+	code, _ := totp.Code(secret, time.Now())
+	helper.AddParam(t, uc, "code", code)
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"token":`)
+	helper.Contains(t, plainResult, `"recovery_codes_left":1`)
+
+	/* The codes can't be used twice, the recovery codes neither. */
+	uc.Datastore.DeleteSessions(ctx, id)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong two-factor authentication code")
+
+	helper.AddParam(t, uc, "code", "ABCD-EFGH")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"recovery_codes_left":0`)
+
+	uc.Datastore.DeleteSessions(ctx, id)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong two-factor authentication code")
+
+	/* A new password ends the pending logins. */
+	acc.SetPassword("54321")
+	helper.UnexpectedError(t, uc.Datastore.UpdateAccountData(ctx, acc))
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The login has expired, log in again")
+}
+
+func TestLoginVerifyLockout(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"code": "000000"})
+	id := helper.RegisterUser(t, uc.Datastore, "guessme", "ritho", "palvarez@ritho.net", "12345")
+	secret := helper.EnableTwoFactor(t, uc.Datastore, "guessme")
+	acc, _ := uc.Datastore.GetAccountByID(ctx, id)
+	helper.AddParam(t, uc, "challenge", twofactor.Challenge(acc))
+	defer lockout.Reset("guessme")
+
+	for i := 0; i < 3; i++ {
+		_, err := uc.Run(ctx)
+		helper.Contains(t, fmt.Sprint(err), "Wrong two-factor authentication code")
+	}
+
+	code, _ := totp.Code(secret, time.Now())
+	helper.AddParam(t, uc, "code", code)
+	_, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	uc.Datastore.DeleteSessions(ctx, id)
+	helper.AddParam(t, uc, "code", "000000")
+	for i := 0; i < 4; i++ {
+		uc.Run(ctx)
+	}
+
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Too many requests, try again later")
+}
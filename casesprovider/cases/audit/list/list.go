@@ -0,0 +1,156 @@
+// Package list implements the audit log query use case.
+package list
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"time"
+
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+)
+
+// DefaultPerPage is the number of records of every page when it's not given.
+const DefaultPerPage = 50
+
+// MaxPerPage is the maximum number of records of every page.
+const MaxPerPage = 500
+
+// UseCase for the audit log query.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the audit log query.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new audit list use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "AuditList",
+			Params: map[string]interface{}{
+				"token":    "",
+				"actor":    "",
+				"target":   "",
+				"action":   "",
+				"since":    "",
+				"until":    "",
+				"page":     0,
+				"per_page": 0,
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new audit list use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the read scope, the API keys need it to query the
+// audit log.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeRead
+}
+
+// Run obtains a page of the records of the audit log matching the filters,
+// newest first, only the administrators can query the audit log.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !acc.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	filter := audit.Filter{
+		Actor:  uc.Params["actor"].(string),
+		Target: uc.Params["target"].(string),
+		Action: uc.Params["action"].(string),
+	}
+
+	filter.Since, err = uc.time("since")
+	if err != nil {
+		return res, err
+	}
+
+	filter.Until, err = uc.time("until")
+	if err != nil {
+		return res, err
+	}
+
+	page, perPage, err := uc.Page(DefaultPerPage, MaxPerPage)
+	if err != nil {
+		return res, err
+	}
+
+	records, err := uc.Datastore.AuditRecords(ctx, filter)
+	if err != nil {
+		return res, err
+	}
+
+	total := len(records)
+	if offset := (page - 1) * perPage; offset < total {
+		records = records[offset:]
+	} else {
+		records = records[:0]
+	}
+
+	if perPage < len(records) {
+		records = records[:perPage]
+	}
+
+	res.Res["records"] = records
+	res.Res["total"] = total
+	res.Res["page"] = page
+	res.Res["per_page"] = perPage
+
+	return res, nil
+}
+
+// time returns the value of the param in RFC 3339 format, or the zero time if
+// the param is not present.
+func (uc *UseCase) time(param string) (time.Time, error) {
+	value := uc.Params[param].(string)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, errWrap.Wrap(errors.ErrParamType, param)
+	}
+
+	return t, nil
+}
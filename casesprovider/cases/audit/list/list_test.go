@@ -0,0 +1,96 @@
+package list
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore/audit"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "AuditList")
+}
+
+func TestAuditList(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "User not logged in")
+
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	helper.LoginUser(t, uc.Datastore, token, "ritho")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Administration privileges required")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	for _, action := range []string{"AccountLogin", "AccountEdit"} {
+		err = uc.Datastore.AddAuditRecord(ctx, audit.Record{Action: action, Target: "ritho"})
+		helper.UnexpectedError(t, err)
+	}
+
+	helper.AddParam(t, uc, "action", "AccountEdit")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"action":"AccountEdit"`)
+	helper.Contains(t, plainResult, `"id":2`)
+	if strings.Contains(plainResult, "AccountLogin") {
+		t.Errorf("Expected only the AccountEdit records, Got %s", plainResult)
+	}
+
+	/* The records are paginated newest first. */
+	uc.Params["action"] = ""
+	helper.AddParams(t, uc, map[string]interface{}{"page": 2, "per_page": 1})
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult = helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"action":"AccountLogin"`)
+	helper.Contains(t, plainResult, `"page":2,"per_page":1`)
+	helper.Contains(t, plainResult, `"total":2`)
+	if strings.Contains(plainResult, "AccountEdit") {
+		t.Errorf("Expected only the second record, Got %s", plainResult)
+	}
+
+	helper.AddParam(t, uc, "page", 3)
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"records":[]`)
+
+	helper.AddParams(t, uc, map[string]interface{}{"page": math.MaxInt, "per_page": 10})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "page: Param is not from the right type")
+
+	helper.AddParams(t, uc, map[string]interface{}{"page": 1, "per_page": MaxPerPage + 1})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "per_page: Param is not from the right type")
+
+	uc.Params["per_page"] = 0
+	helper.AddParam(t, uc, "since", "yesterday")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "since: Param is not from the right type")
+}
@@ -0,0 +1,113 @@
+// Package create implements the use case creating a backup archive of the
+// datastore.
+package create
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"io"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// msgSuccess is the result of a successful backup.
+var msgSuccess = &goi18n.Message{
+	ID:    "BackupCreateSuccess",
+	Other: "Backup archive created",
+}
+
+// UseCase for the backup creation.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the backup creation with the archive, that is
+// streamed to the client instead of the result.
+type Result struct {
+	*usecase.Result
+	datastore *datastore.Datastore
+	sessions  bool
+}
+
+// WriteArchive writes the backup archive to w, once the use case checked the
+// account can back up the datastore. It's not bound to the context of the run,
+// as the archive is streamed after it.
+func (res *Result) WriteArchive(w io.Writer) error {
+	return res.datastore.Backup(context.Background(), w, res.sessions)
+}
+
+// New creates and returns a new backup use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "BackupCreate",
+			Params: map[string]interface{}{
+				"token":    "",
+				"sessions": false,
+			},
+			Timeout: time.Minute,
+			Audit:   true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new backup use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the admin scope, the API keys need it to back up the
+// datastore.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeAdmin
+}
+
+// Run checks the backup archive of the whole datastore can be created, with
+// the sessions only if asked, and returns it to be written. Only the
+// administrators can back up the datastore.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := &Result{
+		Result:    usecase.NewResult(),
+		datastore: uc.Datastore,
+		sessions:  uc.Params["sessions"].(bool),
+	}
+
+	admin, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !admin.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["version"] = datastore.SchemaVersion
+
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package create
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "BackupCreate")
+}
+
+func TestCreate(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"token": token})
+	id := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "admin@ritho.net", "admin")
+	helper.LoginUser(t, uc.Datastore, token, "admin")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Administration privileges required")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), "Backup archive created")
+
+	archive := &bytes.Buffer{}
+	helper.UnexpectedError(t, res.(*Result).WriteArchive(archive))
+	gz, err := gzip.NewReader(archive)
+	helper.UnexpectedError(t, err)
+	data, err := ioutil.ReadAll(gz)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, string(data), fmt.Sprintf(`"version":%d`, datastore.SchemaVersion))
+	if strings.Contains(string(data), token) {
+		t.Error("Expected the archive without the sessions")
+	}
+
+	helper.AddParam(t, uc, "sessions", true)
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	archive.Reset()
+	helper.UnexpectedError(t, res.(*Result).WriteArchive(archive))
+	gz, err = gzip.NewReader(archive)
+	helper.UnexpectedError(t, err)
+	data, err = ioutil.ReadAll(gz)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, string(data), token)
+}
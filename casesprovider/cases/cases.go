@@ -21,6 +21,13 @@ package cases
 
 import (
 	_ "github.com/radar-go/radar/casesprovider/cases/account"
+	_ "github.com/radar-go/radar/casesprovider/cases/audit"
+	_ "github.com/radar-go/radar/casesprovider/cases/backup"
+	_ "github.com/radar-go/radar/casesprovider/cases/edition"
+	_ "github.com/radar-go/radar/casesprovider/cases/member"
+	_ "github.com/radar-go/radar/casesprovider/cases/radar"
+	_ "github.com/radar-go/radar/casesprovider/cases/scim"
+	_ "github.com/radar-go/radar/casesprovider/cases/technology"
 )
 
 func init() {
@@ -0,0 +1,113 @@
+// Package exportbyor implements the use case exporting a radar edition in the
+// format of the ThoughtWorks Build Your Own Radar.
+package exportbyor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/byor"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore"
+)
+
+// UseCase for the radar edition export.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the radar edition export.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new export use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "EditionExport",
+			Params: map[string]interface{}{
+				"edition": "",
+				"format":  "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new export use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run exports the blips of a radar edition in csv, the default, or json
+// format, ready to be loaded in the Build Your Own Radar visualizer.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	name, blips, err := Export(ctx, uc.Datastore, uc.Params["edition"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	format := radar.CleanString(uc.Params["format"].(string))
+	if format == "" {
+		format = byor.CSV
+	}
+
+	data := &bytes.Buffer{}
+	err = byor.Write(data, format, blips)
+	if err != nil {
+		return res, err
+	}
+
+	res.Res["edition"] = name
+	res.Res["format"] = format
+	res.Res["data"] = data.String()
+
+	return res, nil
+}
+
+// Export returns the name of a radar edition of the datastore with its blips,
+// ready to be written as a Build Your Own Radar file.
+func Export(ctx context.Context, ds *datastore.Datastore, edition string) (string, []byor.Blip, error) {
+	e, err := ds.Edition(ctx, edition)
+	if err != nil {
+		return "", nil, err
+	}
+
+	blips := make([]byor.Blip, 0, len(e.Blips))
+	for _, blip := range e.Blips {
+		tech, _ := ds.Technology(ctx, blip.Technology)
+		blips = append(blips, byor.Blip{
+			Name:        blip.Technology,
+			Ring:        blip.Ring,
+			Quadrant:    tech.Type(),
+			IsNew:       blip.New,
+			Description: tech.Description(),
+		})
+	}
+
+	return e.Name, blips, nil
+}
@@ -0,0 +1,60 @@
+package exportbyor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "EditionExport")
+}
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{"edition": "2018"})
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "2018: Radar edition doesn't exists")
+
+	_, _, err = uc.Datastore.UpdateEdition(ctx, "2018",
+		[]datastore.Technology{datastore.NewTechnology("Go", "Languages & Frameworks", "A language")},
+		[]datastore.Blip{{Technology: "Go", Ring: "Adopt", New: true}})
+	helper.UnexpectedError(t, err)
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res),
+		`"data":"name,ring,quadrant,isNew,description\nGo,adopt,languages \u0026 frameworks,TRUE,A language\n"`)
+
+	helper.AddParam(t, uc, "format", "JSON")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"format":"json"`)
+
+	helper.AddParam(t, uc, "format", "xml")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "xml: The format must be csv or json")
+}
@@ -0,0 +1,153 @@
+// Package importbyor implements the use case importing a radar edition in the
+// format of the ThoughtWorks Build Your Own Radar.
+package importbyor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/byor"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+)
+
+// msgSuccess is the result of a successful import.
+var msgSuccess = &goi18n.Message{
+	ID:    "EditionImportSuccess",
+	Other: "Radar edition imported",
+}
+
+// RowError represents a row of the radar that couldn't be imported.
+type RowError struct {
+	Row   int    `json:"row"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// UseCase for the radar edition import.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the radar edition import.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new import use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "EditionImport",
+			Params: map[string]interface{}{
+				"token":   "",
+				"edition": "",
+				"format":  "",
+				"data":    "",
+			},
+			/* The radars can have hundreds of blips. */
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new import use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// APIKeyScope returns the admin scope, the API keys need it to import the
+// radar editions.
+func (uc *UseCase) APIKeyScope() string {
+	return account.ScopeAdmin
+}
+
+// Run creates or updates the technologies and the blips of a radar edition
+// from the data in csv, the default, or json format. The valid rows are
+// imported even if others fail, and the errors are reported by row. Only the
+// administrators can import the radar editions.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	admin, err := uc.SessionAccount(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	if !admin.IsAdmin() {
+		return res, account.ErrNotAdmin
+	}
+
+	blips, rowErrors, err := byor.Read(strings.NewReader(uc.Params["data"].(string)),
+		uc.Params["format"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	created, updated, err := Import(ctx, uc.Datastore, uc.Params["edition"].(string), blips)
+	if err != nil {
+		return res, err
+	}
+
+	loc := i18n.ContextLocalizer(ctx)
+	errs := make([]RowError, 0, len(rowErrors))
+	for _, rowErr := range rowErrors {
+		errs = append(errs, RowError{
+			Row:   rowErr.Row,
+			Name:  rowErr.Name,
+			Error: loc.Error(rowErr.Err),
+		})
+	}
+
+	res.Res["result"] = msgSuccess
+	res.Res["edition"] = strings.TrimSpace(uc.Params["edition"].(string))
+	res.Res["created"] = created
+	res.Res["updated"] = updated
+	res.Res["errors"] = errs
+
+	return res, nil
+}
+
+// Import creates or updates the technologies and the blips of a radar edition
+// in the datastore from the blips of a Build Your Own Radar file. It returns
+// the number of blips created and updated.
+func Import(ctx context.Context, ds *datastore.Datastore, edition string, blips []byor.Blip) (int, int, error) {
+	techs := make([]datastore.Technology, 0, len(blips))
+	editionBlips := make([]datastore.Blip, 0, len(blips))
+	for _, blip := range blips {
+		techs = append(techs, datastore.NewTechnology(blip.Name, blip.Quadrant, blip.Description))
+		editionBlips = append(editionBlips, datastore.Blip{
+			Technology: blip.Name,
+			Ring:       blip.Ring,
+			New:        blip.IsNew,
+		})
+	}
+
+	return ds.UpdateEdition(ctx, edition, techs, editionBlips)
+}
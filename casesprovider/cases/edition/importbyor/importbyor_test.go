@@ -0,0 +1,76 @@
+package importbyor
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "EditionImport")
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{
+		"token":   token,
+		"edition": "2018",
+		"data": "name,ring,quadrant,isNew,description\n" +
+			"Go,adopt,languages & frameworks,TRUE,A language\n" +
+			"Cobol,retire,languages,FALSE,\n",
+	})
+	id := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "admin@ritho.net", "admin")
+	helper.LoginUser(t, uc.Datastore, token, "admin")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Administration privileges required")
+
+	uc.Datastore.SetAdmin(ctx, id, true)
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"created":1`)
+	helper.Contains(t, plainResult, `"errors":[{"row":2,"name":"Cobol","error":"retire: The ring must be adopt, trial, assess or hold"}]`)
+	helper.Contains(t, plainResult, "Radar edition imported")
+
+	edition, err := uc.Datastore.Edition(ctx, "2018")
+	if err != nil || len(edition.Blips) != 1 || edition.Blips[0].Ring != "Adopt" || !edition.Blips[0].New {
+		t.Errorf("Expected the Go blip imported, Got %+v: %v", edition, err)
+	}
+
+	helper.AddParams(t, uc, map[string]interface{}{
+		"format": "json",
+		"data":   `[{"name": "go", "ring": "trial", "quadrant": "languages", "isNew": false}]`,
+	})
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"updated":1`)
+
+	helper.AddParam(t, uc, "data", "{")
+	_, err = uc.Run(ctx)
+	if err == nil {
+		t.Error("Expected error importing an invalid radar")
+	}
+}
@@ -0,0 +1,95 @@
+// Package get implements the member profile retrieval use case.
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+)
+
+// Technology represents a technology known by the member.
+type Technology struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Level int    `json:"level"`
+}
+
+// UseCase for the member profile retrieval.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the member profile retrieval.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new member get use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "MemberGet",
+			Params: map[string]interface{}{
+				"username": "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new member get use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run obtains the public profile of a member.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, uc.Params["username"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	role := ""
+	if acc.CurrentRole() != nil {
+		role = acc.CurrentRole().Title()
+	}
+
+	technologies := make([]Technology, 0, len(acc.Technologies()))
+	for _, tech := range acc.Technologies() {
+		technologies = append(technologies, Technology{
+			Name:  tech.Name(),
+			Type:  tech.Type(),
+			Level: tech.Level(),
+		})
+	}
+
+	res.Res["id"] = acc.UUID()
+	res.Res["username"] = acc.Username()
+	res.Res["name"] = acc.Name()
+	res.Res["role"] = role
+	res.Res["technologies"] = technologies
+
+	return res, nil
+}
@@ -0,0 +1,64 @@
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+	role "github.com/radar-go/radar/entities/role/api"
+	technology "github.com/radar-go/radar/entities/technology/api"
+)
+
+func TestMemberGet(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.TestCaseName(t, uc, "MemberGet")
+	uc.SetDatastore(datastore.New())
+
+	helper.AddParam(t, uc, "username", "ritho")
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprintf("%s", err), "ritho: Account doesn't exists")
+
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "Pablo", "palvarez@ritho.net", "ritho")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"role":"","technologies":[]`)
+
+	acc, err := uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	developer, err := role.New("Developer", time.Now(), time.Time{})
+	helper.UnexpectedError(t, err)
+	acc.AddRole(developer)
+	acc.AddTechnology(technology.New("Go", "languages", 4))
+	helper.UnexpectedError(t, uc.Datastore.UpdateAccountData(ctx, acc))
+
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	expected := fmt.Sprintf(`{"id":%q,"name":"Pablo","role":"Developer",`+
+		`"technologies":[{"name":"Go","type":"languages","level":4}],"username":"ritho"}`, acc.UUID())
+	result := helper.GetResultString(t, res)
+	if result != expected {
+		t.Errorf("Expected %s, Got %s", expected, result)
+	}
+}
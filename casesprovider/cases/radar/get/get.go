@@ -0,0 +1,139 @@
+// Package get implements the radar retrieval use case.
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/render"
+)
+
+// ErrUnknownFlavor raised when the radar flavor doesn't exists.
+var ErrUnknownFlavor = i18n.NewError(&goi18n.Message{
+	ID:    "RadarUnknownFlavor",
+	Other: "Unknown radar flavor",
+})
+
+// UseCase for the radar retrieval.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the radar retrieval.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new radar get use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "RadarGet",
+			Params: map[string]interface{}{
+				"flavor": "",
+			},
+			/* It goes through all the accounts, so it takes longer than the
+			use cases working with a single one. */
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new radar get use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run obtains the blips of a radar flavor.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	flavor := radar.CleanString(uc.Params["flavor"].(string))
+	if !render.IsFlavor(flavor) {
+		return res, errors.Wrap(ErrUnknownFlavor, flavor)
+	}
+
+	/* The datastore doesn't store projects nor resources yet, so only the
+	people radar have blips. */
+	blips := make([]render.Blip, 0)
+	if flavor == "people" {
+		accounts, err := uc.Datastore.Accounts(ctx)
+		if err != nil {
+			return res, err
+		}
+
+		blips = peopleBlips(accounts)
+	}
+
+	res.Res["flavor"] = flavor
+	res.Res["blips"] = blips
+
+	return res, nil
+}
+
+// peopleBlips returns a blip for every technology known by the members, placed
+// in the ring of the highest level of expertise.
+func peopleBlips(accounts []*account.Account) []render.Blip {
+	levels := make(map[string]int)
+	blips := make(map[string]render.Blip)
+	for _, acc := range accounts {
+		for _, tech := range acc.Technologies() {
+			quadrant, ok := render.Quadrant(tech.Type())
+			if !ok {
+				continue
+			}
+
+			key := radar.CleanString(tech.Name())
+			if level, ok := levels[key]; ok && level >= tech.Level() {
+				continue
+			}
+
+			levels[key] = tech.Level()
+			blips[key] = render.Blip{
+				Name:     tech.Name(),
+				Quadrant: quadrant,
+				Ring:     render.Ring(tech.Level()),
+			}
+		}
+	}
+
+	list := make([]render.Blip, 0, len(blips))
+	for _, blip := range blips {
+		list = append(list, blip)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
@@ -0,0 +1,78 @@
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+	technology "github.com/radar-go/radar/entities/technology/api"
+)
+
+func TestRadarGet(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.TestCaseName(t, uc, "RadarGet")
+	uc.SetDatastore(datastore.New())
+
+	_, err := uc.Run(ctx)
+	if errors.Cause(err) != ErrUnknownFlavor {
+		t.Errorf("Expected %s, Got %v", ErrUnknownFlavor, err)
+	}
+
+	for _, user := range []string{"ritho", "senoritho"} {
+		id := helper.RegisterUser(t, uc.Datastore, user, user, user+"@ritho.net", "ritho")
+		acc, err := uc.Datastore.GetAccountByID(ctx, id)
+		helper.UnexpectedError(t, err)
+		acc.AddTechnology(technology.New("Go", "languages", len(user)-3))
+		acc.AddTechnology(technology.New("Unknown", "unknown", 4))
+		helper.UnexpectedError(t, uc.Datastore.UpdateAccountData(ctx, acc))
+	}
+
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, "ritho")
+	helper.UnexpectedError(t, err)
+	acc.AddTechnology(technology.New("Docker", "platform", 1))
+	helper.UnexpectedError(t, uc.Datastore.UpdateAccountData(ctx, acc))
+
+	helper.AddParam(t, uc, "flavor", "People")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	result := helper.GetResultString(t, res)
+	expected := `{"blips":[` +
+		`{"name":"Docker","quadrant":"Platforms","ring":"Hold"},` +
+		`{"name":"Go","quadrant":"Languages \u0026 Frameworks","ring":"Adopt"}` +
+		`],"flavor":"people"}`
+	if result != expected {
+		t.Errorf("Expected %s, Got %s", expected, result)
+	}
+
+	helper.AddParam(t, uc, "flavor", "projects")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `{"blips":[],"flavor":"projects"}`)
+
+	helper.AddParam(t, uc, "flavor", "unknown")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprintf("%s", err), "unknown: Unknown radar flavor")
+}
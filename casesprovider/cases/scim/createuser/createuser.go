@@ -0,0 +1,84 @@
+package createuser
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/scim/provisioning"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/scim"
+)
+
+// UseCase for the creation of the accounts through SCIM.
+type UseCase struct {
+	usecase.UseCase
+	account *account.Account
+}
+
+// New creates and returns a new SCIM user creation use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		UseCase: usecase.UseCase{
+			Name: "SCIMUserCreate",
+			Params: map[string]interface{}{
+				"bearer":   "",
+				"resource": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new SCIM user creation use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// AuditTarget returns the account created.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	return uc.account
+}
+
+// Run registers the account of the user resource.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := &provisioning.Result{}
+	err := provisioning.Authorize(uc.Params["bearer"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	user, err := scim.ParseUser([]byte(uc.Params["resource"].(string)))
+	if err != nil {
+		return res, err
+	}
+
+	uc.account, err = provisioning.Create(ctx, uc.Datastore, user)
+	if err != nil {
+		return res, err
+	}
+
+	res.Resource = provisioning.User(uc.account)
+
+	return res, nil
+}
@@ -0,0 +1,80 @@
+package createuser
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMUserCreate")
+}
+
+func TestCreateUser(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.SetupUseCase(t, uc, map[string]interface{}{
+		"bearer": "scim-secret",
+		"resource": `{"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+			"userName": "Ritho", "name": {"givenName": "Pablo", "familyName": "Álvarez"},
+			"title": "Developer", "emails": [{"value": "palvarez@ritho.net", "type": "work"}]}`,
+	})
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The SCIM provisioning is not configured")
+
+	helper.SCIMToken(t)
+	helper.AddParam(t, uc, "bearer", "wrong")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Wrong SCIM bearer token")
+
+	helper.AddParam(t, uc, "bearer", "scim-secret")
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"userName":"ritho"`)
+	helper.Contains(t, plainResult, `"displayName":"Pablo Álvarez"`)
+	helper.Contains(t, plainResult, `"active":true`)
+
+	acc, err := uc.Datastore.GetAccountByUsername(ctx, "ritho")
+	helper.UnexpectedError(t, err)
+	if !acc.IsActive() || acc.Email() != "palvarez@ritho.net" || acc.Title() != "Developer" {
+		t.Errorf("Unexpected account %s %t %s", acc.Email(), acc.IsActive(), acc.Title())
+	}
+
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Account already exists")
+
+	helper.AddParam(t, uc, "resource", `{"userName": "jdoe-", "active": false, "emails": [{"value": "jdoe@ritho.net"}]}`)
+	_, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	acc, err = uc.Datastore.GetAccountByUsername(ctx, "jdoe-")
+	helper.UnexpectedError(t, err)
+	if acc.IsActive() || acc.Name() != "jdoe-" {
+		t.Errorf("Expected an inactive account named after its username, Got %s %t", acc.Name(), acc.IsActive())
+	}
+
+	helper.AddParam(t, uc, "resource", `{"displayName": "ritho"}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The value of the attribute is not valid")
+}
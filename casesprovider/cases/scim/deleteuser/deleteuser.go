@@ -0,0 +1,76 @@
+package deleteuser
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/scim/provisioning"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// UseCase for removing the accounts through SCIM.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// New creates and returns a new SCIM user delete use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "SCIMUserDelete",
+			Params: map[string]interface{}{
+				"bearer": "",
+				"id":     "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new SCIM user delete use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// AuditTarget returns the account removed.
+func (uc *UseCase) AuditTarget(ctx context.Context) *account.Account {
+	acc, _ := provisioning.Account(ctx, uc.Datastore, uc.Params["id"].(string))
+	return acc
+}
+
+// Run removes the account and its sessions.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := &provisioning.Result{}
+	err := provisioning.Authorize(uc.Params["bearer"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	acc, err := provisioning.Account(ctx, uc.Datastore, uc.Params["id"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	return res, provisioning.Remove(ctx, uc.Datastore, acc)
+}
@@ -0,0 +1,50 @@
+package deleteuser
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMUserDelete")
+}
+
+func TestDeleteUser(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	helper.LoginUser(t, uc.Datastore, "00000000-0000-0000-0000-000000000000", "ritho")
+	helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
+
+	_, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	if _, err = uc.Datastore.GetAccountByID(ctx, id); err == nil || uc.Datastore.SessionsCount() != 0 {
+		t.Error("Expected the account and its session removed")
+	}
+
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Account doesn't exists")
+}
@@ -0,0 +1,59 @@
+package getgroup
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMGroupGet")
+}
+
+func TestGetGroup(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token, "id": "admin"})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	helper.RegisterUser(t, uc.Datastore, "jdoe-", "jdoe", "jdoe@ritho.net", "12345")
+	uc.Datastore.SetAdmin(ctx, id, true)
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	expected := fmt.Sprintf(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:Group"],"id":"admin",`+
+		`"displayName":"admin","members":[{"value":%q,"display":"ritho"}],`+
+		`"meta":{"resourceType":"Group"}}`, helper.AccountUUID(t, uc.Datastore, id))
+	if plainResult := helper.GetResultString(t, res); plainResult != expected {
+		t.Errorf("Expected %s, Got %s", expected, plainResult)
+	}
+
+	helper.AddParam(t, uc, "id", "member")
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"display":"jdoe-"`)
+
+	helper.AddParam(t, uc, "id", "owners")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "owners: Group doesn't exists")
+}
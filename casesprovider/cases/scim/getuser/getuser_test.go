@@ -0,0 +1,55 @@
+package getuser
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMUserGet")
+}
+
+func TestGetUser(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token, "id": "unknown"})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "Pablo", "palvarez@ritho.net", "12345")
+	uc.Datastore.SetAdmin(ctx, id, true)
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "unknown: Account doesn't exists")
+
+	helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	expected := fmt.Sprintf(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"id":%q,`+
+		`"userName":"ritho","name":{"formatted":"Pablo"},"displayName":"Pablo",`+
+		`"emails":[{"value":"palvarez@ritho.net","type":"work","primary":true}],"active":false,`+
+		`"groups":[{"value":"member","display":"member"},{"value":"admin","display":"admin"}],`+
+		`"meta":{"resourceType":"User"}}`, helper.AccountUUID(t, uc.Datastore, id))
+	if plainResult := helper.GetResultString(t, res); plainResult != expected {
+		t.Errorf("Expected %s, Got %s", expected, plainResult)
+	}
+}
@@ -0,0 +1,108 @@
+package listgroups
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/scim/provisioning"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/scim"
+)
+
+// UseCase for searching the groups through SCIM.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// New creates and returns a new SCIM group list use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "SCIMGroupList",
+			Params: map[string]interface{}{
+				"bearer":      "",
+				"filter":      "",
+				"start_index": "",
+				"count":       "",
+			},
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new SCIM group list use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run returns the page of the group resources matching the filter, all of
+// them if there is no filter.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := &provisioning.Result{}
+	err := provisioning.Authorize(uc.Params["bearer"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	startIndex, err := provisioning.Number(uc.Params["start_index"].(string), 1)
+	if err != nil {
+		return res, err
+	}
+
+	count, err := provisioning.Number(uc.Params["count"].(string), scim.MaxResults)
+	if err != nil {
+		return res, err
+	}
+
+	var filter scim.Filter
+	if uc.Params["filter"].(string) != "" {
+		filter, err = scim.ParseFilter(uc.Params["filter"].(string))
+		if err != nil {
+			return res, err
+		}
+	}
+
+	groups, err := provisioning.Groups(ctx, uc.Datastore)
+	if err != nil {
+		return res, err
+	}
+
+	resources := []interface{}{}
+	for _, group := range groups {
+		if filter != nil {
+			resource, err := scim.ToMap(group)
+			if err != nil {
+				return res, err
+			}
+
+			if !filter.Match(resource) {
+				continue
+			}
+		}
+
+		resources = append(resources, group)
+	}
+
+	res.Resource = scim.Page(resources, startIndex, count)
+
+	return res, nil
+}
@@ -0,0 +1,53 @@
+package listgroups
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMGroupList")
+}
+
+func TestListGroups(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token})
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"totalResults":2`)
+
+	helper.AddParam(t, uc, "filter", `displayName eq "Admin"`)
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"totalResults":1`)
+	helper.Contains(t, plainResult, `"id":"admin"`)
+
+	helper.AddParam(t, uc, "filter", `displayName eq "Admin`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The filter is not valid")
+}
@@ -0,0 +1,70 @@
+package listusers
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMUserList")
+}
+
+func TestListUsers(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token})
+	for _, username := range []string{"ritho", "jdoe-", "alice"} {
+		helper.RegisterUser(t, uc.Datastore, username, username, username+"@ritho.net", "12345")
+	}
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult := helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"totalResults":3,"startIndex":1,"itemsPerPage":3`)
+
+	helper.AddParams(t, uc, map[string]interface{}{"start_index": "2", "count": "1"})
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult = helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"totalResults":3,"startIndex":2,"itemsPerPage":1`)
+	helper.Contains(t, plainResult, `"userName":"jdoe-"`)
+
+	helper.AddParams(t, uc, map[string]interface{}{"start_index": "1", "count": "10",
+		"filter": `userName eq "RITHO" or emails[value sw "alice"]`})
+	res, err = uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	plainResult = helper.GetResultString(t, res)
+	helper.Contains(t, plainResult, `"totalResults":2`)
+	helper.Contains(t, plainResult, `"userName":"ritho"`)
+	helper.Contains(t, plainResult, `"userName":"alice"`)
+
+	helper.AddParam(t, uc, "filter", `userName eq`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The filter is not valid")
+
+	helper.AddParams(t, uc, map[string]interface{}{"filter": `active eq false`, "count": "many"})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "many: The value of the attribute is not valid")
+}
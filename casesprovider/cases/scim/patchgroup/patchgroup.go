@@ -0,0 +1,101 @@
+package patchgroup
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/scim/provisioning"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/scim"
+)
+
+// UseCase for patching the groups through SCIM.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// New creates and returns a new SCIM group patch use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "SCIMGroupPatch",
+			Params: map[string]interface{}{
+				"bearer":   "",
+				"id":       "",
+				"resource": "",
+			},
+			Audit: true,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new SCIM group patch use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run applies the patch operations to the group resource, and sets its members
+// with the result.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := &provisioning.Result{}
+	err := provisioning.Authorize(uc.Params["bearer"].(string))
+	if err != nil {
+		return res, err
+	}
+
+	id := uc.Params["id"].(string)
+	group, err := provisioning.Group(ctx, uc.Datastore, id)
+	if err != nil {
+		return res, err
+	}
+
+	ops, err := scim.ParsePatch([]byte(uc.Params["resource"].(string)))
+	if err != nil {
+		return res, err
+	}
+
+	resource, err := scim.ToMap(group)
+	if err != nil {
+		return res, err
+	}
+
+	err = scim.Apply(resource, ops)
+	if err != nil {
+		return res, err
+	}
+
+	patched := &scim.Group{}
+	err = scim.FromMap(resource, patched)
+	if err != nil {
+		return res, err
+	}
+
+	err = provisioning.SetMembers(ctx, uc.Datastore, id, patched.Members)
+	if err != nil {
+		return res, err
+	}
+
+	res.Resource, err = provisioning.Group(ctx, uc.Datastore, id)
+
+	return res, err
+}
@@ -0,0 +1,62 @@
+package patchgroup
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMGroupPatch")
+}
+
+func TestPatchGroup(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token, "id": "admin"})
+	admin := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "admin@ritho.net", "12345")
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	uc.Datastore.SetAdmin(ctx, admin, true)
+
+	helper.AddParam(t, uc, "resource", fmt.Sprintf(`{"Operations": [
+		{"op": "add", "path": "members", "value": [{"value": %q}]},
+		{"op": "remove", "path": "members[value eq \"%s\"]"}]}`,
+		helper.AccountUUID(t, uc.Datastore, id), helper.AccountUUID(t, uc.Datastore, admin)))
+	_, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+
+	acc, _ := uc.Datastore.GetAccountByID(ctx, id)
+	previous, _ := uc.Datastore.GetAccountByID(ctx, admin)
+	if !acc.IsAdmin() || previous.IsAdmin() {
+		t.Error("Expected the administration privileges moved to the new member")
+	}
+
+	helper.AddParam(t, uc, "resource", `{"Operations": [{"op": "remove", "path": "members[value eq]"}]}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The path of the patch operation is not valid")
+
+	helper.AddParam(t, uc, "id", "owners")
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "owners: Group doesn't exists")
+}
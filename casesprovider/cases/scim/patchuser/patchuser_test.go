@@ -0,0 +1,64 @@
+package patchuser
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMUserPatch")
+}
+
+func TestPatchUser(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token,
+		"resource": `{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [
+			{"op": "Replace", "path": "emails[type eq \"work\"].value", "value": "ritho@ritho.net"},
+			{"op": "Replace", "path": "title", "value": "Architect"},
+			{"op": "Replace", "value": {"active": false, "displayName": "Pablo Álvarez"}}]}`})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	uc.Datastore.ActivateAccount(ctx, id)
+	helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"active":false`)
+
+	acc, err := uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	if acc.Email() != "ritho@ritho.net" || acc.Title() != "Architect" || acc.Name() != "Pablo Álvarez" ||
+		acc.IsActive() || !acc.CheckPassword("12345") {
+		t.Errorf("Unexpected account %s %s %s %t", acc.Email(), acc.Title(), acc.Name(), acc.IsActive())
+	}
+
+	helper.AddParam(t, uc, "resource", `{"Operations": [{"op": "remove", "path": "userName"}]}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Username too short")
+
+	helper.AddParam(t, uc, "resource", `{"Operations": [{"op": "copy", "path": "userName"}]}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The request is not a valid SCIM message")
+}
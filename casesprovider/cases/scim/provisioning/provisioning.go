@@ -0,0 +1,327 @@
+package provisioning
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/scim"
+)
+
+// Client is the name of the SCIM provisioning in the audit log.
+const Client = "scim"
+
+// Groups of the accounts, one for each role of the radar. Every account is a
+// member, and only the admin group can be changed.
+const (
+	GroupAdmin  = "admin"
+	GroupMember = "member"
+)
+
+// ErrNotConfigured raised when the provisioning is used without a bearer
+// token configured.
+var ErrNotConfigured = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMNotConfigured",
+	Other: "The SCIM provisioning is not configured",
+})
+
+// ErrUnauthorized raised when the bearer token of the request is wrong.
+var ErrUnauthorized = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMUnauthorized",
+	Other: "Wrong SCIM bearer token",
+})
+
+// ErrGroupNotExists raised when the group doesn't exists.
+var ErrGroupNotExists = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMGroupNotExists",
+	Other: "Group doesn't exists",
+})
+
+// Result stores the SCIM resource returned by a use case, printed as is.
+type Result struct {
+	Resource interface{}
+}
+
+// String returns the resource in string format.
+func (r *Result) String() (string, error) {
+	res, err := r.Bytes()
+	return string(res), err
+}
+
+// Bytes returns the resource in []bytes format.
+func (r *Result) Bytes() ([]byte, error) {
+	return json.Marshal(r.Resource)
+}
+
+// Localize does nothing, the resources have nothing to translate.
+func (r *Result) Localize(l *i18n.Localizer) {}
+
+// Authorize checks the bearer token of the request against the configured
+// one.
+func Authorize(bearer string) error {
+	token := casesprovider.Config().SCIMToken
+	if token == "" {
+		return ErrNotConfigured
+	}
+
+	if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
+
+// User returns the resource of an account.
+func User(acc *account.Account) *scim.User {
+	active := acc.IsActive()
+	groups := []scim.Ref{{Value: GroupMember, Display: GroupMember}}
+	if acc.IsAdmin() {
+		groups = append(groups, scim.Ref{Value: GroupAdmin, Display: GroupAdmin})
+	}
+
+	return &scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          acc.UUID(),
+		UserName:    acc.Username(),
+		Name:        &scim.Name{Formatted: acc.Name()},
+		DisplayName: acc.Name(),
+		Title:       acc.Title(),
+		Emails:      []scim.Email{{Value: acc.Email(), Type: "work", Primary: true}},
+		Active:      &active,
+		Groups:      groups,
+		Meta:        &scim.Meta{ResourceType: "User"},
+	}
+}
+
+// Account returns the account of the resource id.
+func Account(ctx context.Context, ds *datastore.Datastore, id string) (*account.Account, error) {
+	return ds.GetAccountByUUID(ctx, id)
+}
+
+// Create registers the account of a new user, active unless the user says
+// otherwise. A random password is set when the user has none, so the account
+// logs in through the single sign-on or resets it.
+func Create(ctx context.Context, ds *datastore.Datastore, user *scim.User) (*account.Account, error) {
+	password := user.Password
+	if password == "" {
+		var err error
+		if password, err = random(); err != nil {
+			return nil, err
+		}
+	}
+
+	name := user.FullName()
+	if name == "" {
+		name = user.UserName
+	}
+
+	id, err := ds.AccountRegistration(ctx, user.UserName, name, user.Email(), password)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := ds.GetAccountByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Active == nil {
+		active := true
+		user.Active = &active
+	}
+
+	return acc, update(ctx, ds, acc, user)
+}
+
+// Update replaces the data of the account with the one of the user. The
+// account keeps its name, email, password and title when the user has none,
+// and it's stored at once only if all the data is valid.
+func Update(ctx context.Context, ds *datastore.Datastore, acc *account.Account, user *scim.User) error {
+	if err := acc.SetUsername(user.UserName); err != nil {
+		return err
+	}
+
+	acc.SetName(user.FullName())
+	if email := user.Email(); email != "" {
+		if err := acc.SetEmail(email); err != nil {
+			return err
+		}
+	}
+
+	if user.Password != "" {
+		if err := acc.SetPassword(user.Password); err != nil {
+			return err
+		}
+	}
+
+	return update(ctx, ds, acc, user)
+}
+
+// update saves the title of the account and activates or deactivates it, the
+// sessions of the accounts deactivated are closed.
+func update(ctx context.Context, ds *datastore.Datastore, acc *account.Account, user *scim.User) error {
+	if _, err := acc.SetTitle(user.Title, time.Now()); err != nil {
+		return errors.Wrap(scim.ErrInvalidValue, err.Error())
+	}
+
+	deactivated := false
+	switch {
+	case user.Active == nil || *user.Active == acc.IsActive():
+	case *user.Active:
+		acc.Activate()
+	default:
+		acc.Deactivate()
+		deactivated = true
+	}
+
+	if err := ds.UpdateAccountData(ctx, acc); err != nil {
+		return err
+	}
+
+	if deactivated {
+		if _, err := ds.DeleteSessions(ctx, acc.ID()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Remove closes the sessions of the account and removes it.
+func Remove(ctx context.Context, ds *datastore.Datastore, acc *account.Account) error {
+	if _, err := ds.DeleteSessions(ctx, acc.ID()); err != nil {
+		return err
+	}
+
+	return ds.RemoveAccount(ctx, acc)
+}
+
+// Group returns the resource of the group id.
+func Group(ctx context.Context, ds *datastore.Datastore, id string) (*scim.Group, error) {
+	if id != GroupAdmin && id != GroupMember {
+		return nil, errors.Wrap(ErrGroupNotExists, id)
+	}
+
+	accounts, err := ds.Accounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []scim.Ref{}
+	for _, acc := range accounts {
+		if id == GroupMember || acc.IsAdmin() {
+			members = append(members, scim.Ref{Value: acc.UUID(), Display: acc.Username()})
+		}
+	}
+
+	return &scim.Group{
+		Schemas:     []string{scim.SchemaGroup},
+		ID:          id,
+		DisplayName: id,
+		Members:     members,
+		Meta:        &scim.Meta{ResourceType: "Group"},
+	}, nil
+}
+
+// Groups returns the resources of all the groups.
+func Groups(ctx context.Context, ds *datastore.Datastore) ([]*scim.Group, error) {
+	groups := []*scim.Group{}
+	for _, id := range []string{GroupAdmin, GroupMember} {
+		group, err := Group(ctx, ds, id)
+		if err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// SetMembers sets the members of a group, granting the administration
+// privileges to the members of the admin group and revoking them to the rest.
+func SetMembers(ctx context.Context, ds *datastore.Datastore, id string, members []scim.Ref) error {
+	if _, err := Group(ctx, ds, id); err != nil {
+		return err
+	} else if id != GroupAdmin {
+		return errors.Wrap(scim.ErrMutability, id)
+	}
+
+	admins := make(map[int]bool)
+	for _, member := range members {
+		acc, err := Account(ctx, ds, member.Value)
+		if err != nil {
+			return errors.Wrap(scim.ErrInvalidValue, member.Value)
+		}
+
+		admins[acc.ID()] = true
+	}
+
+	accounts, err := ds.Accounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, acc := range accounts {
+		admin := admins[acc.ID()]
+		if acc.IsAdmin() != admin && !ds.SetAdmin(ctx, acc.ID(), admin) {
+			return errors.Errorf("Error changing the administration privileges of %s",
+				acc.Username())
+		}
+	}
+
+	return nil
+}
+
+// Number returns the number in the query param s, or def if it's empty.
+func Number(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Wrap(scim.ErrInvalidValue, s)
+	}
+
+	return n, nil
+}
+
+// random returns a random password.
+func random() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
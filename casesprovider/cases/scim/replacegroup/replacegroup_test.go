@@ -0,0 +1,61 @@
+package replacegroup
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMGroupReplace")
+}
+
+func TestReplaceGroup(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token, "id": "admin"})
+	admin := helper.RegisterUser(t, uc.Datastore, "admin", "admin", "admin@ritho.net", "12345")
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	uc.Datastore.SetAdmin(ctx, admin, true)
+
+	helper.AddParam(t, uc, "resource", fmt.Sprintf(`{"displayName": "admin", "members": [{"value": %q}]}`,
+		helper.AccountUUID(t, uc.Datastore, id)))
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"members":[{"value":"`)
+
+	acc, _ := uc.Datastore.GetAccountByID(ctx, id)
+	previous, _ := uc.Datastore.GetAccountByID(ctx, admin)
+	if !acc.IsAdmin() || previous.IsAdmin() {
+		t.Error("Expected the administration privileges granted only to the members")
+	}
+
+	helper.AddParam(t, uc, "resource", `{"members": [{"value": "999"}]}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "999: The value of the attribute is not valid")
+
+	helper.AddParams(t, uc, map[string]interface{}{"id": "member", "resource": `{"members": []}`})
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "The attribute can't be modified")
+}
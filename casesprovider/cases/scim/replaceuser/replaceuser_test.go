@@ -0,0 +1,84 @@
+package replaceuser
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+)
+
+func TestCaseName(t *testing.T) {
+	helper.TestCaseName(t, New(), "SCIMUserReplace")
+}
+
+func TestReplaceUser(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	token := helper.SCIMToken(t)
+	session := "00000000-0000-0000-0000-000000000000"
+	helper.SetupUseCase(t, uc, map[string]interface{}{"bearer": token, "id": "0",
+		"resource": `{"userName": "palvarez", "displayName": "Pablo Álvarez", "active": false,
+			"emails": [{"value": "ritho@ritho.net"}], "password": "new-password"}`})
+	id := helper.RegisterUser(t, uc.Datastore, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	helper.RegisterUser(t, uc.Datastore, "jdoe-", "jdoe", "jdoe@ritho.net", "12345")
+	uc.Datastore.ActivateAccount(ctx, id)
+	helper.LoginUser(t, uc.Datastore, session, "ritho")
+
+	_, err := uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Account doesn't exists")
+
+	helper.AddParam(t, uc, "id", helper.AccountUUID(t, uc.Datastore, id))
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	helper.Contains(t, helper.GetResultString(t, res), `"userName":"palvarez"`)
+
+	acc, err := uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	if acc.Username() != "palvarez" || acc.Name() != "Pablo Álvarez" || acc.Email() != "ritho@ritho.net" ||
+		!acc.CheckPassword("new-password") || acc.IsActive() {
+		t.Errorf("Unexpected account %s %s %s %t", acc.Username(), acc.Name(), acc.Email(), acc.IsActive())
+	}
+
+	if uc.Datastore.IsAccountRegisteredByUsername(ctx, "ritho") || uc.Datastore.DoesAccountHaveSessionByID(ctx, id) {
+		t.Error("Expected the account renamed and its session closed")
+	}
+
+	helper.AddParam(t, uc, "resource", `{"userName": "jdoe-"}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "jdoe-: Account already exists")
+
+	/* Nothing is changed unless the whole resource is valid. */
+	helper.AddParam(t, uc, "resource", `{"userName": "renamed", "password": "121"}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "Password too short")
+
+	helper.AddParam(t, uc, "resource", `{"userName": "renamed", "emails": [{"value": "jdoe@ritho.net"}]}`)
+	_, err = uc.Run(ctx)
+	helper.Contains(t, fmt.Sprint(err), "jdoe@ritho.net: Account already exists")
+
+	acc, err = uc.Datastore.GetAccountByID(ctx, id)
+	helper.UnexpectedError(t, err)
+	if acc.Username() != "palvarez" || acc.Email() != "ritho@ritho.net" ||
+		uc.Datastore.IsAccountRegisteredByUsername(ctx, "renamed") {
+		t.Errorf("Expected the account unchanged, Got %s %s", acc.Username(), acc.Email())
+	}
+}
@@ -0,0 +1,46 @@
+package scim
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/scim/createuser"
+	"github.com/radar-go/radar/casesprovider/cases/scim/deleteuser"
+	"github.com/radar-go/radar/casesprovider/cases/scim/getgroup"
+	"github.com/radar-go/radar/casesprovider/cases/scim/getuser"
+	"github.com/radar-go/radar/casesprovider/cases/scim/listgroups"
+	"github.com/radar-go/radar/casesprovider/cases/scim/listusers"
+	"github.com/radar-go/radar/casesprovider/cases/scim/patchgroup"
+	"github.com/radar-go/radar/casesprovider/cases/scim/patchuser"
+	"github.com/radar-go/radar/casesprovider/cases/scim/replacegroup"
+	"github.com/radar-go/radar/casesprovider/cases/scim/replaceuser"
+)
+
+func init() {
+	casesprovider.Register(createuser.New())
+	casesprovider.Register(deleteuser.New())
+	casesprovider.Register(getgroup.New())
+	casesprovider.Register(getuser.New())
+	casesprovider.Register(listgroups.New())
+	casesprovider.Register(listusers.New())
+	casesprovider.Register(patchgroup.New())
+	casesprovider.Register(patchuser.New())
+	casesprovider.Register(replacegroup.New())
+	casesprovider.Register(replaceuser.New())
+}
@@ -0,0 +1,128 @@
+// Package get implements the technology retrieval use case.
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/usecase"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/render"
+)
+
+// ErrTechnologyNotExists raised when no member knows the technology.
+var ErrTechnologyNotExists = i18n.NewError(&goi18n.Message{
+	ID:    "TechnologyNotExists",
+	Other: "Technology doesn't exists",
+})
+
+// Member represents a member that knows the technology.
+type Member struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Level    int    `json:"level"`
+}
+
+// UseCase for the technology retrieval.
+type UseCase struct {
+	usecase.UseCase
+}
+
+// Result stores the result of the technology retrieval.
+type Result struct {
+	usecase.Result
+}
+
+// New creates and returns a new technology get use case object.
+func New() *UseCase {
+	uc := &UseCase{
+		usecase.UseCase{
+			Name: "TechnologyGet",
+			Params: map[string]interface{}{
+				"name": "",
+			},
+			/* It goes through all the accounts, so it takes longer than the
+			use cases working with a single one. */
+			Timeout: 30 * time.Second,
+		},
+	}
+
+	return uc
+}
+
+// New creates and returns a new technology get use case object.
+func (uc *UseCase) New() casesprovider.UseCase {
+	return New()
+}
+
+// Run obtains a technology and the members that know it.
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
+	res := usecase.NewResult()
+
+	accounts, err := uc.Datastore.Accounts(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	name := uc.Params["name"].(string)
+	level := 0
+	members := make([]Member, 0)
+	for _, acc := range accounts {
+		for _, tech := range acc.Technologies() {
+			if radar.CleanString(tech.Name()) != radar.CleanString(name) {
+				continue
+			}
+
+			if len(members) == 0 || tech.Level() > level {
+				level = tech.Level()
+				res.Res["name"] = tech.Name()
+				res.Res["type"] = tech.Type()
+			}
+
+			members = append(members, Member{
+				Username: acc.Username(),
+				Name:     acc.Name(),
+				Level:    tech.Level(),
+			})
+		}
+	}
+
+	if len(members) == 0 {
+		return usecase.NewResult(), errors.Wrap(ErrTechnologyNotExists, name)
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].Level > members[j].Level
+	})
+
+	quadrant, _ := render.Quadrant(res.Res["type"].(string))
+	res.Res["quadrant"] = quadrant
+	res.Res["ring"] = render.Ring(level)
+	res.Res["members"] = members
+
+	return res, nil
+}
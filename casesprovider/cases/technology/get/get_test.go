@@ -0,0 +1,62 @@
+package get
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider/helper"
+	"github.com/radar-go/radar/datastore"
+	technology "github.com/radar-go/radar/entities/technology/api"
+)
+
+func TestTechnologyGet(t *testing.T) {
+	ctx := context.Background()
+	uc := New()
+	helper.TestCaseName(t, uc, "TechnologyGet")
+	uc.SetDatastore(datastore.New())
+
+	helper.AddParam(t, uc, "name", "go")
+	_, err := uc.Run(ctx)
+	if errors.Cause(err) != ErrTechnologyNotExists {
+		t.Errorf("Expected %s, Got %v", ErrTechnologyNotExists, err)
+	}
+
+	for i, user := range []string{"ritho", "senoritho"} {
+		id := helper.RegisterUser(t, uc.Datastore, user, user, user+"@ritho.net", "ritho")
+		acc, err := uc.Datastore.GetAccountByID(ctx, id)
+		helper.UnexpectedError(t, err)
+		acc.AddTechnology(technology.New("Go", "languages", i+2))
+		helper.UnexpectedError(t, uc.Datastore.UpdateAccountData(ctx, acc))
+	}
+
+	res, err := uc.Run(ctx)
+	helper.UnexpectedError(t, err)
+	expected := `{"members":[` +
+		`{"username":"senoritho","name":"senoritho","level":3},` +
+		`{"username":"ritho","name":"ritho","level":2}` +
+		`],"name":"Go","quadrant":"Languages \u0026 Frameworks","ring":"Trial","type":"languages"}`
+	result := helper.GetResultString(t, res)
+	if result != expected {
+		t.Errorf("Expected %s, Got %s", expected, result)
+	}
+}
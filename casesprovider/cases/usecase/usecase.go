@@ -19,9 +19,12 @@ package usecase
 */
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"time"
 
 	errWrap "github.com/pkg/errors"
 
@@ -29,11 +32,14 @@ import (
 	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/casesprovider/errors"
 	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
 )
 
 // Result represents a generic user case result.
 type Result struct {
-	Res map[string]interface{}
+	Res       map[string]interface{}
+	localizer *i18n.Localizer
 }
 
 // NewResult creates a new result object.
@@ -43,9 +49,30 @@ func NewResult() *Result {
 	}
 }
 
+// Localize sets the localizer used to translate the messages and errors of the
+// result, by default they are in the default language.
+func (r *Result) Localize(l *i18n.Localizer) {
+	r.localizer = l
+}
+
+// translated returns the result with the messages and errors translated.
+func (r *Result) translated() map[string]interface{} {
+	l := r.localizer
+	if l == nil {
+		l = i18n.Default
+	}
+
+	res := make(map[string]interface{}, len(r.Res))
+	for key, value := range r.Res {
+		res[key] = l.Translate(value)
+	}
+
+	return res
+}
+
 // Bytes returns the use case result in string format.
 func (r *Result) String() (string, error) {
-	res, err := json.Marshal(r.Res)
+	res, err := json.Marshal(r.translated())
 	if err != nil {
 		return "{}", err
 	}
@@ -55,7 +82,7 @@ func (r *Result) String() (string, error) {
 
 // Bytes returns the use case result in []bytes format.
 func (r *Result) Bytes() ([]byte, error) {
-	return json.Marshal(r.Res)
+	return json.Marshal(r.translated())
 }
 
 // UseCase represents a generic use case.
@@ -63,6 +90,8 @@ type UseCase struct {
 	Name      string
 	Datastore *datastore.Datastore
 	Params    map[string]interface{}
+	Timeout   time.Duration
+	Audit     bool
 }
 
 // New returns a new UseCase object.
@@ -133,7 +162,65 @@ func (uc *UseCase) SetDatastore(ds *datastore.Datastore) {
 	uc.Datastore = ds
 }
 
+// Audited returns true if the runs of the use case are recorded in the audit
+// log.
+func (uc *UseCase) Audited() bool {
+	return uc.Audit
+}
+
+// GetParams returns the params of the use case.
+func (uc *UseCase) GetParams() map[string]interface{} {
+	return uc.Params
+}
+
+// GetTimeout returns the maximum duration of the use case runs.
+func (uc *UseCase) GetTimeout() time.Duration {
+	if uc.Timeout <= 0 {
+		return casesprovider.DefaultTimeout
+	}
+
+	return uc.Timeout
+}
+
+// SessionAccount returns the account of the session in the token param. The
+// account is taken from the context when the session have been already checked
+// running the use case through the interceptors.
+func (uc *UseCase) SessionAccount(ctx context.Context) (*account.Account, error) {
+	if acc := casesprovider.ContextAccount(ctx); acc != nil {
+		return acc, nil
+	}
+
+	token, _ := uc.Params["token"].(string)
+	return uc.Datastore.GetAccountBySession(ctx, token)
+}
+
+// Page returns the page and the number of items of every page asked in the
+// page and per_page params, the first page of defaultPerPage items if they are
+// not present. It returns an error if they are negative, the items are more
+// than maxPerPage or the offset of the page would overflow.
+func (uc *UseCase) Page(defaultPerPage, maxPerPage int) (int, int, error) {
+	page, _ := uc.Params["page"].(int)
+	perPage, _ := uc.Params["per_page"].(int)
+	if perPage < 0 || perPage > maxPerPage {
+		return 0, 0, errWrap.Wrap(errors.ErrParamType, "per_page")
+	}
+
+	if perPage == 0 {
+		perPage = defaultPerPage
+	}
+
+	if page < 0 || page > math.MaxInt/perPage {
+		return 0, 0, errWrap.Wrap(errors.ErrParamType, "page")
+	}
+
+	if page == 0 {
+		page = 1
+	}
+
+	return page, perPage, nil
+}
+
 // Run executes the use case.
-func (uc *UseCase) Run() (casesprovider.ResultPrinter, error) {
+func (uc *UseCase) Run(ctx context.Context) (casesprovider.ResultPrinter, error) {
 	return nil, fmt.Errorf("Function Run not implemented")
 }
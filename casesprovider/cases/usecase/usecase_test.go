@@ -20,12 +20,16 @@ package usecase
 
 import (
 	"bytes"
+	"context"
 	"testing"
+	"time"
 
+	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/datastore"
 )
 
 func TestUseCase(t *testing.T) {
+	ctx := context.Background()
 	uc := &UseCase{
 		Name: "UseCase",
 	}
@@ -35,11 +39,20 @@ func TestUseCase(t *testing.T) {
 		t.Errorf("Expected UseCase, Got %s", uc.GetName())
 	}
 
-	_, err := uc.Run()
+	_, err := uc.Run(ctx)
 	if err == nil {
 		t.Error("Expected error running the use case")
 	}
 
+	if uc.GetTimeout() != casesprovider.DefaultTimeout {
+		t.Errorf("Expected %s, Got %s", casesprovider.DefaultTimeout, uc.GetTimeout())
+	}
+
+	uc.Timeout = time.Second
+	if uc.GetTimeout() != time.Second {
+		t.Errorf("Expected 1s, Got %s", uc.GetTimeout())
+	}
+
 	err = uc.AddParam("param", 1)
 	if err == nil {
 		t.Error("Expected error adding a param")
@@ -20,44 +20,92 @@ package casesprovider
 */
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/golang/glog"
-
+	"github.com/radar-go/radar/config"
 	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/ldap"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/mailer"
+	"github.com/radar-go/radar/metrics"
+	"github.com/radar-go/radar/oidc"
+	"github.com/radar-go/radar/token"
 )
 
+// DefaultTimeout is the maximum duration of the use case runs when the use case
+// doesn't define its own timeout.
+const DefaultTimeout = 10 * time.Second
+
+// OIDCCallbackPath is the path of the web interface the single sign-on
+// provider redirects back to.
+const OIDCCallbackPath = "/login/oidc/callback"
+
 // ResultPrinter for the Use Case.
 type ResultPrinter interface {
 	String() (string, error)
 	Bytes() ([]byte, error)
+	Localize(l *i18n.Localizer)
 }
 
 // UseCase defines the operations that can be done over any use case.
 type UseCase interface {
 	AddParam(string, interface{}) error
 	AddParams(map[string]interface{}) error
+	Audited() bool
 	GetName() string
+	GetParams() map[string]interface{}
+	GetTimeout() time.Duration
 	New() UseCase
 	SetDatastore(*datastore.Datastore)
-	Run() (ResultPrinter, error)
+	Run(context.Context) (ResultPrinter, error)
 }
 
 // UCases struct to call to the different Radar use cases.
 type UCases struct {
-	ds       *datastore.Datastore
-	useCases map[string]UseCase
+	/* The datastore is replaced while the metrics are scraped, so it's read
+	and set under its lock. */
+	dsMu         sync.RWMutex
+	ds           *datastore.Datastore
+	useCases     map[string]UseCase
+	interceptors []Interceptor
+	cfg          *config.Config
+	mailer       mailer.Mailer
+	signer       *token.Signer
+	oidc         *oidc.Provider
+	ldap         *ldap.Directory
 }
 
 var cases = &UCases{
 	ds:       datastore.New(),
 	useCases: make(map[string]UseCase),
+	cfg:      config.New(),
+	mailer:   &mailer.Log{},
+	signer:   token.NewSigner(""),
+}
+
+func init() {
+	metrics.RegisterGauge("accounts_registered", "Number of accounts registered.",
+		func() float64 {
+			return float64(Datastore().AccountsCount())
+		})
+	metrics.RegisterGauge("sessions_active", "Number of sessions active.",
+		func() float64 {
+			return float64(Datastore().SessionsCount())
+		})
+
+	Intercept(logRun, observeRun, localizeResult, limitRun, authenticate, requireTwoFactor,
+		auditRun)
 }
 
 // Register registers a new UseCase into the list of use cases.
 func Register(uCase UseCase) {
 	if _, ok := cases.useCases[uCase.GetName()]; ok {
-		glog.Errorf("Use case %s already register", uCase.GetName())
+		logging.Logger.Error("use case already registered", "name", uCase.GetName())
 	}
 
 	cases.useCases[uCase.GetName()] = uCase
@@ -71,9 +119,113 @@ func GetUseCase(name string) (UseCase, error) {
 	}
 
 	uc := useCase.New()
-	uc.SetDatastore(cases.ds)
+	uc.SetDatastore(Datastore())
+
+	return &intercepted{uc}, nil
+}
+
+// SetDatastore sets the datastore used by all the use cases.
+func SetDatastore(ds *datastore.Datastore) {
+	cases.dsMu.Lock()
+	defer cases.dsMu.Unlock()
+
+	cases.ds = ds
+}
+
+// Datastore returns the datastore used by all the use cases.
+func Datastore() *datastore.Datastore {
+	cases.dsMu.RLock()
+	defer cases.dsMu.RUnlock()
+
+	return cases.ds
+}
+
+// Configure sets the configuration used by all the use cases, creating the
+// mailer, the signer of the tokens sent to the users, the single sign-on
+// provider and the LDAP directory.
+func Configure(cfg *config.Config) error {
+	m, err := mailer.New(cfg.Mailer, cfg.MailFrom)
+	if err != nil {
+		return err
+	}
+
+	groups, err := oidc.ParseGroups(cfg.OIDCGroups)
+	if err != nil {
+		return err
+	}
+
+	if cfg.LDAPUserFilter != "" {
+		if err = ldap.CheckFilter(cfg.LDAPUserFilter); err != nil {
+			return err
+		}
+	}
+
+	if cfg.SecretKey == "" {
+		logging.Logger.Warn("no secret key configured, the links sent to the users won't survive a restart")
+	}
+
+	cases.cfg = cfg
+	cases.mailer = m
+	cases.signer = token.NewSigner(cfg.SecretKey)
+	cases.oidc = nil
+	if cfg.OIDCIssuer != "" {
+		cases.oidc = oidc.New(cfg.OIDCIssuer, cfg.OIDCClientID, cfg.OIDCClientSecret,
+			strings.TrimRight(cfg.PublicURL, "/")+OIDCCallbackPath)
+		cases.oidc.Groups = groups
+		if cfg.OIDCScopes != "" {
+			cases.oidc.Scopes = cfg.OIDCScopes
+		}
+	}
+
+	cases.ldap = nil
+	if cfg.LDAPURL != "" {
+		cases.ldap = ldap.New(cfg.LDAPURL, cfg.LDAPBindDN, cfg.LDAPBindPassword, cfg.LDAPBaseDN)
+		if cfg.LDAPUserFilter != "" {
+			cases.ldap.UserFilter = cfg.LDAPUserFilter
+		}
+	}
+
+	return nil
+}
+
+// Config returns the configuration used by all the use cases.
+func Config() *config.Config {
+	return cases.cfg
+}
+
+// SetMailer sets the mailer used by all the use cases.
+func SetMailer(m mailer.Mailer) {
+	cases.mailer = m
+}
+
+// Mailer returns the mailer used by all the use cases.
+func Mailer() mailer.Mailer {
+	return cases.mailer
+}
+
+// Signer returns the signer of the tokens sent to the users.
+func Signer() *token.Signer {
+	return cases.signer
+}
+
+// SetOIDC sets the single sign-on provider used by all the use cases.
+func SetOIDC(p *oidc.Provider) {
+	cases.oidc = p
+}
+
+// OIDC returns the single sign-on provider, nil if it's not configured.
+func OIDC() *oidc.Provider {
+	return cases.oidc
+}
+
+// SetLDAP sets the LDAP directory used by all the use cases.
+func SetLDAP(d *ldap.Directory) {
+	cases.ldap = d
+}
 
-	return uc, nil
+// LDAP returns the LDAP directory, nil if it's not configured.
+func LDAP() *ldap.Directory {
+	return cases.ldap
 }
 
 // UseCaseList returns the list of names of all the Use Cases.
@@ -19,7 +19,16 @@ package casesprovider
 */
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/metrics"
 )
 
 func TestCasesProvider(t *testing.T) {
@@ -57,3 +66,132 @@ func TestCasesProvider(t *testing.T) {
 		t.Errorf("Expected error getting the use case did not happened")
 	}
 }
+
+// slowUseCase is an use case that doesn't finish until its context is done.
+type slowUseCase struct {
+	MockUseCase
+}
+
+func (uc *slowUseCase) New() UseCase {
+	return &slowUseCase{MockUseCase{Name: uc.Name, Timeout: uc.Timeout}}
+}
+
+func (uc *slowUseCase) Run(ctx context.Context) (ResultPrinter, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestUseCaseContext(t *testing.T) {
+	Register(&slowUseCase{MockUseCase{Name: "slow", Timeout: 10 * time.Millisecond}})
+	uc, err := GetUseCase("slow")
+	if err != nil {
+		t.Fatalf("Unexpected error getting the use case: %+v", err)
+	}
+
+	_, err = uc.Run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "The operation took too long") {
+		t.Errorf("Expected timeout error, Got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = uc.Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), "The operation has been cancelled") {
+		t.Errorf("Expected cancellation error, Got %v", err)
+	}
+}
+
+func TestConfigureOIDC(t *testing.T) {
+	defer SetOIDC(nil)
+
+	cfg := config.New()
+	if err := Configure(cfg); err != nil || OIDC() != nil {
+		t.Errorf("Expected the single sign-on disabled, Got %+v: %v", OIDC(), err)
+	}
+
+	cfg.OIDCIssuer = "https://sso.ritho.net/"
+	cfg.OIDCClientID = "radar"
+	cfg.OIDCGroups = "radar-admins=admin"
+	if err := Configure(cfg); err != nil || !OIDC().Configured() {
+		t.Fatalf("Expected the single sign-on enabled, Got %+v: %v", OIDC(), err)
+	}
+
+	p := OIDC()
+	if p.RedirectURL != "http://localhost:10080/login/oidc/callback" ||
+		p.Scopes != "openid email profile" || p.Groups["radar-admins"] != "admin" {
+		t.Errorf("Unexpected provider %+v", p)
+	}
+
+	cfg.OIDCGroups = "radar-admins=root"
+	if err := Configure(cfg); err == nil {
+		t.Error("Expected error configuring a wrong group mapping")
+	}
+}
+
+func TestConfigureLDAP(t *testing.T) {
+	defer SetLDAP(nil)
+
+	cfg := config.New()
+	if err := Configure(cfg); err != nil || LDAP().Configured() {
+		t.Errorf("Expected the directory disabled, Got %+v: %v", LDAP(), err)
+	}
+
+	cfg.LDAPURL = "ldap://ldap.ritho.net"
+	cfg.LDAPBaseDN = "ou=people,dc=ritho,dc=net"
+	cfg.LDAPUserFilter = "(&(objectClass=person)(memberOf=cn=radar,dc=ritho,dc=net))"
+	if err := Configure(cfg); err != nil || !LDAP().Configured() ||
+		LDAP().UserFilter != cfg.LDAPUserFilter {
+		t.Fatalf("Expected the directory enabled, Got %+v: %v", LDAP(), err)
+	}
+
+	cfg.LDAPUserFilter = "(objectClass=person"
+	if err := Configure(cfg); err == nil {
+		t.Error("Expected error configuring a wrong user filter")
+	}
+}
+
+func TestGaugesWhileRegistering(t *testing.T) {
+	ctx := context.Background()
+	ds := datastore.New()
+	SetDatastore(ds)
+	defer SetDatastore(datastore.New())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+
+			username := fmt.Sprintf("ritho%d", i)
+			_, err := ds.AccountRegistration(ctx, username, "ritho", username+"@ritho.net", "ritho")
+			if err != nil {
+				t.Errorf("Unexpected error registering %s: %+v", username, err)
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+
+			if _, err := metrics.Registry.Gather(); err != nil {
+				t.Errorf("Unexpected error gathering the metrics: %+v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	families, err := metrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("Unexpected error gathering the metrics: %+v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() == "radar_accounts_registered" {
+			if value := family.GetMetric()[0].GetGauge().GetValue(); value != 8 {
+				t.Errorf("Expected 8 accounts registered, got %v", value)
+			}
+			return
+		}
+	}
+
+	t.Error("Expected the accounts_registered gauge to be gathered")
+}
@@ -20,16 +20,48 @@ package errors
 */
 
 import (
-	"errors"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/i18n"
 )
 
 // ErrParamUnknown defines the error when the use case param is not defined.
-var ErrParamUnknown = errors.New("Unknown parameter for the use case")
+var ErrParamUnknown = i18n.NewError(&goi18n.Message{
+	ID:    "ParamUnknown",
+	Other: "Unknown parameter for the use case",
+})
 
 // ErrParamType defines the error when the ad param is not of the right type for
 // the use case.
-var ErrParamType = errors.New("Param is not from the right type")
+var ErrParamType = i18n.NewError(&goi18n.Message{
+	ID:    "ParamType",
+	Other: "Param is not from the right type",
+})
 
 // ErrParamEmpty defines the error when the ad param is not present or is empty
 // for the use case.
-var ErrParamEmpty = errors.New("Param is not present or empty")
+var ErrParamEmpty = i18n.NewError(&goi18n.Message{
+	ID:    "ParamEmpty",
+	Other: "Param is not present or empty",
+})
+
+// ErrTimeout defines the error when the use case doesn't finish before its
+// deadline.
+var ErrTimeout = i18n.NewError(&goi18n.Message{
+	ID:    "UseCaseTimeout",
+	Other: "The operation took too long",
+})
+
+// ErrTooManyRequests defines the error when the client exceeds the number of
+// requests allowed in a period of time.
+var ErrTooManyRequests = i18n.NewError(&goi18n.Message{
+	ID:    "TooManyRequests",
+	Other: "Too many requests, try again later",
+})
+
+// ErrCanceled defines the error when the use case is cancelled before it
+// finishes.
+var ErrCanceled = i18n.NewError(&goi18n.Message{
+	ID:    "UseCaseCanceled",
+	Other: "The operation has been cancelled",
+})
@@ -21,13 +21,18 @@ package helper
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/radar-go/radar/casesprovider"
 	"github.com/radar-go/radar/datastore"
 	"github.com/radar-go/radar/helper"
+	"github.com/radar-go/radar/ldap/ldaptest"
+	"github.com/radar-go/radar/oidc/oidctest"
+	"github.com/radar-go/radar/totp"
 )
 
 // SaveGoldenData saves test data in a golden file.
@@ -109,17 +114,117 @@ func AddParams(t *testing.T, uc casesprovider.UseCase, params map[string]interfa
 // RegisterUser helper function to register an user in the datastore for the tests.
 func RegisterUser(t *testing.T, ds *datastore.Datastore, username, name, email, password string) int {
 	t.Helper()
-	id, err := ds.AccountRegistration(username, name, email, password)
+	ctx := context.Background()
+	id, err := ds.AccountRegistration(ctx, username, name, email, password)
 	UnexpectedError(t, err)
 
 	return id
 }
 
+// ActivateUser helper function to activate an account for the tests, as the
+// registered accounts can't log in until they verify their email.
+func ActivateUser(t *testing.T, ds *datastore.Datastore, id int) {
+	t.Helper()
+	if !ds.ActivateAccount(context.Background(), id) {
+		t.Fatalf("Unexpected error activating the account %d", id)
+	}
+}
+
+// AccountUUID helper function to obtain the public identifier of an account by
+// its id for the tests.
+func AccountUUID(t *testing.T, ds *datastore.Datastore, id int) string {
+	t.Helper()
+	acc, err := ds.GetAccountByID(context.Background(), id)
+	UnexpectedError(t, err)
+	if acc == nil {
+		return ""
+	}
+
+	return acc.UUID()
+}
+
 // LoginUser helper function to login an user into the datastore for the tests.
 func LoginUser(t *testing.T, ds *datastore.Datastore, token, username string) {
 	t.Helper()
-	err := ds.AddSession(token, username)
+	ctx := context.Background()
+	err := ds.AddSession(ctx, token, username)
+	UnexpectedError(t, err)
+}
+
+// EnableTwoFactor helper function to enable the two-factor authentication of an
+// account for the tests, returning its secret. The code of the current period
+// is left unused, so the tests can log in with it.
+func EnableTwoFactor(t *testing.T, ds *datastore.Datastore, username string, recoveryCodes ...string) string {
+	t.Helper()
+	ctx := context.Background()
+	acc, err := ds.GetAccountByUsername(ctx, username)
+	UnexpectedError(t, err)
+
+	secret, err := totp.GenerateSecret()
+	UnexpectedError(t, err)
+	UnexpectedError(t, acc.SetTwoFactorSecret(secret))
+
+	code, err := totp.Code(secret, time.Now().Add(-totp.Period))
+	UnexpectedError(t, err)
+	UnexpectedError(t, acc.EnableTwoFactor(code, time.Now(), recoveryCodes))
+	UnexpectedError(t, ds.UpdateAccountData(ctx, acc))
+
+	return secret
+}
+
+// AddAPIKey helper function to add an API key with the scopes to an account
+// for the tests, expiring in an hour.
+func AddAPIKey(t *testing.T, ds *datastore.Datastore, username, id, key string, scopes ...string) {
+	t.Helper()
+	ctx := context.Background()
+	acc, err := ds.GetAccountByUsername(ctx, username)
 	UnexpectedError(t, err)
+
+	now := time.Now()
+	UnexpectedError(t, acc.AddAPIKey(id, id, key, scopes, now, now.Add(time.Hour)))
+	UnexpectedError(t, ds.UpdateAccountData(ctx, acc))
+}
+
+// OIDCProvider helper function to start a mock identity provider logging in the
+// user, and set it as the single sign-on provider of the use cases until the
+// test ends.
+func OIDCProvider(t *testing.T, user oidctest.User) *oidctest.Server {
+	t.Helper()
+	s := oidctest.New(user)
+	casesprovider.SetOIDC(s.Provider("http://localhost:10080" + casesprovider.OIDCCallbackPath))
+	t.Cleanup(func() {
+		casesprovider.SetOIDC(nil)
+		s.Close()
+	})
+
+	return s
+}
+
+// LDAPDirectory helper function to start a test LDAP server, and set it as the
+// directory of the use cases until the test ends.
+func LDAPDirectory(t *testing.T) *ldaptest.Server {
+	t.Helper()
+	s := ldaptest.NewDirectory()
+	casesprovider.SetLDAP(s.Directory())
+	t.Cleanup(func() {
+		casesprovider.SetLDAP(nil)
+		s.Close()
+	})
+
+	return s
+}
+
+// SCIMToken helper function to configure the bearer token of the SCIM
+// provisioning until the test ends.
+func SCIMToken(t *testing.T) string {
+	t.Helper()
+	token := "scim-secret"
+	casesprovider.Config().SCIMToken = token
+	t.Cleanup(func() {
+		casesprovider.Config().SCIMToken = ""
+	})
+
+	return token
 }
 
 // SetupUseCase helper function to initialize an use case for the tests.
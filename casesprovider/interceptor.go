@@ -0,0 +1,368 @@
+package casesprovider
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/metrics"
+)
+
+// Handler runs an use case and returns its result.
+type Handler func(ctx context.Context, uc UseCase) (ResultPrinter, error)
+
+// Interceptor wraps the runs of the use cases. It can do its work before
+// and after calling next, which runs the rest of the chain and the use case,
+// change the params of the use case, process the result, or return its own
+// result without calling next at all.
+type Interceptor func(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error)
+
+// AuditTargeter is implemented by the audited use cases that work on an
+// account that isn't the one of the session.
+type AuditTargeter interface {
+	AuditTarget(ctx context.Context) *account.Account
+}
+
+// TwoFactorExempter is implemented by the use cases the sessions can run
+// before enrolling the two-factor authentication required to their accounts,
+// like the enrollment itself.
+type TwoFactorExempter interface {
+	TwoFactorExempt() bool
+}
+
+// APIKeyScoper is implemented by the use cases that can be run with an API
+// key instead of a session, returning the scope the key needs.
+type APIKeyScoper interface {
+	APIKeyScope() string
+}
+
+// accountKey is the key of the session account stored in a context.
+type accountKey struct{}
+
+// trustedKey is the key of the mark of the trusted runs in a context.
+type trustedKey struct{}
+
+// clientKey is the key of the client running the use case in a context.
+type clientKey struct{}
+
+// Intercept adds interceptors to the chain wrapping every use case run. They
+// run in the order they are added, the first one being the outermost. The
+// chain starts with the interceptors logging, recording the metrics,
+// translating the results, limiting the duration, authenticating, requiring the
+// two-factor authentication and auditing the runs.
+func Intercept(interceptors ...Interceptor) {
+	cases.interceptors = append(cases.interceptors, interceptors...)
+}
+
+// intercepted wraps an use case to run it through the interceptors chain.
+type intercepted struct {
+	UseCase
+}
+
+// Run runs the use case through the interceptors chain.
+func (i *intercepted) Run(ctx context.Context) (ResultPrinter, error) {
+	return chain(cases.interceptors)(ctx, i.UseCase)
+}
+
+// chain returns the handler running the use cases through the interceptors.
+func chain(interceptors []Interceptor) Handler {
+	if len(interceptors) == 0 {
+		return func(ctx context.Context, uc UseCase) (ResultPrinter, error) {
+			return uc.Run(ctx)
+		}
+	}
+
+	next := chain(interceptors[1:])
+	return func(ctx context.Context, uc UseCase) (ResultPrinter, error) {
+		return interceptors[0](ctx, uc, next)
+	}
+}
+
+// WithAccount returns a copy of ctx carrying the account of the session that
+// runs the use case.
+func WithAccount(ctx context.Context, acc *account.Account) context.Context {
+	return context.WithValue(ctx, accountKey{}, acc)
+}
+
+// ContextAccount returns the account of the session carried by ctx, if any.
+func ContextAccount(ctx context.Context) *account.Account {
+	acc, _ := ctx.Value(accountKey{}).(*account.Account)
+	return acc
+}
+
+// WithTrusted returns a copy of ctx for the runs done by the local
+// administration commands, which don't need the two-factor authentication
+// required to the sessions opened remotely.
+func WithTrusted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trustedKey{}, true)
+}
+
+// trusted returns true if the run is done by a local administration command.
+func trusted(ctx context.Context) bool {
+	t, _ := ctx.Value(trustedKey{}).(bool)
+	return t
+}
+
+// WithClient returns a copy of ctx for the runs done by a client authenticated
+// with its own credentials instead of a session, like the SCIM provisioning.
+func WithClient(ctx context.Context, client string) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ContextClient returns the client running the use case carried by ctx, if
+// any.
+func ContextClient(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
+
+// logRun logs the result of the run with the ID of the request that runs it.
+func logRun(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+	start := time.Now()
+	res, err := next(ctx, uc)
+
+	logger := logging.Logger.With(
+		"request_id", logging.ContextRequestID(ctx),
+		"user", logging.ContextUser(ctx),
+		"usecase", uc.GetName(),
+		"duration_ms", float64(time.Since(start).Microseconds())/1000,
+	)
+	if err != nil {
+		logger.Warn("use case failed", "error", err.Error())
+	} else {
+		logger.Info("use case run")
+	}
+
+	return res, err
+}
+
+// observeRun records the duration and errors of the run.
+func observeRun(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+	start := time.Now()
+	res, err := next(ctx, uc)
+	metrics.ObserveUseCase(uc.GetName(), start, err)
+
+	return res, err
+}
+
+// localizeResult translates the result to the language of the request.
+func localizeResult(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+	res, err := next(ctx, uc)
+	if res != nil {
+		res.Localize(i18n.ContextLocalizer(ctx))
+	}
+
+	return res, err
+}
+
+// limitRun cancels the run once the timeout of the use case expires, returning
+// a translatable error when it's interrupted by its context.
+func limitRun(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+	ctx, cancel := context.WithTimeout(ctx, uc.GetTimeout())
+	defer cancel()
+
+	res, err := next(ctx, uc)
+	switch errWrap.Cause(err) {
+	case context.DeadlineExceeded:
+		return res, errWrap.Wrap(errors.ErrTimeout, uc.GetName())
+	case context.Canceled:
+		return res, errWrap.Wrap(errors.ErrCanceled, uc.GetName())
+	}
+
+	return res, err
+}
+
+// authenticate stops the runs of the use cases that need a session when the
+// session doesn't exists, and passes the account of the session to the use
+// case otherwise. The token can be an API key too.
+func authenticate(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+	token, ok := uc.GetParams()["token"].(string)
+	if !ok {
+		return next(ctx, uc)
+	}
+
+	if strings.HasPrefix(token, account.APIKeyPrefix) {
+		return authenticateAPIKey(ctx, uc, token, next)
+	}
+
+	acc, err := Datastore().GetAccountBySession(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return next(WithAccount(ctx, acc), uc)
+}
+
+// authenticateAPIKey passes the account of the API key to the use case if the
+// key has the scope needed to run it.
+func authenticateAPIKey(ctx context.Context, uc UseCase, key string, next Handler) (ResultPrinter, error) {
+	acc, apiKey, err := Datastore().GetAccountByAPIKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	scoper, ok := uc.(APIKeyScoper)
+	if !ok || !apiKey.HasScope(scoper.APIKeyScope()) {
+		return nil, errWrap.Wrap(account.ErrAPIKeyScope, uc.GetName())
+	}
+
+	return next(WithAccount(ctx, acc), uc)
+}
+
+// requireTwoFactor stops the runs of the sessions whose accounts must enroll
+// the two-factor authentication, except the ones enrolling it.
+func requireTwoFactor(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+	acc := ContextAccount(ctx)
+	if acc == nil || acc.HasTwoFactor() || trusted(ctx) {
+		return next(ctx, uc)
+	}
+
+	if exempter, ok := uc.(TwoFactorExempter); ok && exempter.TwoFactorExempt() {
+		return next(ctx, uc)
+	}
+
+	policy, err := Datastore().Policy(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.RequiresTwoFactor(acc) {
+		return nil, account.ErrTwoFactorRequired
+	}
+
+	return next(ctx, uc)
+}
+
+// auditRun records the successful runs of the audited use cases in the audit
+// log, with the changes done to the target account.
+func auditRun(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+	if !uc.Audited() {
+		return next(ctx, uc)
+	}
+
+	ds := Datastore()
+	target := auditTarget(ctx, uc)
+	before := auditFields(ctx, target)
+	res, err := next(ctx, uc)
+	if err != nil {
+		return res, err
+	}
+
+	/* The record is kept even if the run consumed the time of the request. */
+	ctx = context.WithoutCancel(ctx)
+	erased := ""
+	if target != nil {
+		/* A removed account is kept until purged, so it's still audited, and
+		the one gone after the run was erased. */
+		uuid := target.UUID()
+		target, err = ds.GetAccountByID(ctx, target.ID())
+		if err != nil {
+			target, _ = ds.GetRemovedAccount(ctx, uuid)
+		}
+
+		if target == nil {
+			erased = uuid
+		}
+	} else {
+		target = auditTarget(ctx, uc)
+	}
+
+	rec := audit.Record{
+		Time:      time.Now().UTC(),
+		Action:    uc.GetName(),
+		RequestID: logging.ContextRequestID(ctx),
+		Changes:   audit.Diff(before, auditFields(ctx, target)),
+	}
+	if target != nil {
+		rec.Target = target.Username()
+		rec.TargetID = target.UUID()
+	} else if username, ok := before["username"].(string); ok {
+		rec.Target = username
+	}
+
+	rec.Actor = rec.Target
+	if acc := ContextAccount(ctx); acc != nil {
+		rec.Actor = acc.Username()
+	} else if client := ContextClient(ctx); client != "" {
+		rec.Actor = client
+	}
+
+	if erased != "" {
+		/* Nothing personal of an erased account is recorded. */
+		rec.TargetID = erased
+		rec.Erase(erased, rec.Target)
+	}
+
+	err = ds.AddAuditRecord(ctx, rec)
+	if err != nil {
+		logging.Logger.Error("audit record lost", "request_id", rec.RequestID,
+			"usecase", rec.Action, "error", err.Error())
+	}
+
+	return res, nil
+}
+
+// auditTarget returns the account the use case works on: the one given by the
+// use case, the account of the session or the one of the username or login
+// params.
+func auditTarget(ctx context.Context, uc UseCase) *account.Account {
+	if targeter, ok := uc.(AuditTargeter); ok {
+		if acc := targeter.AuditTarget(ctx); acc != nil {
+			return acc
+		}
+	}
+
+	if acc := ContextAccount(ctx); acc != nil {
+		return acc
+	}
+
+	for _, param := range []string{"username", "login"} {
+		username, ok := uc.GetParams()[param].(string)
+		if !ok {
+			continue
+		}
+
+		acc, err := Datastore().GetAccountByUsername(ctx, username)
+		if err == nil {
+			return acc
+		}
+	}
+
+	return nil
+}
+
+// auditFields returns the audited fields of the account, including if it has
+// a session.
+func auditFields(ctx context.Context, acc *account.Account) map[string]interface{} {
+	fields := audit.Fields(acc)
+	if fields != nil {
+		fields["session"] = Datastore().DoesAccountHaveSessionByID(ctx, acc.ID())
+	}
+
+	return fields
+}
@@ -0,0 +1,369 @@
+package casesprovider
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	errWrap "github.com/pkg/errors"
+
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+	"github.com/radar-go/radar/logging"
+)
+
+// echoUseCase is an use case returning its params as result.
+type echoUseCase struct {
+	MockUseCase
+}
+
+func (uc *echoUseCase) Run(ctx context.Context) (ResultPrinter, error) {
+	res := NewMockResult()
+	for key, value := range uc.Params {
+		res.Res[key] = value
+	}
+
+	return res, nil
+}
+
+func TestInterceptors(t *testing.T) {
+	defer func(interceptors []Interceptor) {
+		cases.interceptors = interceptors
+	}(cases.interceptors)
+
+	calls := make([]string, 0)
+	Intercept(func(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+		calls = append(calls, "outer")
+		res, err := next(ctx, uc)
+		if err == nil {
+			res.(*MockResult).Res["processed"] = true
+		}
+
+		return res, err
+	}, func(ctx context.Context, uc UseCase, next Handler) (ResultPrinter, error) {
+		calls = append(calls, "inner")
+		if uc.GetParams()["name"] == "blocked" {
+			return nil, errors.New("Blocked")
+		}
+
+		err := uc.AddParam("name", "enriched")
+		if err != nil {
+			return nil, err
+		}
+
+		return next(ctx, uc)
+	})
+
+	uc := &intercepted{&echoUseCase{MockUseCase{
+		Name:   "echo",
+		Params: map[string]interface{}{"name": ""},
+	}}}
+	res, err := uc.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(calls) != 2 || calls[0] != "outer" || calls[1] != "inner" {
+		t.Errorf("Expected the interceptors to run in order, Got %v", calls)
+	}
+
+	result := res.(*MockResult).Res
+	if result["name"] != "enriched" || result["processed"] != true {
+		t.Errorf("Expected the params enriched and the result processed, Got %v", result)
+	}
+
+	blocked := &intercepted{&echoUseCase{MockUseCase{
+		Name:   "echo",
+		Params: map[string]interface{}{"name": "blocked"},
+	}}}
+	_, err = blocked.Run(context.Background())
+	if err == nil || err.Error() != "Blocked" {
+		t.Errorf("Expected the run to be blocked, Got %v", err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	SetDatastore(datastore.New())
+	defer SetDatastore(datastore.New())
+
+	ctx := context.Background()
+	uc := &echoUseCase{MockUseCase{
+		Name:   "echo",
+		Params: map[string]interface{}{"token": "00000000-0000-0000-0000-000000000000"},
+	}}
+
+	var acc *account.Account
+	next := func(ctx context.Context, uc UseCase) (ResultPrinter, error) {
+		acc = ContextAccount(ctx)
+		return uc.Run(ctx)
+	}
+
+	_, err := authenticate(ctx, uc, next)
+	if err == nil {
+		t.Error("Expected error running the use case without session")
+	}
+
+	_, err = Datastore().AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err = Datastore().AddSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = authenticate(ctx, uc, next)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if acc == nil || acc.Username() != "ritho" {
+		t.Errorf("Expected the account of the session, Got %v", acc)
+	}
+}
+
+// scopedUseCase is an use case that can be run with the API keys with the
+// read scope.
+type scopedUseCase struct {
+	echoUseCase
+}
+
+func (uc *scopedUseCase) APIKeyScope() string {
+	return account.ScopeRead
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	SetDatastore(datastore.New())
+	defer SetDatastore(datastore.New())
+
+	ctx := context.Background()
+	id, err := Datastore().AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	Datastore().ActivateAccount(ctx, id)
+	acc, _ := Datastore().GetAccountByID(ctx, id)
+	now := time.Now()
+	err = acc.AddAPIKey("1234", "ci", "radar_secret", []string{account.ScopeRead}, now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if err = Datastore().UpdateAccountData(ctx, acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	var runAcc *account.Account
+	next := func(ctx context.Context, uc UseCase) (ResultPrinter, error) {
+		runAcc = ContextAccount(ctx)
+		return uc.Run(ctx)
+	}
+
+	scoped := &scopedUseCase{echoUseCase{MockUseCase{
+		Name:   "scoped",
+		Params: map[string]interface{}{"token": "radar_secret"},
+	}}}
+	_, err = authenticate(ctx, scoped, next)
+	if err != nil || runAcc == nil || runAcc.ID() != id {
+		t.Errorf("Expected the account of the key, Got %v: %v", runAcc, err)
+	}
+
+	unscoped := &echoUseCase{MockUseCase{
+		Name:   "echo",
+		Params: map[string]interface{}{"token": "radar_secret"},
+	}}
+	_, err = authenticate(ctx, unscoped, next)
+	if errWrap.Cause(err) != account.ErrAPIKeyScope {
+		t.Errorf("Expected %s, Got %v", account.ErrAPIKeyScope, err)
+	}
+
+	scoped.Params["token"] = "radar_wrong"
+	_, err = authenticate(ctx, scoped, next)
+	if err != account.ErrAPIKeyInvalid {
+		t.Errorf("Expected %s, Got %v", account.ErrAPIKeyInvalid, err)
+	}
+}
+
+// exemptUseCase is an use case exempt of the two-factor authentication.
+type exemptUseCase struct {
+	echoUseCase
+}
+
+func (uc *exemptUseCase) TwoFactorExempt() bool {
+	return true
+}
+
+func TestRequireTwoFactor(t *testing.T) {
+	SetDatastore(datastore.New())
+	defer SetDatastore(datastore.New())
+
+	ctx := context.Background()
+	ds := Datastore()
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	acc, _ := ds.GetAccountByID(ctx, id)
+	ctx = WithAccount(ctx, acc)
+	uc := &echoUseCase{MockUseCase{Name: "echo", Params: map[string]interface{}{}}}
+	next := func(ctx context.Context, uc UseCase) (ResultPrinter, error) {
+		return uc.Run(ctx)
+	}
+
+	ds.SetPolicy(ctx, datastore.Policy{TwoFactor: datastore.TwoFactorAdmins})
+	_, err = requireTwoFactor(ctx, uc, next)
+	if err != nil {
+		t.Errorf("Unexpected error running as member: %s", err)
+	}
+
+	ds.SetAdmin(ctx, id, true)
+	acc, _ = ds.GetAccountByID(ctx, id)
+	ctx = WithAccount(ctx, acc)
+	_, err = requireTwoFactor(ctx, uc, next)
+	if err != account.ErrTwoFactorRequired {
+		t.Errorf("Expected %s, Got %v", account.ErrTwoFactorRequired, err)
+	}
+
+	_, err = requireTwoFactor(ctx, &exemptUseCase{*uc}, next)
+	if err != nil {
+		t.Errorf("Unexpected error running an exempt use case: %s", err)
+	}
+
+	_, err = requireTwoFactor(WithTrusted(ctx), uc, next)
+	if err != nil {
+		t.Errorf("Unexpected error running a trusted use case: %s", err)
+	}
+
+	ds.SetPolicy(ctx, datastore.Policy{TwoFactor: datastore.TwoFactorOptional})
+	_, err = requireTwoFactor(ctx, uc, next)
+	if err != nil {
+		t.Errorf("Unexpected error with the two-factor optional: %s", err)
+	}
+}
+
+// deactivateUseCase is an audited use case deactivating the account of the
+// session.
+type deactivateUseCase struct {
+	MockUseCase
+}
+
+func (uc *deactivateUseCase) Run(ctx context.Context) (ResultPrinter, error) {
+	ContextAccount(ctx).Deactivate()
+	return NewMockResult(), nil
+}
+
+// clientUseCase is an audited use case run by a client without session.
+type clientUseCase struct {
+	MockUseCase
+}
+
+func (uc *clientUseCase) Run(ctx context.Context) (ResultPrinter, error) {
+	return NewMockResult(), nil
+}
+
+// eraseUseCase is an audited use case erasing the account of the session.
+type eraseUseCase struct {
+	MockUseCase
+}
+
+func (uc *eraseUseCase) Run(ctx context.Context) (ResultPrinter, error) {
+	return NewMockResult(), Datastore().EraseAccount(ctx, ContextAccount(ctx))
+}
+
+func TestAuditRun(t *testing.T) {
+	SetDatastore(datastore.New())
+	defer SetDatastore(datastore.New())
+
+	ctx := logging.WithRequestID(context.Background(), "audit-1")
+	ds := Datastore()
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	acc, _ := ds.GetAccountByID(ctx, id)
+	acc.Activate()
+	uc := &deactivateUseCase{MockUseCase{Name: "MockDeactivate", Audit: true}}
+	_, err = auditRun(WithAccount(ctx, acc), uc, chain(nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	records, err := ds.AuditRecords(ctx, audit.Filter{})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected 1 audit record, Got %v: %v", records, err)
+	}
+
+	rec := records[0]
+	if rec.Action != "MockDeactivate" || rec.Actor != "ritho" || rec.Target != "ritho" ||
+		rec.TargetID != acc.UUID() || rec.RequestID != "audit-1" {
+		t.Errorf("Unexpected audit record %+v", rec)
+	}
+
+	change := rec.Changes["active"]
+	if len(rec.Changes) != 1 || change.Before != true || change.After != false {
+		t.Errorf("Expected the account deactivation, Got %v", rec.Changes)
+	}
+
+	uc.Audit = false
+	_, err = auditRun(WithAccount(ctx, acc), uc, chain(nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	records, _ = ds.AuditRecords(ctx, audit.Filter{})
+	if len(records) != 1 {
+		t.Errorf("Expected the run not to be audited, Got %v", records)
+	}
+
+	/* The runs of the clients without session are done by the client. */
+	client := &clientUseCase{MockUseCase{Name: "MockClient", Audit: true,
+		Params: map[string]interface{}{"username": "ritho"}}}
+	_, err = auditRun(WithClient(ctx, "scim"), client, chain(nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	records, _ = ds.AuditRecords(ctx, audit.Filter{})
+	if len(records) != 2 || records[0].Actor != "scim" || records[0].Target != "ritho" {
+		t.Errorf("Expected the run audited as done by the client, Got %+v", records)
+	}
+
+	/* Nothing personal of the erased accounts is recorded. */
+	erase := &eraseUseCase{MockUseCase{Name: "MockErase", Audit: true}}
+	_, err = auditRun(WithAccount(ctx, acc), erase, chain(nil))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	records, _ = ds.AuditRecords(ctx, audit.Filter{})
+	rec = records[0]
+	if len(records) != 3 || rec.Actor != audit.Erased || rec.Target != audit.Erased ||
+		rec.TargetID != acc.UUID() || rec.Changes["email"].Before != audit.Erased ||
+		records[1].Target != audit.Erased {
+		t.Errorf("Expected the records of the account erased, Got %+v", records)
+	}
+}
@@ -19,14 +19,17 @@ package casesprovider
 */
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"time"
 
 	errWrap "github.com/pkg/errors"
 
 	"github.com/radar-go/radar/casesprovider/errors"
 	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/i18n"
 )
 
 // MockResult represents a generic user case result.
@@ -56,11 +59,16 @@ func (r *MockResult) Bytes() ([]byte, error) {
 	return json.Marshal(r.Res)
 }
 
+// Localize does nothing, the mock result is not translated.
+func (r *MockResult) Localize(l *i18n.Localizer) {}
+
 // MockUseCase represents a generic use case.
 type MockUseCase struct {
 	Name      string
 	Datastore *datastore.Datastore
 	Params    map[string]interface{}
+	Timeout   time.Duration
+	Audit     bool
 }
 
 // New returns a new MockUseCase object.
@@ -122,7 +130,27 @@ func (uc *MockUseCase) SetDatastore(ds *datastore.Datastore) {
 	uc.Datastore = ds
 }
 
+// Audited returns true if the runs of the use case are recorded in the audit
+// log.
+func (uc *MockUseCase) Audited() bool {
+	return uc.Audit
+}
+
+// GetParams returns the params of the use case.
+func (uc *MockUseCase) GetParams() map[string]interface{} {
+	return uc.Params
+}
+
+// GetTimeout returns the maximum duration of the use case runs.
+func (uc *MockUseCase) GetTimeout() time.Duration {
+	if uc.Timeout <= 0 {
+		return DefaultTimeout
+	}
+
+	return uc.Timeout
+}
+
 // Run executes the use case.
-func (uc *MockUseCase) Run() (ResultPrinter, error) {
+func (uc *MockUseCase) Run(ctx context.Context) (ResultPrinter, error) {
 	return nil, fmt.Errorf("Function Run not implemented")
 }
@@ -0,0 +1,411 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// RegisterRequest represents the params to register an account.
+type RegisterRequest struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// RegisterResponse represents the result of registering an account.
+type RegisterResponse struct {
+	Result string `json:"result"`
+	ID     string `json:"id"`
+}
+
+// LoginRequest represents the params to log in an account.
+type LoginRequest struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+// LoginResponse represents the result of logging in an account. The accounts
+// with two-factor authentication get a challenge instead of the token, to
+// verify it with a code.
+type LoginResponse struct {
+	Result            string `json:"result"`
+	ID                string `json:"id"`
+	Username          string `json:"username"`
+	Name              string `json:"name"`
+	Email             string `json:"email"`
+	Token             string `json:"token"`
+	Challenge         string `json:"challenge"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	RecoveryCodesLeft int    `json:"recovery_codes_left"`
+}
+
+// LoginVerifyRequest represents the params to verify a login challenge with a
+// code of the authenticator app or a recovery code.
+type LoginVerifyRequest struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+// OIDCStartResponse represents the start of a single sign-on: the url where the
+// user logs in the identity provider, and the state and verifier to keep to
+// finish it.
+type OIDCStartResponse struct {
+	Result   string `json:"result"`
+	URL      string `json:"url"`
+	State    string `json:"state"`
+	Verifier string `json:"verifier"`
+}
+
+// OIDCLoginRequest represents the params to finish a single sign-on with the
+// code the identity provider redirected back with.
+type OIDCLoginRequest struct {
+	Code     string `json:"code"`
+	State    string `json:"state"`
+	Verifier string `json:"verifier"`
+}
+
+// LogoutRequest represents the params to log out an account.
+type LogoutRequest struct {
+	Username string `json:"username"`
+	Token    string `json:"token"`
+}
+
+// LogoutResponse represents the result of logging out an account.
+type LogoutResponse struct {
+	Result   string `json:"result"`
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// EditRequest represents the params to edit an account.
+type EditRequest struct {
+	ID       string `json:"id"`
+	Token    string `json:"token"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// SessionRequest represents the params of the operations done with only a
+// session over an account: deactivate, remove, restore and erase.
+type SessionRequest struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// ActivateRequest represents the params to activate an account with the code
+// of the link sent to its email.
+type ActivateRequest struct {
+	Code string `json:"code"`
+}
+
+// ResendRequest represents the params to send a new verification link.
+type ResendRequest struct {
+	Username string `json:"username"`
+}
+
+// PasswordResetRequest represents the params to ask for a link to reset the
+// password of an account.
+type PasswordResetRequest struct {
+	Username string `json:"username"`
+}
+
+// PasswordResetConfirmRequest represents the params to set a new password with
+// the code of the link sent to the email of the account.
+type PasswordResetConfirmRequest struct {
+	Code     string `json:"code"`
+	Password string `json:"password"`
+}
+
+// UnlockRequest represents the params to unlock an account locked out after
+// too many failed logins.
+type UnlockRequest struct {
+	Token    string `json:"token"`
+	Username string `json:"username"`
+}
+
+// UnlockResponse represents the result of unlocking an account.
+type UnlockResponse struct {
+	Result   string `json:"result"`
+	Username string `json:"username"`
+	Locked   bool   `json:"locked"`
+}
+
+// TwoFactorRequest represents the params to manage the two-factor
+// authentication of the account of the session. The enrollment doesn't need
+// the code.
+type TwoFactorRequest struct {
+	Token string `json:"token"`
+	Code  string `json:"code,omitempty"`
+}
+
+// TwoFactorEnrollResponse represents the result of enrolling the two-factor
+// authentication.
+type TwoFactorEnrollResponse struct {
+	Result string `json:"result"`
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// TwoFactorConfirmResponse represents the result of confirming the enrollment
+// of the two-factor authentication.
+type TwoFactorConfirmResponse struct {
+	Result        string   `json:"result"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorPolicyRequest represents the params to choose the accounts that
+// must use the two-factor authentication, optional or admins.
+type TwoFactorPolicyRequest struct {
+	Token   string `json:"token"`
+	Require string `json:"require"`
+}
+
+// TwoFactorPolicyResponse represents the result of changing the two-factor
+// policy.
+type TwoFactorPolicyResponse struct {
+	Result  string `json:"result"`
+	Require string `json:"require"`
+}
+
+// AccountGetRequest represents the params to get the profile of an account
+// by its id or its username.
+type AccountGetRequest struct {
+	Token    string `json:"token"`
+	ID       string `json:"id,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// AccountProfile represents the profile of an account. The private fields are
+// only present for the account itself and the administrators.
+type AccountProfile struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	Email     string `json:"email,omitempty"`
+	Active    *bool  `json:"active,omitempty"`
+	Admin     *bool  `json:"admin,omitempty"`
+	TwoFactor *bool  `json:"two_factor,omitempty"`
+}
+
+// AccountGetResponse represents the profile of an account.
+type AccountGetResponse struct {
+	Account AccountProfile `json:"account"`
+}
+
+// AccountListRequest represents the params to list the accounts, sorted by id,
+// username, name or email, descending if the sort starts with -.
+type AccountListRequest struct {
+	Token   string `json:"token"`
+	Search  string `json:"search,omitempty"`
+	Sort    string `json:"sort,omitempty"`
+	Page    int    `json:"page,omitempty"`
+	PerPage int    `json:"per_page,omitempty"`
+}
+
+// AccountListResponse represents a page of the profiles of the accounts.
+type AccountListResponse struct {
+	Accounts []*AccountProfile `json:"accounts"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	PerPage  int               `json:"per_page"`
+}
+
+// LDAPSyncRequest represents the params to synchronize the accounts with the
+// LDAP directory.
+type LDAPSyncRequest struct {
+	Token string `json:"token"`
+}
+
+// LDAPSyncResponse represents the result of synchronizing the accounts with the
+// LDAP directory: the number of accounts created, linked to an existing one,
+// updated and deactivated, and the users of the directory that couldn't be
+// imported.
+type LDAPSyncResponse struct {
+	Result      string   `json:"result"`
+	Created     int      `json:"created"`
+	Linked      int      `json:"linked"`
+	Updated     int      `json:"updated"`
+	Deactivated int      `json:"deactivated"`
+	Conflicts   []string `json:"conflicts"`
+}
+
+// ResultResponse represents the result of an operation without more data.
+type ResultResponse struct {
+	Result string `json:"result"`
+}
+
+// AccountResponse represents the result of an operation over an account.
+type AccountResponse struct {
+	Result string `json:"result"`
+	ID     string `json:"id"`
+}
+
+// Register registers a new account.
+func (c *Client) Register(req *RegisterRequest) (*RegisterResponse, error) {
+	res := &RegisterResponse{}
+	return res, c.do("POST", "/account/register", req, res)
+}
+
+// Login logs in an account, the token of the response identifies the session
+// in the rest of the calls.
+func (c *Client) Login(req *LoginRequest) (*LoginResponse, error) {
+	res := &LoginResponse{}
+	return res, c.do("POST", "/account/login", req, res)
+}
+
+// VerifyLogin completes the login of an account with two-factor
+// authentication, the token of the response identifies the session.
+func (c *Client) VerifyLogin(req *LoginVerifyRequest) (*LoginResponse, error) {
+	res := &LoginResponse{}
+	return res, c.do("POST", "/account/login/verify", req, res)
+}
+
+// StartOIDC starts a single sign-on with the identity provider of the radar.
+func (c *Client) StartOIDC() (*OIDCStartResponse, error) {
+	res := &OIDCStartResponse{}
+	return res, c.do("POST", "/account/oidc/start", struct{}{}, res)
+}
+
+// OIDCLogin finishes a single sign-on, the token of the response identifies
+// the session unless the account needs to verify a challenge.
+func (c *Client) OIDCLogin(req *OIDCLoginRequest) (*LoginResponse, error) {
+	res := &LoginResponse{}
+	return res, c.do("POST", "/account/oidc/login", req, res)
+}
+
+// Logout logs out an account.
+func (c *Client) Logout(req *LogoutRequest) (*LogoutResponse, error) {
+	res := &LogoutResponse{}
+	return res, c.do("POST", "/account/logout", req, res)
+}
+
+// Edit updates the data of an account.
+func (c *Client) Edit(req *EditRequest) (*AccountResponse, error) {
+	res := &AccountResponse{}
+	return res, c.do("POST", "/account/edit", req, res)
+}
+
+// Activate activates an account with the code sent to its email.
+func (c *Client) Activate(req *ActivateRequest) (*AccountResponse, error) {
+	res := &AccountResponse{}
+	return res, c.do("POST", "/account/activate", req, res)
+}
+
+// ResendVerification sends a new verification link to the email of an account
+// pending activation.
+func (c *Client) ResendVerification(req *ResendRequest) (*ResultResponse, error) {
+	res := &ResultResponse{}
+	return res, c.do("POST", "/account/verification/resend", req, res)
+}
+
+// RequestPasswordReset sends a link to reset the password to the email of an
+// account.
+func (c *Client) RequestPasswordReset(req *PasswordResetRequest) (*ResultResponse, error) {
+	res := &ResultResponse{}
+	return res, c.do("POST", "/account/password/reset", req, res)
+}
+
+// ConfirmPasswordReset sets a new password with the code sent to the email of
+// the account, closing all its sessions.
+func (c *Client) ConfirmPasswordReset(req *PasswordResetConfirmRequest) (*AccountResponse, error) {
+	res := &AccountResponse{}
+	return res, c.do("POST", "/account/password/reset/confirm", req, res)
+}
+
+// Unlock unlocks an account locked out after too many failed logins, it needs
+// the session of an administrator.
+func (c *Client) Unlock(req *UnlockRequest) (*UnlockResponse, error) {
+	res := &UnlockResponse{}
+	return res, c.do("POST", "/account/unlock", req, res)
+}
+
+// Restore restores a removed account not purged yet, it needs the session of
+// an administrator and the id of the account removed.
+func (c *Client) Restore(req *SessionRequest) (*AccountResponse, error) {
+	res := &AccountResponse{}
+	return res, c.do("POST", "/account/restore", req, res)
+}
+
+// EnrollTwoFactor generates a new secret for the two-factor authentication of
+// the account, to add it to the authenticator app.
+func (c *Client) EnrollTwoFactor(req *TwoFactorRequest) (*TwoFactorEnrollResponse, error) {
+	res := &TwoFactorEnrollResponse{}
+	return res, c.do("POST", "/account/2fa/enroll", req, res)
+}
+
+// ConfirmTwoFactor enables the two-factor authentication with a code of the
+// enrolled secret, returning the recovery codes.
+func (c *Client) ConfirmTwoFactor(req *TwoFactorRequest) (*TwoFactorConfirmResponse, error) {
+	res := &TwoFactorConfirmResponse{}
+	return res, c.do("POST", "/account/2fa/confirm", req, res)
+}
+
+// DisableTwoFactor removes the two-factor authentication of the account.
+func (c *Client) DisableTwoFactor(req *TwoFactorRequest) (*ResultResponse, error) {
+	res := &ResultResponse{}
+	return res, c.do("POST", "/account/2fa/disable", req, res)
+}
+
+// SetTwoFactorPolicy chooses the accounts that must use the two-factor
+// authentication, it needs the session of an administrator.
+func (c *Client) SetTwoFactorPolicy(req *TwoFactorPolicyRequest) (*TwoFactorPolicyResponse, error) {
+	res := &TwoFactorPolicyResponse{}
+	return res, c.do("POST", "/account/2fa/policy", req, res)
+}
+
+// Deactivate deactivates an account.
+func (c *Client) Deactivate(req *SessionRequest) (*AccountResponse, error) {
+	res := &AccountResponse{}
+	return res, c.do("POST", "/account/deactivate", req, res)
+}
+
+// Remove removes an account.
+func (c *Client) Remove(req *SessionRequest) (*AccountResponse, error) {
+	res := &AccountResponse{}
+	return res, c.do("POST", "/account/remove", req, res)
+}
+
+// Erase erases an account and its personal data for good, an administrator
+// can erase any account.
+func (c *Client) Erase(req *SessionRequest) (*AccountResponse, error) {
+	res := &AccountResponse{}
+	return res, c.do("POST", "/account/erase", req, res)
+}
+
+// GetAccount returns the profile of an account, only the administrators can
+// get the inactive ones.
+func (c *Client) GetAccount(req *AccountGetRequest) (*AccountGetResponse, error) {
+	res := &AccountGetResponse{}
+	return res, c.do("POST", "/account/get", req, res)
+}
+
+// ListAccounts returns a page of the profiles of the accounts matching the
+// search.
+func (c *Client) ListAccounts(req *AccountListRequest) (*AccountListResponse, error) {
+	res := &AccountListResponse{}
+	return res, c.do("POST", "/account/list", req, res)
+}
+
+// SyncLDAP synchronizes the accounts with the LDAP directory, it needs the
+// session of an administrator.
+func (c *Client) SyncLDAP(req *LDAPSyncRequest) (*LDAPSyncResponse, error) {
+	res := &LDAPSyncResponse{}
+	return res, c.do("POST", "/account/ldap/sync", req, res)
+}
@@ -0,0 +1,88 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import "time"
+
+// APIKeyCreateRequest represents the params to create an API key for the
+// account of the session, the scopes are separated by commas and the expiry
+// date is in RFC 3339 format, empty for the default lifetime.
+type APIKeyCreateRequest struct {
+	Token   string `json:"token"`
+	Name    string `json:"name"`
+	Scopes  string `json:"scopes"`
+	Expires string `json:"expires,omitempty"`
+}
+
+// APIKeyCreateResponse represents the result of creating an API key, the key
+// is only shown once.
+type APIKeyCreateResponse struct {
+	Result  string    `json:"result"`
+	ID      string    `json:"id"`
+	Key     string    `json:"key"`
+	Name    string    `json:"name"`
+	Scopes  []string  `json:"scopes"`
+	Expires time.Time `json:"expires"`
+}
+
+// APIKeyRequest represents the params to query or revoke the API keys of the
+// account of the session.
+type APIKeyRequest struct {
+	Token string `json:"token"`
+	ID    string `json:"id,omitempty"`
+}
+
+// APIKey represents an API key of an account, without the key.
+type APIKey struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Scopes   []string  `json:"scopes"`
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// APIKeyListResponse represents the API keys of an account.
+type APIKeyListResponse struct {
+	Keys []*APIKey `json:"keys"`
+}
+
+// APIKeyRevokeResponse represents the result of revoking an API key.
+type APIKeyRevokeResponse struct {
+	Result string `json:"result"`
+	ID     string `json:"id"`
+}
+
+// CreateAPIKey creates an API key for the account of the session.
+func (c *Client) CreateAPIKey(req *APIKeyCreateRequest) (*APIKeyCreateResponse, error) {
+	res := &APIKeyCreateResponse{}
+	return res, c.do("POST", "/account/apikey/create", req, res)
+}
+
+// ListAPIKeys returns the API keys of the account of the session.
+func (c *Client) ListAPIKeys(req *APIKeyRequest) (*APIKeyListResponse, error) {
+	res := &APIKeyListResponse{}
+	return res, c.do("POST", "/account/apikey/list", req, res)
+}
+
+// RevokeAPIKey revokes an API key of the account of the session.
+func (c *Client) RevokeAPIKey(req *APIKeyRequest) (*APIKeyRevokeResponse, error) {
+	res := &APIKeyRevokeResponse{}
+	return res, c.do("POST", "/account/apikey/revoke", req, res)
+}
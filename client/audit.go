@@ -0,0 +1,66 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import "time"
+
+// AuditRequest represents the filters to query the audit log, the empty ones
+// match any record.
+type AuditRequest struct {
+	Token   string `json:"token"`
+	Actor   string `json:"actor,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Action  string `json:"action,omitempty"`
+	Since   string `json:"since,omitempty"`
+	Until   string `json:"until,omitempty"`
+	Page    int    `json:"page,omitempty"`
+	PerPage int    `json:"per_page,omitempty"`
+}
+
+// AuditChange represents the values of a field before and after an operation.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditRecord represents an operation recorded in the audit log.
+type AuditRecord struct {
+	ID        int                    `json:"id"`
+	Time      time.Time              `json:"time"`
+	Action    string                 `json:"action"`
+	Actor     string                 `json:"actor"`
+	Target    string                 `json:"target"`
+	TargetID  string                 `json:"target_id"`
+	RequestID string                 `json:"request_id"`
+	Changes   map[string]AuditChange `json:"changes"`
+}
+
+// AuditResponse represents the records of the audit log, the newest first.
+type AuditResponse struct {
+	Records []*AuditRecord `json:"records"`
+	Total   int            `json:"total"`
+	Page    int            `json:"page"`
+	PerPage int            `json:"per_page"`
+}
+
+// Audit queries the audit log, it needs the session of an administrator.
+func (c *Client) Audit(req *AuditRequest) (*AuditResponse, error) {
+	res := &AuditResponse{}
+	return res, c.do("POST", "/audit/list", req, res)
+}
@@ -0,0 +1,70 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// Backup writes to w a backup archive of the datastore streamed by the API, it
+// needs the session of an administrator or an API key with the admin scope.
+// The sessions are only included in the archive if asked.
+func (c *Client) Backup(token string, sessions bool, w io.Writer) error {
+	path := "/backup"
+	if sessions {
+		path += "?sessions=true"
+	}
+
+	httpReq, err := http.NewRequest("GET", c.URL+path, nil)
+	if err != nil {
+		return errors.Wrap(err, "Error creating the request")
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpRes, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return errors.Wrap(err, "Error calling the API")
+	}
+	defer httpRes.Body.Close()
+
+	if httpRes.StatusCode != http.StatusOK {
+		resBody, err := ioutil.ReadAll(httpRes.Body)
+		if err != nil {
+			return errors.Wrap(err, "Error reading the API response")
+		}
+
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+
+		if json.Unmarshal(resBody, &apiErr) != nil || apiErr.Error == "" {
+			apiErr.Error = string(resBody)
+		}
+
+		return &Error{StatusCode: httpRes.StatusCode, Message: apiErr.Error}
+	}
+
+	_, err = io.Copy(w, httpRes.Body)
+	return errors.Wrap(err, "Error reading the backup archive")
+}
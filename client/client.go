@@ -0,0 +1,130 @@
+// Package client implements a client library to access the Radar API.
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Error represents an error returned by the Radar API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error returns the error message.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+}
+
+// Client to access the Radar API.
+type Client struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+// New creates and returns a new Client object for the API listening in url.
+func New(url string) *Client {
+	return &Client{
+		URL: strings.TrimRight(url, "/"),
+		HTTPClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Healthcheck checks that the API is up and running.
+func (c *Client) Healthcheck() error {
+	var res struct {
+		Status string `json:"status"`
+	}
+
+	err := c.do("GET", "/healthcheck", nil, &res)
+	if err != nil {
+		return err
+	}
+
+	if res.Status != "ok" {
+		return fmt.Errorf("Unexpected API status %s", res.Status)
+	}
+
+	return nil
+}
+
+// do calls to the API endpoint path encoding req as the request body and
+// decoding the response into res.
+func (c *Client) do(method, path string, req, res interface{}) error {
+	var body []byte
+	var err error
+
+	if req != nil {
+		body, err = json.Marshal(req)
+		if err != nil {
+			return errors.Wrap(err, "Error encoding the request")
+		}
+	}
+
+	httpReq, err := http.NewRequest(method, c.URL+path, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Error creating the request")
+	}
+
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+
+	httpRes, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return errors.Wrap(err, "Error calling the API")
+	}
+	defer httpRes.Body.Close()
+
+	resBody, err := ioutil.ReadAll(httpRes.Body)
+	if err != nil {
+		return errors.Wrap(err, "Error reading the API response")
+	}
+
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+
+	/* The use cases can report an error with a successful status code, so
+	the error is checked in any case. */
+	jsonErr := json.Unmarshal(resBody, &apiErr)
+	if httpRes.StatusCode != http.StatusOK {
+		if jsonErr != nil || apiErr.Error == "" {
+			apiErr.Error = string(resBody)
+		}
+
+		return &Error{StatusCode: httpRes.StatusCode, Message: apiErr.Error}
+	} else if jsonErr == nil && apiErr.Error != "" {
+		return &Error{StatusCode: httpRes.StatusCode, Message: apiErr.Error}
+	}
+
+	return errors.Wrap(json.Unmarshal(resBody, res), "Error decoding the API response")
+}
@@ -0,0 +1,585 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/ldap/ldaptest"
+	"github.com/radar-go/radar/mailer"
+	"github.com/radar-go/radar/oidc/oidctest"
+	"github.com/radar-go/radar/totp"
+	"github.com/radar-go/radar/ui/api/controller"
+)
+
+// newTestClient returns a client connected to an in memory Radar API.
+func newTestClient(t *testing.T) (*Client, func()) {
+	t.Helper()
+
+	ln := fasthttputil.NewInmemoryListener()
+	server := &fasthttp.Server{
+		Handler: controller.New().Router.Handler,
+	}
+
+	go server.Serve(ln)
+
+	c := New("http://radar/")
+	c.HTTPClient.Transport = &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}
+
+	return c, func() { ln.Close() }
+}
+
+func TestClient(t *testing.T) {
+	c, stop := newTestClient(t)
+	defer stop()
+
+	emails := &bytes.Buffer{}
+	casesprovider.SetMailer(mailer.NewWriter(emails, "radar@localhost"))
+	defer casesprovider.SetMailer(&mailer.Log{})
+
+	err := c.Healthcheck()
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	reg, err := c.Register(&RegisterRequest{
+		Username: "clientuser",
+		Name:     "Client",
+		Email:    "palvarez@ritho.net",
+		Password: "ritho",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if reg.ID == "" || reg.Result != "Account registered successfully, follow the link sent to your email to activate it" {
+		t.Errorf("Unexpected register response %+v", reg)
+	}
+
+	_, err = c.Login(&LoginRequest{Login: "clientuser", Password: "wrong"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	_, err = c.Login(&LoginRequest{Login: "clientuser", Password: "ritho"})
+	if apiErr, ok := err.(*Error); !ok || !strings.Contains(apiErr.Message, "The account is not active") {
+		t.Errorf("Expected the inactive account refused, Got %v", err)
+	}
+
+	emails.Reset()
+	resend, err := c.ResendVerification(&ResendRequest{Username: "clientuser"})
+	if err != nil || resend.Result == "" {
+		t.Errorf("Unexpected resend response %+v: %v", resend, err)
+	}
+
+	match := regexp.MustCompile(`code=(\S+)`).FindStringSubmatch(emails.String())
+	if match == nil {
+		t.Fatalf("Expected a verification link, Got %s", emails)
+	}
+
+	code, _ := url.QueryUnescape(match[1])
+	res, err := c.Activate(&ActivateRequest{Code: code})
+	if err != nil || res.ID != reg.ID {
+		t.Errorf("Unexpected activate response %+v: %v", res, err)
+	}
+
+	login, err := c.Login(&LoginRequest{Login: "clientuser", Password: "ritho"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if login.ID != reg.ID || login.Token == "" || login.Username != "clientuser" {
+		t.Errorf("Unexpected login response %+v", login)
+	}
+
+	session := &SessionRequest{ID: login.ID, Token: login.Token}
+
+	res, err = c.Edit(&EditRequest{
+		ID:       login.ID,
+		Token:    login.Token,
+		Username: "clientuser",
+		Name:     "Client Edited",
+		Email:    "palvarez@ritho.net",
+		Password: "121212",
+	})
+	if err != nil || res.Result != "Account data updated successfully" {
+		t.Errorf("Unexpected edit response %+v: %v", res, err)
+	}
+
+	_, err = c.Audit(&AuditRequest{Token: login.Token})
+	if apiErr, ok := err.(*Error); !ok || apiErr.Message != "Administration privileges required" {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	acc, _ := casesprovider.Datastore().GetAccountByUUID(context.Background(), reg.ID)
+	casesprovider.Datastore().SetAdmin(context.Background(), acc.ID(), true)
+	audit, err := c.Audit(&AuditRequest{Token: login.Token, Action: "AccountEdit"})
+	if err != nil || len(audit.Records) != 1 {
+		t.Fatalf("Unexpected audit response %+v: %v", audit, err)
+	}
+
+	change := audit.Records[0].Changes["name"]
+	if audit.Records[0].Actor != "clientuser" || change.Before != "Client" ||
+		change.After != "Client Edited" {
+		t.Errorf("Unexpected audit record %+v", audit.Records[0])
+	}
+
+	audit, err = c.Audit(&AuditRequest{Token: login.Token, Page: 2, PerPage: 1})
+	if err != nil || len(audit.Records) != 1 || audit.Page != 2 || audit.PerPage != 1 || audit.Total < 2 {
+		t.Errorf("Unexpected audit page %+v: %v", audit, err)
+	}
+
+	unlock, err := c.Unlock(&UnlockRequest{Token: login.Token, Username: "clientuser"})
+	if err != nil || unlock.Username != "clientuser" || unlock.Locked {
+		t.Errorf("Unexpected unlock response %+v: %v", unlock, err)
+	}
+
+	_, err = c.Restore(&SessionRequest{ID: reg.ID, Token: login.Token})
+	if apiErr, ok := err.(*Error); !ok || !strings.Contains(apiErr.Message, "The account is not removed") {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	enroll, err := c.EnrollTwoFactor(&TwoFactorRequest{Token: login.Token})
+	if err != nil || !strings.HasPrefix(enroll.URI, "otpauth://totp/") {
+		t.Fatalf("Unexpected enroll response %+v: %v", enroll, err)
+	}
+
+	code, _ = totp.Code(enroll.Secret, time.Now().Add(-totp.Period))
+	confirm, err := c.ConfirmTwoFactor(&TwoFactorRequest{Token: login.Token, Code: code})
+	if err != nil || len(confirm.RecoveryCodes) != 10 {
+		t.Errorf("Unexpected confirm response %+v: %v", confirm, err)
+	}
+
+	policy, err := c.SetTwoFactorPolicy(&TwoFactorPolicyRequest{Token: login.Token, Require: "optional"})
+	if err != nil || policy.Require != "optional" {
+		t.Errorf("Unexpected policy response %+v: %v", policy, err)
+	}
+
+	logout, err := c.Logout(&LogoutRequest{Username: "clientuser", Token: login.Token})
+	if err != nil || logout.ID != reg.ID {
+		t.Errorf("Unexpected logout response %+v: %v", logout, err)
+	}
+
+	_, err = c.Deactivate(session)
+	if err == nil {
+		t.Error("Expected error deactivating without session")
+	}
+
+	login, err = c.Login(&LoginRequest{Login: "clientuser", Password: "121212"})
+	if err != nil || login.Token != "" || login.Challenge == "" {
+		t.Fatalf("Expected a login challenge, Got %+v: %v", login, err)
+	}
+
+	login, err = c.VerifyLogin(&LoginVerifyRequest{
+		Challenge: login.Challenge,
+		Code:      confirm.RecoveryCodes[0],
+	})
+	if err != nil || login.Token == "" || login.RecoveryCodesLeft != 9 {
+		t.Fatalf("Unexpected verify response %+v: %v", login, err)
+	}
+
+	code, _ = totp.Code(enroll.Secret, time.Now())
+	disable, err := c.DisableTwoFactor(&TwoFactorRequest{Token: login.Token, Code: code})
+	if err != nil || disable.Result != "Two-factor authentication disabled" {
+		t.Errorf("Unexpected disable response %+v: %v", disable, err)
+	}
+
+	emails.Reset()
+	reset, err := c.RequestPasswordReset(&PasswordResetRequest{Username: "clientuser"})
+	if err != nil || reset.Result == "" {
+		t.Errorf("Unexpected password reset response %+v: %v", reset, err)
+	}
+
+	match = regexp.MustCompile(`code=(\S+)`).FindStringSubmatch(emails.String())
+	if match == nil {
+		t.Fatalf("Expected a password reset link, Got %s", emails)
+	}
+
+	code, _ = url.QueryUnescape(match[1])
+	res, err = c.ConfirmPasswordReset(&PasswordResetConfirmRequest{Code: code, Password: "newpassword"})
+	if err != nil || res.ID != reg.ID {
+		t.Errorf("Unexpected password reset confirm response %+v: %v", res, err)
+	}
+
+	_, err = c.Deactivate(&SessionRequest{ID: login.ID, Token: login.Token})
+	if err == nil {
+		t.Error("Expected the session to be closed after the password reset")
+	}
+
+	login, err = c.Login(&LoginRequest{Login: "clientuser", Password: "newpassword"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	export, err := c.Export(&ExportRequest{Token: login.Token})
+	if err != nil || export.Account.ID != reg.ID || export.Account.Name != "Client Edited" ||
+		len(export.Sessions) != 1 || len(export.Audit) == 0 {
+		t.Errorf("Unexpected export response %+v: %v", export, err)
+	}
+
+	_, err = c.Erase(&SessionRequest{ID: "00000000-0000-0000-0000-000000000001", Token: login.Token})
+	if apiErr, ok := err.(*Error); !ok || !strings.Contains(apiErr.Message, "Account doesn't exists") {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	session = &SessionRequest{ID: login.ID, Token: login.Token}
+	res, err = c.Remove(session)
+	if err != nil || res.Result != "Account removed successfully" {
+		t.Errorf("Unexpected remove response %+v: %v", res, err)
+	}
+}
+
+func TestClientOIDC(t *testing.T) {
+	c, stop := newTestClient(t)
+	defer stop()
+
+	_, err := c.StartOIDC()
+	if err == nil || !strings.Contains(err.Error(), "Single sign-on is not configured") {
+		t.Errorf("Expected the single sign-on not configured, Got %v", err)
+	}
+
+	s := oidctest.New(oidctest.User{
+		Subject:       "client",
+		Email:         "clientsso@ritho.net",
+		EmailVerified: true,
+		Username:      "clientsso",
+	})
+	defer s.Close()
+	casesprovider.SetOIDC(s.Provider("http://localhost:10080" + casesprovider.OIDCCallbackPath))
+	defer casesprovider.SetOIDC(nil)
+
+	start, err := c.StartOIDC()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	code, state, err := s.Authorize(start.URL)
+	if err != nil || state != start.State {
+		t.Fatalf("Unexpected authorization %s %s: %v", code, state, err)
+	}
+
+	login, err := c.OIDCLogin(&OIDCLoginRequest{Code: code, State: state, Verifier: start.Verifier})
+	if err != nil || login.Username != "clientsso" || login.Token == "" {
+		t.Errorf("Unexpected login response %+v: %v", login, err)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	err := (&Error{StatusCode: 400, Message: "Bad request"}).Error()
+	if err != "400: Bad request" {
+		t.Errorf("Expected '400: Bad request', Got '%s'", err)
+	}
+
+	c := New("http://127.0.0.1:1")
+	err2 := c.Healthcheck()
+	if err2 == nil {
+		t.Error("Expected error calling an API not listening")
+	}
+}
+
+func TestClientRadar(t *testing.T) {
+	c, stop := newTestClient(t)
+	defer stop()
+
+	_, err := c.Register(&RegisterRequest{
+		Username: "radaruser",
+		Name:     "Radar",
+		Email:    "radar@ritho.net",
+		Password: "ritho",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	member, err := c.Member(&MemberRequest{Username: "radaruser"})
+	if err != nil || member.Name != "Radar" || len(member.Technologies) != 0 {
+		t.Errorf("Unexpected member response %+v: %v", member, err)
+	}
+
+	radar, err := c.Radar(&RadarRequest{Flavor: "projects"})
+	if err != nil || radar.Flavor != "projects" || len(radar.Blips) != 0 {
+		t.Errorf("Unexpected radar response %+v: %v", radar, err)
+	}
+
+	_, err = c.Radar(&RadarRequest{Flavor: "unknown"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	_, err = c.Technology(&TechnologyRequest{Name: "unknown"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	err = c.Backup("unknown", false, &bytes.Buffer{})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	ctx := context.Background()
+	ds := casesprovider.Datastore()
+	acc, err := ds.GetAccountByUsername(ctx, "radaruser")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	token := "00000000-0000-0000-0000-0000000000aa"
+	ds.ActivateAccount(ctx, acc.ID())
+	ds.SetAdmin(ctx, acc.ID(), true)
+	err = ds.AddSession(ctx, token, "radaruser")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	archive := &bytes.Buffer{}
+	err = c.Backup(token, true, archive)
+	if err != nil || !bytes.HasPrefix(archive.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Errorf("Expected a gzipped archive, Got %q: %v", archive.Bytes(), err)
+	}
+
+	_, err = c.ExportEdition(&EditionExportRequest{Edition: "unknown"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	_, err = c.ImportEdition(&EditionImportRequest{
+		Token:   "unknown",
+		Edition: "2018",
+		Data:    "name,ring,quadrant,isNew,description\n",
+	})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+}
+
+func TestClientAPIKeys(t *testing.T) {
+	c, stop := newTestClient(t)
+	defer stop()
+
+	_, err := c.Register(&RegisterRequest{
+		Username: "apikeyuser",
+		Name:     "API Key",
+		Email:    "apikey@ritho.net",
+		Password: "ritho",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	ds := casesprovider.Datastore()
+	acc, err := ds.GetAccountByUsername(ctx, "apikeyuser")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	token := "00000000-0000-0000-0000-0000000000ab"
+	ds.ActivateAccount(ctx, acc.ID())
+	err = ds.AddSession(ctx, token, "apikeyuser")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = c.CreateAPIKey(&APIKeyCreateRequest{Token: token, Name: "ci", Scopes: "unknown"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	key, err := c.CreateAPIKey(&APIKeyCreateRequest{Token: token, Name: "ci", Scopes: "read"})
+	if err != nil || key.ID == "" || key.Key == "" || key.Name != "ci" ||
+		len(key.Scopes) != 1 || key.Scopes[0] != "read" || key.Expires.IsZero() {
+		t.Fatalf("Unexpected create response %+v: %v", key, err)
+	}
+
+	keys, err := c.ListAPIKeys(&APIKeyRequest{Token: token})
+	if err != nil || len(keys.Keys) != 1 || keys.Keys[0].ID != key.ID || keys.Keys[0].Name != "ci" {
+		t.Errorf("Unexpected list response %+v: %v", keys, err)
+	}
+
+	_, err = c.CreateAPIKey(&APIKeyCreateRequest{Token: key.Key, Name: "other", Scopes: "read"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected the API key refused, Got %v", err)
+	}
+
+	revoke, err := c.RevokeAPIKey(&APIKeyRequest{Token: token, ID: key.ID})
+	if err != nil || revoke.ID != key.ID || revoke.Result != "API key revoked successfully" {
+		t.Errorf("Unexpected revoke response %+v: %v", revoke, err)
+	}
+
+	keys, err = c.ListAPIKeys(&APIKeyRequest{Token: token})
+	if err != nil || len(keys.Keys) != 0 {
+		t.Errorf("Unexpected list response %+v: %v", keys, err)
+	}
+
+	_, err = c.RevokeAPIKey(&APIKeyRequest{Token: token, ID: key.ID})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+}
+
+func TestClientAccounts(t *testing.T) {
+	c, stop := newTestClient(t)
+	defer stop()
+
+	ctx := context.Background()
+	ds := casesprovider.Datastore()
+	for _, username := range []string{"listuser", "listother"} {
+		reg, err := c.Register(&RegisterRequest{
+			Username: username,
+			Name:     "List " + username,
+			Email:    username + "@ritho.net",
+			Password: "ritho",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		acc, _ := ds.GetAccountByUUID(ctx, reg.ID)
+		ds.ActivateAccount(ctx, acc.ID())
+	}
+
+	token := "00000000-0000-0000-0000-0000000000ac"
+	err := ds.AddSession(ctx, token, "listuser")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	get, err := c.GetAccount(&AccountGetRequest{Token: token, Username: "listuser"})
+	if err != nil || get.Account.Username != "listuser" || get.Account.Email != "listuser@ritho.net" ||
+		get.Account.Active == nil || !*get.Account.Active {
+		t.Errorf("Unexpected get response %+v: %v", get, err)
+	}
+
+	other, err := c.GetAccount(&AccountGetRequest{Token: token, Username: "listother"})
+	if err != nil || other.Account.Name != "List listother" || other.Account.Email != "" ||
+		other.Account.Active != nil {
+		t.Errorf("Unexpected get response %+v: %v", other, err)
+	}
+
+	get, err = c.GetAccount(&AccountGetRequest{Token: token, ID: other.Account.ID})
+	if err != nil || get.Account.Username != "listother" {
+		t.Errorf("Unexpected get response %+v: %v", get, err)
+	}
+
+	_, err = c.GetAccount(&AccountGetRequest{Token: token, Username: "unknown"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.StatusCode != 400 {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	list, err := c.ListAccounts(&AccountListRequest{Token: token, Search: "list", Sort: "-username"})
+	if err != nil || list.Total != 2 || len(list.Accounts) != 2 || list.Page != 1 ||
+		list.Accounts[0].Username != "listuser" || list.Accounts[1].Username != "listother" {
+		t.Errorf("Unexpected list response %+v: %v", list, err)
+	}
+
+	list, err = c.ListAccounts(&AccountListRequest{Token: token, Search: "list", Page: 2, PerPage: 1})
+	if err != nil || list.Total != 2 || len(list.Accounts) != 1 || list.Page != 2 || list.PerPage != 1 {
+		t.Errorf("Unexpected list page %+v: %v", list, err)
+	}
+
+	_, err = c.ListAccounts(&AccountListRequest{Token: token, Sort: "email"})
+	if apiErr, ok := err.(*Error); !ok || apiErr.Message != "Administration privileges required" {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	key, err := c.CreateAPIKey(&APIKeyCreateRequest{Token: token, Name: "reader", Scopes: "read"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	list, err = c.ListAccounts(&AccountListRequest{Token: key.Key, Search: "listother"})
+	if err != nil || list.Total != 1 || list.Accounts[0].Username != "listother" {
+		t.Errorf("Unexpected list response with the API key %+v: %v", list, err)
+	}
+}
+
+func TestClientLDAPSync(t *testing.T) {
+	c, stop := newTestClient(t)
+	defer stop()
+
+	_, err := c.Register(&RegisterRequest{
+		Username: "ldapadmin",
+		Name:     "LDAP Admin",
+		Email:    "ldapadmin@ritho.net",
+		Password: "ritho",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	ds := casesprovider.Datastore()
+	acc, err := ds.GetAccountByUsername(ctx, "ldapadmin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	token := "00000000-0000-0000-0000-0000000000ad"
+	ds.ActivateAccount(ctx, acc.ID())
+	err = ds.AddSession(ctx, token, "ldapadmin")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = c.SyncLDAP(&LDAPSyncRequest{Token: token})
+	if apiErr, ok := err.(*Error); !ok || apiErr.Message != "Administration privileges required" {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	ds.SetAdmin(ctx, acc.ID(), true)
+	_, err = c.SyncLDAP(&LDAPSyncRequest{Token: token})
+	if apiErr, ok := err.(*Error); !ok || !strings.Contains(apiErr.Message, "The directory is not configured") {
+		t.Errorf("Expected API error, Got %v", err)
+	}
+
+	s := ldaptest.NewDirectory()
+	defer s.Close()
+	casesprovider.SetLDAP(s.Directory())
+	defer casesprovider.SetLDAP(nil)
+
+	s.AddUser("ldapclient", "LDAP Client", "ldapclient@ritho.net", "Developer", "secret")
+	s.AddUser("ldapadmin", "LDAP Admin", "other@ritho.net", "", "secret")
+	sync, err := c.SyncLDAP(&LDAPSyncRequest{Token: token})
+	if err != nil || sync.Created != 1 || len(sync.Conflicts) != 1 || sync.Conflicts[0] != "ldapadmin" ||
+		sync.Result != "Accounts synchronized with the directory successfully" {
+		t.Errorf("Unexpected sync response %+v: %v", sync, err)
+	}
+
+	s.RemoveUser("ldapclient")
+	sync, err = c.SyncLDAP(&LDAPSyncRequest{Token: token})
+	if err != nil || sync.Created != 0 || sync.Deactivated != 1 {
+		t.Errorf("Unexpected sync response %+v: %v", sync, err)
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// EditionImportRequest represents the params to import a radar edition in the
+// Build Your Own Radar format, csv or json.
+type EditionImportRequest struct {
+	Token   string `json:"token"`
+	Edition string `json:"edition"`
+	Format  string `json:"format,omitempty"`
+	Data    string `json:"data"`
+}
+
+// EditionRowError represents a row of the radar that couldn't be imported.
+type EditionRowError struct {
+	Row   int    `json:"row"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// EditionImportResponse represents the result of importing a radar edition.
+type EditionImportResponse struct {
+	Result  string             `json:"result"`
+	Edition string             `json:"edition"`
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Errors  []*EditionRowError `json:"errors"`
+}
+
+// EditionExportRequest represents the params to export a radar edition in the
+// Build Your Own Radar format, csv or json.
+type EditionExportRequest struct {
+	Edition string `json:"edition"`
+	Format  string `json:"format,omitempty"`
+}
+
+// EditionExportResponse represents a radar edition exported.
+type EditionExportResponse struct {
+	Edition string `json:"edition"`
+	Format  string `json:"format"`
+	Data    string `json:"data"`
+}
+
+// ImportEdition creates or updates the blips of a radar edition, it needs the
+// session of an administrator.
+func (c *Client) ImportEdition(req *EditionImportRequest) (*EditionImportResponse, error) {
+	res := &EditionImportResponse{}
+	return res, c.do("POST", "/edition/import", req, res)
+}
+
+// ExportEdition obtains a radar edition in the Build Your Own Radar format.
+func (c *Client) ExportEdition(req *EditionExportRequest) (*EditionExportResponse, error) {
+	res := &EditionExportResponse{}
+	return res, c.do("POST", "/edition/export", req, res)
+}
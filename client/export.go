@@ -0,0 +1,75 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import "time"
+
+// ExportRequest represents the params to export the personal data of the
+// account of the session.
+type ExportRequest struct {
+	Token string `json:"token"`
+}
+
+// ExportAPIKey represents an API key of the account, without the key.
+type ExportAPIKey struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Scopes   []string  `json:"scopes"`
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// ExportAccount represents the account in the personal data export.
+type ExportAccount struct {
+	ID         string          `json:"id"`
+	Username   string          `json:"username"`
+	Name       string          `json:"name"`
+	Role       string          `json:"role"`
+	Email      string          `json:"email"`
+	Active     bool            `json:"active"`
+	Admin      bool            `json:"admin"`
+	TwoFactor  bool            `json:"two_factor"`
+	ExternalID string          `json:"external_id"`
+	APIKeys    []*ExportAPIKey `json:"api_keys"`
+}
+
+// ExportRole represents a role the member has had.
+type ExportRole struct {
+	Title    string    `json:"title"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// ExportResponse represents all the personal data of an account, the sessions
+// shortened so they can't be used.
+type ExportResponse struct {
+	Exported     time.Time           `json:"exported"`
+	Account      *ExportAccount      `json:"account"`
+	Roles        []*ExportRole       `json:"roles"`
+	Technologies []*MemberTechnology `json:"technologies"`
+	Sessions     []string            `json:"sessions"`
+	Audit        []*AuditRecord      `json:"audit"`
+}
+
+// Export exports all the personal data of the account of the session.
+func (c *Client) Export(req *ExportRequest) (*ExportResponse, error) {
+	res := &ExportResponse{}
+	return res, c.do("POST", "/account/export", req, res)
+}
@@ -0,0 +1,97 @@
+package client
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+// Blip represents a technology placed in a radar.
+type Blip struct {
+	Name     string `json:"name"`
+	Quadrant string `json:"quadrant"`
+	Ring     string `json:"ring"`
+}
+
+// RadarRequest represents the params to obtain a radar.
+type RadarRequest struct {
+	Flavor string `json:"flavor"`
+}
+
+// RadarResponse represents a radar and its blips.
+type RadarResponse struct {
+	Flavor string  `json:"flavor"`
+	Blips  []*Blip `json:"blips"`
+}
+
+// TechnologyRequest represents the params to obtain a technology.
+type TechnologyRequest struct {
+	Name string `json:"name"`
+}
+
+// TechnologyMember represents a member that knows a technology.
+type TechnologyMember struct {
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Level    int    `json:"level"`
+}
+
+// TechnologyResponse represents a technology and the members that know it.
+type TechnologyResponse struct {
+	Name     string              `json:"name"`
+	Type     string              `json:"type"`
+	Quadrant string              `json:"quadrant"`
+	Ring     string              `json:"ring"`
+	Members  []*TechnologyMember `json:"members"`
+}
+
+// MemberRequest represents the params to obtain a member profile.
+type MemberRequest struct {
+	Username string `json:"username"`
+}
+
+// MemberTechnology represents a technology known by a member.
+type MemberTechnology struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Level int    `json:"level"`
+}
+
+// MemberResponse represents the public profile of a member.
+type MemberResponse struct {
+	ID           string              `json:"id"`
+	Username     string              `json:"username"`
+	Name         string              `json:"name"`
+	Role         string              `json:"role"`
+	Technologies []*MemberTechnology `json:"technologies"`
+}
+
+// Radar obtains the blips of a radar flavor.
+func (c *Client) Radar(req *RadarRequest) (*RadarResponse, error) {
+	res := &RadarResponse{}
+	return res, c.do("POST", "/radar/get", req, res)
+}
+
+// Technology obtains a technology and the members that know it.
+func (c *Client) Technology(req *TechnologyRequest) (*TechnologyResponse, error) {
+	res := &TechnologyResponse{}
+	return res, c.do("POST", "/technology/get", req, res)
+}
+
+// Member obtains the public profile of a member.
+func (c *Client) Member(req *MemberRequest) (*MemberResponse, error) {
+	res := &MemberResponse{}
+	return res, c.do("POST", "/member/get", req, res)
+}
@@ -0,0 +1,106 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/config"
+)
+
+// errBackupDatastore raised when backing up or restoring without a persistent
+// datastore.
+var errBackupDatastore = errors.New("The backup commands need a persistent datastore, set it with -datastore")
+
+// backup writes a backup archive of the datastore to the output or to a file
+// readable only by its owner, as the archive holds the account credentials.
+func backup(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	sessions := flags.Bool("sessions", false, "Include the sessions, so the users stay logged in")
+	output := flags.String("output", "", "File to write the archive, the standard output if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DatastorePath == "" {
+		return errBackupDatastore
+	}
+
+	err = openDatastore(cfg)
+	if err != nil {
+		return err
+	}
+
+	ds := casesprovider.Datastore()
+	if *output == "" {
+		return ds.Backup(context.Background(), out, *sessions)
+	}
+
+	f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = ds.Backup(context.Background(), f, *sessions)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// restore replaces the content of the datastore file with a backup archive
+// read from a file or from the standard input.
+func restore(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	input := flags.String("input", "", "File to read the archive, the standard input if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DatastorePath == "" {
+		return errBackupDatastore
+	}
+
+	err = openDatastore(cfg)
+	if err != nil {
+		return err
+	}
+
+	ds := casesprovider.Datastore()
+	if *input == "" {
+		return ds.Restore(context.Background(), os.Stdin)
+	}
+
+	f, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return ds.Restore(context.Background(), f)
+}
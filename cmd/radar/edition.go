@@ -0,0 +1,161 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/radar-go/radar/byor"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/edition/exportbyor"
+	"github.com/radar-go/radar/casesprovider/cases/edition/importbyor"
+	"github.com/radar-go/radar/config"
+)
+
+// errEditionDatastore raised when importing or exporting without a persistent
+// datastore.
+var errEditionDatastore = errors.New("The import and export commands need a persistent datastore, set it with -datastore")
+
+// errEditionMissing raised when the edition argument is not present.
+var errEditionMissing = errors.New("The edition is missing, set it with -edition")
+
+// importEdition creates or updates a radar edition from a Build Your Own Radar
+// file, read from a file or from the standard input. The rows that aren't
+// valid are skipped and reported.
+func importEdition(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	edition := flags.String("edition", "", "Name of the radar edition")
+	input := flags.String("input", "", "File to read the radar, the standard input if empty")
+	format := flags.String("format", "", "Format of the radar, csv or json, by the file extension if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = openEditionDatastore(cfg, *edition)
+	if err != nil {
+		return err
+	}
+
+	r := io.Reader(os.Stdin)
+	if *input != "" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	blips, rowErrors, err := byor.Read(r, editionFormat(*format, *input))
+	if err != nil {
+		return err
+	}
+
+	for _, rowErr := range rowErrors {
+		fmt.Fprintf(out, "Row %d (%s) skipped: %s\n", rowErr.Row, rowErr.Name, rowErr.Err)
+	}
+
+	created, updated, err := importbyor.Import(context.Background(), casesprovider.Datastore(),
+		*edition, blips)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Edition %s imported: %d blips created, %d updated\n",
+		strings.TrimSpace(*edition), created, updated)
+
+	return nil
+}
+
+// exportEdition writes a radar edition as a Build Your Own Radar file to the
+// output or to a file.
+func exportEdition(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	edition := flags.String("edition", "", "Name of the radar edition")
+	output := flags.String("output", "", "File to write the radar, the standard output if empty")
+	format := flags.String("format", "", "Format of the radar, csv or json, by the file extension if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = openEditionDatastore(cfg, *edition)
+	if err != nil {
+		return err
+	}
+
+	_, blips, err := exportbyor.Export(context.Background(), casesprovider.Datastore(), *edition)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return byor.Write(out, editionFormat(*format, *output), blips)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	err = byor.Write(f, editionFormat(*format, *output), blips)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// openEditionDatastore loads the datastore the radar editions are imported to
+// or exported from.
+func openEditionDatastore(cfg *config.Config, edition string) error {
+	if cfg.DatastorePath == "" {
+		return errEditionDatastore
+	}
+
+	if strings.TrimSpace(edition) == "" {
+		return errEditionMissing
+	}
+
+	return openDatastore(cfg)
+}
+
+// editionFormat returns the format of a radar file, the one given or the one
+// of the file extension, csv by default.
+func editionFormat(format, path string) string {
+	if format != "" {
+		return format
+	}
+
+	if strings.EqualFold(filepath.Ext(path), "."+byor.JSON) {
+		return byor.JSON
+	}
+
+	return byor.CSV
+}
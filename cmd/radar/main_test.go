@@ -1,6 +1,6 @@
 package main
 
-/* Copyright (C) 2017 Radar team (see AUTHORS)
+/* Copyright (C) 2017-2018 Radar team (see AUTHORS)
 
    This file is part of radar.
 
@@ -19,11 +19,392 @@ package main
 */
 
 import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/ldap/ldaptest"
 )
 
+func TestFindCommand(t *testing.T) {
+	for _, name := range []string{"serve", "user", "render", "migrate", "backup", "restore", "import", "export"} {
+		if findCommand(name) == nil {
+			t.Errorf("Expected command %s to exist", name)
+		}
+	}
+
+	for _, name := range []string{"unknown"} {
+		if findCommand(name) != nil {
+			t.Errorf("Expected command %s to not exist", name)
+		}
+	}
+}
+
+func TestCommandsHelp(t *testing.T) {
+	/* All the commands listed are implemented and parse their arguments. */
+	for _, cmd := range commands {
+		err := cmd.run(config.New(), []string{"-h"}, ioutil.Discard)
+		if err != flag.ErrHelp {
+			t.Errorf("Expected the help of the command %s, Got %v", cmd.name, err)
+		}
+	}
+}
+
+func TestUserCommands(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	defer casesprovider.SetDatastore(datastore.New())
+
+	cfg := config.New()
+	err = user(cfg, []string{"list"}, ioutil.Discard)
+	if err != errMemoryDatastore {
+		t.Errorf("Expected %s, Got %v", errMemoryDatastore, err)
+	}
+
+	cfg.DatastorePath = filepath.Join(dir, "radar.db")
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			name: "Create",
+			args: []string{"create", "-username", "ritho", "-name", "Pablo",
+				"-email", "palvarez@ritho.net", "-password", "ritho", "-admin"},
+			expected: "Account ritho created with id",
+		},
+		{
+			name:     "ListActiveAdmin",
+			args:     []string{"list"},
+			expected: "palvarez@ritho.net  true    true",
+		},
+		{
+			name:     "Deactivate",
+			args:     []string{"deactivate", "ritho"},
+			expected: "Account deactivated successfully",
+		},
+		{
+			name:     "ListInactive",
+			args:     []string{"list"},
+			expected: "palvarez@ritho.net  false   true",
+		},
+		{
+			name:     "Activate",
+			args:     []string{"activate", "ritho"},
+			expected: "Account activated successfully",
+		},
+		{
+			name:     "ResetPassword",
+			args:     []string{"reset-password", "-password", "121212", "ritho"},
+			expected: "Password of ritho updated",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := user(cfg, tc.args, out)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+
+			if !strings.Contains(out.String(), tc.expected) {
+				t.Errorf("Expected '%s', Got '%s'", tc.expected, out)
+			}
+		})
+	}
+
+	ds, err := datastore.Open(cfg.DatastorePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	acc, err := ds.GetAccountByUsername(ctx, "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !acc.IsActive() || !acc.IsAdmin() || !acc.CheckPassword("121212") {
+		t.Errorf("Unexpected account state: active %t, admin %t, password %s",
+			acc.IsActive(), acc.IsAdmin(), acc.Password())
+	}
+
+	err = user(cfg, []string{"activate"}, ioutil.Discard)
+	if err != errUsernameMissing {
+		t.Errorf("Expected %s, Got %v", errUsernameMissing, err)
+	}
+}
+
+func TestUserSyncLDAP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	defer casesprovider.SetDatastore(datastore.New())
+
+	cfg := config.New()
+	cfg.DatastorePath = filepath.Join(dir, "radar.db")
+	err = user(cfg, []string{"sync-ldap"}, ioutil.Discard)
+	if err == nil || !strings.Contains(err.Error(), "The directory is not configured") {
+		t.Errorf("Expected the directory to not be configured, Got %v", err)
+	}
+
+	s := ldaptest.NewDirectory()
+	defer s.Close()
+	casesprovider.SetLDAP(s.Directory())
+	defer casesprovider.SetLDAP(nil)
+	s.AddUser("ritho", "Pablo Álvarez", "palvarez@ritho.net", "Developer", "ritho-secret")
+
+	out := &bytes.Buffer{}
+	err = user(cfg, []string{"sync-ldap"}, out)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if !strings.Contains(out.String(), "Created: 1") {
+		t.Errorf("Expected the account to be created, Got '%s'", out)
+	}
+}
+
 func BenchmarkExample(t *testing.B) {
 	for i := 0; i < t.N; i++ {
 		// Benchmark test
 	}
 }
+
+func TestRenderCommand(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := renderRadar(config.New(), []string{"-flavor", "projects"}, out)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if !strings.HasPrefix(out.String(), "<svg") {
+		t.Errorf("Expected a svg image, Got %s", out)
+	}
+
+	err = renderRadar(config.New(), []string{"-flavor", "unknown"}, out)
+	if err == nil {
+		t.Error("Expected error rendering an unknown flavor")
+	}
+}
+
+func TestBackupCommands(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	defer casesprovider.SetDatastore(datastore.New())
+
+	cfg := config.New()
+	err = backup(cfg, nil, ioutil.Discard)
+	if err != errBackupDatastore {
+		t.Errorf("Expected error %s, Got %v", errBackupDatastore, err)
+	}
+
+	err = restore(cfg, nil, ioutil.Discard)
+	if err != errBackupDatastore {
+		t.Errorf("Expected error %s, Got %v", errBackupDatastore, err)
+	}
+
+	cfg.DatastorePath = filepath.Join(dir, "radar.db")
+	err = user(cfg, []string{"create", "-username", "admin", "-name", "Admin",
+		"-email", "admin@ritho.net", "-password", "admin", "-admin"}, ioutil.Discard)
+	if err != nil {
+		t.Fatalf("Unexpected error creating the account: %s", err)
+	}
+
+	archive := filepath.Join(dir, "radar.json.gz")
+	err = backup(cfg, []string{"-output", archive}, ioutil.Discard)
+	if err != nil {
+		t.Fatalf("Unexpected error backing up the datastore: %s", err)
+	}
+
+	info, err := os.Stat(archive)
+	if err != nil {
+		t.Fatalf("Unexpected error reading the archive: %s", err)
+	}
+
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the archive readable only by its owner, Got %v", info.Mode().Perm())
+	}
+
+	err = user(cfg, []string{"deactivate", "admin"}, ioutil.Discard)
+	if err != nil {
+		t.Fatalf("Unexpected error deactivating the account: %s", err)
+	}
+
+	err = restore(cfg, []string{"-input", archive}, ioutil.Discard)
+	if err != nil {
+		t.Fatalf("Unexpected error restoring the datastore: %s", err)
+	}
+
+	ds, err := datastore.Open(cfg.DatastorePath)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	acc, err := ds.GetAccountByUsername(ctx, "admin")
+	if err != nil || !acc.IsActive() {
+		t.Errorf("Expected the account to be restored active, Got %v", err)
+	}
+
+	err = restore(cfg, []string{"-input", filepath.Join(dir, "unknown")}, ioutil.Discard)
+	if err == nil {
+		t.Error("Expected error restoring an unknown archive")
+	}
+}
+
+func TestEditionCommands(t *testing.T) {
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	defer casesprovider.SetDatastore(datastore.New())
+
+	cfg := config.New()
+	err = importEdition(cfg, []string{"-edition", "2018"}, ioutil.Discard)
+	if err != errEditionDatastore {
+		t.Errorf("Expected error %s, Got %v", errEditionDatastore, err)
+	}
+
+	cfg.DatastorePath = filepath.Join(dir, "radar.db")
+	err = exportEdition(cfg, nil, ioutil.Discard)
+	if err != errEditionMissing {
+		t.Errorf("Expected error %s, Got %v", errEditionMissing, err)
+	}
+
+	input := filepath.Join(dir, "radar.csv")
+	err = ioutil.WriteFile(input, []byte("name,ring,quadrant,isNew,description\n"+
+		"Go,adopt,languages & frameworks,TRUE,A language\n"+
+		"Cobol,retire,languages & frameworks,FALSE,\n"), 0644)
+	if err != nil {
+		t.Fatalf("Unexpected error writing the radar: %s", err)
+	}
+
+	out := &bytes.Buffer{}
+	err = importEdition(cfg, []string{"-edition", "2018", "-input", input}, out)
+	if err != nil {
+		t.Fatalf("Unexpected error importing the edition: %s", err)
+	}
+
+	if !strings.Contains(out.String(), "Row 2 (Cobol) skipped") ||
+		!strings.Contains(out.String(), "Edition 2018 imported: 1 blips created, 0 updated") {
+		t.Errorf("Unexpected import output %s", out)
+	}
+
+	output := filepath.Join(dir, "radar.json")
+	err = exportEdition(cfg, []string{"-edition", "2018", "-output", output}, ioutil.Discard)
+	if err != nil {
+		t.Fatalf("Unexpected error exporting the edition: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(output)
+	if err != nil || !strings.Contains(string(data), `"description":"A language"`) {
+		t.Errorf("Expected the edition exported as json, Got %s: %v", data, err)
+	}
+
+	out.Reset()
+	err = exportEdition(cfg, []string{"-edition", "2018"}, out)
+	if err != nil || out.String() != "name,ring,quadrant,isNew,description\n"+
+		"Go,adopt,languages & frameworks,TRUE,A language\n" {
+		t.Errorf("Expected the edition exported as csv, Got %s: %v", out, err)
+	}
+
+	err = exportEdition(cfg, []string{"-edition", "2019"}, ioutil.Discard)
+	if errors.Cause(err) != datastore.ErrEditionNotExists {
+		t.Errorf("Expected error %s, Got %v", datastore.ErrEditionNotExists, err)
+	}
+}
+
+func TestMigrateCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := config.New()
+	err = migrate(cfg, nil, ioutil.Discard)
+	if err != errMigrateDatastore {
+		t.Errorf("Expected error %s, Got %v", errMigrateDatastore, err)
+	}
+
+	cfg.DatastorePath = filepath.Join(dir, "radar.db")
+	err = ioutil.WriteFile(cfg.DatastorePath, []byte(`{"accounts": []}`), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+		pending  bool
+	}{
+		{"DryRun", []string{"-dry-run"}, "Migration 1 account-uuids pending", true},
+		{"Apply", nil, "Migration 1 account-uuids applied", false},
+		{"UpToDate", nil, fmt.Sprintf("The datastore is up to date with the schema %d", datastore.SchemaVersion), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := migrate(cfg, tc.args, out)
+			if err != nil || !strings.Contains(out.String(), tc.expected) {
+				t.Errorf("Expected %s, Got %s: %v", tc.expected, out, err)
+			}
+
+			err = openDatastore(cfg)
+			if tc.pending != (errors.Cause(err) == datastore.ErrSchemaOutdated) {
+				t.Errorf("Expected pending migrations %t, Got %v", tc.pending, err)
+			}
+		})
+	}
+
+	/* radar serve applies the pending migrations unless it's told not to. */
+	err = ioutil.WriteFile(cfg.DatastorePath, []byte(`{"accounts": []}`), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	out := &bytes.Buffer{}
+	err = autoMigrate(cfg, out)
+	if err != nil || !strings.Contains(out.String(), "Migration 1 account-uuids applied") {
+		t.Errorf("Expected the migrations applied, Got %s: %v", out, err)
+	}
+
+	err = openDatastore(cfg)
+	if err != nil {
+		t.Errorf("Unexpected error opening the migrated datastore: %s", err)
+	}
+
+	err = ioutil.WriteFile(cfg.DatastorePath, []byte(`{"schema": {"version": 999}}`), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err = migrate(cfg, nil, ioutil.Discard)
+	if err == nil {
+		t.Error("Expected error migrating a newer schema")
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/datastore"
+)
+
+// errMigrateDatastore raised when migrating without a persistent datastore.
+var errMigrateDatastore = errors.New("The migrate command needs a persistent datastore, set it with -datastore")
+
+// migrate applies the pending migrations to the datastore file, or only shows
+// them with -dry-run.
+func migrate(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "Show the pending migrations, checking they succeed, without applying them")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if cfg.DatastorePath == "" {
+		return errMigrateDatastore
+	}
+
+	migrations, err := datastore.Migrate(cfg.DatastorePath, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	state := "applied"
+	if *dryRun {
+		state = "pending"
+	}
+
+	printMigrations(out, migrations, state)
+	if len(migrations) == 0 {
+		fmt.Fprintf(out, "The datastore is up to date with the schema %d\n", datastore.SchemaVersion)
+	}
+
+	return nil
+}
+
+// autoMigrate applies the pending migrations of the datastore file, if any,
+// so radar can start with it.
+func autoMigrate(cfg *config.Config, out io.Writer) error {
+	if cfg.DatastorePath == "" {
+		return nil
+	}
+
+	migrations, err := datastore.Migrate(cfg.DatastorePath, false)
+	if err != nil {
+		return err
+	}
+
+	printMigrations(out, migrations, "applied")
+
+	return nil
+}
+
+// printMigrations writes the migrations to the output in their state.
+func printMigrations(out io.Writer, migrations []datastore.Migration, state string) {
+	for _, m := range migrations {
+		fmt.Fprintf(out, "Migration %d %s %s\n", m.Version, m.Name, state)
+	}
+}
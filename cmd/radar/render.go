@@ -0,0 +1,93 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/render"
+)
+
+// renderRadar writes the svg image of a radar flavor to the output or to a
+// file.
+func renderRadar(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flavor := flags.String("flavor", "people", "Flavor of the radar: people, projects or resources")
+	output := flags.String("output", "", "File to write the svg image, the standard output if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = openDatastore(cfg)
+	if err != nil {
+		return err
+	}
+
+	uc, err := casesprovider.GetUseCase("RadarGet")
+	if err != nil {
+		return err
+	}
+
+	err = uc.AddParam("flavor", *flavor)
+	if err != nil {
+		return err
+	}
+
+	res, err := uc.Run(context.Background())
+	if err != nil {
+		return err
+	}
+
+	data, err := res.Bytes()
+	if err != nil {
+		return err
+	}
+
+	radar := struct {
+		Blips []render.Blip `json:"blips"`
+	}{}
+	err = json.Unmarshal(data, &radar)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return render.SVG(out, radar.Blips, nil)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+
+	err = render.SVG(f, radar.Blips, nil)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,116 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"flag"
+	"io"
+	"time"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/directory"
+	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/ui/api"
+	"github.com/radar-go/radar/ui/web"
+)
+
+// serve starts the radar API and the web interface, it returns when any of
+// them stops.
+func serve(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	/* The other commands refuse the datastores with pending migrations. */
+	if cfg.AutoMigrate {
+		err = autoMigrate(cfg, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = openDatastore(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.LDAPSyncInterval > 0 && casesprovider.LDAP().Configured() {
+		go syncLDAP(cfg.LDAPSyncInterval)
+	}
+
+	if cfg.RemovalRetention > 0 {
+		go purgeAccounts(cfg.RemovalRetention)
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		errs <- api.NewWithConfig(cfg).Start()
+	}()
+
+	go func() {
+		errs <- web.NewWithConfig(cfg).Start()
+	}()
+
+	return <-errs
+}
+
+// syncLDAP synchronizes the accounts with the LDAP directory every interval,
+// the errors are only logged so the next synchronization is tried anyway.
+func syncLDAP(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		report, err := directory.Sync(context.Background(), casesprovider.Datastore(),
+			casesprovider.LDAP())
+		if err != nil {
+			logging.Logger.Error("accounts not synchronized with the directory", "error", err.Error())
+			continue
+		}
+
+		logging.Logger.Info("accounts synchronized with the directory", "created", report.Created,
+			"linked", report.Linked, "updated", report.Updated, "deactivated", report.Deactivated,
+			"conflicts", len(report.Conflicts))
+	}
+}
+
+// purgeInterval is the time between the purges of the removed accounts.
+const purgeInterval = time.Hour
+
+// purgeAccounts deletes for good every purgeInterval the accounts removed for
+// longer than the retention, freeing their usernames and emails.
+func purgeAccounts(retention time.Duration) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		purged, err := casesprovider.Datastore().PurgeAccounts(context.Background(),
+			time.Now().UTC().Add(-retention))
+		if err != nil {
+			logging.Logger.Error("removed accounts not purged", "error", err.Error())
+			continue
+		}
+
+		if purged > 0 {
+			logging.Logger.Info("removed accounts purged", "purged", purged)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/golang-plus/uuid"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/directory"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
+	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// errMemoryDatastore raised when an administration command is called without a
+// persistent datastore.
+var errMemoryDatastore = errors.New("The user commands need a persistent datastore, set it with -datastore")
+
+// errUsernameMissing raised when the username argument is not present.
+var errUsernameMissing = errors.New("The username is missing")
+
+// userCommands is the list of subcommands of the user command.
+var userCommands = []*command{
+	{"create", "Creates a new account", userCreate},
+	{"list", "Lists all the accounts", userList},
+	{"activate", "Activates an account", userActivate},
+	{"deactivate", "Deactivates an account", userDeactivate},
+	{"reset-password", "Sets a new password for an account", userResetPassword},
+	{"sync-ldap", "Synchronizes the accounts with the LDAP directory", userSyncLDAP},
+}
+
+// user runs the account administration subcommands. The administration is
+// done directly against the datastore file, so the API should be stopped while
+// running them.
+func user(cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return userUsage(out)
+	}
+
+	for _, cmd := range userCommands {
+		if cmd.name == args[0] {
+			if cfg.DatastorePath == "" {
+				return errMemoryDatastore
+			}
+
+			err := openDatastore(cfg)
+			if err != nil {
+				return err
+			}
+
+			return cmd.run(cfg, args[1:], out)
+		}
+	}
+
+	return userUsage(out)
+}
+
+// userUsage prints the help of the user command.
+func userUsage(out io.Writer) error {
+	fmt.Fprintln(out, "Usage: radar user <command> [arguments]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, cmd := range userCommands {
+		fmt.Fprintf(out, "  %-15s %s\n", cmd.name, cmd.summary)
+	}
+
+	return flag.ErrHelp
+}
+
+// userCreate registers a new account.
+func userCreate(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("user create", flag.ContinueOnError)
+	username := flags.String("username", "", "Username of the account")
+	name := flags.String("name", "", "Name of the account owner")
+	email := flags.String("email", "", "Email of the account")
+	password := flags.String("password", "", "Password of the account")
+	active := flags.Bool("active", true, "Activate the account")
+	admin := flags.Bool("admin", false, "Grant administration privileges")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	uc, err := casesprovider.GetUseCase("AccountRegister")
+	if err != nil {
+		return err
+	}
+
+	err = uc.AddParams(map[string]interface{}{
+		"username": *username,
+		"name":     *name,
+		"email":    *email,
+		"password": *password,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	_, err = uc.Run(ctx)
+	if err != nil {
+		return err
+	}
+
+	ds := casesprovider.Datastore()
+	acc, err := ds.GetAccountByUsername(ctx, *username)
+	if err != nil {
+		return err
+	}
+
+	if *active {
+		_, err = activateAccount(ctx, acc)
+		if err != nil {
+			return err
+		}
+	}
+
+	if *admin && !ds.SetAdmin(ctx, acc.ID(), true) {
+		return fmt.Errorf("Error granting administration privileges to %s",
+			acc.Username())
+	}
+
+	fmt.Fprintf(out, "Account %s created with id %s\n", acc.Username(), acc.UUID())
+
+	return nil
+}
+
+// userList prints all the accounts stored in the datastore.
+func userList(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("user list", flag.ContinueOnError)
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := casesprovider.Datastore().Accounts(context.Background())
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tUSERNAME\tNAME\tEMAIL\tACTIVE\tADMIN")
+	for _, acc := range accounts {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%t\n", acc.UUID(), acc.Username(),
+			acc.Name(), acc.Email(), acc.IsActive(), acc.IsAdmin())
+	}
+
+	return w.Flush()
+}
+
+// userActivate activates an account without following the link sent to its
+// email.
+func userActivate(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("user activate", flag.ContinueOnError)
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	acc, err := accountFromArgs(ctx, flags)
+	if err != nil {
+		return err
+	}
+
+	res, err := activateAccount(ctx, acc)
+	if err != nil {
+		return err
+	}
+
+	result, err := res.String()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, result)
+
+	return nil
+}
+
+// userDeactivate deactivates an account.
+func userDeactivate(cfg *config.Config, args []string, out io.Writer) error {
+	return runOnAccount("user deactivate", "AccountDeactivate", args, out)
+}
+
+// userResetPassword sets a new password for an account.
+func userResetPassword(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	password := flags.String("password", "", "New password of the account")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	acc, err := accountFromArgs(ctx, flags)
+	if err != nil {
+		return err
+	}
+
+	_, err = runAs(ctx, acc, "AccountEdit", map[string]interface{}{
+		"username": acc.Username(),
+		"name":     acc.Name(),
+		"email":    acc.Email(),
+		"password": *password,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Password of %s updated\n", acc.Username())
+
+	return nil
+}
+
+// userSyncLDAP imports the users of the LDAP directory and deactivates the
+// accounts of the users removed from it.
+func userSyncLDAP(cfg *config.Config, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("user sync-ldap", flag.ContinueOnError)
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	report, err := directory.Sync(context.Background(), casesprovider.Datastore(),
+		casesprovider.LDAP())
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Created: %d\nLinked: %d\nUpdated: %d\nDeactivated: %d\n",
+		report.Created, report.Linked, report.Updated, report.Deactivated)
+	for _, username := range report.Conflicts {
+		fmt.Fprintf(out, "Conflict: %s\n", username)
+	}
+
+	return nil
+}
+
+// runOnAccount runs a use case that only needs the account session over the
+// account passed as argument.
+func runOnAccount(name, useCase string, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	acc, err := accountFromArgs(ctx, flags)
+	if err != nil {
+		return err
+	}
+
+	res, err := runAs(ctx, acc, useCase, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+
+	result, err := res.String()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, result)
+
+	return nil
+}
+
+// accountFromArgs returns the account whose username is the first positional
+// argument.
+func accountFromArgs(ctx context.Context, flags *flag.FlagSet) (*account.Account, error) {
+	if flags.NArg() == 0 {
+		return nil, errUsernameMissing
+	}
+
+	return casesprovider.Datastore().GetAccountByUsername(ctx, flags.Arg(0))
+}
+
+// activateAccount activates an account with a verification code, the same way
+// the account would do it following the link sent to its email.
+func activateAccount(ctx context.Context, acc *account.Account) (casesprovider.ResultPrinter, error) {
+	uc, err := casesprovider.GetUseCase("AccountActivate")
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.AddParam("code", verification.Code(acc))
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.Run(ctx)
+}
+
+// runAs runs a use case on behalf of an account, opening a temporary session
+// for it the same way the account would do it logging in. The run is trusted,
+// as the command is run by who administers the datastore.
+func runAs(ctx context.Context, acc *account.Account, name string,
+	params map[string]interface{}) (casesprovider.ResultPrinter, error) {
+	ds := casesprovider.Datastore()
+	session, err := uuid.NewTimeBased()
+	if err != nil {
+		return nil, err
+	}
+
+	token := session.String()
+	err = ds.AddSession(ctx, token, acc.Username())
+	if err != nil {
+		return nil, err
+	}
+	defer ds.DeleteSession(ctx, token, acc.Username())
+
+	uc, err := casesprovider.GetUseCase(name)
+	if err != nil {
+		return nil, err
+	}
+
+	params["id"] = acc.UUID()
+	params["token"] = token
+	err = uc.AddParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.Run(casesprovider.WithTrusted(ctx))
+}
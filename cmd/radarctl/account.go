@@ -0,0 +1,509 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/radar-go/radar/client"
+)
+
+// health checks the status of the API.
+func health(ctl *radarctl, args []string) error {
+	err := ctl.client.Healthcheck()
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, map[string]string{"status": "ok"})
+}
+
+// register registers a new account.
+func register(ctl *radarctl, args []string) error {
+	flags := flag.NewFlagSet("register", flag.ContinueOnError)
+	username := flags.String("username", "", "Username of the account")
+	name := flags.String("name", "", "Name of the account owner")
+	email := flags.String("email", "", "Email of the account")
+	password := flags.String("password", "", "Password of the account, read from the input if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = ctl.readPassword(password)
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.Register(&client.RegisterRequest{
+		Username: *username,
+		Name:     *name,
+		Email:    *email,
+		Password: *password,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// login logs in an account and stores its session in the configuration.
+func login(ctl *radarctl, args []string) error {
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	username := flags.String("username", "", "Username of the account")
+	password := flags.String("password", "", "Password of the account, read from the input if empty")
+	code := flags.String("code", "", "Code of the authenticator app or a recovery code, read from the input if empty and needed")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = ctl.readPassword(password)
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.Login(&client.LoginRequest{
+		Login:    *username,
+		Password: *password,
+	})
+	if err != nil {
+		return err
+	}
+
+	/* The accounts with two-factor authentication log in in two steps. */
+	if res.Challenge != "" {
+		err = ctl.readInput("Code", code)
+		if err != nil {
+			return err
+		}
+
+		res, err = ctl.client.VerifyLogin(&client.LoginVerifyRequest{
+			Challenge: res.Challenge,
+			Code:      *code,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ctl.cfg.ID = res.ID
+	ctl.cfg.Username = res.Username
+	ctl.cfg.Name = res.Name
+	ctl.cfg.Email = res.Email
+	ctl.cfg.Token = res.Token
+	err = ctl.cfg.Save(ctl.configPath)
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// logout logs out the account and removes its session from the configuration.
+func logout(ctl *radarctl, args []string) error {
+	if ctl.cfg.Token == "" {
+		return errNotLoggedIn
+	}
+
+	res, err := ctl.client.Logout(&client.LogoutRequest{
+		Username: ctl.cfg.Username,
+		Token:    ctl.cfg.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctl.cfg.ClearSession()
+	err = ctl.cfg.Save(ctl.configPath)
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// whoami shows the account logged in.
+func whoami(ctl *radarctl, args []string) error {
+	if ctl.cfg.Token == "" {
+		return errNotLoggedIn
+	}
+
+	return printResult(ctl.out, ctl.format, struct {
+		URL      string `json:"url"`
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+	}{ctl.cfg.URL, ctl.cfg.ID, ctl.cfg.Username, ctl.cfg.Name, ctl.cfg.Email})
+}
+
+// edit edits the account logged in, keeping the current values of the fields
+// not set.
+func edit(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("edit", flag.ContinueOnError)
+	username := flags.String("username", ctl.cfg.Username, "New username of the account")
+	name := flags.String("name", ctl.cfg.Name, "New name of the account owner")
+	email := flags.String("email", ctl.cfg.Email, "New email of the account")
+	password := flags.String("password", "", "Password of the account, read from the input if empty")
+	err = flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = ctl.readPassword(password)
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.Edit(&client.EditRequest{
+		ID:       session.ID,
+		Token:    session.Token,
+		Username: *username,
+		Name:     *name,
+		Email:    *email,
+		Password: *password,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctl.cfg.Username = *username
+	ctl.cfg.Name = *name
+	ctl.cfg.Email = *email
+	err = ctl.cfg.Save(ctl.configPath)
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// activate activates an account with the code of the link sent to its email.
+func activate(ctl *radarctl, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl activate <code>")
+	}
+
+	res, err := ctl.client.Activate(&client.ActivateRequest{Code: args[0]})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// resend sends a new verification link to the email of an account, the one
+// logged in if no username is given.
+func resend(ctl *radarctl, args []string) error {
+	username := ctl.cfg.Username
+	if len(args) > 0 {
+		username = args[0]
+	}
+
+	if username == "" {
+		return fmt.Errorf("Usage: radarctl resend <username>")
+	}
+
+	res, err := ctl.client.ResendVerification(&client.ResendRequest{Username: username})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// forgotPassword sends a link to reset the password to the email of an
+// account.
+func forgotPassword(ctl *radarctl, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl forgot-password <username>")
+	}
+
+	res, err := ctl.client.RequestPasswordReset(&client.PasswordResetRequest{Username: args[0]})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// resetPassword sets a new password with the code of the link sent to the
+// email of the account.
+func resetPassword(ctl *radarctl, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	code := flags.String("code", "", "Code of the link sent to the email")
+	password := flags.String("password", "", "New password, read from the input if empty")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	err = ctl.readPassword(password)
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.ConfirmPasswordReset(&client.PasswordResetConfirmRequest{
+		Code:     *code,
+		Password: *password,
+	})
+	if err != nil {
+		return err
+	}
+
+	/* The reset closes all the sessions of the account. */
+	if ctl.cfg.Token != "" && ctl.cfg.ID == res.ID {
+		ctl.cfg.ClearSession()
+		err = ctl.cfg.Save(ctl.configPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// unlock unlocks an account locked out after too many failed logins, it needs
+// the session of an administrator.
+func unlock(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl unlock <username>")
+	}
+
+	res, err := ctl.client.Unlock(&client.UnlockRequest{
+		Token:    session.Token,
+		Username: args[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// restore restores a removed account by its id before it's purged, it needs
+// the session of an administrator.
+func restore(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl restore <id>")
+	}
+
+	res, err := ctl.client.Restore(&client.SessionRequest{
+		ID:    args[0],
+		Token: session.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// enrollTwoFactor starts the enrollment of the two-factor authentication of
+// the account logged in, showing the secret to add to the authenticator app.
+func enrollTwoFactor(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.EnrollTwoFactor(&client.TwoFactorRequest{Token: session.Token})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// confirmTwoFactor enables the two-factor authentication of the account logged
+// in with a code of the authenticator app, showing the recovery codes.
+func confirmTwoFactor(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl 2fa-confirm <code>")
+	}
+
+	res, err := ctl.client.ConfirmTwoFactor(&client.TwoFactorRequest{
+		Token: session.Token,
+		Code:  args[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// disableTwoFactor removes the two-factor authentication of the account logged
+// in, with a code of the authenticator app or a recovery code.
+func disableTwoFactor(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl 2fa-disable <code>")
+	}
+
+	res, err := ctl.client.DisableTwoFactor(&client.TwoFactorRequest{
+		Token: session.Token,
+		Code:  args[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// twoFactorPolicy chooses the accounts that must use the two-factor
+// authentication, it needs the session of an administrator.
+func twoFactorPolicy(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl 2fa-policy <optional|admins>")
+	}
+
+	res, err := ctl.client.SetTwoFactorPolicy(&client.TwoFactorPolicyRequest{
+		Token:   session.Token,
+		Require: args[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// deactivate deactivates the account logged in.
+func deactivate(ctl *radarctl, args []string) error {
+	return ctl.sessionCall(ctl.client.Deactivate, true)
+}
+
+// remove removes the account logged in.
+func remove(ctl *radarctl, args []string) error {
+	return ctl.sessionCall(ctl.client.Remove, true)
+}
+
+// erase erases the account logged in and its personal data for good.
+func erase(ctl *radarctl, args []string) error {
+	return ctl.sessionCall(ctl.client.Erase, true)
+}
+
+// export exports all the personal data of the account logged in, as json
+// unless yaml is asked, the table format not fitting it.
+func export(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.Export(&client.ExportRequest{Token: session.Token})
+	if err != nil {
+		return err
+	}
+
+	format := ctl.format
+	if format != "yaml" {
+		format = "json"
+	}
+
+	return printResult(ctl.out, format, res)
+}
+
+// sessionCall calls to an API operation that only needs the session, clearing
+// it afterwards if the API closes it.
+func (ctl *radarctl) sessionCall(call func(*client.SessionRequest) (*client.AccountResponse, error), closesSession bool) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	res, err := call(session)
+	if err != nil {
+		return err
+	}
+
+	if closesSession {
+		ctl.cfg.ClearSession()
+		err = ctl.cfg.Save(ctl.configPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// readPassword reads the password from the input if it's empty.
+func (ctl *radarctl) readPassword(password *string) error {
+	return ctl.readInput("Password", password)
+}
+
+// readInput reads a value from the input, asking for it, if it's empty. The
+// input is buffered once, so the values can be read one after another.
+func (ctl *radarctl) readInput(name string, value *string) error {
+	if *value != "" {
+		return nil
+	}
+
+	if ctl.reader == nil {
+		ctl.reader = bufio.NewReader(ctl.in)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: ", name)
+	line, err := ctl.reader.ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("Error reading the %s: %s", strings.ToLower(name), err)
+	}
+
+	*value = strings.TrimSpace(line)
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"flag"
+	"os"
+
+	"github.com/radar-go/radar/client"
+)
+
+// audit queries the audit log, it needs the session of an administrator.
+func audit(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("audit", flag.ContinueOnError)
+	actor := flags.String("actor", "", "Username of the account doing the operations")
+	target := flags.String("target", "", "Username of the account changed by the operations")
+	action := flags.String("action", "", "Use case of the operations, like AccountEdit")
+	since := flags.String("since", "", "Show the operations done since this time, in RFC 3339 format")
+	until := flags.String("until", "", "Show the operations done before this time, in RFC 3339 format")
+	page := flags.Int("page", 0, "Page of the records, newest first")
+	perPage := flags.Int("per-page", 0, "Number of records of every page")
+	err = flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.Audit(&client.AuditRequest{
+		Token:   session.Token,
+		Actor:   *actor,
+		Target:  *target,
+		Action:  *action,
+		Since:   *since,
+		Until:   *until,
+		Page:    *page,
+		PerPage: *perPage,
+	})
+	if err != nil {
+		return err
+	}
+
+	if ctl.format == "table" {
+		return printResult(ctl.out, ctl.format, res.Records)
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// backup writes a backup archive of the datastore to the output or to a file
+// readable only by its owner, it needs the session of an administrator.
+func backup(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	sessions := flags.Bool("sessions", false, "Include the sessions, so the users stay logged in")
+	output := flags.String("output", "", "File to write the archive, the standard output if empty")
+	err = flags.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return ctl.client.Backup(session.Token, *sessions, ctl.out)
+	}
+
+	f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	err = ctl.client.Backup(session.Token, *sessions, f)
+	if err != nil {
+		f.Close()
+		os.Remove(*output)
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,83 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Config stores the radarctl configuration between calls.
+type Config struct {
+	URL      string `json:"url"`
+	ID       string `json:"id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// LoadConfig reads the configuration from path, returning the default one if
+// the file doesn't exists.
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{
+		URL: "http://localhost:10000",
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Error reading the configuration")
+	}
+
+	err = json.Unmarshal(data, cfg)
+	if typeErr, ok := err.(*json.UnmarshalTypeError); ok && typeErr.Field == "id" {
+		/* The configurations saved before the accounts had UUIDs have the
+		old numeric id, so the session has to be opened again. */
+		cfg.ClearSession()
+		err = nil
+	}
+
+	return cfg, errors.Wrap(err, "Error decoding the configuration")
+}
+
+// Save writes the configuration to path. The file is only readable by the
+// user because it contains the session token.
+func (cfg *Config) Save(path string) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding the configuration")
+	}
+
+	return errors.Wrap(ioutil.WriteFile(path, data, 0600),
+		"Error writing the configuration")
+}
+
+// ClearSession removes the session data from the configuration.
+func (cfg *Config) ClearSession() {
+	cfg.ID = ""
+	cfg.Username = ""
+	cfg.Name = ""
+	cfg.Email = ""
+	cfg.Token = ""
+}
@@ -0,0 +1,179 @@
+// Package main implements the radarctl command, a terminal client for the
+// Radar API.
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/radar-go/radar/client"
+)
+
+// command represents one of the radarctl subcommands.
+type command struct {
+	name    string
+	summary string
+	run     func(ctl *radarctl, args []string) error
+}
+
+// commands is the list of subcommands implemented by radarctl.
+var commands = []*command{
+	{"health", "Checks the status of the API", health},
+	{"register", "Registers a new account", register},
+	{"login", "Logs in and stores the session token", login},
+	{"logout", "Logs out and removes the session token", logout},
+	{"whoami", "Shows the account logged in", whoami},
+	{"edit", "Edits the account logged in", edit},
+	{"activate", "Activates an account with the code sent to its email", activate},
+	{"resend", "Sends a new activation link to the email of an account", resend},
+	{"forgot-password", "Sends a link to reset the password of an account", forgotPassword},
+	{"reset-password", "Sets a new password with the code sent to the email", resetPassword},
+	{"deactivate", "Deactivates the account logged in", deactivate},
+	{"remove", "Removes the account logged in", remove},
+	{"export", "Exports all the personal data of the account logged in", export},
+	{"erase", "Erases the account logged in and its personal data for good", erase},
+	{"technologies", "Shows a technology and the members that know it", technologies},
+	{"members", "Shows the profile of a member", members},
+	{"radars", "Lists the radars or the blips of one of them", radars},
+	{"import-edition", "Imports a radar edition from a Build Your Own Radar file, only for administrators", importEdition},
+	{"export-edition", "Exports a radar edition in the Build Your Own Radar format", exportEdition},
+	{"audit", "Queries the audit log, only for administrators", audit},
+	{"unlock", "Unlocks an account after too many failed logins, only for administrators", unlock},
+	{"restore", "Restores a removed account by its id, only for administrators", restore},
+	{"backup", "Writes a backup archive of the datastore, only for administrators", backup},
+	{"2fa-enroll", "Starts the two-factor authentication enrollment", enrollTwoFactor},
+	{"2fa-confirm", "Enables the two-factor authentication with a code", confirmTwoFactor},
+	{"2fa-disable", "Disables the two-factor authentication with a code", disableTwoFactor},
+	{"2fa-policy", "Requires the two-factor authentication to the admins, only for administrators", twoFactorPolicy},
+}
+
+// errNotLoggedIn raised when the subcommand needs a session and there is no
+// one stored.
+var errNotLoggedIn = errors.New("Not logged in, run radarctl login first")
+
+// radarctl stores the state shared by all the subcommands.
+type radarctl struct {
+	cfg        *Config
+	configPath string
+	format     string
+	client     *client.Client
+	in         io.Reader
+	reader     *bufio.Reader
+	out        io.Writer
+}
+
+func main() {
+	ctl := &radarctl{
+		in:  os.Stdin,
+		out: os.Stdout,
+	}
+
+	/* Parse the arguments. */
+	url := flag.String("url", "", "URL of the Radar API, stored after login")
+	flag.StringVar(&ctl.configPath, "config",
+		filepath.Join(os.Getenv("HOME"), ".radarctl.json"), "Configuration file")
+	flag.StringVar(&ctl.format, "o", "table", "Output format: table, json or yaml")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd := findCommand(flag.Arg(0))
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command %s\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	err := ctl.init(*url)
+	if err == nil {
+		err = cmd.run(ctl, flag.Args()[1:])
+	}
+
+	if err == flag.ErrHelp {
+		os.Exit(2)
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the radarctl command help.
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [flags] <command> [arguments]\n\n", os.Args[0])
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", cmd.name, cmd.summary)
+	}
+
+	fmt.Fprintln(os.Stderr, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// findCommand returns the subcommand by its name or nil if it doesn't exists.
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+// init loads the configuration and creates the API client.
+func (ctl *radarctl) init(url string) error {
+	var err error
+
+	if !validFormat(ctl.format) {
+		return fmt.Errorf("Unknown output format %s", ctl.format)
+	}
+
+	ctl.cfg, err = LoadConfig(ctl.configPath)
+	if err != nil {
+		return err
+	}
+
+	if url != "" {
+		ctl.cfg.URL = url
+	}
+
+	ctl.client = client.New(ctl.cfg.URL)
+
+	return nil
+}
+
+// session returns the params of the stored session.
+func (ctl *radarctl) session() (*client.SessionRequest, error) {
+	if ctl.cfg.Token == "" {
+		return nil, errNotLoggedIn
+	}
+
+	return &client.SessionRequest{ID: ctl.cfg.ID, Token: ctl.cfg.Token}, nil
+}
@@ -0,0 +1,263 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/passwordreset"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
+	"github.com/radar-go/radar/client"
+	"github.com/radar-go/radar/totp"
+	"github.com/radar-go/radar/ui/api/controller"
+)
+
+func TestPrintResult(t *testing.T) {
+	res := &client.AccountResponse{Result: "Account activated successfully", ID: "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c"}
+	testCases := map[string]string{
+		"table": "result:  Account activated successfully\nid:      9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c\n",
+		"json":  "{\n  \"result\": \"Account activated successfully\",\n  \"id\": \"9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c\"\n}\n",
+		"yaml":  "result: Account activated successfully\nid: 9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c\n",
+	}
+
+	for format, expected := range testCases {
+		t.Run(format, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := printResult(out, format, res)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+
+			if out.String() != expected {
+				t.Errorf("Expected %q, Got %q", expected, out)
+			}
+		})
+	}
+
+	out := &bytes.Buffer{}
+	err := printResult(out, "table", []*client.AccountResponse{res, res})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	expected := "RESULT                          ID\n" +
+		"Account activated successfully  9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c\n" +
+		"Account activated successfully  9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, Got %q", expected, out)
+	}
+
+	if validFormat("xml") {
+		t.Error("Expected xml to not be a valid format")
+	}
+}
+
+func TestRadarctl(t *testing.T) {
+	dir, err := ioutil.TempDir("", "radarctl")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ln := fasthttputil.NewInmemoryListener()
+	defer ln.Close()
+	go (&fasthttp.Server{Handler: controller.New().Router.Handler}).Serve(ln)
+
+	out := &bytes.Buffer{}
+	ctl := &radarctl{
+		configPath: filepath.Join(dir, "radarctl.json"),
+		format:     "json",
+		in:         strings.NewReader("ritho\n"),
+		out:        out,
+	}
+
+	err = ctl.init("http://radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctl.client.HTTPClient.Transport = &http.Transport{
+		Dial: func(network, addr string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}
+
+	err = whoami(ctl, nil)
+	if err != errNotLoggedIn {
+		t.Errorf("Expected %s, Got %v", errNotLoggedIn, err)
+	}
+
+	err = register(ctl, []string{"-username", "radarctl", "-name", "Radarctl",
+		"-email", "palvarez@ritho.net"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err = login(ctl, []string{"-username", "radarctl", "-password", "ritho"})
+	if err == nil || !strings.Contains(err.Error(), "The account is not active") {
+		t.Errorf("Expected the inactive account refused, Got %v", err)
+	}
+
+	out.Reset()
+	err = resend(ctl, []string{"radarctl"})
+	if err != nil || !strings.Contains(out.String(), "a new link has been sent") {
+		t.Errorf("Unexpected resend result %s: %v", out, err)
+	}
+
+	acc, err := casesprovider.Datastore().GetAccountByUsername(context.Background(), "radarctl")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	out.Reset()
+	err = activate(ctl, []string{verification.Code(acc)})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if !strings.Contains(out.String(), "Account activated successfully") {
+		t.Errorf("Expected the account to be activated, Got %s", out)
+	}
+
+	err = login(ctl, []string{"-username", "radarctl", "-password", "ritho"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	cfg, err := LoadConfig(ctl.configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.Token == "" || cfg.Username != "radarctl" || cfg.URL != "http://radar" {
+		t.Errorf("Unexpected configuration stored %+v", cfg)
+	}
+
+	out.Reset()
+	err = forgotPassword(ctl, []string{"radarctl"})
+	if err != nil || !strings.Contains(out.String(), "a link to reset its password") {
+		t.Errorf("Unexpected forgot-password result %s: %v", out, err)
+	}
+
+	out.Reset()
+	err = resetPassword(ctl, []string{"-code", passwordreset.Code(acc), "-password", "newpassword"})
+	if err != nil || !strings.Contains(out.String(), "Password changed successfully") {
+		t.Errorf("Unexpected reset-password result %s: %v", out, err)
+	}
+
+	cfg, err = LoadConfig(ctl.configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.Token != "" {
+		t.Errorf("Expected the session to be removed, Got %+v", cfg)
+	}
+
+	err = login(ctl, []string{"-username", "radarctl", "-password", "newpassword"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	out.Reset()
+	err = enrollTwoFactor(ctl, nil)
+	acc, _ = casesprovider.Datastore().GetAccountByUsername(context.Background(), "radarctl")
+	if err != nil || !strings.Contains(out.String(), acc.TwoFactorSecret()) {
+		t.Errorf("Unexpected 2fa-enroll result %s: %v", out, err)
+	}
+
+	out.Reset()
+	code, _ := totp.Code(acc.TwoFactorSecret(), time.Now().Add(-totp.Period))
+	err = confirmTwoFactor(ctl, []string{code})
+	if err != nil || !strings.Contains(out.String(), `"recovery_codes"`) {
+		t.Errorf("Unexpected 2fa-confirm result %s: %v", out, err)
+	}
+
+	err = logout(ctl, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	/* The code is asked after the password. */
+	code, _ = totp.Code(acc.TwoFactorSecret(), time.Now())
+	ctl.in, ctl.reader = strings.NewReader(code+"\n"), nil
+	err = login(ctl, []string{"-username", "radarctl", "-password", "newpassword"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	cfg, err = LoadConfig(ctl.configPath)
+	if err != nil || cfg.Token == "" {
+		t.Errorf("Expected the session stored after the code, Got %+v: %v", cfg, err)
+	}
+
+	err = logout(ctl, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	cfg, err = LoadConfig(ctl.configPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if cfg.Token != "" {
+		t.Errorf("Expected the session to be removed, Got %+v", cfg)
+	}
+
+	out.Reset()
+	err = members(ctl, []string{"radarctl"})
+	if err != nil || !strings.Contains(out.String(), `"username": "radarctl"`) {
+		t.Errorf("Unexpected members output %s: %v", out, err)
+	}
+
+	out.Reset()
+	err = radars(ctl, []string{"people"})
+	if err != nil || !strings.Contains(out.String(), `"flavor": "people"`) {
+		t.Errorf("Unexpected radars output %s: %v", out, err)
+	}
+
+	err = technologies(ctl, nil)
+	if err == nil {
+		t.Error("Expected error calling technologies without name")
+	}
+
+	err = exportEdition(ctl, nil)
+	if err == nil {
+		t.Error("Expected error calling export-edition without edition")
+	}
+
+	err = exportEdition(ctl, []string{"unknown"})
+	if err == nil {
+		t.Error("Expected error exporting an unknown edition")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v2"
+)
+
+// validFormat returns true if format is one of the supported output formats.
+func validFormat(format string) bool {
+	return format == "table" || format == "json" || format == "yaml"
+}
+
+// printResult writes v to out in the given format. The table format prints a
+// row per element when v is a slice of structs and a row per field otherwise.
+func printResult(out io.Writer, format string, v interface{}) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		_, err = out.Write(data)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() == reflect.Slice {
+		if value.Len() == 0 {
+			return nil
+		}
+
+		elemType := reflect.Indirect(value.Index(0)).Type()
+		headers := make([]string, 0, elemType.NumField())
+		for i := 0; i < elemType.NumField(); i++ {
+			headers = append(headers, strings.ToUpper(fieldName(elemType.Field(i))))
+		}
+
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+		for i := 0; i < value.Len(); i++ {
+			elem := reflect.Indirect(value.Index(i))
+			fields := make([]string, 0, elem.NumField())
+			for j := 0; j < elem.NumField(); j++ {
+				fields = append(fields, fmt.Sprint(elem.Field(j).Interface()))
+			}
+
+			fmt.Fprintln(w, strings.Join(fields, "\t"))
+		}
+	} else if value.Kind() == reflect.Struct {
+		for i := 0; i < value.NumField(); i++ {
+			fmt.Fprintf(w, "%s:\t%v\n", fieldName(value.Type().Field(i)),
+				value.Field(i).Interface())
+		}
+	} else {
+		fmt.Fprintln(w, value.Interface())
+	}
+
+	return w.Flush()
+}
+
+// fieldName returns the name of the struct field as it's encoded in json.
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		name = strings.ToLower(field.Name)
+	}
+
+	return name
+}
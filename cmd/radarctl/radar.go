@@ -0,0 +1,139 @@
+package main
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/radar-go/radar/client"
+	"github.com/radar-go/radar/render"
+)
+
+// radars lists the radar flavors, or the blips of a flavor if one is given.
+func radars(ctl *radarctl, args []string) error {
+	if len(args) == 0 {
+		return printResult(ctl.out, ctl.format, render.Flavors)
+	}
+
+	res, err := ctl.client.Radar(&client.RadarRequest{Flavor: args[0]})
+	if err != nil {
+		return err
+	}
+
+	if ctl.format == "table" {
+		return printResult(ctl.out, ctl.format, res.Blips)
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// technologies shows a technology and the members that know it.
+func technologies(ctl *radarctl, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl technologies <name>")
+	}
+
+	res, err := ctl.client.Technology(&client.TechnologyRequest{Name: args[0]})
+	if err != nil {
+		return err
+	}
+
+	if ctl.format == "table" {
+		return printResult(ctl.out, ctl.format, res.Members)
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// members shows the profile of a member.
+func members(ctl *radarctl, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("Usage: radarctl members <username>")
+	}
+
+	res, err := ctl.client.Member(&client.MemberRequest{Username: args[0]})
+	if err != nil {
+		return err
+	}
+
+	if ctl.format == "table" {
+		return printResult(ctl.out, ctl.format, res.Technologies)
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// importEdition creates or updates the blips of a radar edition from a file in
+// the Build Your Own Radar format, csv or json by its extension.
+func importEdition(ctl *radarctl, args []string) error {
+	session, err := ctl.session()
+	if err != nil {
+		return err
+	}
+
+	if len(args) != 2 {
+		return fmt.Errorf("Usage: radarctl import-edition <edition> <file>")
+	}
+
+	data, err := ioutil.ReadFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	res, err := ctl.client.ImportEdition(&client.EditionImportRequest{
+		Token:   session.Token,
+		Edition: args[0],
+		Format:  strings.TrimPrefix(strings.ToLower(filepath.Ext(args[1])), "."),
+		Data:    string(data),
+	})
+	if err != nil {
+		return err
+	}
+
+	if ctl.format == "table" && len(res.Errors) > 0 {
+		return printResult(ctl.out, ctl.format, res.Errors)
+	}
+
+	return printResult(ctl.out, ctl.format, res)
+}
+
+// exportEdition writes a radar edition in the Build Your Own Radar format, csv
+// by default or json.
+func exportEdition(ctl *radarctl, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("Usage: radarctl export-edition <edition> [csv|json]")
+	}
+
+	req := &client.EditionExportRequest{Edition: args[0]}
+	if len(args) == 2 {
+		req.Format = args[1]
+	}
+
+	res, err := ctl.client.ExportEdition(req)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(ctl.out, res.Data)
+	return err
+}
@@ -19,14 +19,81 @@ package config
    along with radar. If not, see <http://www.gnu.org/licenses/>.
 */
 
+import "time"
+
 // Config structure to store the general configurations.
 type Config struct {
 	APIPort int
+	WebPort int
+	// DatastorePath is the file where the datastore is persisted, an empty
+	// path keeps the datastore in memory.
+	DatastorePath string
+	// PublicURL is the address of the web interface used in the links sent
+	// to the users.
+	PublicURL string
+	// Mailer is the url of the mailer sending the emails: smtp://host:port,
+	// file:///path or log.
+	Mailer string
+	// MailFrom is the address sending the emails.
+	MailFrom string
+	// SecretKey signs the links sent to the users, a random one is used if
+	// empty so the links don't survive a restart.
+	SecretKey string
+	// VerificationTTL is the time the email verification links are valid.
+	VerificationTTL time.Duration
+	// ResetTTL is the time the password reset links are valid.
+	ResetTTL time.Duration
+	// OIDCIssuer is the url of the OpenID Connect provider the users log in
+	// with, the single sign-on is disabled if empty.
+	OIDCIssuer string
+	// OIDCClientID and OIDCClientSecret identify the radar in the provider.
+	OIDCClientID     string
+	OIDCClientSecret string
+	// OIDCScopes are the scopes asked to the provider, separated by spaces.
+	OIDCScopes string
+	// OIDCGroups maps the groups of the provider to the radar roles, as a
+	// comma separated list of group=role. Any user can log in if empty.
+	OIDCGroups string
+	// LDAPURL is the url of the LDAP directory the users log in with,
+	// ldap://host:port or ldaps://host:port, disabled if empty.
+	LDAPURL string
+	// LDAPBindDN and LDAPBindPassword are the credentials of the radar in the
+	// directory, the searches are anonymous if empty.
+	LDAPBindDN       string
+	LDAPBindPassword string
+	// LDAPBaseDN is the entry the users are searched under.
+	LDAPBaseDN string
+	// LDAPUserFilter selects the entries of the users.
+	LDAPUserFilter string
+	// LDAPSyncInterval is the time between the synchronizations of the
+	// accounts with the directory, never synchronized if zero.
+	LDAPSyncInterval time.Duration
+	// SCIMToken is the bearer token the identity platform provisions the
+	// accounts with through SCIM, the provisioning is disabled if empty.
+	SCIMToken string
+	// RemovalRetention is the time the removed accounts are kept, so the
+	// administrators can restore them, before being purged. Never purged if
+	// zero.
+	RemovalRetention time.Duration
+	// AutoMigrate applies the pending migrations of the datastore when radar
+	// starts, otherwise it refuses to start until they are applied with the
+	// migrate command.
+	AutoMigrate bool
 }
 
 // New creates and returns a new Config object.
 func New() *Config {
 	return &Config{
-		APIPort: 10000,
+		APIPort:          10000,
+		WebPort:          10080,
+		PublicURL:        "http://localhost:10080",
+		Mailer:           "log",
+		MailFrom:         "radar@localhost",
+		VerificationTTL:  24 * time.Hour,
+		ResetTTL:         time.Hour,
+		OIDCScopes:       "openid email profile",
+		LDAPUserFilter:   "(objectClass=person)",
+		RemovalRetention: 30 * 24 * time.Hour,
+		AutoMigrate:      true,
 	}
 }
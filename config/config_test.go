@@ -20,6 +20,7 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 func TestConfig(t *testing.T) {
@@ -27,4 +28,17 @@ func TestConfig(t *testing.T) {
 	if cfg.APIPort != 10000 {
 		t.Errorf("Expected 10000, got %d", cfg.APIPort)
 	}
+
+	if cfg.WebPort != 10080 {
+		t.Errorf("Expected 10080, got %d", cfg.WebPort)
+	}
+
+	if cfg.DatastorePath != "" {
+		t.Errorf("Expected an in memory datastore, got %s", cfg.DatastorePath)
+	}
+
+	if cfg.Mailer != "log" || cfg.VerificationTTL != 24*time.Hour {
+		t.Errorf("Expected the log mailer and 24h links, got %s and %s", cfg.Mailer,
+			cfg.VerificationTTL)
+	}
 }
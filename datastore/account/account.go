@@ -20,23 +20,108 @@ package account
 */
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
 	"github.com/goware/emailx"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/radar-go/radar"
 	"github.com/radar-go/radar/entities/member"
+	role "github.com/radar-go/radar/entities/role/api"
+	technology "github.com/radar-go/radar/entities/technology/api"
+	"github.com/radar-go/radar/totp"
 )
 
-var accountSeq int
+// maxPasswordLength is the length of the longest password bcrypt hashes.
+const maxPasswordLength = 72
+
+// unknownHash is the hash of a random password, with the cost of the account
+// passwords, to check the ones of the accounts that don't exist.
+const unknownHash = "$2a$10$CjqXmuyHvAZ372Ew4ez/Tu0VbhNfamyGHEAsegRcdTs7QyavmaN2W"
 
 // Account represents an account in the data store.
 type Account struct {
 	member.Member
+
+	/* The id is internal to the datastore, the accounts are identified out of
+	it by their UUID. Both are assigned when the account is stored. */
 	id       int
+	uuid     string
 	username string
 	email    string
-	password string
+	password string // bcrypt hash
 	active   bool
+	admin    bool
+
+	/* The accounts deactivated are told apart from the ones whose email isn't
+	verified yet, so they are only activated again on purpose. */
+	deactivated bool
+
+	/* The removed accounts are kept until the retention period ends, so they
+	can be restored. */
+	removed time.Time
+
+	/* The identity of the account in the single sign-on provider, as
+	issuer|subject, so it keeps linked if the email changes. */
+	externalID string
+
+	/* The secret is set on the enrollment, but the two-factor authentication
+	is enabled only once the first code is confirmed. */
+	twoFactorSecret string
+	twoFactor       bool
+	twoFactorStep   int64
+	recoveryCodes   []string
+
+	apiKeys []APIKey
+}
+
+// record is the representation of an account when it's persisted.
+type record struct {
+	ID           int                `json:"id"`
+	UUID         string             `json:"uuid,omitempty"`
+	Username     string             `json:"username"`
+	Name         string             `json:"name"`
+	Email        string             `json:"email"`
+	Password     string             `json:"password"`
+	Active       bool               `json:"active"`
+	Deactivated  bool               `json:"deactivated,omitempty"`
+	Admin        bool               `json:"admin"`
+	Removed      *time.Time         `json:"removed,omitempty"`
+	ExternalID   string             `json:"external_id,omitempty"`
+	TwoFactor    *twoFactorRecord   `json:"two_factor,omitempty"`
+	APIKeys      []apiKeyRecord     `json:"api_keys,omitempty"`
+	Roles        []roleRecord       `json:"roles,omitempty"`
+	Technologies []technologyRecord `json:"technologies,omitempty"`
+}
+
+// twoFactorRecord is the representation of the two-factor authentication of
+// an account when it's persisted.
+type twoFactorRecord struct {
+	Secret        string   `json:"secret"`
+	Enabled       bool     `json:"enabled"`
+	Step          int64    `json:"step,omitempty"`
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+// roleRecord is the representation of a member role when it's persisted.
+type roleRecord struct {
+	Title    string    `json:"title"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// technologyRecord is the representation of a member technology when it's
+// persisted.
+type technologyRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Level int    `json:"level"`
 }
 
 // New returns a new Account object.
@@ -56,17 +141,26 @@ func New(username, name, email, password string) (*Account, error) {
 		return nil, err
 	}
 
-	accountSeq++
-	account.id = accountSeq
-
 	return account, nil
 }
 
-// ID returns the account id.
+// ID returns the internal id of the account in the datastore.
 func (a *Account) ID() int {
 	return a.id
 }
 
+// UUID returns the public identifier of the account.
+func (a *Account) UUID() string {
+	return a.uuid
+}
+
+// SetIdentifiers sets the internal id and the public identifier of the
+// account, assigned by the datastore when it's stored.
+func (a *Account) SetIdentifiers(id int, uuid string) {
+	a.id = id
+	a.uuid = uuid
+}
+
 // Username returns the account username.
 func (a *Account) Username() string {
 	return a.username
@@ -77,16 +171,44 @@ func (a *Account) Email() string {
 	return a.email
 }
 
-// Password returns the account password.
+// Password returns the bcrypt hash of the account password.
 func (a *Account) Password() string {
 	return a.password
 }
 
+// CheckPassword returns true if the password is the one of the account.
+func (a *Account) CheckPassword(p string) bool {
+	if a.password == "" {
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(a.password), []byte(radar.CleanString(p))) == nil
+}
+
+// CheckUnknownPassword checks the password of an account that doesn't exist,
+// taking as long as CheckPassword so the accounts can't be told apart by the
+// time their logins take.
+func CheckUnknownPassword(p string) {
+	_ = bcrypt.CompareHashAndPassword([]byte(unknownHash), []byte(radar.CleanString(p)))
+}
+
 // IsActive returns true if the account is active or false otherwise.
 func (a *Account) IsActive() bool {
 	return a.active
 }
 
+// IsDeactivated returns true if the account has been deactivated, or false if
+// it's active or its email isn't verified yet.
+func (a *Account) IsDeactivated() bool {
+	return a.deactivated
+}
+
+// IsAdmin returns true if the account have administration privileges or false
+// otherwise.
+func (a *Account) IsAdmin() bool {
+	return a.admin
+}
+
 // SetUsername sets the account username.
 func (a *Account) SetUsername(username string) error {
 	newUsername := radar.CleanString(username)
@@ -111,31 +233,377 @@ func (a *Account) SetEmail(e string) error {
 	return nil
 }
 
-// SetPassword sets the account password.
+// SetPassword sets the account password, storing only its hash.
 func (a *Account) SetPassword(p string) error {
 	newPassword := radar.CleanString(p)
 	if len(newPassword) < 5 {
 		return ErrPasswordTooShort
 	}
 
-	a.password = newPassword
+	if len(newPassword) > maxPasswordLength {
+		return ErrPasswordTooLong
+	}
+
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	a.password = hash
 
 	return nil
 }
 
+// HashPassword returns the bcrypt hash of the password.
+func HashPassword(p string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
+	if err != nil {
+		return "", errors.Wrap(err, "Error hashing the password")
+	}
+
+	return string(hash), nil
+}
+
+// IsPasswordHash returns true if the password is already a bcrypt hash.
+func IsPasswordHash(p string) bool {
+	_, err := bcrypt.Cost([]byte(p))
+	return err == nil
+}
+
 // Activate sets the account to active.
 func (a *Account) Activate() {
 	a.active = true
+	a.deactivated = false
 }
 
-// Deactivate sets the account to not active.
+// Deactivate sets the account to not active until it's activated again.
 func (a *Account) Deactivate() {
 	a.active = false
+	a.deactivated = true
+}
+
+// SetAdmin grants or revokes the administration privileges of the account.
+func (a *Account) SetAdmin(admin bool) {
+	a.admin = admin
+}
+
+// Removed returns when the account was removed, zero if it's not removed.
+func (a *Account) Removed() time.Time {
+	return a.removed
+}
+
+// IsRemoved returns true if the account is removed.
+func (a *Account) IsRemoved() bool {
+	return !a.removed.IsZero()
+}
+
+// Remove marks the account as removed at t, its member becomes a former
+// member of the organization.
+func (a *Account) Remove(t time.Time) {
+	a.removed = t
+	a.SetFormer(true)
+}
+
+// Restore undoes the removal of the account.
+func (a *Account) Restore() {
+	a.removed = time.Time{}
+	a.SetFormer(false)
+}
+
+// Erase erases the personal data of the account and anonymizes its member,
+// so what references it shows a former member. The UUID and the username are
+// kept to record the erasure.
+func (a *Account) Erase() {
+	a.Anonymize()
+	a.email = ""
+	a.password = ""
+	a.active = false
+	a.admin = false
+	a.externalID = ""
+	a.DisableTwoFactor()
+	a.apiKeys = nil
+}
+
+// ExternalID returns the identity of the account in the single sign-on
+// provider, empty if it's not linked.
+func (a *Account) ExternalID() string {
+	return a.externalID
+}
+
+// SetExternalID links the account with its identity in the single sign-on
+// provider.
+func (a *Account) SetExternalID(id string) {
+	a.externalID = id
+}
+
+// Title returns the title of the current role of the member, empty if it has
+// no active role.
+func (a *Account) Title() string {
+	current := a.CurrentRole()
+	if current == nil || !current.IsActive() {
+		return ""
+	}
+
+	return current.Title()
+}
+
+// SetTitle makes title the current role of the member since t, finishing the
+// previous one so the history of the member is kept. It returns true if the
+// title has changed.
+func (a *Account) SetTitle(title string, t time.Time) (bool, error) {
+	if title == "" || title == a.Title() {
+		return false, nil
+	}
+
+	r, err := role.New(title, t, time.Time{})
+	if err != nil {
+		return false, err
+	}
+
+	if current := a.CurrentRole(); current != nil && current.IsActive() {
+		if err = current.SetFinished(t); err != nil {
+			return false, err
+		}
+	}
+
+	a.AddRole(r)
+
+	return true, nil
+}
+
+// HasTwoFactor returns true if the account logs in with the two-factor
+// authentication or false otherwise.
+func (a *Account) HasTwoFactor() bool {
+	return a.twoFactor
+}
+
+// TwoFactorSecret returns the secret of the two-factor authentication codes,
+// empty if the account never enrolled it.
+func (a *Account) TwoFactorSecret() string {
+	return a.twoFactorSecret
+}
+
+// SetTwoFactorSecret starts the enrollment of the two-factor authentication
+// with a new secret, which is not used to log in until the enrollment is
+// confirmed.
+func (a *Account) SetTwoFactorSecret(secret string) error {
+	if a.twoFactor {
+		return ErrTwoFactorEnabled
+	}
+
+	a.twoFactorSecret = secret
+	a.twoFactorStep = 0
+
+	return nil
+}
+
+// EnableTwoFactor confirms the enrollment of the two-factor authentication
+// with a code of the secret, replacing the recovery codes of the account.
+func (a *Account) EnableTwoFactor(code string, t time.Time, recoveryCodes []string) error {
+	if a.twoFactor {
+		return ErrTwoFactorEnabled
+	}
+
+	if a.twoFactorSecret == "" {
+		return ErrTwoFactorNotEnrolled
+	}
+
+	if !a.CheckTwoFactorCode(code, t) {
+		return ErrTwoFactorCode
+	}
+
+	a.twoFactor = true
+	a.recoveryCodes = make([]string, 0, len(recoveryCodes))
+	for _, c := range recoveryCodes {
+		a.recoveryCodes = append(a.recoveryCodes, hashRecoveryCode(c))
+	}
+
+	return nil
+}
+
+// DisableTwoFactor removes the two-factor authentication of the account.
+func (a *Account) DisableTwoFactor() {
+	a.twoFactorSecret = ""
+	a.twoFactor = false
+	a.twoFactorStep = 0
+	a.recoveryCodes = nil
+}
+
+// CheckTwoFactorCode returns true if the code of the authenticator app is
+// valid at t. Every code can be used only once.
+func (a *Account) CheckTwoFactorCode(code string, t time.Time) bool {
+	if a.twoFactorSecret == "" {
+		return false
+	}
+
+	step, ok := totp.Validate(a.twoFactorSecret, code, t)
+	if !ok || step <= a.twoFactorStep {
+		return false
+	}
+
+	a.twoFactorStep = step
+
+	return true
+}
+
+// UseRecoveryCode returns true if the code is one of the recovery codes of the
+// account, removing it so it can't be used again.
+func (a *Account) UseRecoveryCode(code string) bool {
+	hash := hashRecoveryCode(code)
+	for i, c := range a.recoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(c), []byte(hash)) == 1 {
+			a.recoveryCodes = append(a.recoveryCodes[:i:i], a.recoveryCodes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// RecoveryCodesLeft returns the number of recovery codes not used yet.
+func (a *Account) RecoveryCodesLeft() int {
+	return len(a.recoveryCodes)
+}
+
+// hashRecoveryCode returns the hash of the recovery code stored instead of the
+// code, ignoring the case and the separators the users type.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// Clone returns a deep copy of the account, which can be changed without
+// changing the account.
+func (a *Account) Clone() *Account {
+	c := *a
+	c.Member = member.Member{}
+	c.SetName(a.Name())
+	c.SetFormer(a.IsFormer())
+	for _, r := range a.Roles() {
+		/* The role is valid already, so copying it can't fail. */
+		cr, _ := role.New(r.Title(), r.Started(), r.Finished())
+		c.AddRole(cr)
+	}
+
+	for _, t := range a.Technologies() {
+		c.AddTechnology(technology.New(t.Name(), t.Type(), t.Level()))
+	}
+
+	c.recoveryCodes = append([]string(nil), a.recoveryCodes...)
+	c.apiKeys = nil
+	for _, k := range a.apiKeys {
+		k.Scopes = append([]string(nil), k.Scopes...)
+		c.apiKeys = append(c.apiKeys, k)
+	}
+
+	return &c
 }
 
 // Equals check that two accounts are deep equal.
 func (a *Account) Equals(compare *Account) bool {
 	return a.Member.Equals(compare.Member) && a.ID() == compare.ID() &&
-		a.Email() == compare.Email() && a.Username() == compare.Username() &&
+		a.UUID() == compare.UUID() && a.Email() == compare.Email() && a.Username() == compare.Username() &&
 		a.Password() == compare.Password()
 }
+
+// MarshalJSON returns the account encoded as json to persist it.
+func (a *Account) MarshalJSON() ([]byte, error) {
+	r := record{
+		ID:          a.id,
+		UUID:        a.uuid,
+		Username:    a.username,
+		Name:        a.Name(),
+		Email:       a.email,
+		Password:    a.password,
+		Active:      a.active,
+		Deactivated: a.deactivated,
+		Admin:       a.admin,
+		ExternalID:  a.externalID,
+	}
+
+	if a.IsRemoved() {
+		r.Removed = &a.removed
+	}
+
+	if a.twoFactorSecret != "" {
+		r.TwoFactor = &twoFactorRecord{
+			Secret:        a.twoFactorSecret,
+			Enabled:       a.twoFactor,
+			Step:          a.twoFactorStep,
+			RecoveryCodes: a.recoveryCodes,
+		}
+	}
+
+	for _, k := range a.apiKeys {
+		r.APIKeys = append(r.APIKeys, apiKeyRecord{APIKey: k, Hash: k.hash})
+	}
+
+	for _, accRole := range a.Roles() {
+		r.Roles = append(r.Roles, roleRecord{
+			Title:    accRole.Title(),
+			Started:  accRole.Started(),
+			Finished: accRole.Finished(),
+		})
+	}
+
+	for _, tech := range a.Technologies() {
+		r.Technologies = append(r.Technologies, technologyRecord{
+			Name:  tech.Name(),
+			Type:  tech.Type(),
+			Level: tech.Level(),
+		})
+	}
+
+	return json.Marshal(r)
+}
+
+// UnmarshalJSON restores an account previously encoded with MarshalJSON.
+func (a *Account) UnmarshalJSON(data []byte) error {
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	a.id = r.ID
+	a.uuid = r.UUID
+	a.username = r.Username
+	a.SetName(r.Name)
+	a.email = r.Email
+	a.password = r.Password
+	a.active = r.Active
+	a.deactivated = r.Deactivated
+	a.admin = r.Admin
+	a.externalID = r.ExternalID
+	if r.Removed != nil {
+		a.Remove(*r.Removed)
+	}
+
+	if r.TwoFactor != nil {
+		a.twoFactorSecret = r.TwoFactor.Secret
+		a.twoFactor = r.TwoFactor.Enabled
+		a.twoFactorStep = r.TwoFactor.Step
+		a.recoveryCodes = r.TwoFactor.RecoveryCodes
+	}
+
+	for _, kr := range r.APIKeys {
+		k := kr.APIKey
+		k.hash = kr.Hash
+		a.apiKeys = append(a.apiKeys, k)
+	}
+
+	for _, rr := range r.Roles {
+		accRole, err := role.New(rr.Title, rr.Started, rr.Finished)
+		if err != nil {
+			return err
+		}
+
+		a.AddRole(accRole)
+	}
+
+	for _, tr := range r.Technologies {
+		a.AddTechnology(technology.New(tr.Name, tr.Type, tr.Level))
+	}
+
+	return nil
+}
@@ -19,10 +19,18 @@ package account
 */
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/goware/emailx"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	role "github.com/radar-go/radar/entities/role/api"
+	technology "github.com/radar-go/radar/entities/technology/api"
+	"github.com/radar-go/radar/totp"
 )
 
 func TestAccount(t *testing.T) {
@@ -31,8 +39,14 @@ func TestAccount(t *testing.T) {
 		t.Errorf("Unexpected error: %s", err)
 	}
 
-	if account.ID() != 1 {
-		t.Errorf("Expected user id 1, Got %d", account.ID())
+	if account.ID() != 0 || account.UUID() != "" {
+		t.Errorf("Expected the identifiers to be unset, Got %d, %s", account.ID(), account.UUID())
+	}
+
+	account.SetIdentifiers(1, "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c")
+	if account.ID() != 1 || account.UUID() != "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c" {
+		t.Errorf("Expected the identifiers 1 and 9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c, Got %d, %s",
+			account.ID(), account.UUID())
 	}
 
 	if account.Username() != "username" {
@@ -47,8 +61,20 @@ func TestAccount(t *testing.T) {
 		t.Errorf("Expected 'email@ritho.net', Got %s", account.email)
 	}
 
-	if account.Password() != "password" {
-		t.Errorf("Expected password, Got %s", account.password)
+	if !account.CheckPassword("password") || account.CheckPassword("other") ||
+		!IsPasswordHash(account.Password()) {
+		t.Errorf("Expected the hash of password, Got %s", account.password)
+	}
+
+	err = account.SetPassword(strings.Repeat("p", maxPasswordLength+1))
+	if err != ErrPasswordTooLong {
+		t.Errorf("Expected %s, Got %v", ErrPasswordTooLong, err)
+	}
+
+	/* The unknown accounts take as long as the known ones to check. */
+	cost, err := bcrypt.Cost([]byte(unknownHash))
+	if err != nil || cost != bcrypt.DefaultCost {
+		t.Errorf("Expected the cost %d for the unknown accounts, Got %d: %v", bcrypt.DefaultCost, cost, err)
 	}
 }
 
@@ -78,3 +104,217 @@ func TestAccountFail(t *testing.T) {
 		t.Errorf("Unexpected error: %s", err)
 	}
 }
+
+func TestAccountJSON(t *testing.T) {
+	acc, err := New("username", "name", "email@ritho.net", "password")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	acc.SetIdentifiers(7, "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c")
+	acc.Activate()
+	acc.SetAdmin(true)
+	acc.SetExternalID("https://sso.ritho.net|1234")
+	developer, err := role.New("developer", time.Now(), time.Time{})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	acc.AddRole(developer)
+	acc.AddTechnology(technology.New("Go", "languages", 4))
+	data, err := json.Marshal(acc)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	restored := &Account{}
+	err = json.Unmarshal(data, restored)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if !restored.Equals(acc) || !restored.IsActive() || !restored.IsAdmin() ||
+		restored.ExternalID() != acc.ExternalID() {
+		t.Errorf("Expected %s, Got %+v", data, restored)
+	}
+
+	if restored.CurrentRole() == nil || restored.CurrentRole().Title() != "developer" {
+		t.Errorf("Expected the developer role, Got %+v", restored.Roles())
+	}
+
+	techs := restored.Technologies()
+	if len(techs) != 1 || !techs[0].Equals(acc.Technologies()[0]) || techs[0].Level() != 4 {
+		t.Errorf("Expected the Go technology, Got %+v", techs)
+	}
+
+	if restored.IsRemoved() || restored.IsFormer() {
+		t.Error("Expected the account not to be removed")
+	}
+
+	removed := time.Date(2018, 3, 1, 12, 0, 0, 0, time.UTC)
+	acc.Remove(removed)
+	data, _ = json.Marshal(acc)
+	restored = &Account{}
+	err = json.Unmarshal(data, restored)
+	if err != nil || !restored.Removed().Equal(removed) || !restored.IsFormer() {
+		t.Errorf("Expected the account removed at %s, Got %s: %v", removed, restored.Removed(), err)
+	}
+
+	restored.Restore()
+	if restored.IsRemoved() || restored.IsFormer() {
+		t.Error("Expected the account to be restored")
+	}
+
+	if restored.IsDeactivated() {
+		t.Error("Expected the account not to be deactivated")
+	}
+
+	restored.Deactivate()
+	data, _ = json.Marshal(restored)
+	restored = &Account{}
+	err = json.Unmarshal(data, restored)
+	if err != nil || restored.IsActive() || !restored.IsDeactivated() {
+		t.Errorf("Expected the account deactivated, Got %s: %v", data, err)
+	}
+
+	restored.Activate()
+	if !restored.IsActive() || restored.IsDeactivated() {
+		t.Error("Expected the account to be activated again")
+	}
+
+	restored.Erase()
+	if restored.Name() != "" || restored.Email() != "" || restored.Password() != "" ||
+		!restored.IsFormer() || restored.UUID() != acc.UUID() {
+		t.Errorf("Expected the account to be erased, Got %s %s", restored.Name(), restored.Email())
+	}
+
+	err = json.Unmarshal([]byte(`{"id": "1"}`), restored)
+	if err == nil {
+		t.Error("Expected error restoring an account")
+	}
+}
+
+func TestAccountClone(t *testing.T) {
+	acc, err := New("username", "name", "email@ritho.net", "password")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	started := time.Now().Add(-time.Hour)
+	acc.SetIdentifiers(7, "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c")
+	acc.Activate()
+	_, _ = acc.SetTitle("developer", started)
+	acc.AddTechnology(technology.New("Go", "languages", 4))
+	err = acc.AddAPIKey("key", "ci", "radar_key", []string{ScopeRead}, started, started.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	clone := acc.Clone()
+	if !clone.Equals(acc) || !clone.IsActive() || clone.Title() != "developer" ||
+		len(clone.Technologies()) != 1 || len(clone.APIKeys()) != 1 {
+		t.Fatalf("Expected a copy of the account, Got %+v", clone)
+	}
+
+	_ = clone.SetUsername("renamed")
+	_, _ = clone.SetTitle("architect", time.Now())
+	clone.apiKeys[0].Scopes[0] = ScopeAdmin
+	clone.AddTechnology(technology.New("Rust", "languages", 1))
+	clone.Deactivate()
+	if acc.Username() != "username" || acc.Title() != "developer" || len(acc.Roles()) != 1 ||
+		!acc.CurrentRole().IsActive() || len(acc.Technologies()) != 1 || !acc.IsActive() ||
+		acc.APIKeys()[0].Scopes[0] != ScopeRead {
+		t.Errorf("Expected the account not to change with its copy, Got %+v", acc)
+	}
+}
+
+func TestAccountTwoFactor(t *testing.T) {
+	acc, err := New("username", "name", "email@ritho.net", "password")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	now := time.Now()
+	err = acc.EnableTwoFactor("123456", now, nil)
+	if err != ErrTwoFactorNotEnrolled {
+		t.Errorf("Expected %s, Got %v", ErrTwoFactorNotEnrolled, err)
+	}
+
+	secret, _ := totp.GenerateSecret()
+	err = acc.SetTwoFactorSecret(secret)
+	if err != nil || acc.HasTwoFactor() {
+		t.Errorf("Expected the enrollment pending, Got %t: %v", acc.HasTwoFactor(), err)
+	}
+
+	code, _ := totp.Code(secret, now)
+	err = acc.EnableTwoFactor(code, now, []string{"abcd-efgh", "ijkl-mnop"})
+	if err != nil || !acc.HasTwoFactor() || acc.RecoveryCodesLeft() != 2 {
+		t.Errorf("Expected two-factor enabled, Got %t: %v", acc.HasTwoFactor(), err)
+	}
+
+	if acc.CheckTwoFactorCode(code, now) {
+		t.Error("Expected the code refused the second time")
+	}
+
+	next, _ := totp.Code(secret, now.Add(totp.Period))
+	if !acc.CheckTwoFactorCode(next, now.Add(totp.Period)) {
+		t.Error("Expected the code of the next period valid")
+	}
+
+	if err = acc.SetTwoFactorSecret(secret); err != ErrTwoFactorEnabled {
+		t.Errorf("Expected %s, Got %v", ErrTwoFactorEnabled, err)
+	}
+
+	data, err := json.Marshal(acc)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	restored := &Account{}
+	err = json.Unmarshal(data, restored)
+	if err != nil || !restored.HasTwoFactor() || restored.TwoFactorSecret() != secret {
+		t.Errorf("Expected the two-factor authentication restored from %s: %v", data, err)
+	}
+
+	if !restored.UseRecoveryCode("ABCD EFGH") || restored.UseRecoveryCode("abcd-efgh") {
+		t.Error("Expected the recovery code valid only once")
+	}
+
+	if restored.RecoveryCodesLeft() != 1 || acc.RecoveryCodesLeft() != 2 {
+		t.Errorf("Expected 1 recovery code left, Got %d", restored.RecoveryCodesLeft())
+	}
+
+	restored.DisableTwoFactor()
+	if restored.HasTwoFactor() || restored.TwoFactorSecret() != "" || restored.CheckTwoFactorCode(next, now) {
+		t.Error("Expected the two-factor authentication disabled")
+	}
+}
+
+func TestAccountTitle(t *testing.T) {
+	acc, err := New("username", "name", "email@ritho.net", "password")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	now := time.Now()
+	changed, err := acc.SetTitle("", now)
+	if changed || err != nil || acc.Title() != "" {
+		t.Errorf("Expected no title, Got %s: %v", acc.Title(), err)
+	}
+
+	changed, err = acc.SetTitle("Developer", now)
+	if !changed || err != nil || acc.Title() != "Developer" {
+		t.Errorf("Expected Developer, Got %s: %v", acc.Title(), err)
+	}
+
+	changed, _ = acc.SetTitle("Developer", now.Add(time.Hour))
+	if changed || len(acc.Roles()) != 1 {
+		t.Error("Expected the title unchanged")
+	}
+
+	changed, err = acc.SetTitle("Architect", now.Add(time.Hour))
+	if !changed || err != nil || acc.Title() != "Architect" || len(acc.Roles()) != 2 ||
+		acc.Roles()[0].IsActive() {
+		t.Errorf("Expected the previous role finished, Got %v: %v", acc.Roles(), err)
+	}
+}
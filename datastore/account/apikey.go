@@ -0,0 +1,191 @@
+package account
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Scopes of the API keys: reading the data, changing it and administering
+// the radar.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// APIKeyPrefix starts every API key, telling them apart from the session
+// tokens.
+const APIKeyPrefix = "radar_"
+
+// MaxAPIKeyLifetime is the longest time an API key can be used.
+const MaxAPIKeyLifetime = 365 * 24 * time.Hour
+
+// APIKey is a personal key of an account, used by the scripts to run the use
+// cases allowed by its scopes without logging in. Only the hash of the key is
+// stored.
+type APIKey struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Scopes   []string  `json:"scopes"`
+	Created  time.Time `json:"created"`
+	Expires  time.Time `json:"expires"`
+	LastUsed time.Time `json:"last_used"`
+	hash     string
+}
+
+// apiKeyRecord is the representation of an API key when it's persisted.
+type apiKeyRecord struct {
+	APIKey
+	Hash string `json:"hash"`
+}
+
+// ValidScope returns true if the scope is one of the scopes of the API keys.
+func ValidScope(scope string) bool {
+	return scope == ScopeRead || scope == ScopeWrite || scope == ScopeAdmin
+}
+
+// HasScope returns true if the key has the scope.
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Hash returns the hash of the key, stored instead of the key itself.
+func (k APIKey) Hash() string {
+	return k.hash
+}
+
+// Expired returns true if the key can't be used at t.
+func (k APIKey) Expired(t time.Time) bool {
+	return !t.Before(k.Expires)
+}
+
+// AddAPIKey adds a new API key to the account, the key itself is not stored
+// but its hash.
+func (a *Account) AddAPIKey(id, name, key string, scopes []string, created, expires time.Time) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrAPIKeyName
+	}
+
+	for _, k := range a.apiKeys {
+		if k.Name == name {
+			return ErrAPIKeyName
+		}
+	}
+
+	if len(scopes) == 0 {
+		return ErrAPIKeyScopeUnknown
+	}
+
+	for _, scope := range scopes {
+		if !ValidScope(scope) {
+			return ErrAPIKeyScopeUnknown
+		}
+	}
+
+	if !expires.After(created) || expires.Sub(created) > MaxAPIKeyLifetime {
+		return ErrAPIKeyExpiration
+	}
+
+	a.apiKeys = append(a.apiKeys, APIKey{
+		ID:      id,
+		Name:    name,
+		Scopes:  append([]string(nil), scopes...),
+		Created: created.UTC(),
+		Expires: expires.UTC(),
+		hash:    HashAPIKey(key),
+	})
+
+	return nil
+}
+
+// APIKeys returns the API keys of the account.
+func (a *Account) APIKeys() []APIKey {
+	return append([]APIKey(nil), a.apiKeys...)
+}
+
+// RevokeAPIKey removes the API key with the id, so it can't be used anymore.
+func (a *Account) RevokeAPIKey(id string) error {
+	for i, k := range a.apiKeys {
+		if k.ID == id {
+			a.apiKeys = append(a.apiKeys[:i:i], a.apiKeys[i+1:]...)
+			return nil
+		}
+	}
+
+	return ErrAPIKeyNotExists
+}
+
+// APIKey returns the API key of the account matching the key if it hasn't
+// expired at t.
+func (a *Account) APIKey(key string, t time.Time) (APIKey, bool) {
+	i := a.apiKey(key, t)
+	if i < 0 {
+		return APIKey{}, false
+	}
+
+	return a.apiKeys[i], true
+}
+
+// UseAPIKey returns the API key of the account matching the key if it hasn't
+// expired at t, storing t as the last time it was used.
+func (a *Account) UseAPIKey(key string, t time.Time) (APIKey, bool) {
+	i := a.apiKey(key, t)
+	if i < 0 {
+		return APIKey{}, false
+	}
+
+	a.apiKeys[i].LastUsed = t.UTC()
+	return a.apiKeys[i], true
+}
+
+// apiKey returns the index of the API key of the account matching the key if
+// it hasn't expired at t, or -1 otherwise.
+func (a *Account) apiKey(key string, t time.Time) int {
+	hash := HashAPIKey(key)
+	for i, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(k.hash), []byte(hash)) != 1 {
+			continue
+		}
+
+		if k.Expired(t) {
+			return -1
+		}
+
+		return i
+	}
+
+	return -1
+}
+
+// HashAPIKey returns the hash of the API key stored instead of the key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
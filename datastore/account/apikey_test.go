@@ -0,0 +1,104 @@
+package account
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccountAPIKeys(t *testing.T) {
+	acc, err := New("username", "name", "email@ritho.net", "password")
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	now := time.Now()
+	expires := now.Add(24 * time.Hour)
+	testCases := map[string]struct {
+		name     string
+		scopes   []string
+		expires  time.Time
+		expected error
+	}{
+		"NoName":        {"", []string{ScopeRead}, expires, ErrAPIKeyName},
+		"NoScopes":      {"ci", nil, expires, ErrAPIKeyScopeUnknown},
+		"UnknownScope":  {"ci", []string{"delete"}, expires, ErrAPIKeyScopeUnknown},
+		"Expired":       {"ci", []string{ScopeRead}, now, ErrAPIKeyExpiration},
+		"TooLong":       {"ci", []string{ScopeRead}, now.Add(2 * MaxAPIKeyLifetime), ErrAPIKeyExpiration},
+		"Success":       {"ci", []string{ScopeRead, ScopeWrite}, expires, nil},
+		"NameDuplicate": {" ci ", []string{ScopeRead}, expires, ErrAPIKeyName},
+	}
+
+	for _, name := range []string{"NoName", "NoScopes", "UnknownScope", "Expired", "TooLong", "Success", "NameDuplicate"} {
+		tc := testCases[name]
+		err = acc.AddAPIKey("1234", tc.name, "radar_secret", tc.scopes, now, tc.expires)
+		if err != tc.expected {
+			t.Errorf("%s: Expected %v, Got %v", name, tc.expected, err)
+		}
+	}
+
+	if _, ok := acc.UseAPIKey("radar_wrong", now); ok {
+		t.Error("Expected the wrong key refused")
+	}
+
+	key, ok := acc.APIKey("radar_secret", now)
+	if !ok || !key.LastUsed.IsZero() || key.Hash() != HashAPIKey("radar_secret") {
+		t.Errorf("Expected the key unused, Got %+v", key)
+	}
+
+	key, ok = acc.UseAPIKey("radar_secret", now)
+	if !ok || key.ID != "1234" || !key.HasScope(ScopeWrite) || key.HasScope(ScopeAdmin) {
+		t.Errorf("Expected the key with the read and write scopes, Got %+v", key)
+	}
+
+	if _, ok = acc.UseAPIKey("radar_secret", expires); ok {
+		t.Error("Expected the expired key refused")
+	}
+
+	data, err := json.Marshal(acc)
+	if err != nil || strings.Contains(string(data), "radar_secret") {
+		t.Errorf("Expected only the hash of the key stored, Got %s: %v", data, err)
+	}
+
+	restored := &Account{}
+	err = json.Unmarshal(data, restored)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	keys := restored.APIKeys()
+	if len(keys) != 1 || !keys[0].LastUsed.Equal(now) || keys[0].Name != "ci" {
+		t.Errorf("Expected the key restored, Got %+v", keys)
+	}
+
+	if _, ok = restored.UseAPIKey("radar_secret", now); !ok {
+		t.Error("Expected the restored key valid")
+	}
+
+	if err = restored.RevokeAPIKey("4321"); err != ErrAPIKeyNotExists {
+		t.Errorf("Expected %s, Got %v", ErrAPIKeyNotExists, err)
+	}
+
+	err = restored.RevokeAPIKey("1234")
+	if _, ok = restored.UseAPIKey("radar_secret", now); err != nil || ok {
+		t.Errorf("Expected the key revoked, Got %v", err)
+	}
+}
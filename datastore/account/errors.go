@@ -19,32 +19,210 @@ package account
 */
 
 import (
-	"github.com/pkg/errors"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/i18n"
 )
 
 // ErrAccountExists raised when the account already exists in the datastore.
-var ErrAccountExists = errors.New("Account already exists")
+var ErrAccountExists = i18n.NewError(&goi18n.Message{
+	ID:    "AccountExists",
+	Other: "Account already exists",
+})
 
 // ErrAccountNotExists raised when the user doesn't exists in the datastore.
-var ErrAccountNotExists = errors.New("Account doesn't exists")
+var ErrAccountNotExists = i18n.NewError(&goi18n.Message{
+	ID:    "AccountNotExists",
+	Other: "Account doesn't exists",
+})
 
 // ErrEmailEmpty raised when the email is empty.
-var ErrEmailEmpty = errors.New("Email is empty")
+var ErrEmailEmpty = i18n.NewError(&goi18n.Message{
+	ID:    "AccountEmailEmpty",
+	Other: "Email is empty",
+})
 
 // ErrUsernameEmpty raised when the username is empty.
-var ErrUsernameEmpty = errors.New("Username is empty")
+var ErrUsernameEmpty = i18n.NewError(&goi18n.Message{
+	ID:    "AccountUsernameEmpty",
+	Other: "Username is empty",
+})
 
 // ErrPasswordEmpty raised when the password is empty.
-var ErrPasswordEmpty = errors.New("Password is empty")
+var ErrPasswordEmpty = i18n.NewError(&goi18n.Message{
+	ID:    "AccountPasswordEmpty",
+	Other: "Password is empty",
+})
 
 // ErrUserAlreadyLogin raised when the user tries to log in more than once.
-var ErrUserAlreadyLogin = errors.New("User already logged in")
+var ErrUserAlreadyLogin = i18n.NewError(&goi18n.Message{
+	ID:    "AccountAlreadyLoggedIn",
+	Other: "User already logged in",
+})
 
 // ErrUserNotLoggedIn raised when the user session is not present.
-var ErrUserNotLoggedIn = errors.New("User not logged in")
+var ErrUserNotLoggedIn = i18n.NewError(&goi18n.Message{
+	ID:    "AccountNotLoggedIn",
+	Other: "User not logged in",
+})
 
 // ErrUsernameTooShort raised when the username is too short.
-var ErrUsernameTooShort = errors.New("Username too short")
+var ErrUsernameTooShort = i18n.NewError(&goi18n.Message{
+	ID:    "AccountUsernameTooShort",
+	Other: "Username too short",
+})
 
 // ErrPasswordTooShort raised when the password is too short.
-var ErrPasswordTooShort = errors.New("Password too short")
+var ErrPasswordTooShort = i18n.NewError(&goi18n.Message{
+	ID:    "AccountPasswordTooShort",
+	Other: "Password too short",
+})
+
+// ErrPasswordTooLong raised when the password is longer than the ones that
+// can be hashed.
+var ErrPasswordTooLong = i18n.NewError(&goi18n.Message{
+	ID:    "AccountPasswordTooLong",
+	Other: "Password too long, use at most 72 characters",
+})
+
+// ErrPasswordUnchanged raised when a password reset sets the same password
+// the account already has.
+var ErrPasswordUnchanged = i18n.NewError(&goi18n.Message{
+	ID:    "AccountPasswordUnchanged",
+	Other: "The new password must be different from the current one",
+})
+
+// ErrInvalidCredentials raised when the login fails, without telling if the
+// account doesn't exist or the password is wrong.
+var ErrInvalidCredentials = i18n.NewError(&goi18n.Message{
+	ID:    "AccountInvalidCredentials",
+	Other: "Wrong username or password",
+})
+
+// ErrAccountInactive raised when logging in an account whose email isn't
+// verified yet or that an administrator deactivated.
+var ErrAccountInactive = i18n.NewError(&goi18n.Message{
+	ID:    "AccountInactive",
+	Other: "The account is not active, verify your email or ask an administrator",
+})
+
+// ErrPasswordMismatch raised when the password doesn't match with the one of
+// the account.
+var ErrPasswordMismatch = i18n.NewError(&goi18n.Message{
+	ID:    "AccountPasswordMismatch",
+	Other: "Password missmatch",
+})
+
+// ErrSessionMismatch raised when the account id doesn't match with the account
+// of the session.
+var ErrSessionMismatch = i18n.NewError(&goi18n.Message{
+	ID:    "AccountSessionMismatch",
+	Other: "The account id doesn't match with the session information",
+})
+
+// ErrUserMismatch raised when the account id doesn't match with the account
+// logged in.
+var ErrUserMismatch = i18n.NewError(&goi18n.Message{
+	ID:    "AccountUserMismatch",
+	Other: "The account id doesn't match with the user logged in",
+})
+
+// ErrNotAdmin raised when the account doesn't have administration privileges.
+var ErrNotAdmin = i18n.NewError(&goi18n.Message{
+	ID:    "AccountNotAdmin",
+	Other: "Administration privileges required",
+})
+
+// ErrTwoFactorEnabled raised when the two-factor authentication is enrolled
+// again while it's enabled.
+var ErrTwoFactorEnabled = i18n.NewError(&goi18n.Message{
+	ID:    "AccountTwoFactorEnabled",
+	Other: "Two-factor authentication is already enabled",
+})
+
+// ErrTwoFactorNotEnrolled raised when the two-factor authentication is
+// confirmed or disabled without enrolling it first.
+var ErrTwoFactorNotEnrolled = i18n.NewError(&goi18n.Message{
+	ID:    "AccountTwoFactorNotEnrolled",
+	Other: "Two-factor authentication is not enrolled",
+})
+
+// ErrTwoFactorCode raised when the two-factor authentication code is wrong or
+// has already been used.
+var ErrTwoFactorCode = i18n.NewError(&goi18n.Message{
+	ID:    "AccountTwoFactorCode",
+	Other: "Wrong two-factor authentication code",
+})
+
+// ErrTwoFactorRequired raised when the account has to enroll the two-factor
+// authentication before doing anything else.
+var ErrTwoFactorRequired = i18n.NewError(&goi18n.Message{
+	ID:    "AccountTwoFactorRequired",
+	Other: "Two-factor authentication is required, enroll it first",
+})
+
+// ErrEmailAmbiguous raised when more than one account is registered with the
+// same email.
+var ErrEmailAmbiguous = i18n.NewError(&goi18n.Message{
+	ID:    "AccountEmailAmbiguous",
+	Other: "More than one account is registered with the email",
+})
+
+// ErrAPIKeyInvalid raised when the API key doesn't exist, has expired or its
+// account is not active.
+var ErrAPIKeyInvalid = i18n.NewError(&goi18n.Message{
+	ID:    "AccountAPIKeyInvalid",
+	Other: "Wrong or expired API key",
+})
+
+// ErrAPIKeyScope raised when the scopes of the API key don't allow running the
+// use case.
+var ErrAPIKeyScope = i18n.NewError(&goi18n.Message{
+	ID:    "AccountAPIKeyScope",
+	Other: "The API key is not allowed to do this operation",
+})
+
+// ErrAPIKeyScopeUnknown raised when an API key is created without scopes or
+// with an unknown one.
+var ErrAPIKeyScopeUnknown = i18n.NewError(&goi18n.Message{
+	ID:    "AccountAPIKeyScopeUnknown",
+	Other: "Unknown API key scope, use read, write or admin",
+})
+
+// ErrAPIKeyName raised when an API key is created without a name or with the
+// name of another key of the account.
+var ErrAPIKeyName = i18n.NewError(&goi18n.Message{
+	ID:    "AccountAPIKeyName",
+	Other: "The API key needs a name not used by other keys",
+})
+
+// ErrAPIKeyExpiration raised when an API key is created already expired or
+// expiring too late.
+var ErrAPIKeyExpiration = i18n.NewError(&goi18n.Message{
+	ID:    "AccountAPIKeyExpiration",
+	Other: "The API key must expire in the future and within a year",
+})
+
+// ErrAPIKeyNotExists raised when the API key to revoke doesn't exist.
+var ErrAPIKeyNotExists = i18n.NewError(&goi18n.Message{
+	ID:    "AccountAPIKeyNotExists",
+	Other: "API key doesn't exists",
+})
+
+// ErrSortUnknown raised when the accounts are sorted by an unknown field.
+var ErrSortUnknown = i18n.NewError(&goi18n.Message{
+	ID:    "AccountSortUnknown",
+	Other: "Unknown sort field, use id, username, name or email",
+})
+
+// ErrQueryOffset raised when the accounts are queried from a negative offset.
+var ErrQueryOffset = i18n.NewError(&goi18n.Message{
+	ID:    "AccountQueryOffset",
+	Other: "The offset of the accounts query can't be negative",
+})
+
+// ErrAccountNotRemoved raised when restoring an account that isn't removed.
+var ErrAccountNotRemoved = i18n.NewError(&goi18n.Message{
+	ID:    "AccountNotRemoved",
+	Other: "The account is not removed",
+})
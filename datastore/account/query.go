@@ -0,0 +1,97 @@
+package account
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"strings"
+
+	"github.com/radar-go/radar"
+)
+
+// Fields the accounts can be sorted by.
+const (
+	SortID       = "id"
+	SortUsername = "username"
+	SortName     = "name"
+	SortEmail    = "email"
+)
+
+// Query selects the accounts of the directory, how they are sorted and the
+// page of them returned.
+type Query struct {
+	/* Search matches the username, the name and, when SearchEmail is set,
+	the email of the accounts, ignoring the case. */
+	Search      string
+	SearchEmail bool
+
+	/* The inactive accounts are left out unless Inactive is set. */
+	Inactive bool
+
+	Sort string
+	Desc bool
+
+	Offset int
+	Limit  int
+}
+
+// ValidSort returns true if the accounts can be sorted by the field.
+func ValidSort(field string) bool {
+	switch field {
+	case SortID, SortUsername, SortName, SortEmail:
+		return true
+	}
+
+	return false
+}
+
+// Match returns true if the account is selected by the query.
+func (q Query) Match(acc *Account) bool {
+	if !q.Inactive && !acc.IsActive() {
+		return false
+	}
+
+	search := radar.CleanString(q.Search)
+	if search == "" {
+		return true
+	}
+
+	return strings.Contains(acc.Username(), search) ||
+		strings.Contains(strings.ToLower(acc.Name()), search) ||
+		q.SearchEmail && strings.Contains(acc.Email(), search)
+}
+
+// Less returns true if the account a goes before b in the sorting of the
+// query. The accounts are sorted by id when the field is empty or they are
+// equal on it.
+func (q Query) Less(a, b *Account) bool {
+	var x, y string
+	switch q.Sort {
+	case SortUsername:
+		x, y = a.Username(), b.Username()
+	case SortName:
+		x, y = strings.ToLower(a.Name()), strings.ToLower(b.Name())
+	case SortEmail:
+		x, y = a.Email(), b.Email()
+	}
+
+	if x == y {
+		return a.ID() != b.ID() && q.Desc != (a.ID() < b.ID())
+	}
+
+	return q.Desc != (x < y)
+}
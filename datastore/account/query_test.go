@@ -0,0 +1,66 @@
+package account
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	ritho, _ := New("ritho", "Pablo Álvarez", "palvarez@ritho.net", "ritho")
+	jdoe, _ := New("jdoe-", "John Doe", "jdoe@example.com", "12345")
+	ritho.SetIdentifiers(1, "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c")
+	jdoe.SetIdentifiers(2, "3f6c2a1e-8b7d-4c5e-9a0f-1d2e3c4b5a69")
+	ritho.Activate()
+
+	testCases := map[string]struct {
+		query    Query
+		acc      *Account
+		expected bool
+	}{
+		"Inactive":        {Query{}, jdoe, false},
+		"IncludeInactive": {Query{Inactive: true}, jdoe, true},
+		"Username":        {Query{Search: "RIT"}, ritho, true},
+		"Name":            {Query{Search: "álvarez"}, ritho, true},
+		"EmailHidden":     {Query{Search: "ritho.net"}, ritho, false},
+		"Email":           {Query{Search: "ritho.net", SearchEmail: true}, ritho, true},
+		"NoMatch":         {Query{Search: "doe"}, ritho, false},
+	}
+
+	for name, tc := range testCases {
+		if tc.query.Match(tc.acc) != tc.expected {
+			t.Errorf("%s: Expected %t, Got %t", name, tc.expected, !tc.expected)
+		}
+	}
+
+	if !(Query{}).Less(ritho, jdoe) || (Query{Desc: true}).Less(ritho, jdoe) {
+		t.Error("Expected the accounts sorted by id by default")
+	}
+
+	if !(Query{Sort: SortName}).Less(jdoe, ritho) || !(Query{Sort: SortEmail, Desc: true}).Less(ritho, jdoe) {
+		t.Error("Expected the accounts sorted by the field")
+	}
+
+	if (Query{Desc: true}).Less(ritho, ritho) {
+		t.Error("Expected an account not to go before itself")
+	}
+
+	if !ValidSort(SortUsername) || ValidSort("password") {
+		t.Error("Expected only the fields of the directory to be valid")
+	}
+}
@@ -0,0 +1,228 @@
+// Package audit implements the audit log of the operations changing the
+// datastore.
+package audit
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// redacted replaces the values of the sensitive fields in the changes.
+const redacted = "[redacted]"
+
+// sensitive fields whose values are never stored in the audit log.
+var sensitive = map[string]bool{"password": true}
+
+// Erased replaces the personal data of the erased accounts in the records.
+const Erased = "[erased]"
+
+// personal fields whose values are replaced when the account is erased.
+var personal = map[string]bool{"username": true, "name": true, "email": true}
+
+// Change represents the values of a field before and after an operation.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Record represents an operation changing the datastore.
+type Record struct {
+	ID        int               `json:"id"`
+	Time      time.Time         `json:"time"`
+	Action    string            `json:"action"`
+	Actor     string            `json:"actor"`
+	Target    string            `json:"target"`
+	TargetID  string            `json:"target_id,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	Changes   map[string]Change `json:"changes,omitempty"`
+}
+
+// UnmarshalJSON restores a record. The records written before the accounts
+// had UUIDs have the numeric id of the account as target id, it's restored as
+// a string for the datastore to replace it.
+func (r *Record) UnmarshalJSON(data []byte) error {
+	type plain Record
+	aux := struct {
+		*plain
+		TargetID interface{} `json:"target_id"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	switch id := aux.TargetID.(type) {
+	case string:
+		r.TargetID = id
+	case float64:
+		r.TargetID = strconv.Itoa(int(id))
+	}
+
+	return nil
+}
+
+// Of returns true if the record was done by or on the account with the UUID
+// and the usernames: by one of its usernames as actor, and on it by its UUID
+// or, when the record has no target id, by one of its usernames as target.
+func (r Record) Of(uuid string, usernames ...string) bool {
+	return isUsername(r.Actor, usernames) || r.onAccount(uuid, usernames)
+}
+
+// Erase replaces the personal data of an erased account in the record: its
+// usernames as actor, and as target with the values of its fields changed.
+// The records with a target id only match the account by its UUID.
+func (r *Record) Erase(uuid string, usernames ...string) {
+	if isUsername(r.Actor, usernames) {
+		r.Actor = Erased
+	}
+
+	if !r.onAccount(uuid, usernames) {
+		return
+	}
+
+	r.Target = Erased
+	for field, change := range r.Changes {
+		if !personal[field] {
+			continue
+		}
+
+		if change.Before != nil {
+			change.Before = Erased
+		}
+
+		if change.After != nil {
+			change.After = Erased
+		}
+
+		r.Changes[field] = change
+	}
+}
+
+// onAccount returns true if the target of the record is the account with the
+// UUID and the usernames.
+func (r Record) onAccount(uuid string, usernames []string) bool {
+	return r.TargetID == uuid || (r.TargetID == "" && isUsername(r.Target, usernames))
+}
+
+// isUsername returns true if the name is one of the usernames.
+func isUsername(name string, usernames []string) bool {
+	for _, username := range usernames {
+		if radar.CleanString(name) == radar.CleanString(username) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter selects the records of the audit log, the empty values match any
+// record.
+type Filter struct {
+	Actor  string
+	Target string
+	Action string
+	Since  time.Time
+	Until  time.Time
+}
+
+// Match returns true if the record matches the filter.
+func (f Filter) Match(r Record) bool {
+	if f.Actor != "" && radar.CleanString(f.Actor) != radar.CleanString(r.Actor) {
+		return false
+	}
+
+	if f.Target != "" && radar.CleanString(f.Target) != radar.CleanString(r.Target) {
+		return false
+	}
+
+	if f.Action != "" && radar.CleanString(f.Action) != radar.CleanString(r.Action) {
+		return false
+	}
+
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+
+	return f.Until.IsZero() || r.Time.Before(f.Until)
+}
+
+// Fields returns the audited fields of an account, or nil if there is no
+// account.
+func Fields(acc *account.Account) map[string]interface{} {
+	if acc == nil {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"username":   acc.Username(),
+		"name":       acc.Name(),
+		"email":      acc.Email(),
+		"password":   acc.Password(),
+		"active":     acc.IsActive(),
+		"admin":      acc.IsAdmin(),
+		"two_factor": acc.HasTwoFactor(),
+		"removed":    acc.IsRemoved(),
+	}
+}
+
+// Diff returns the fields whose value is different before and after an
+// operation. The fields missing in one of the states have a nil value, and
+// the values of the sensitive fields are redacted.
+func Diff(before, after map[string]interface{}) map[string]Change {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	changes := make(map[string]Change)
+	for _, key := range keys {
+		b, a := before[key], after[key]
+		if b == a {
+			continue
+		}
+
+		if sensitive[key] {
+			b, a = redact(b), redact(a)
+		}
+
+		changes[key] = Change{Before: b, After: a}
+	}
+
+	return changes
+}
+
+// redact returns the value hidden, keeping if it was set or not.
+func redact(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	return redacted
+}
@@ -0,0 +1,172 @@
+package audit
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/radar-go/radar/datastore/account"
+)
+
+func TestDiff(t *testing.T) {
+	acc, err := account.New("ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	before := Fields(acc)
+	err = acc.SetEmail("ritho@ritho.net")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err = acc.SetPassword("secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	acc.Activate()
+	changes := Diff(before, Fields(acc))
+	if len(changes) != 3 {
+		t.Errorf("Expected 3 changes, Got %v", changes)
+	}
+
+	if changes["email"].Before != "palvarez@ritho.net" || changes["email"].After != "ritho@ritho.net" {
+		t.Errorf("Unexpected email change %v", changes["email"])
+	}
+
+	if changes["active"].Before != false || changes["active"].After != true {
+		t.Errorf("Unexpected active change %v", changes["active"])
+	}
+
+	if changes["password"].Before != redacted || changes["password"].After != redacted {
+		t.Errorf("Expected the password to be redacted, Got %v", changes["password"])
+	}
+
+	changes = Diff(Fields(acc), Fields(nil))
+	if len(changes) != 8 || changes["username"].Before != "ritho" || changes["username"].After != nil {
+		t.Errorf("Expected all the fields removed, Got %v", changes)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	now := time.Now()
+	rec := Record{
+		Time:   now,
+		Action: "AccountDeactivate",
+		Actor:  "admin",
+		Target: "ritho",
+	}
+
+	testCases := map[string]struct {
+		filter   Filter
+		expected bool
+	}{
+		"Empty":       {Filter{}, true},
+		"Actor":       {Filter{Actor: "Admin"}, true},
+		"OtherActor":  {Filter{Actor: "ritho"}, false},
+		"Target":      {Filter{Target: "ritho"}, true},
+		"OtherTarget": {Filter{Target: "admin"}, false},
+		"Action":      {Filter{Action: "accountdeactivate"}, true},
+		"OtherAction": {Filter{Action: "AccountRemove"}, false},
+		"Since":       {Filter{Since: now.Add(-time.Hour)}, true},
+		"Future":      {Filter{Since: now.Add(time.Hour)}, false},
+		"Until":       {Filter{Until: now.Add(time.Hour)}, true},
+		"Past":        {Filter{Until: now.Add(-time.Hour)}, false},
+	}
+
+	for name, tc := range testCases {
+		if tc.filter.Match(rec) != tc.expected {
+			t.Errorf("%s: Expected %t", name, tc.expected)
+		}
+	}
+}
+
+func TestRecordJSON(t *testing.T) {
+	testCases := map[string]struct {
+		data     string
+		expected string
+	}{
+		"UUID":   {`{"id":1,"target_id":"9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c"}`, "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c"},
+		"Legacy": {`{"id":1,"target_id":7}`, "7"},
+		"Empty":  {`{"id":1}`, ""},
+	}
+
+	for name, tc := range testCases {
+		var rec Record
+		err := json.Unmarshal([]byte(tc.data), &rec)
+		if err != nil || rec.ID != 1 || rec.TargetID != tc.expected {
+			t.Errorf("%s: Expected the target id %q, Got %+v: %v", name, tc.expected, rec, err)
+		}
+	}
+}
+
+func TestRecordErase(t *testing.T) {
+	uuid := "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c"
+	testCases := map[string]struct {
+		rec    Record
+		actor  string
+		target string
+	}{
+		"Target": {Record{Actor: "admin", Target: "ritho", TargetID: uuid}, "admin", Erased},
+		"Actor":  {Record{Actor: "Ritho", Target: "jdoe", TargetID: "other"}, Erased, "jdoe"},
+		"Legacy": {Record{Actor: "admin", Target: "palvarez"}, "admin", Erased},
+		"Reused": {Record{Actor: "admin", Target: "palvarez", TargetID: "other"}, "admin", "palvarez"},
+	}
+
+	for name, tc := range testCases {
+		tc.rec.Erase(uuid, "ritho", "palvarez")
+		if tc.rec.Actor != tc.actor || tc.rec.Target != tc.target {
+			t.Errorf("%s: Expected %s and %s, Got %+v", name, tc.actor, tc.target, tc.rec)
+		}
+	}
+
+	rec := Record{TargetID: uuid, Changes: map[string]Change{
+		"email":  {Before: "palvarez@ritho.net", After: "ritho@ritho.net"},
+		"name":   {Before: "ritho", After: nil},
+		"active": {Before: false, After: true},
+	}}
+	rec.Erase(uuid, "ritho")
+	if rec.Changes["email"].Before != Erased || rec.Changes["email"].After != Erased ||
+		rec.Changes["name"].After != nil || rec.Changes["active"].After != true {
+		t.Errorf("Expected the personal values erased, Got %v", rec.Changes)
+	}
+}
+
+func TestRecordOf(t *testing.T) {
+	uuid := "9d0b1b2c-5e3a-4f7e-8c61-2f0a6b3e4d5c"
+	testCases := map[string]struct {
+		rec      Record
+		expected bool
+	}{
+		"Target":  {Record{Actor: "admin", Target: "ritho", TargetID: uuid}, true},
+		"Actor":   {Record{Actor: "Palvarez", Target: "jdoe", TargetID: "other"}, true},
+		"Legacy":  {Record{Actor: "admin", Target: "palvarez"}, true},
+		"Reused":  {Record{Actor: "admin", Target: "palvarez", TargetID: "other"}, false},
+		"Another": {Record{Actor: "admin", Target: "jdoe"}, false},
+	}
+
+	for name, tc := range testCases {
+		if tc.rec.Of(uuid, "ritho", "palvarez") != tc.expected {
+			t.Errorf("%s: Expected %t, Got %t", name, tc.expected, !tc.expected)
+		}
+	}
+}
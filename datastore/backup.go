@@ -0,0 +1,170 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/metrics"
+)
+
+// ErrBackupVersion raised when the schema version of a backup archive is
+// unknown or newer than the one of the datastore.
+var ErrBackupVersion = i18n.NewError(&goi18n.Message{
+	ID:    "BackupVersion",
+	Other: "Unsupported schema version of the backup archive",
+})
+
+// archive represents the content of a backup archive. The datastore is the
+// snapshot when it's written, and a *json.RawMessage to decode it later.
+type archive struct {
+	Version   int              `json:"version"`
+	Created   time.Time        `json:"created"`
+	Datastore interface{}      `json:"datastore"`
+	Sessions  []archiveSession `json:"sessions,omitempty"`
+}
+
+// archiveSession represents a session kept in a backup archive with the id of
+// its account.
+type archiveSession struct {
+	Session string `json:"session"`
+	Account int    `json:"account"`
+}
+
+// Backup writes to w a backup archive, a gzipped JSON document with the schema
+// version and all the content of the datastore. The sessions are only included
+// if asked, so restoring the archive doesn't log out the users. The archive is
+// compressed as it's written, without keeping it in memory, under the read
+// lock so the snapshot and the sessions are consistent: the changes wait until
+// the archive is written.
+func (d *Datastore) Backup(ctx context.Context, w io.Writer, sessions bool) error {
+	defer metrics.ObserveDatastore("Backup", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	arch := archive{
+		Version:   d.schema.Version,
+		Created:   time.Now().UTC(),
+		Datastore: d.currentSnapshot(),
+	}
+
+	if sessions {
+		for session, id := range d.sessions {
+			arch.Sessions = append(arch.Sessions, archiveSession{session, id})
+		}
+
+		sort.Slice(arch.Sessions, func(i, j int) bool {
+			return arch.Sessions[i].Session < arch.Sessions[j].Session
+		})
+	}
+
+	gz := gzip.NewWriter(w)
+	err := json.NewEncoder(gz).Encode(arch)
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+
+	return errors.Wrap(err, "Error writing the backup archive")
+}
+
+// Restore replaces all the content of the datastore with the one of the backup
+// archive read from r, writing it to the datastore file if any. The archives
+// of older schema versions are migrated, and the archive is validated before
+// anything is replaced, so the datastore is left untouched if its schema
+// version isn't supported or it's not valid.
+func (d *Datastore) Restore(ctx context.Context, r io.Reader) error {
+	defer metrics.ObserveDatastore("Restore", time.Now())
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrap(err, "Error reading the backup archive")
+	}
+	defer gz.Close()
+
+	var data json.RawMessage
+	arch := archive{Datastore: &data}
+	err = json.NewDecoder(gz).Decode(&arch)
+	if err != nil {
+		return errors.Wrap(err, "Error decoding the backup archive")
+	}
+
+	if arch.Version < 1 || arch.Version > SchemaVersion {
+		return errors.Wrap(ErrBackupVersion, fmt.Sprint(arch.Version))
+	}
+
+	snap, _, err := decodeSnapshot(data, true)
+	if err != nil {
+		return errors.Wrap(err, "Error decoding the backup archive")
+	}
+
+	restored := New()
+	err = restored.loadSnapshot(snap)
+	if err != nil {
+		return errors.Wrap(err, "Error decoding the backup archive")
+	}
+
+	for _, s := range arch.Sessions {
+		if _, ok := restored.accounts[s.Account]; !ok {
+			return errors.Errorf("Error decoding the backup archive: session of the unknown account %d",
+				s.Account)
+		}
+
+		restored.sessions[s.Session] = s.Account
+		restored.owners[s.Account] = append(restored.owners[s.Account], s.Session)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.accounts = restored.accounts
+	d.keys = restored.keys
+	d.uuids = restored.uuids
+	d.usernames = restored.usernames
+	d.emails = restored.emails
+	d.apiKeys = restored.apiKeys
+	d.sessions = restored.sessions
+	d.owners = restored.owners
+	d.seq = restored.seq
+	d.schema = restored.schema
+	d.audit = restored.audit
+	d.policy = restored.policy
+	d.technologies = restored.technologies
+	d.editions = restored.editions
+
+	return d.save()
+}
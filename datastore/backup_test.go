@@ -0,0 +1,181 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/datastore/audit"
+)
+
+func TestDatastoreBackup(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error registering an account: %+v", err)
+	}
+
+	err = ds.AddSession(ctx, "00000000-0000-0000-0000-000000000001", "ritho")
+	if err != nil {
+		t.Errorf("Unexpected error adding a session: %s", err)
+	}
+
+	err = ds.AddAuditRecord(ctx, audit.Record{Action: "AccountActivate", Target: "ritho"})
+	if err != nil {
+		t.Errorf("Unexpected error adding an audit record: %s", err)
+	}
+
+	_, _, err = ds.UpdateEdition(ctx, "2018", []Technology{NewTechnology("Go", "Tools", "")},
+		[]Blip{{Technology: "Go", Ring: "Adopt"}})
+	if err != nil {
+		t.Errorf("Unexpected error updating the edition: %s", err)
+	}
+
+	withSessions := &bytes.Buffer{}
+	err = ds.Backup(ctx, withSessions, true)
+	if err != nil {
+		t.Fatalf("Unexpected error backing up the datastore: %s", err)
+	}
+
+	withoutSessions := &bytes.Buffer{}
+	err = ds.Backup(ctx, withoutSessions, false)
+	if err != nil {
+		t.Fatalf("Unexpected error backing up the datastore: %s", err)
+	}
+
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "radar.db")
+	restored, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	_, err = restored.AccountRegistration(ctx, "other", "other", "other@ritho.net", "other")
+	if err != nil {
+		t.Errorf("Unexpected error registering an account: %+v", err)
+	}
+
+	err = restored.Restore(ctx, withSessions)
+	if err != nil {
+		t.Fatalf("Unexpected error restoring the datastore: %s", err)
+	}
+
+	acc, err := restored.GetAccountBySession(ctx, "00000000-0000-0000-0000-000000000001")
+	if err != nil || acc.ID() != id {
+		t.Errorf("Expected the session to be restored, Got %v", err)
+	}
+
+	restored, err = Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	accounts, err := restored.Accounts(ctx)
+	if err != nil || len(accounts) != 1 || accounts[0].ID() != id ||
+		accounts[0].Username() != "ritho" {
+		t.Errorf("Expected the account to be restored, Got %v: %v", accounts, err)
+	}
+
+	records, err := restored.AuditRecords(ctx, audit.Filter{})
+	if err != nil || len(records) != 1 {
+		t.Errorf("Expected the audit log to be restored, Got %v: %v", records, err)
+	}
+
+	edition, err := restored.Edition(ctx, "2018")
+	if err != nil || len(edition.Blips) != 1 {
+		t.Errorf("Expected the edition to be restored, Got %+v: %v", edition, err)
+	}
+
+	err = restored.Restore(ctx, withoutSessions)
+	if err != nil {
+		t.Fatalf("Unexpected error restoring the datastore: %s", err)
+	}
+
+	if restored.DoesAccountHaveSessionByID(ctx, id) {
+		t.Error("Expected the sessions to not be restored")
+	}
+}
+
+func TestDatastoreRestore(t *testing.T) {
+	ctx := context.Background()
+	tests := map[string]struct {
+		archive string
+		err     error
+	}{
+		"Future version": {
+			archive: `{"version": 999, "datastore": {"accounts": []}}`,
+			err:     ErrBackupVersion,
+		},
+		"No version": {
+			archive: `{"datastore": {"accounts": []}}`,
+			err:     ErrBackupVersion,
+		},
+		"Unknown session": {
+			archive: `{"version": 1, "datastore": {"accounts": []},
+				"sessions": [{"session": "s", "account": 1}]}`,
+		},
+		"Not JSON": {
+			archive: `radar`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ds := New()
+			_, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+			if err != nil {
+				t.Fatalf("Unexpected error registering an account: %+v", err)
+			}
+
+			data := &bytes.Buffer{}
+			gz := gzip.NewWriter(data)
+			_, _ = gz.Write([]byte(tc.archive))
+			_ = gz.Close()
+
+			err = ds.Restore(ctx, data)
+			if err == nil || (tc.err != nil && errors.Cause(err) != tc.err) {
+				t.Errorf("Expected error %v, Got %v", tc.err, err)
+			}
+
+			if ds.AccountsCount() != 1 {
+				t.Error("Expected the datastore to be left untouched")
+			}
+		})
+	}
+
+	err := New().Restore(ctx, strings.NewReader("radar"))
+	if err == nil {
+		t.Error("Expected error restoring an archive not gzipped")
+	}
+}
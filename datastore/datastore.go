@@ -20,111 +20,348 @@ package datastore
 */
 
 import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
 	"github.com/golang-plus/uuid"
-	"github.com/golang/glog"
 	"github.com/pkg/errors"
 
 	"github.com/radar-go/radar"
 	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/metrics"
 )
 
-// Datastore struct to access to the datastore.
+// apiKeyUseInterval is how often the last use of an API key is updated.
+const apiKeyUseInterval = time.Minute
+
+// Datastore struct to access to the datastore. The operations receive the
+// context of the request, so they are not started once it's cancelled or its
+// deadline is exceeded.
 type Datastore struct {
-	accounts map[string]*account.Account
-	sessions map[string]*account.Account
+	/* The lock guards every field below: the operations that only read take
+	it shared and the ones that change the datastore take it exclusively, so
+	the purge and the directory synchronization can run alongside the
+	requests. */
+	mu sync.RWMutex
+
+	/* The accounts are stored by id, with unique indexes of their UUIDs,
+	usernames, emails and the hashes of their API keys, and the sessions
+	store the id of their account. */
+	accounts  map[int]*account.Account
+	keys      map[int]keys
+	uuids     map[string]int
+	usernames map[string]int
+	emails    map[string]int
+	apiKeys   map[string]int
+	sessions  map[string]int
+	owners    map[int][]string
+
+	/* The ids are never reused, so the last one assigned is kept even if its
+	account is removed. */
+	seq int
+
+	schema Schema
+	audit  []audit.Record
+	policy Policy
+	path   string
+
+	/* The technologies are shared by the radar editions, both stored by their
+	clean name. */
+	technologies map[string]Technology
+	editions     map[string]*Edition
 }
 
 // New creates and returns a new datastore object.
 func New() *Datastore {
 	return &Datastore{
-		accounts: make(map[string]*account.Account),
-		sessions: make(map[string]*account.Account),
+		accounts:  make(map[int]*account.Account),
+		keys:      make(map[int]keys),
+		uuids:     make(map[string]int),
+		usernames: make(map[string]int),
+		emails:    make(map[string]int),
+		apiKeys:   make(map[string]int),
+		sessions:  make(map[string]int),
+		owners:    make(map[int][]string),
+		schema:    Schema{Version: SchemaVersion},
+
+		technologies: make(map[string]Technology),
+		editions:     make(map[string]*Edition),
 	}
 }
 
 // Endpoints returns a list of endpoints linked with their use case.
 func (d *Datastore) Endpoints() map[string]string {
 	return map[string]string{
-		"/account/activate":   "AccountActivate",
-		"/account/deactivate": "AccountDeactivate",
-		"/account/edit":       "AccountEdit",
-		"/account/login":      "AccountLogin",
-		"/account/logout":     "AccountLogout",
-		"/account/register":   "AccountRegister",
-		"/account/remove":     "AccountRemove",
+		"/account/2fa/confirm":            "AccountTwoFactorConfirm",
+		"/account/2fa/disable":            "AccountTwoFactorDisable",
+		"/account/2fa/enroll":             "AccountTwoFactorEnroll",
+		"/account/2fa/policy":             "AccountTwoFactorPolicy",
+		"/account/activate":               "AccountActivate",
+		"/account/apikey/create":          "AccountAPIKeyCreate",
+		"/account/apikey/list":            "AccountAPIKeyList",
+		"/account/apikey/revoke":          "AccountAPIKeyRevoke",
+		"/account/deactivate":             "AccountDeactivate",
+		"/account/edit":                   "AccountEdit",
+		"/account/erase":                  "AccountErase",
+		"/account/export":                 "AccountExport",
+		"/account/get":                    "AccountGet",
+		"/account/ldap/sync":              "AccountLDAPSync",
+		"/account/list":                   "AccountList",
+		"/account/login":                  "AccountLogin",
+		"/account/login/verify":           "AccountLoginVerify",
+		"/account/logout":                 "AccountLogout",
+		"/account/oidc/login":             "AccountOIDCLogin",
+		"/account/oidc/start":             "AccountOIDCStart",
+		"/account/password/reset":         "AccountPasswordResetRequest",
+		"/account/password/reset/confirm": "AccountPasswordResetConfirm",
+		"/account/register":               "AccountRegister",
+		"/account/remove":                 "AccountRemove",
+		"/account/restore":                "AccountRestore",
+		"/account/unlock":                 "AccountUnlock",
+		"/account/verification/resend":    "AccountVerificationResend",
+		"/audit/list":                     "AuditList",
+		"/edition/export":                 "EditionExport",
+		"/edition/import":                 "EditionImport",
+		"/member/get":                     "MemberGet",
+		"/radar/get":                      "RadarGet",
+		"/technology/get":                 "TechnologyGet",
 	}
 }
 
-// AccountRegistration registers a new user in the datasore.
-func (d *Datastore) AccountRegistration(username, name, email, password string) (int, error) {
+// AccountRegistration registers a new user in the datasore, the username and
+// the email can't be used by another account.
+func (d *Datastore) AccountRegistration(ctx context.Context, username, name, email, password string) (int, error) {
+	defer metrics.ObserveDatastore("AccountRegistration", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	cleanUsername := radar.CleanString(username)
-	_, ok := d.accounts[cleanUsername]
-	if ok {
+	if _, ok := d.usernames[cleanUsername]; ok {
+		return 0, errors.Wrap(account.ErrAccountExists, email)
+	}
+
+	if _, ok := d.emails[radar.CleanString(email)]; ok {
 		return 0, errors.Wrap(account.ErrAccountExists, email)
 	}
 
-	glog.Infof("Registering user '%s'", username)
+	logging.Logger.Info("registering account", "request_id", logging.ContextRequestID(ctx),
+		"username", username)
 	acc, err := account.New(cleanUsername, name, email, password)
 	if err != nil {
 		return 0, err
 	}
 
-	d.accounts[cleanUsername] = acc
+	if err = d.add(acc); err != nil {
+		return 0, err
+	}
 
-	return acc.ID(), nil
+	return acc.ID(), d.save()
 }
 
 // IsAccountRegisteredByUsername returns true if an account is registered by an
 // username, false otherwise.
-func (d *Datastore) IsAccountRegisteredByUsername(username string) bool {
-	cleanUsername := radar.CleanString(username)
-	_, ok := d.accounts[cleanUsername]
+func (d *Datastore) IsAccountRegisteredByUsername(ctx context.Context, username string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, ok := d.usernames[radar.CleanString(username)]
 
 	return ok
 }
 
 // IsAccountRegisteredByID returns true if an account is registered by an id,
 // false otherwise.
-func (d *Datastore) IsAccountRegisteredByID(id int) bool {
-	for _, acc := range d.accounts {
-		if acc.ID() == id {
-			return true
-		}
-	}
+func (d *Datastore) IsAccountRegisteredByID(ctx context.Context, id int) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, ok := d.accounts[id]
 
-	return false
+	return ok
 }
 
 // GetAccountByID returns an user stored in the datastore by its id or an error
 // in case it doesn't exists.
-func (d *Datastore) GetAccountByID(id int) (*account.Account, error) {
-	for _, acc := range d.accounts {
-		if acc.ID() == id {
-			return acc, nil
-		}
+func (d *Datastore) GetAccountByID(ctx context.Context, id int) (*account.Account, error) {
+	defer metrics.ObserveDatastore("GetAccountByID", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	acc, err := d.account(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return acc.Clone(), nil
+}
+
+// account returns the account stored by its id or an error if it doesn't
+// exist or it's removed.
+func (d *Datastore) account(id int) (*account.Account, error) {
+	acc, ok := d.accounts[id]
+	if !ok || acc.IsRemoved() {
+		return nil, account.ErrAccountNotExists
+	}
+
+	return acc, nil
+}
+
+// GetAccountByUUID returns an user stored in the datastore by its public
+// identifier or an error in case it doesn't exists.
+func (d *Datastore) GetAccountByUUID(ctx context.Context, uuid string) (*account.Account, error) {
+	defer metrics.ObserveDatastore("GetAccountByUUID", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, ok := d.uuids[radar.CleanString(uuid)]
+	if !ok || d.accounts[id].IsRemoved() {
+		return nil, errors.Wrap(account.ErrAccountNotExists, uuid)
 	}
 
-	return nil, account.ErrAccountNotExists
+	return d.accounts[id].Clone(), nil
 }
 
 // GetAccountByUsername returns an user stored in the datastore by its username or
 // an error in case it doesn't exists.
-func (d *Datastore) GetAccountByUsername(username string) (*account.Account, error) {
-	var err error
+func (d *Datastore) GetAccountByUsername(ctx context.Context, username string) (*account.Account, error) {
+	defer metrics.ObserveDatastore("GetAccountByUsername", time.Now())
 
-	cleanUsername := radar.CleanString(username)
-	acc, ok := d.accounts[cleanUsername]
-	if !ok {
-		err = errors.Wrap(account.ErrAccountNotExists, username)
-		glog.Errorf("%+v", err)
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, ok := d.usernames[radar.CleanString(username)]
+	if !ok || d.accounts[id].IsRemoved() {
+		return nil, errors.Wrap(account.ErrAccountNotExists, username)
+	}
+
+	return d.accounts[id].Clone(), nil
+}
+
+// GetAccountByEmail returns an user stored in the datastore by its email or an
+// error in case it doesn't exists or there are more than one account with it,
+// which only happens with the accounts of old datastores.
+func (d *Datastore) GetAccountByEmail(ctx context.Context, email string) (*account.Account, error) {
+	defer metrics.ObserveDatastore("GetAccountByEmail", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, ok := d.emails[radar.CleanString(email)]
+	if ok && id == ambiguous {
+		return nil, errors.Wrap(account.ErrEmailAmbiguous, email)
+	}
+
+	if !ok || d.accounts[id].IsRemoved() {
+		return nil, errors.Wrap(account.ErrAccountNotExists, email)
 	}
 
-	return acc, err
+	return d.accounts[id].Clone(), nil
+}
+
+// GetAccountByExternalID returns an user stored in the datastore by its
+// identity in the single sign-on provider or an error in case it doesn't
+// exists.
+func (d *Datastore) GetAccountByExternalID(ctx context.Context, id string) (*account.Account, error) {
+	defer metrics.ObserveDatastore("GetAccountByExternalID", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, acc := range d.accounts {
+		if id != "" && acc.ExternalID() == id && !acc.IsRemoved() {
+			return acc.Clone(), nil
+		}
+	}
+
+	return nil, errors.Wrap(account.ErrAccountNotExists, id)
+}
+
+// QueryAccounts returns the page of the accounts selected by the query, sorted
+// as it asks, and the number of accounts selected, or an error if its offset
+// is negative.
+func (d *Datastore) QueryAccounts(ctx context.Context, q account.Query) ([]*account.Account, int, error) {
+	defer metrics.ObserveDatastore("QueryAccounts", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if q.Offset < 0 {
+		return nil, 0, errors.Wrapf(account.ErrQueryOffset, "%d", q.Offset)
+	}
+
+	accounts := make([]*account.Account, 0)
+	for _, acc := range d.accounts {
+		if !acc.IsRemoved() && q.Match(acc) {
+			accounts = append(accounts, acc)
+		}
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return q.Less(accounts[i], accounts[j])
+	})
+
+	total := len(accounts)
+	if q.Offset >= total {
+		return []*account.Account{}, total, nil
+	}
+
+	accounts = accounts[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(accounts) {
+		accounts = accounts[:q.Limit]
+	}
+
+	for i, acc := range accounts {
+		accounts[i] = acc.Clone()
+	}
+
+	return accounts, total, nil
 }
 
 // AddSession adds an account session to the datastore.
-func (d *Datastore) AddSession(session, username string) error {
-	var err error
+func (d *Datastore) AddSession(ctx context.Context, session, username string) error {
+	defer metrics.ObserveDatastore("AddSession", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	cleanSession := radar.CleanString(session)
 	if len(cleanSession) != len(uuid.Nil.String()) {
@@ -135,61 +372,101 @@ func (d *Datastore) AddSession(session, username string) error {
 		return errors.Wrap(account.ErrUserAlreadyLogin, username)
 	}
 
-	cleanUsername := radar.CleanString(username)
-	if !d.IsAccountRegisteredByUsername(cleanUsername) {
+	id, ok := d.usernames[radar.CleanString(username)]
+	if !ok || d.accounts[id].IsRemoved() {
 		return errors.Wrap(account.ErrAccountNotExists, username)
 	}
 
-	d.sessions[cleanSession] = d.accounts[username]
+	d.sessions[cleanSession] = id
+	d.owners[id] = append(d.owners[id], cleanSession)
 
-	return err
+	return nil
 }
 
 // DeleteSession removes the user session from the datastore.
-func (d *Datastore) DeleteSession(session, username string) error {
-	var err error
+func (d *Datastore) DeleteSession(ctx context.Context, session, username string) error {
+	defer metrics.ObserveDatastore("DeleteSession", time.Now())
 
-	cleanSession := radar.CleanString(session)
-	cleanUsername := radar.CleanString(username)
-	if !d.IsAccountRegisteredByUsername(cleanUsername) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	id, ok := d.usernames[radar.CleanString(username)]
+	if !ok {
 		return errors.Wrap(account.ErrAccountNotExists, username)
 	}
 
-	if !d.DoesAccountHaveSessionByUsername(cleanUsername) {
+	if len(d.owners[id]) == 0 {
 		return errors.Wrap(account.ErrUserNotLoggedIn, username)
 	}
 
-	delete(d.sessions, cleanSession)
+	d.removeSession(radar.CleanString(session))
+
+	return nil
+}
+
+// DeleteSessions removes all the sessions of an account from the datastore and
+// returns how many were removed.
+func (d *Datastore) DeleteSessions(ctx context.Context, id int) (int, error) {
+	defer metrics.ObserveDatastore("DeleteSessions", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	sessions := d.owners[id]
+	for _, session := range sessions {
+		delete(d.sessions, session)
+	}
+
+	delete(d.owners, id)
 
-	return err
+	return len(sessions), nil
 }
 
 // GetAccountBySession returns an account by its session id or an error in case
 // the account have not an active session.
-func (d *Datastore) GetAccountBySession(session string) (*account.Account, error) {
-	var err error
+func (d *Datastore) GetAccountBySession(ctx context.Context, session string) (*account.Account, error) {
+	defer metrics.ObserveDatastore("GetAccountBySession", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
 	cleanSession := radar.CleanString(session)
 	if len(cleanSession) == 0 {
 		return nil, account.ErrUserNotLoggedIn
 	}
 
-	acc, ok := d.sessions[cleanSession]
+	id, ok := d.sessions[cleanSession]
 	if !ok {
-		err = errors.Wrap(account.ErrUserNotLoggedIn, session)
-		glog.Errorf("%+v", err)
+		return nil, errors.Wrap(account.ErrUserNotLoggedIn, session)
 	}
 
-	return acc, err
+	return d.accounts[id].Clone(), nil
 }
 
 // GetSessionByID returns a session associated to an account id or error if it
 // doesn't exists.
-func (d *Datastore) GetSessionByID(id int) (string, error) {
-	for session, value := range d.sessions {
-		if value.ID() == id {
-			return session, nil
-		}
+func (d *Datastore) GetSessionByID(ctx context.Context, id int) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	if sessions := d.owners[id]; len(sessions) > 0 {
+		return sessions[0], nil
 	}
 
 	return "", errors.New("No session associated to the account id")
@@ -197,100 +474,470 @@ func (d *Datastore) GetSessionByID(id int) (string, error) {
 
 // GetSessionByUsername returns a session associated to an username or error if
 // it doesn't exists.
-func (d *Datastore) GetSessionByUsername(username string) (string, error) {
-	cleanUsername := radar.CleanString(username)
-	for session, value := range d.sessions {
-		if value.Username() == cleanUsername {
-			return session, nil
-		}
+func (d *Datastore) GetSessionByUsername(ctx context.Context, username string) (string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	id, ok := d.usernames[radar.CleanString(username)]
+	if sessions := d.owners[id]; ok && len(sessions) > 0 {
+		return sessions[0], nil
 	}
 
 	return "", errors.New("No session associated to the username")
 }
 
+// AccountSessions returns the sessions of an account by its id.
+func (d *Datastore) AccountSessions(ctx context.Context, id int) ([]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return append([]string{}, d.owners[id]...), nil
+}
+
 // DoesAccountHaveSessionByID returns true if the account id have associated a
 // session and false otherwise.
-func (d *Datastore) DoesAccountHaveSessionByID(id int) bool {
-	for _, value := range d.sessions {
-		if value.ID() == id {
-			return true
-		}
-	}
+func (d *Datastore) DoesAccountHaveSessionByID(ctx context.Context, id int) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
 
-	return false
+	return len(d.owners[id]) > 0
 }
 
 // DoesAccountHaveSessionByUsername returns true if the username have associated
 // a session and false otherwise.
-func (d *Datastore) DoesAccountHaveSessionByUsername(username string) bool {
-	cleanUsername := radar.CleanString(username)
-	for _, value := range d.sessions {
-		if value.Username() == cleanUsername {
-			return true
+func (d *Datastore) DoesAccountHaveSessionByUsername(ctx context.Context, username string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	id, ok := d.usernames[radar.CleanString(username)]
+
+	return ok && len(d.owners[id]) > 0
+}
+
+// GetAccountByAPIKey returns the account of the API key and the key itself,
+// storing the time it's used. The time is only updated once every
+// apiKeyUseInterval, and it's saved with the next change of the datastore,
+// so the keys are checked under the read lock.
+func (d *Datastore) GetAccountByAPIKey(ctx context.Context, key string) (*account.Account, account.APIKey, error) {
+	defer metrics.ObserveDatastore("GetAccountByAPIKey", time.Now())
+
+	now := time.Now()
+	acc, apiKey, err := d.apiKeyAccount(ctx, key, now)
+	if err != nil {
+		return nil, account.APIKey{}, err
+	}
+
+	if now.Sub(apiKey.LastUsed) >= apiKeyUseInterval {
+		d.mu.Lock()
+		if stored, ok := d.accounts[acc.ID()]; ok {
+			stored.UseAPIKey(key, now)
 		}
+		d.mu.Unlock()
 	}
 
-	return false
+	apiKey, _ = acc.UseAPIKey(key, now)
+
+	return acc, apiKey, nil
 }
 
-// UpdateAccountData updates the account data information in the datastore.
-func (d *Datastore) UpdateAccountData(acc *account.Account, session string) error {
-	var err error
+// apiKeyAccount returns a copy of the active account of the API key, found by
+// its hash, and the key itself if it hasn't expired at t.
+func (d *Datastore) apiKeyAccount(ctx context.Context, key string, t time.Time) (*account.Account, account.APIKey, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, account.APIKey{}, err
+	}
+
+	id, ok := d.apiKeys[account.HashAPIKey(key)]
+	if !ok {
+		return nil, account.APIKey{}, account.ErrAPIKeyInvalid
+	}
 
-	if !d.IsAccountRegisteredByID(acc.ID()) {
+	acc := d.accounts[id]
+	if !acc.IsActive() || acc.IsRemoved() {
+		return nil, account.APIKey{}, account.ErrAPIKeyInvalid
+	}
+
+	apiKey, ok := acc.APIKey(key, t)
+	if !ok {
+		return nil, account.APIKey{}, account.ErrAPIKeyInvalid
+	}
+
+	return acc.Clone(), apiKey, nil
+}
+
+// UpdateAccountData updates the account data information in the datastore,
+// storing a copy of the account so the changes made to it afterwards are not
+// stored. The username and the email of the account can't be used by another
+// one, the previous ones are restored in the account when they are.
+func (d *Datastore) UpdateAccountData(ctx context.Context, acc *account.Account) error {
+	defer metrics.ObserveDatastore("UpdateAccountData", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := d.accounts[acc.ID()]; !ok {
 		return errors.Wrap(account.ErrAccountNotExists, acc.Username())
 	}
 
-	cleanSession := radar.CleanString(session)
-	if _, ok := d.sessions[cleanSession]; ok {
-		d.sessions[cleanSession] = acc
+	if err := d.reindex(acc); err != nil {
+		return err
 	}
 
-	d.accounts[acc.Username()] = acc
+	d.unindexAPIKeys(acc.ID())
+	d.accounts[acc.ID()] = acc.Clone()
+	d.indexAPIKeys(acc)
 
-	return err
+	return d.save()
 }
 
-// RemoveAccount removes an account from the datastore.
-func (d *Datastore) RemoveAccount(acc *account.Account) error {
-	if !d.IsAccountRegisteredByID(acc.ID()) {
+// RemoveAccount removes an account and its sessions from the datastore. The
+// account is kept, with its username and email, until it's purged so it can
+// be restored.
+func (d *Datastore) RemoveAccount(ctx context.Context, acc *account.Account) error {
+	defer metrics.ObserveDatastore("RemoveAccount", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored, ok := d.accounts[acc.ID()]
+	if !ok || stored.IsRemoved() {
 		return errors.Wrap(account.ErrAccountNotExists, acc.Username())
 	}
 
-	if d.DoesAccountHaveSessionByID(acc.ID()) {
-		session, _ := d.GetSessionByID(acc.ID())
+	for _, session := range d.owners[acc.ID()] {
 		delete(d.sessions, session)
 	}
 
-	delete(d.accounts, acc.Username())
+	delete(d.owners, acc.ID())
+	removed := time.Now().UTC()
+	stored.Remove(removed)
+	acc.Remove(removed)
 
-	return nil
+	return d.save()
+}
+
+// GetRemovedAccount returns a removed account by its public identifier or an
+// error in case it doesn't exists or it's not removed.
+func (d *Datastore) GetRemovedAccount(ctx context.Context, uuid string) (*account.Account, error) {
+	defer metrics.ObserveDatastore("GetRemovedAccount", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	id, ok := d.uuids[radar.CleanString(uuid)]
+	if !ok {
+		return nil, errors.Wrap(account.ErrAccountNotExists, uuid)
+	}
+
+	if !d.accounts[id].IsRemoved() {
+		return nil, errors.Wrap(account.ErrAccountNotRemoved, uuid)
+	}
+
+	return d.accounts[id].Clone(), nil
+}
+
+// RestoreAccount undoes the removal of an account not purged yet.
+func (d *Datastore) RestoreAccount(ctx context.Context, acc *account.Account) error {
+	defer metrics.ObserveDatastore("RestoreAccount", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored, ok := d.accounts[acc.ID()]
+	if !ok {
+		return errors.Wrap(account.ErrAccountNotExists, acc.Username())
+	}
+
+	if !stored.IsRemoved() {
+		return errors.Wrap(account.ErrAccountNotRemoved, acc.Username())
+	}
+
+	stored.Restore()
+	acc.Restore()
+
+	return d.save()
+}
+
+// PurgeAccounts deletes for good the accounts removed before a time, and
+// returns how many were deleted.
+func (d *Datastore) PurgeAccounts(ctx context.Context, before time.Time) (int, error) {
+	defer metrics.ObserveDatastore("PurgeAccounts", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for id, acc := range d.accounts {
+		if acc.IsRemoved() && acc.Removed().Before(before) {
+			d.unindex(id)
+			d.unindexAPIKeys(id)
+			delete(d.accounts, id)
+			purged++
+		}
+	}
+
+	if purged == 0 {
+		return 0, nil
+	}
+
+	return purged, d.save()
+}
+
+// EraseAccount deletes an account for good, even if it's removed, erasing its
+// personal data from the audit log and anonymizing its member so what
+// references it shows a former member.
+func (d *Datastore) EraseAccount(ctx context.Context, acc *account.Account) error {
+	defer metrics.ObserveDatastore("EraseAccount", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	stored, ok := d.accounts[acc.ID()]
+	if !ok {
+		return errors.Wrap(account.ErrAccountNotExists, acc.Username())
+	}
+
+	usernames := d.usernameHistory(stored)
+	for i := range d.audit {
+		d.audit[i].Erase(stored.UUID(), usernames...)
+	}
+
+	for _, session := range d.owners[stored.ID()] {
+		delete(d.sessions, session)
+	}
+
+	delete(d.owners, stored.ID())
+	d.unindex(stored.ID())
+	d.unindexAPIKeys(stored.ID())
+	delete(d.accounts, stored.ID())
+	stored.Erase()
+	acc.Erase()
+
+	return d.save()
 }
 
 // ActivateAccount activates an account by its id.
-func (d *Datastore) ActivateAccount(id int) bool {
-	acc, err := d.GetAccountByID(id)
+func (d *Datastore) ActivateAccount(ctx context.Context, id int) bool {
+	defer metrics.ObserveDatastore("ActivateAccount", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		logging.Logger.Error("account not updated", "request_id", logging.ContextRequestID(ctx),
+			"id", id, "error", err.Error())
+		return false
+	}
+
+	acc, err := d.account(id)
 	if err != nil {
-		glog.Errorf("Unexpected error: %s", err)
+		logging.Logger.Error("account not updated", "request_id", logging.ContextRequestID(ctx),
+			"id", id, "error", err.Error())
 		return false
 	}
 
 	acc.Activate()
-	d.accounts[acc.Username()] = acc
 
-	return true
+	return d.persist()
 }
 
 // DeactivateAccount deactivates an account by its id.
-func (d *Datastore) DeactivateAccount(id int) bool {
-	acc, err := d.GetAccountByID(id)
+func (d *Datastore) DeactivateAccount(ctx context.Context, id int) bool {
+	defer metrics.ObserveDatastore("DeactivateAccount", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		logging.Logger.Error("account not updated", "request_id", logging.ContextRequestID(ctx),
+			"id", id, "error", err.Error())
+		return false
+	}
+
+	acc, err := d.account(id)
 	if err != nil {
-		glog.Errorf("Unexpected error: %s", err)
+		logging.Logger.Error("account not updated", "request_id", logging.ContextRequestID(ctx),
+			"id", id, "error", err.Error())
 		return false
 	}
 
 	acc.Deactivate()
-	d.accounts[acc.Username()] = acc
+
+	return d.persist()
+}
+
+// SetAdmin grants or revokes the administration privileges of an account by its
+// id.
+func (d *Datastore) SetAdmin(ctx context.Context, id int, admin bool) bool {
+	defer metrics.ObserveDatastore("SetAdmin", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		logging.Logger.Error("account not updated", "request_id", logging.ContextRequestID(ctx),
+			"id", id, "error", err.Error())
+		return false
+	}
+
+	acc, err := d.account(id)
+	if err != nil {
+		logging.Logger.Error("account not updated", "request_id", logging.ContextRequestID(ctx),
+			"id", id, "error", err.Error())
+		return false
+	}
+
+	acc.SetAdmin(admin)
+
+	return d.persist()
+}
+
+// persist saves the datastore logging the error, if any, and returns true if
+// it have been saved successfully.
+func (d *Datastore) persist() bool {
+	if err := d.save(); err != nil {
+		logging.Logger.Error("datastore not saved", "error", err.Error())
+		return false
+	}
 
 	return true
 }
+
+// AddAuditRecord appends the record to the audit log, setting its id. The
+// records can't be changed nor removed once added.
+func (d *Datastore) AddAuditRecord(ctx context.Context, rec audit.Record) error {
+	defer metrics.ObserveDatastore("AddAuditRecord", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rec.ID = len(d.audit) + 1
+	d.audit = append(d.audit, rec)
+
+	return d.save()
+}
+
+// AuditRecords returns the records of the audit log matching the filter, the
+// newest first.
+func (d *Datastore) AuditRecords(ctx context.Context, filter audit.Filter) ([]audit.Record, error) {
+	defer metrics.ObserveDatastore("AuditRecords", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	records := make([]audit.Record, 0)
+	for i := len(d.audit) - 1; i >= 0; i-- {
+		if filter.Match(d.audit[i]) {
+			records = append(records, d.audit[i])
+		}
+	}
+
+	return records, nil
+}
+
+// AccountAuditRecords returns the audit records done by or on the account,
+// the newest first. The records are matched by its UUID and by all the
+// usernames it had, as the older ones are done with them.
+func (d *Datastore) AccountAuditRecords(ctx context.Context, acc *account.Account) ([]audit.Record, error) {
+	defer metrics.ObserveDatastore("AccountAuditRecords", time.Now())
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	usernames := d.usernameHistory(acc)
+	records := make([]audit.Record, 0)
+	for i := len(d.audit) - 1; i >= 0; i-- {
+		if d.audit[i].Of(acc.UUID(), usernames...) {
+			records = append(records, d.audit[i])
+		}
+	}
+
+	return records, nil
+}
+
+// usernameHistory returns the current username of the account and the ones it
+// had before, taken from the changes of its audit records.
+func (d *Datastore) usernameHistory(acc *account.Account) []string {
+	usernames := []string{acc.Username()}
+	for _, rec := range d.audit {
+		if change, ok := rec.Changes["username"]; ok && rec.TargetID == acc.UUID() {
+			for _, username := range []interface{}{change.Before, change.After} {
+				if username, ok := username.(string); ok {
+					usernames = append(usernames, username)
+				}
+			}
+		}
+	}
+
+	return usernames
+}
+
+// AccountsCount returns the number of accounts registered.
+func (d *Datastore) AccountsCount() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	count := 0
+	for _, acc := range d.accounts {
+		if !acc.IsRemoved() {
+			count++
+		}
+	}
+
+	return count
+}
+
+// SessionsCount returns the number of sessions active.
+func (d *Datastore) SessionsCount() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return len(d.sessions)
+}
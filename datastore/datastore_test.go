@@ -19,19 +19,24 @@ package datastore
 */
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/goware/emailx"
 	"github.com/pkg/errors"
 
 	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
 )
 
 func TestEndpoints(t *testing.T) {
 	ds := New()
 
-	numEndpoints := 7
+	numEndpoints := 33
 	endpoints := ds.Endpoints()
 	if len(endpoints) != numEndpoints {
 		t.Errorf("Expected %d, Got %d", numEndpoints, len(endpoints))
@@ -39,9 +44,10 @@ func TestEndpoints(t *testing.T) {
 }
 
 func TestDatastoreRegisterAccountSuccess(t *testing.T) {
+	ctx := context.Background()
 	ds := New()
 
-	id, err := ds.AccountRegistration("ritho", "ritho", "palvarez@ritho.net", "ritho")
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
 	if err != nil {
 		t.Errorf("Unexpected error registering an account: %+v", err)
 	}
@@ -51,176 +57,791 @@ func TestDatastoreRegisterAccountSuccess(t *testing.T) {
 	}
 }
 
+func TestDatastoreParallelRegistration(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+
+	workers := 16
+	ids := make(chan int, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			username := fmt.Sprintf("ritho%d", i)
+			id, err := ds.AccountRegistration(ctx, username, "ritho",
+				username+"@ritho.net", "ritho")
+			if err != nil {
+				t.Errorf("Unexpected error registering %s: %+v", username, err)
+				return
+			}
+
+			session := fmt.Sprintf("00000000-0000-0000-0000-%012d", i)
+			if err = ds.AddSession(ctx, session, username); err != nil {
+				t.Errorf("Unexpected error adding the session of %s: %+v", username, err)
+			}
+
+			ds.IsAccountRegisteredByUsername(ctx, username)
+			ds.QueryAccounts(ctx, account.Query{Limit: workers})
+			ds.AccountsCount()
+			ds.SessionsCount()
+			ids <- id
+		}(i)
+	}
+
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("Expected unique ids, got %d twice", id)
+		}
+
+		seen[id] = true
+	}
+
+	if len(seen) != workers {
+		t.Errorf("Expected %d accounts, got %d", workers, len(seen))
+	}
+
+	if ds.AccountsCount() != workers || ds.SessionsCount() != workers {
+		t.Errorf("Expected %d accounts and sessions, got %d and %d", workers,
+			ds.AccountsCount(), ds.SessionsCount())
+	}
+}
+
+func TestDatastoreParallelEdition(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	/* The accounts are edited as the use cases do, while they are searched
+	and got, run it with -race to catch the accesses out of the lock. */
+	workers := 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+
+			acc, err := ds.GetAccountByID(ctx, id)
+			if err != nil {
+				t.Errorf("Unexpected error getting the account: %s", err)
+				return
+			}
+
+			acc.SetName(fmt.Sprintf("Pablo %d", i))
+			acc.SetUsername(fmt.Sprintf("ritho%d", i))
+			if err = ds.UpdateAccountData(ctx, acc); err != nil {
+				t.Errorf("Unexpected error updating the account: %s", err)
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			accounts, _, _ := ds.QueryAccounts(ctx, account.Query{Search: "ritho", Inactive: true})
+			for _, acc := range accounts {
+				acc.SetName("Pablo")
+			}
+
+			ds.GetAccountByID(ctx, id)
+		}()
+	}
+
+	wg.Wait()
+
+	acc, _ := ds.GetAccountByID(ctx, id)
+	if !strings.HasPrefix(acc.Name(), "Pablo ") || !ds.IsAccountRegisteredByUsername(ctx, acc.Username()) {
+		t.Errorf("Expected the account of the last edition, Got %s %s", acc.Username(), acc.Name())
+	}
+}
+
 func TestDatastoreAccountRegisterError(t *testing.T) {
+	ctx := context.Background()
 	ds := New()
 
-	_, err := ds.AccountRegistration("ritho", "ritho", "", "ritho")
+	_, err := ds.AccountRegistration(ctx, "ritho", "ritho", "", "ritho")
 	if errors.Cause(err) != emailx.ErrInvalidFormat {
 		t.Errorf("Expected '%v', Got '%v'", account.ErrEmailEmpty, err)
 	}
 
-	_, err = ds.AccountRegistration("", "ritho", "palvarez@ritho.net", "ritho")
+	_, err = ds.AccountRegistration(ctx, "", "ritho", "palvarez@ritho.net", "ritho")
 	if errors.Cause(err) != account.ErrUsernameTooShort {
 		t.Errorf("Expected '%v', Got '%v'", account.ErrUsernameTooShort, err)
 	}
 
-	_, err = ds.AccountRegistration("ritho", "ritho", "palvarez@ritho.net", "")
+	_, err = ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "")
 	if errors.Cause(err) != account.ErrPasswordTooShort {
 		t.Errorf("Expected '%v', Got '%v'", account.ErrPasswordTooShort, err)
 	}
 
-	_, err = ds.AccountRegistration("ritho", "ritho", "palvarez@ritho.net", "ritho")
+	_, err = ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
 	if err != nil {
 		t.Errorf("Unexpected error registering an account: %+v", err)
 	}
 
-	_, err = ds.AccountRegistration("ritho", "ritho", "palvarez@ritho.net", "ritho")
+	_, err = ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
 	if errors.Cause(err) != account.ErrAccountExists {
 		t.Errorf("Expected error %+v, Got %+v", account.ErrAccountExists, err)
 	}
 }
 
 func TestDatastoreGetAccount(t *testing.T) {
+	ctx := context.Background()
 	ds := New()
 
-	_, err := ds.GetAccountByUsername("ritho")
+	_, err := ds.GetAccountByUsername(ctx, "ritho")
 	if fmt.Sprintf("%v", err) != "ritho: Account doesn't exists" {
 		t.Errorf("Expected 'ritho: Account doesn't exists', Got '%v'", err)
 	}
 
-	ds.accounts["ritho"] = &account.Account{}
-	_, err = ds.GetAccountByUsername("ritho")
+	acc, _ := account.New("ritho", "ritho", "palvarez@ritho.net", "ritho")
+	ds.add(acc)
+	_, err = ds.GetAccountByUsername(ctx, "ritho")
 	if err != nil {
 		t.Errorf("Unexpected error %+v", err)
 	}
+
+	if acc.ID() != 1 || acc.UUID() == "" {
+		t.Errorf("Expected the account to get the id 1 and an UUID, Got %d, %s", acc.ID(), acc.UUID())
+	}
+
+	found, err := ds.GetAccountByUUID(ctx, strings.ToUpper(acc.UUID()))
+	if err != nil || !found.Equals(acc) || found == acc {
+		t.Errorf("Expected a copy of the account %s, Got %+v: %v", acc.UUID(), found, err)
+	}
+
+	_, err = ds.GetAccountByUUID(ctx, "1")
+	if errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotExists, err)
+	}
+
+	other, _ := account.New("senoritho", "ritho", "senoritho@ritho.net", "ritho")
+	ds.add(other)
+	if other.ID() != 2 || other.UUID() == acc.UUID() {
+		t.Errorf("Expected the account to get the id 2 and another UUID, Got %d, %s", other.ID(), other.UUID())
+	}
+}
+
+func TestDatastoreGetAccountByEmail(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+
+	_, err := ds.GetAccountByEmail(ctx, "palvarez@ritho.net")
+	if errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotExists, err)
+	}
+
+	id, _ := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	acc, err := ds.GetAccountByEmail(ctx, " PAlvarez@ritho.net")
+	if err != nil || acc.ID() != id {
+		t.Errorf("Expected the account %d, Got %+v: %v", id, acc, err)
+	}
+
+	acc.SetExternalID("https://sso.ritho.net|1234")
+	if err = ds.UpdateAccountData(ctx, acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	acc, err = ds.GetAccountByExternalID(ctx, "https://sso.ritho.net|1234")
+	if err != nil || acc.ID() != id {
+		t.Errorf("Expected the account %d, Got %+v: %v", id, acc, err)
+	}
+
+	_, err = ds.GetAccountByExternalID(ctx, "https://sso.ritho.net|5678")
+	if errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotExists, err)
+	}
+
+	_, err = ds.AccountRegistration(ctx, "ritho2", "ritho", "PAlvarez@ritho.net", "ritho")
+	if errors.Cause(err) != account.ErrAccountExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountExists, err)
+	}
+
+	/* The datastores written before the emails were unique can have accounts
+	sharing them. */
+	shared, _ := account.New("ritho2", "ritho", "palvarez@ritho.net", "ritho")
+	shared.SetIdentifiers(id+1, "5f1c2b3a-8d4e-4c6f-9a7b-0e1d2c3b4a59")
+	ds.load(shared)
+	_, err = ds.GetAccountByEmail(ctx, "palvarez@ritho.net")
+	if errors.Cause(err) != account.ErrEmailAmbiguous {
+		t.Errorf("Expected %s, Got %v", account.ErrEmailAmbiguous, err)
+	}
+
+	/* The email is reserved by the removed account until it's purged. */
+	err = ds.RemoveAccount(ctx, shared)
+	if err == nil {
+		_, err = ds.PurgeAccounts(ctx, shared.Removed().Add(time.Second))
+	}
+
+	acc, _ = ds.GetAccountByEmail(ctx, "palvarez@ritho.net")
+	if err != nil || acc == nil || acc.ID() != id {
+		t.Errorf("Expected the account %d, Got %+v: %v", id, acc, err)
+	}
 }
 
 func TestGetAccountSession(t *testing.T) {
+	ctx := context.Background()
 	ds := New()
 
-	_, err := ds.GetAccountBySession(" ")
+	_, err := ds.GetAccountBySession(ctx, " ")
 	if err == nil {
 		t.Error("Expected error getting the account by session.")
 	} else if errors.Cause(err) != account.ErrUserNotLoggedIn {
 		t.Errorf("Expected %s, Got %s", account.ErrUserNotLoggedIn, errors.Cause(err))
 	}
 
-	_, err = ds.GetAccountBySession("00000000-0000-0000-0000-000000000000")
+	_, err = ds.GetAccountBySession(ctx, "00000000-0000-0000-0000-000000000000")
 	if err == nil {
 		t.Error("Expected error getting the account by session.")
 	} else if errors.Cause(err) != account.ErrUserNotLoggedIn {
 		t.Errorf("Expected %s, Got %s", account.ErrUserNotLoggedIn, errors.Cause(err))
 	}
 
-	ds.sessions["00000000-0000-0000-0000-000000000000"] = &account.Account{}
-	_, err = ds.GetAccountBySession("00000000-0000-0000-0000-000000000000")
+	ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	ds.AddSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
+	_, err = ds.GetAccountBySession(ctx, "00000000-0000-0000-0000-000000000000")
 	if err != nil {
 		t.Errorf("Unexpected error: %s", err)
 	}
 }
 
 func TestDatastoreLogin(t *testing.T) {
+	ctx := context.Background()
 	ds := New()
 
-	err := ds.AddSession("00000000-0000-0000-0000-000000000000", "ritho")
+	err := ds.AddSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
 	if fmt.Sprintf("%v", err) != "ritho: Account doesn't exists" {
 		t.Errorf("Expected 'ritho: Account doesn't exists', Got '%v'", err)
 	}
 
-	ds.accounts["ritho"] = &account.Account{}
-	err = ds.AddSession("00000000-0000-0000-0000-000000000000", "ritho")
+	acc, _ := account.New("ritho", "ritho", "palvarez@ritho.net", "ritho")
+	ds.add(acc)
+	err = ds.AddSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
 	if err != nil {
 		t.Errorf("Unexpected error %+v", err)
 	}
 
-	err = ds.AddSession("00000000-0000-0000-0000-000000000000", "ritho")
+	err = ds.AddSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
 	if fmt.Sprintf("%v", err) != "ritho: User already logged in" {
 		t.Errorf("Expected 'ritho: User already logged in', Got '%v'", err)
 	}
 }
 
+func TestDatastoreAPIKey(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+	id, _ := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	acc, _ := ds.GetAccountByID(ctx, id)
+	now := time.Now()
+	err := acc.AddAPIKey("1234", "ci", "radar_secret", []string{account.ScopeRead}, now, now.Add(time.Hour))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if err = ds.UpdateAccountData(ctx, acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, _, err = ds.GetAccountByAPIKey(ctx, "radar_secret")
+	if err != account.ErrAPIKeyInvalid {
+		t.Errorf("Expected the key of the inactive account refused, Got %v", err)
+	}
+
+	ds.ActivateAccount(ctx, id)
+	found, key, err := ds.GetAccountByAPIKey(ctx, "radar_secret")
+	if err != nil || found.ID() != id || key.ID != "1234" || key.LastUsed.IsZero() {
+		t.Errorf("Expected the account of the key, Got %v: %v", found, err)
+	}
+
+	_, _, err = ds.GetAccountByAPIKey(ctx, "radar_wrong")
+	if err != account.ErrAPIKeyInvalid {
+		t.Errorf("Expected %s, Got %v", account.ErrAPIKeyInvalid, err)
+	}
+
+	/* The last use is kept in the account, not updated on every use. */
+	acc, _ = ds.GetAccountByID(ctx, id)
+	used := acc.APIKeys()[0].LastUsed
+	_, again, _ := ds.GetAccountByAPIKey(ctx, "radar_secret")
+	acc, _ = ds.GetAccountByID(ctx, id)
+	if !used.Equal(key.LastUsed) || !acc.APIKeys()[0].LastUsed.Equal(used) || again.LastUsed.Before(used) {
+		t.Errorf("Expected the last use updated once a minute, Got %s", acc.APIKeys()[0].LastUsed)
+	}
+
+	if err = acc.RevokeAPIKey("1234"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err = acc.AddAPIKey("5678", "deploy", "radar_deploy", []string{account.ScopeRead}, now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if err = ds.UpdateAccountData(ctx, acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, _, err = ds.GetAccountByAPIKey(ctx, "radar_secret")
+	if err != account.ErrAPIKeyInvalid {
+		t.Errorf("Expected the revoked key refused, Got %v", err)
+	}
+
+	if _, key, err = ds.GetAccountByAPIKey(ctx, "radar_deploy"); err != nil || key.ID != "5678" {
+		t.Errorf("Expected the new key, Got %+v: %v", key, err)
+	}
+
+	if err = ds.EraseAccount(ctx, acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(ds.apiKeys) != 0 {
+		t.Errorf("Expected the keys of the erased account unindexed, Got %v", ds.apiKeys)
+	}
+}
+
+func TestQueryAccounts(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+	for _, username := range []string{"ritho", "jdoe-", "asmith", "bjones"} {
+		id, err := ds.AccountRegistration(ctx, username, username, username+"@ritho.net", "12345")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		if username != "bjones" {
+			ds.ActivateAccount(ctx, id)
+		}
+	}
+
+	accounts, total, err := ds.QueryAccounts(ctx, account.Query{Sort: account.SortUsername, Limit: 2})
+	if err != nil || total != 3 || len(accounts) != 2 ||
+		accounts[0].Username() != "asmith" || accounts[1].Username() != "jdoe-" {
+		t.Errorf("Expected the first page of the active accounts, Got %d %v: %v", total, accounts, err)
+	}
+
+	accounts, total, _ = ds.QueryAccounts(ctx, account.Query{Inactive: true, Desc: true, Offset: 3, Limit: 2})
+	if total != 4 || len(accounts) != 1 || accounts[0].Username() != "ritho" {
+		t.Errorf("Expected the last page of all the accounts, Got %d %v", total, accounts)
+	}
+
+	accounts, total, _ = ds.QueryAccounts(ctx, account.Query{Offset: 10})
+	if total != 3 || len(accounts) != 0 {
+		t.Errorf("Expected an empty page, Got %d %v", total, accounts)
+	}
+
+	_, _, err = ds.QueryAccounts(ctx, account.Query{Offset: -1})
+	if errors.Cause(err) != account.ErrQueryOffset {
+		t.Errorf("Expected '%v', Got '%v'", account.ErrQueryOffset, err)
+	}
+}
+
 func TestDatastoreLogout(t *testing.T) {
+	ctx := context.Background()
 	ds := New()
 
-	ds.accounts["ritho"] = &account.Account{}
-	ds.accounts["ritho"].SetUsername("ritho")
-	err := ds.AddSession("00000000-0000-0000-0000-000000000000", "ritho")
+	acc, _ := account.New("ritho", "ritho", "palvarez@ritho.net", "ritho")
+	ds.add(acc)
+	err := ds.AddSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
 	if err != nil {
 		t.Errorf("Unexpected error %+v", err)
 	}
 
-	err = ds.DeleteSession("00000000-0000-0000-0000-000000000000", "ritho")
+	err = ds.DeleteSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
 	if err != nil {
 		t.Errorf("Unexpected error '%v'", err)
 	}
 
-	err = ds.DeleteSession("00000000-0000-0000-0000-000000000000", "ritho")
+	err = ds.DeleteSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
 	if fmt.Sprintf("%v", err) != "ritho: User not logged in" {
 		t.Errorf("Expected 'ritho: User not logged in', Got '%v'", err)
 	}
 
-	err = ds.DeleteSession("00000000-0000-0000-0000-000000000000", "rit")
+	err = ds.DeleteSession(ctx, "00000000-0000-0000-0000-000000000000", "rit")
 	if fmt.Sprintf("%v", err) != "rit: Account doesn't exists" {
 		t.Errorf("Expected 'rit: Account doesn't exists', Got '%v'", err)
 	}
 }
 
 func TestUpdateAccount(t *testing.T) {
+	ctx := context.Background()
 	acc := &account.Account{}
 	session := "00000000-0000-0000-0000-000000000000"
 	ds := New()
 
-	err := ds.UpdateAccountData(acc, " ")
+	err := ds.UpdateAccountData(ctx, acc)
 	if err == nil {
 		t.Error("Expected error updating the account data")
 	} else if errors.Cause(err) != account.ErrAccountNotExists {
 		t.Errorf("Expected %s, Got %s", account.ErrAccountNotExists, errors.Cause(err))
 	}
 
-	err = ds.UpdateAccountData(acc, session)
-	if err == nil {
-		t.Error("Expected error updating the account data")
-	} else if errors.Cause(err) != account.ErrAccountNotExists {
-		t.Errorf("Expected %s, Got %s", account.ErrAccountNotExists, errors.Cause(err))
+	id, _ := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	ds.AccountRegistration(ctx, "jdoe-", "jdoe", "jdoe@ritho.net", "12345")
+	ds.AddSession(ctx, session, "ritho")
+	acc, _ = ds.GetAccountByID(ctx, id)
+	acc.SetUsername("palvarez")
+	acc.SetEmail("pablo@ritho.net")
+	err = ds.UpdateAccountData(ctx, acc)
+	if err != nil {
+		t.Errorf("Unexpected error updating the accoung data: %s", err)
 	}
 
-	ds.sessions[session] = acc
-	err = ds.UpdateAccountData(acc, session)
-	if err == nil {
-		t.Error("Expected error updating the account data")
-	} else if errors.Cause(err) != account.ErrAccountNotExists {
-		t.Errorf("Expected %s, Got %s", account.ErrAccountNotExists, errors.Cause(err))
+	if ds.IsAccountRegisteredByUsername(ctx, "ritho") || !ds.IsAccountRegisteredByUsername(ctx, "palvarez") {
+		t.Error("Expected the account indexed by its new username only")
 	}
 
-	ds.accounts[acc.Username()] = acc
-	err = ds.UpdateAccountData(acc, session)
-	if err != nil {
-		t.Errorf("Unexpected error updating the accoung data: %s", err)
+	if _, err = ds.GetAccountByEmail(ctx, "palvarez@ritho.net"); errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected the account indexed by its new email only, Got %v", err)
+	}
+
+	if sessionAcc, _ := ds.GetAccountBySession(ctx, session); !sessionAcc.Equals(acc) {
+		t.Errorf("Expected the session of the account, Got %+v", sessionAcc)
+	}
+
+	acc.SetName("Pablo")
+	acc.SetUsername("jdoe-")
+	err = ds.UpdateAccountData(ctx, acc)
+	if errors.Cause(err) != account.ErrAccountExists || acc.Username() != "palvarez" {
+		t.Errorf("Expected %s and the username restored, Got %s: %v", account.ErrAccountExists,
+			acc.Username(), err)
+	}
+
+	acc.SetEmail("JDoe@ritho.net")
+	err = ds.UpdateAccountData(ctx, acc)
+	if errors.Cause(err) != account.ErrAccountExists || acc.Email() != "pablo@ritho.net" {
+		t.Errorf("Expected %s and the email restored, Got %s: %v", account.ErrAccountExists,
+			acc.Email(), err)
+	}
+
+	if ds.AccountsCount() != 2 {
+		t.Errorf("Expected 2 accounts, Got %d", ds.AccountsCount())
 	}
 }
 
 func TestRemoveAccount(t *testing.T) {
+	ctx := context.Background()
 	acc := &account.Account{}
 	session := "00000000-0000-0000-0000-000000000000"
 	ds := New()
 
-	err := ds.RemoveAccount(acc)
+	err := ds.RemoveAccount(ctx, acc)
 	if err == nil {
 		t.Error("Expected error removing the account")
 	} else if errors.Cause(err) != account.ErrAccountNotExists {
 		t.Errorf("Expected %s, Got %s", account.ErrAccountNotExists, errors.Cause(err))
 	}
 
-	ds.accounts[acc.Username()] = acc
-	err = ds.RemoveAccount(acc)
+	id, _ := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	ds.AddSession(ctx, session, "ritho")
+	acc, _ = ds.GetAccountByID(ctx, id)
+	err = ds.RemoveAccount(ctx, acc)
 	if err != nil {
 		t.Errorf("Unexpected error removing the account: %s", err)
 	}
 
-	ds.accounts[acc.Username()] = acc
-	ds.sessions[session] = acc
-	err = ds.RemoveAccount(acc)
+	if _, err := ds.GetAccountByUsername(ctx, "ritho"); errors.Cause(err) != account.ErrAccountNotExists || ds.SessionsCount() != 0 {
+		t.Error("Expected the account hidden and its sessions removed")
+	}
+
+	if !acc.IsRemoved() || ds.AccountsCount() != 0 {
+		t.Error("Expected the account removed")
+	}
+
+	err = ds.RemoveAccount(ctx, acc)
+	if errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotExists, err)
+	}
+
+	_, err = ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	if err == nil {
+		t.Error("Expected the username and the email reserved until the account is purged")
+	}
+}
+
+func TestRestoreAccount(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+
+	id, _ := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	acc, _ := ds.GetAccountByID(ctx, id)
+
+	_, err := ds.GetRemovedAccount(ctx, acc.UUID())
+	if errors.Cause(err) != account.ErrAccountNotRemoved {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotRemoved, err)
+	}
+
+	err = ds.RestoreAccount(ctx, acc)
+	if errors.Cause(err) != account.ErrAccountNotRemoved {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotRemoved, err)
+	}
+
+	if err = ds.RemoveAccount(ctx, acc); err != nil {
+		t.Fatalf("Unexpected error removing the account: %s", err)
+	}
+
+	removed, err := ds.GetRemovedAccount(ctx, acc.UUID())
 	if err != nil {
-		t.Errorf("Unexpected error removing the account: %s", err)
+		t.Fatalf("Unexpected error getting the removed account: %s", err)
+	}
+
+	if err = ds.RestoreAccount(ctx, removed); err != nil {
+		t.Errorf("Unexpected error restoring the account: %s", err)
+	}
+
+	if _, err = ds.GetAccountByUsername(ctx, "ritho"); err != nil {
+		t.Errorf("Expected the account restored, Got %v", err)
+	}
+
+	_, err = ds.GetRemovedAccount(ctx, "00000000-0000-0000-0000-000000000000")
+	if errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotExists, err)
+	}
+}
+
+func TestPurgeAccounts(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+
+	id, _ := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	acc, _ := ds.GetAccountByID(ctx, id)
+	if err := ds.RemoveAccount(ctx, acc); err != nil {
+		t.Fatalf("Unexpected error removing the account: %s", err)
+	}
+
+	purged, err := ds.PurgeAccounts(ctx, acc.Removed())
+	if err != nil || purged != 0 {
+		t.Errorf("Expected no account purged within the retention, Got %d, %v", purged, err)
+	}
+
+	purged, err = ds.PurgeAccounts(ctx, acc.Removed().Add(time.Second))
+	if err != nil || purged != 1 {
+		t.Errorf("Expected 1 account purged, Got %d, %v", purged, err)
+	}
+
+	_, err = ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	if err != nil {
+		t.Errorf("Expected the username and the email free again, Got %v", err)
+	}
+}
+
+func TestEraseAccount(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+
+	err := ds.EraseAccount(ctx, &account.Account{})
+	if errors.Cause(err) != account.ErrAccountNotExists {
+		t.Errorf("Expected %s, Got %v", account.ErrAccountNotExists, err)
+	}
+
+	id, _ := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	acc, _ := ds.GetAccountByID(ctx, id)
+	ds.AddSession(ctx, "00000000-0000-0000-0000-000000000000", "ritho")
+	ds.AddAuditRecord(ctx, audit.Record{Action: "AccountEdit", Actor: "palvarez", Target: "ritho",
+		TargetID: acc.UUID(), Changes: map[string]audit.Change{
+			"username": {Before: "palvarez", After: "ritho"},
+			"email":    {Before: "ritho@ritho.net", After: "palvarez@ritho.net"},
+		}})
+	ds.AddAuditRecord(ctx, audit.Record{Action: "AccountLogin", Actor: "ritho", Target: "ritho",
+		TargetID: acc.UUID()})
+	ds.AddAuditRecord(ctx, audit.Record{Action: "AccountLogin", Actor: "admin", Target: "admin",
+		TargetID: "other"})
+
+	err = ds.EraseAccount(ctx, acc)
+	if err != nil {
+		t.Fatalf("Unexpected error erasing the account: %s", err)
+	}
+
+	if _, err = ds.GetRemovedAccount(ctx, acc.UUID()); errors.Cause(err) != account.ErrAccountNotExists ||
+		ds.SessionsCount() != 0 {
+		t.Error("Expected the account and its sessions deleted")
+	}
+
+	if acc.Name() != "" || acc.Email() != "" || !acc.IsFormer() {
+		t.Errorf("Expected the member anonymized, Got %s %s", acc.Name(), acc.Email())
+	}
+
+	records, _ := ds.AuditRecords(ctx, audit.Filter{Target: audit.Erased})
+	if len(records) != 2 || records[1].Actor != audit.Erased || records[0].Actor != audit.Erased ||
+		records[1].Changes["email"].Before != audit.Erased {
+		t.Errorf("Expected the records of the account erased, Got %+v", records)
+	}
+
+	records, _ = ds.AuditRecords(ctx, audit.Filter{Actor: "admin"})
+	if len(records) != 1 || records[0].Target != "admin" {
+		t.Errorf("Expected the records of other accounts kept, Got %+v", records)
+	}
+
+	_, err = ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "12345")
+	if err != nil {
+		t.Errorf("Expected the username and the email free again, Got %v", err)
+	}
+}
+
+func TestDatastoreCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ds := New()
+	_, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Errorf("Unexpected error registering an account: %+v", err)
+	}
+
+	cancel()
+	_, err = ds.AccountRegistration(ctx, "ritho2", "ritho", "palvarez@ritho.net", "ritho")
+	if err != context.Canceled {
+		t.Errorf("Expected %s, Got %v", context.Canceled, err)
+	}
+
+	_, err = ds.GetAccountByUsername(ctx, "ritho")
+	if err != context.Canceled {
+		t.Errorf("Expected %s, Got %v", context.Canceled, err)
+	}
+
+	_, err = ds.Accounts(ctx)
+	if err != context.Canceled {
+		t.Errorf("Expected %s, Got %v", context.Canceled, err)
+	}
+
+	if ds.AccountsCount() != 1 {
+		t.Errorf("Expected 1 account, Got %d", ds.AccountsCount())
+	}
+}
+
+func TestDatastoreDeleteSessions(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error registering an account: %+v", err)
+	}
+
+	for _, session := range []string{"00000000-0000-0000-0000-000000000001",
+		"00000000-0000-0000-0000-000000000002"} {
+		err = ds.AddSession(ctx, session, "ritho")
+		if err != nil {
+			t.Fatalf("Unexpected error adding a session: %+v", err)
+		}
+	}
+
+	sessions, err := ds.AccountSessions(ctx, id)
+	if err != nil || len(sessions) != 2 {
+		t.Errorf("Expected 2 sessions, Got %v: %v", sessions, err)
+	}
+
+	removed, err := ds.DeleteSessions(ctx, id)
+	if err != nil || removed != 2 {
+		t.Errorf("Expected 2 sessions removed, Got %d: %v", removed, err)
+	}
+
+	if ds.DoesAccountHaveSessionByID(ctx, id) {
+		t.Error("Expected the account to have no sessions")
+	}
+}
+
+func TestDatastoreAudit(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+	for _, action := range []string{"AccountLogin", "AccountEdit", "AccountLogout"} {
+		err := ds.AddAuditRecord(ctx, audit.Record{Action: action, Actor: "ritho"})
+		if err != nil {
+			t.Errorf("Unexpected error adding an audit record: %s", err)
+		}
+	}
+
+	records, err := ds.AuditRecords(ctx, audit.Filter{})
+	if err != nil || len(records) != 3 {
+		t.Fatalf("Expected 3 records, Got %d: %v", len(records), err)
+	}
+
+	if records[0].ID != 3 || records[0].Action != "AccountLogout" {
+		t.Errorf("Expected the newest record first, Got %v", records[0])
+	}
+
+	records, err = ds.AuditRecords(ctx, audit.Filter{Action: "AccountEdit"})
+	if err != nil || len(records) != 1 || records[0].ID != 2 {
+		t.Errorf("Expected the record 2, Got %v: %v", records, err)
+	}
+}
+
+func TestDatastorePolicy(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error registering an account: %+v", err)
+	}
+
+	acc, _ := ds.GetAccountByID(ctx, id)
+	err = ds.SetPolicy(ctx, Policy{TwoFactor: "everyone"})
+	if err != ErrTwoFactorPolicy {
+		t.Errorf("Expected %s, Got %v", ErrTwoFactorPolicy, err)
+	}
+
+	err = ds.SetPolicy(ctx, Policy{TwoFactor: TwoFactorAdmins})
+	if err != nil {
+		t.Errorf("Unexpected error setting the policy: %s", err)
+	}
+
+	policy, _ := ds.Policy(ctx)
+	if policy.RequiresTwoFactor(acc) {
+		t.Error("Expected the two-factor authentication optional for the members")
+	}
+
+	ds.SetAdmin(ctx, id, true)
+	acc, _ = ds.GetAccountByID(ctx, id)
+	if !policy.RequiresTwoFactor(acc) {
+		t.Error("Expected the two-factor authentication required for the admins")
+	}
+
+	ds.SetPolicy(ctx, Policy{})
+	policy, _ = ds.Policy(ctx)
+	if policy.TwoFactor != TwoFactorOptional || policy.RequiresTwoFactor(acc) {
+		t.Errorf("Expected the two-factor authentication optional, Got %+v", policy)
+	}
+}
+
+func TestDatastoreEdition(t *testing.T) {
+	ctx := context.Background()
+	ds := New()
+
+	_, err := ds.Edition(ctx, "2018")
+	if errors.Cause(err) != ErrEditionNotExists {
+		t.Errorf("Expected %s, Got %v", ErrEditionNotExists, err)
+	}
+
+	techs := []Technology{NewTechnology("Go", "Languages & Frameworks", "A language"),
+		NewTechnology("Docker", "Platforms", "")}
+	blips := []Blip{{Technology: "Go", Ring: "Trial", New: true}, {Technology: "Docker", Ring: "Adopt"}}
+	created, updated, err := ds.UpdateEdition(ctx, " 2018 ", techs, blips)
+	if err != nil || created != 2 || updated != 0 {
+		t.Errorf("Expected 2 blips created, Got %d, %d: %v", created, updated, err)
+	}
+
+	techs = []Technology{NewTechnology("Go", "Tools", "")}
+	blips = []Blip{{Technology: "go", Ring: "Adopt"}}
+	created, updated, err = ds.UpdateEdition(ctx, "2018", techs, blips)
+	if err != nil || created != 0 || updated != 1 {
+		t.Errorf("Expected 1 blip updated, Got %d, %d: %v", created, updated, err)
+	}
+
+	edition, err := ds.Edition(ctx, "2018")
+	if err != nil || edition.Name != "2018" || len(edition.Blips) != 2 ||
+		edition.Blips[0] != (Blip{Technology: "go", Ring: "Adopt"}) {
+		t.Errorf("Unexpected edition %+v: %v", edition, err)
+	}
+
+	tech, ok := ds.Technology(ctx, "GO")
+	if !ok || tech.Type() != "Tools" || tech.Description() != "A language" {
+		t.Errorf("Expected the technology updated keeping its description, Got %+v", tech)
+	}
+
+	_, _, err = ds.UpdateEdition(ctx, " ", techs, blips)
+	if errors.Cause(err) != ErrEditionNotExists {
+		t.Errorf("Expected %s, Got %v", ErrEditionNotExists, err)
 	}
 }
@@ -0,0 +1,242 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/entities/technology"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/metrics"
+)
+
+// ErrEditionNotExists raised when the radar edition doesn't exists.
+var ErrEditionNotExists = i18n.NewError(&goi18n.Message{
+	ID:    "EditionNotExists",
+	Other: "Radar edition doesn't exists",
+})
+
+// Technology represents a technology placed in the radar editions, its type
+// being the quadrant of the radar where it's placed.
+type Technology struct {
+	technology.Technology
+
+	description string
+}
+
+// technologyRecord is the representation of a technology of the radar
+// editions when it's persisted.
+type technologyRecord struct {
+	Name        string `json:"name"`
+	Quadrant    string `json:"quadrant"`
+	Description string `json:"description,omitempty"`
+}
+
+// NewTechnology returns a new Technology object.
+func NewTechnology(name, quadrant, description string) Technology {
+	tech := Technology{description: description}
+	tech.SetName(name)
+	tech.SetType(quadrant)
+
+	return tech
+}
+
+// Description returns the description of the technology.
+func (t *Technology) Description() string {
+	return t.description
+}
+
+// SetDescription sets the description of the technology.
+func (t *Technology) SetDescription(description string) {
+	t.description = description
+}
+
+// merge updates the technology with the fields of tech that aren't empty, so
+// a radar without the descriptions doesn't remove them.
+func (t *Technology) merge(tech Technology) {
+	if tech.Name() != "" {
+		t.SetName(tech.Name())
+	}
+
+	if tech.Type() != "" {
+		t.SetType(tech.Type())
+	}
+
+	if tech.Description() != "" {
+		t.SetDescription(tech.Description())
+	}
+}
+
+// MarshalJSON returns the technology encoded as json to persist it.
+func (t *Technology) MarshalJSON() ([]byte, error) {
+	return json.Marshal(technologyRecord{
+		Name:        t.Name(),
+		Quadrant:    t.Type(),
+		Description: t.description,
+	})
+}
+
+// UnmarshalJSON restores a technology previously encoded with MarshalJSON.
+func (t *Technology) UnmarshalJSON(data []byte) error {
+	var r technologyRecord
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	*t = NewTechnology(r.Name, r.Quadrant, r.Description)
+
+	return nil
+}
+
+// Blip represents a technology placed in a ring of a radar edition.
+type Blip struct {
+	Technology string `json:"technology"`
+	Ring       string `json:"ring"`
+	New        bool   `json:"new,omitempty"`
+}
+
+// Edition represents a radar published by the organization, with its blips.
+type Edition struct {
+	Name  string `json:"name"`
+	Blips []Blip `json:"blips"`
+}
+
+// Edition returns a radar edition by its name.
+func (d *Datastore) Edition(ctx context.Context, name string) (Edition, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return Edition{}, err
+	}
+
+	edition, ok := d.editions[radar.CleanString(name)]
+	if !ok {
+		return Edition{}, errors.Wrap(ErrEditionNotExists, name)
+	}
+
+	return Edition{
+		Name:  edition.Name,
+		Blips: append([]Blip(nil), edition.Blips...),
+	}, nil
+}
+
+// Technology returns a technology of the radar editions by its name, or false
+// if it doesn't exists.
+func (d *Datastore) Technology(ctx context.Context, name string) (Technology, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	tech, ok := d.technologies[radar.CleanString(name)]
+	return tech, ok
+}
+
+// UpdateEdition creates or updates the technologies and the blips of a radar
+// edition, creating the edition if it doesn't exists. The technologies already
+// stored only get the fields that aren't empty updated. It returns the number
+// of blips created and updated.
+func (d *Datastore) UpdateEdition(ctx context.Context, name string, techs []Technology, blips []Blip) (int, int, error) {
+	defer metrics.ObserveDatastore("UpdateEdition", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	key := radar.CleanString(name)
+	if key == "" {
+		return 0, 0, errors.Wrap(ErrEditionNotExists, name)
+	}
+
+	for _, tech := range techs {
+		key := radar.CleanString(tech.Name())
+		if current, ok := d.technologies[key]; ok {
+			current.merge(tech)
+			tech = current
+		}
+
+		d.technologies[key] = tech
+	}
+
+	edition, ok := d.editions[key]
+	if !ok {
+		edition = &Edition{Name: strings.TrimSpace(name)}
+		d.editions[key] = edition
+	}
+
+	created, updated := 0, 0
+	for _, blip := range blips {
+		found := false
+		for i := range edition.Blips {
+			if radar.CleanString(edition.Blips[i].Technology) == radar.CleanString(blip.Technology) {
+				edition.Blips[i] = blip
+				found = true
+				break
+			}
+		}
+
+		if found {
+			updated++
+		} else {
+			edition.Blips = append(edition.Blips, blip)
+			created++
+		}
+	}
+
+	return created, updated, d.save()
+}
+
+// sortedTechnologies returns the technologies of the radar editions sorted by
+// name.
+func (d *Datastore) sortedTechnologies() []Technology {
+	techs := make([]Technology, 0, len(d.technologies))
+	for _, tech := range d.technologies {
+		techs = append(techs, tech)
+	}
+
+	sort.Slice(techs, func(i, j int) bool {
+		return techs[i].Name() < techs[j].Name()
+	})
+
+	return techs
+}
+
+// sortedEditions returns the radar editions sorted by name.
+func (d *Datastore) sortedEditions() []Edition {
+	editions := make([]Edition, 0, len(d.editions))
+	for _, edition := range d.editions {
+		editions = append(editions, *edition)
+	}
+
+	sort.Slice(editions, func(i, j int) bool {
+		return editions[i].Name < editions[j].Name
+	})
+
+	return editions
+}
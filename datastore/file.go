@@ -0,0 +1,229 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+	"github.com/radar-go/radar/metrics"
+)
+
+// snapshot represents the content of the datastore when it's persisted.
+type snapshot struct {
+	Schema   Schema             `json:"schema"`
+	Sequence int                `json:"sequence,omitempty"`
+	Accounts []*account.Account `json:"accounts"`
+	Audit    []audit.Record     `json:"audit,omitempty"`
+	Policy   Policy             `json:"policy"`
+
+	Technologies []Technology `json:"technologies,omitempty"`
+	Editions     []Edition    `json:"editions,omitempty"`
+}
+
+// Open creates and returns a new datastore object backed by the file in path.
+// The content of the file is loaded if it already exists, and every change on
+// the datastore is written back to it. The files with pending migrations are
+// refused until they are migrated with Migrate, as are the files written by a
+// newer radar.
+func Open(path string) (*Datastore, error) {
+	d, _, err := open(path, false, false)
+	return d, err
+}
+
+// open loads the datastore file in path, applying its pending migrations only
+// if migrate is set, and returns it with the migrations applied. With dryRun
+// the migrations are not written back and the datastore returned isn't backed
+// by the file.
+func open(path string, migrate, dryRun bool) (*Datastore, []Migration, error) {
+	defer metrics.ObserveDatastore("Open", time.Now())
+
+	d := New()
+	if !dryRun {
+		d.path = path
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil, nil
+	} else if err != nil {
+		return nil, nil, errors.Wrap(err, "Error reading the datastore file")
+	}
+
+	snap, applied, err := decodeSnapshot(data, migrate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = d.loadSnapshot(snap)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Error decoding the datastore file")
+	}
+
+	if len(applied) > 0 && !dryRun {
+		/* Write the migrated schema so it's not migrated again. */
+		if err = d.save(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return d, applied, nil
+}
+
+// decodeSnapshot decodes the content of the datastore, applying the pending
+// migrations of its schema if migrate is set, and returns it with the
+// migrations applied. It returns an error if there are migrations pending and
+// migrate isn't set.
+func decodeSnapshot(data []byte, migrate bool) (snapshot, []Migration, error) {
+	var snap snapshot
+	doc, err := decodeDocument(data)
+	if err != nil {
+		return snap, nil, errors.Wrap(err, "Error decoding the datastore file")
+	}
+
+	applied, err := upgrade(doc, migrate, time.Now().UTC())
+	if err != nil {
+		return snap, nil, err
+	}
+
+	data, err = json.Marshal(doc)
+	if err == nil {
+		err = json.Unmarshal(data, &snap)
+	}
+
+	return snap, applied, errors.Wrap(err, "Error decoding the datastore file")
+}
+
+// loadSnapshot stores the content of a snapshot in the datastore.
+func (d *Datastore) loadSnapshot(snap snapshot) error {
+	for _, acc := range snap.Accounts {
+		if err := d.load(acc); err != nil {
+			return err
+		}
+	}
+
+	if snap.Sequence > d.seq {
+		d.seq = snap.Sequence
+	}
+
+	d.schema = snap.Schema
+	d.audit = snap.Audit
+	d.policy = snap.Policy
+	for _, tech := range snap.Technologies {
+		d.technologies[radar.CleanString(tech.Name())] = tech
+	}
+
+	for i := range snap.Editions {
+		d.editions[radar.CleanString(snap.Editions[i].Name)] = &snap.Editions[i]
+	}
+
+	return nil
+}
+
+// currentSnapshot returns the content of the datastore to persist it.
+func (d *Datastore) currentSnapshot() snapshot {
+	return snapshot{
+		Schema:   d.schema,
+		Sequence: d.seq,
+		Accounts: d.sortedAccounts(),
+		Audit:    d.audit,
+		Policy:   d.policy,
+
+		Technologies: d.sortedTechnologies(),
+		Editions:     d.sortedEditions(),
+	}
+}
+
+// Accounts returns all the accounts stored in the datastore, but the removed
+// ones, sorted by id.
+func (d *Datastore) Accounts(ctx context.Context) ([]*account.Account, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*account.Account, 0, len(d.accounts))
+	for _, acc := range d.sortedAccounts() {
+		if !acc.IsRemoved() {
+			accounts = append(accounts, acc.Clone())
+		}
+	}
+
+	return accounts, nil
+}
+
+// sortedAccounts returns all the accounts stored in the datastore sorted by id.
+func (d *Datastore) sortedAccounts() []*account.Account {
+	accounts := make([]*account.Account, 0, len(d.accounts))
+	for _, acc := range d.accounts {
+		accounts = append(accounts, acc)
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].ID() < accounts[j].ID()
+	})
+
+	return accounts
+}
+
+// save writes the content of the datastore to its file, if any.
+func (d *Datastore) save() error {
+	defer metrics.ObserveDatastore("Save", time.Now())
+
+	if d.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(d.currentSnapshot(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "Error encoding the datastore")
+	}
+
+	/* Write to a temporary file first so a failure never leaves a truncated
+	datastore behind. */
+	tmp, err := ioutil.TempFile(filepath.Dir(d.path), ".radar-")
+	if err != nil {
+		return errors.Wrap(err, "Error writing the datastore file")
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "Error writing the datastore file")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), d.path),
+		"Error writing the datastore file")
+}
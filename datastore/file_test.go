@@ -0,0 +1,198 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+)
+
+func TestDatastoreFile(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "radar.db")
+	ds, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	id, err := ds.AccountRegistration(ctx, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Errorf("Unexpected error registering an account: %+v", err)
+	}
+
+	if !ds.ActivateAccount(ctx, id) || !ds.SetAdmin(ctx, id, true) {
+		t.Error("Expected the account to be activated and promoted")
+	}
+
+	err = ds.AddAuditRecord(ctx, audit.Record{Action: "AccountActivate", Target: "ritho"})
+	if err != nil {
+		t.Errorf("Unexpected error adding an audit record: %s", err)
+	}
+
+	err = ds.SetPolicy(ctx, Policy{TwoFactor: TwoFactorAdmins})
+	if err != nil {
+		t.Errorf("Unexpected error setting the policy: %s", err)
+	}
+
+	_, _, err = ds.UpdateEdition(ctx, "2018", []Technology{NewTechnology("Go", "Tools", "")},
+		[]Blip{{Technology: "Go", Ring: "Adopt"}})
+	if err != nil {
+		t.Errorf("Unexpected error updating the edition: %s", err)
+	}
+
+	ds, err = Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	accounts, err := ds.Accounts(ctx)
+	if err != nil || len(accounts) != 1 {
+		t.Fatalf("Expected 1 account, Got %d", len(accounts))
+	}
+
+	records, err := ds.AuditRecords(ctx, audit.Filter{})
+	if err != nil || len(records) != 1 || records[0].Action != "AccountActivate" {
+		t.Errorf("Expected the audit log to be restored, Got %v: %v", records, err)
+	}
+
+	policy, err := ds.Policy(ctx)
+	if err != nil || policy.TwoFactor != TwoFactorAdmins {
+		t.Errorf("Expected the policy to be restored, Got %+v: %v", policy, err)
+	}
+
+	edition, err := ds.Edition(ctx, "2018")
+	tech, ok := ds.Technology(ctx, "go")
+	if err != nil || len(edition.Blips) != 1 || !ok || tech.Type() != "Tools" {
+		t.Errorf("Expected the edition to be restored, Got %+v %+v: %v", edition, tech, err)
+	}
+
+	acc := accounts[0]
+	if acc.ID() != id || acc.Username() != "ritho" || !acc.IsActive() || !acc.IsAdmin() {
+		t.Errorf("Unexpected account restored: %d %s %t %t", acc.ID(),
+			acc.Username(), acc.IsActive(), acc.IsAdmin())
+	}
+
+	restored, err := ds.GetAccountByUUID(ctx, acc.UUID())
+	if err != nil || !restored.Equals(acc) {
+		t.Errorf("Expected the account by its UUID %s, Got %v", acc.UUID(), err)
+	}
+
+	err = ds.RemoveAccount(ctx, acc)
+	if err != nil {
+		t.Errorf("Unexpected error removing the account: %s", err)
+	}
+
+	ds, err = Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	accounts, err = ds.Accounts(ctx)
+	if err != nil || len(accounts) != 0 {
+		t.Errorf("Expected 0 accounts, Got %d", len(accounts))
+	}
+
+	removed, err := ds.GetRemovedAccount(ctx, acc.UUID())
+	if err != nil || !removed.IsRemoved() || removed.Removed().IsZero() {
+		t.Errorf("Expected the removed account to be kept until purged, Got %v", err)
+	}
+
+	newID, err := ds.AccountRegistration(ctx, "ritho2", "ritho", "ritho@ritho.net", "ritho")
+	if err != nil || newID != id+1 {
+		t.Errorf("Expected the id of the removed account not to be reused, Got %d: %v", newID, err)
+	}
+
+	/* The datastores written before the accounts were stored by id have the
+	renamed accounts twice, and have no UUIDs. */
+	acc, _ = account.New("palvarez", "ritho", "palvarez@ritho.net", "ritho")
+	acc.SetIdentifiers(7, "")
+	data, _ := json.Marshal(acc)
+	err = ioutil.WriteFile(path, []byte(fmt.Sprintf(`{"accounts": [%s, %s], "audit": [
+		{"id": 1, "action": "AccountActivate", "target": "palvarez", "target_id": 7},
+		{"id": 2, "action": "AccountRemove", "target": "jdoe", "target_id": 3}]}`, data, data)), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = Open(path)
+	if errors.Cause(err) != ErrSchemaOutdated {
+		t.Fatalf("Expected %s opening the datastore before migrating it, Got %v", ErrSchemaOutdated, err)
+	}
+
+	_, err = Migrate(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error migrating the datastore: %s", err)
+	}
+
+	ds, err = Open(path)
+	if err != nil || ds.AccountsCount() != 1 || !ds.IsAccountRegisteredByUsername(ctx, "palvarez") {
+		t.Fatalf("Expected the account loaded once, Got %d: %v", ds.AccountsCount(), err)
+	}
+
+	acc, _ = ds.GetAccountByID(ctx, 7)
+	if acc == nil || acc.UUID() == "" {
+		t.Fatal("Expected the account to get an UUID")
+	}
+
+	ds, err = Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	restored, err = ds.GetAccountByUUID(ctx, acc.UUID())
+	if err != nil || restored.ID() != 7 {
+		t.Errorf("Expected the UUID of the account to be written back, Got %v", err)
+	}
+
+	records, _ = ds.AuditRecords(ctx, audit.Filter{})
+	if len(records) != 2 || records[0].TargetID != "" || records[1].TargetID != acc.UUID() {
+		t.Errorf("Expected the target ids to be migrated to the UUIDs, Got %+v", records)
+	}
+
+	newID, err = ds.AccountRegistration(ctx, "ritho", "ritho", "ritho@ritho.net", "ritho")
+	if err != nil || newID != 8 {
+		t.Errorf("Expected the ids to follow the ones loaded, Got %d: %v", newID, err)
+	}
+
+	err = ioutil.WriteFile(path, []byte("{"), 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, err = Open(path)
+	if err == nil {
+		t.Error("Expected error opening a corrupted datastore")
+	}
+}
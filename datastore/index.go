@@ -0,0 +1,228 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+import (
+	"github.com/golang-plus/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar"
+	"github.com/radar-go/radar/datastore/account"
+)
+
+// ambiguous is the id indexed for an email shared by more than one account,
+// which the datastores written before the emails were unique can have.
+const ambiguous = -1
+
+// keys are the UUID, the username and the email an account is indexed by,
+// kept to remove its entries from the indexes when it changes them.
+type keys struct {
+	uuid     string
+	username string
+	email    string
+}
+
+// keysOf returns the keys of the account as they are indexed.
+func keysOf(acc *account.Account) keys {
+	return keys{
+		uuid:     radar.CleanString(acc.UUID()),
+		username: radar.CleanString(acc.Username()),
+		email:    radar.CleanString(acc.Email()),
+	}
+}
+
+// add stores a new account with the next id and a new UUID, or returns an
+// error if its username or email is used by another account.
+func (d *Datastore) add(acc *account.Account) error {
+	if err := d.conflict(0, keysOf(acc), keys{}); err != nil {
+		return err
+	}
+
+	publicID, err := uuid.NewRandom()
+	if err != nil {
+		return errors.Wrap(err, "Error generating the account UUID")
+	}
+
+	d.seq++
+	id := d.seq
+
+	acc.SetIdentifiers(id, publicID.String())
+	d.accounts[id] = acc
+	d.index(id, keysOf(acc))
+	d.indexAPIKeys(acc)
+
+	return nil
+}
+
+// load stores an account read from the datastore file, indexing its email as
+// ambiguous if another account already has it. The accounts stored before
+// they had a UUID get one migrating the datastore.
+func (d *Datastore) load(acc *account.Account) error {
+	k := keysOf(acc)
+	if id, ok := d.usernames[k.username]; ok && id != acc.ID() {
+		return errors.Wrap(account.ErrAccountExists, k.username)
+	}
+
+	if id, ok := d.uuids[k.uuid]; ok && id != acc.ID() {
+		return errors.Wrap(account.ErrAccountExists, k.uuid)
+	}
+
+	d.unindex(acc.ID())
+	d.unindexAPIKeys(acc.ID())
+	d.accounts[acc.ID()] = acc
+	d.index(acc.ID(), k)
+	d.indexAPIKeys(acc)
+
+	if acc.ID() > d.seq {
+		d.seq = acc.ID()
+	}
+
+	return nil
+}
+
+// reindex updates the indexes after the username or the email of the account
+// change. If they are used by another account the previous ones are restored
+// in the account and an error is returned.
+func (d *Datastore) reindex(acc *account.Account) error {
+	previous, k := d.keys[acc.ID()], keysOf(acc)
+	if k == previous {
+		return nil
+	}
+
+	if err := d.conflict(acc.ID(), k, previous); err != nil {
+		/* The account is the one stored, so the change is undone to keep it
+		matching the indexes. */
+		if k.username != previous.username {
+			_ = acc.SetUsername(previous.username)
+		}
+
+		if k.email != previous.email {
+			_ = acc.SetEmail(previous.email)
+		}
+
+		return err
+	}
+
+	d.unindex(acc.ID())
+	d.index(acc.ID(), k)
+
+	return nil
+}
+
+// conflict returns an error if the keys changed from the previous ones are
+// used by an account other than the one with the id.
+func (d *Datastore) conflict(id int, k, previous keys) error {
+	if other, ok := d.usernames[k.username]; ok && other != id && k.username != previous.username {
+		return errors.Wrap(account.ErrAccountExists, k.username)
+	}
+
+	if other, ok := d.emails[k.email]; ok && other != id && k.email != previous.email {
+		return errors.Wrap(account.ErrAccountExists, k.email)
+	}
+
+	return nil
+}
+
+// index adds the keys of the account with the id to the indexes.
+func (d *Datastore) index(id int, k keys) {
+	d.keys[id] = k
+	d.uuids[k.uuid] = id
+	d.usernames[k.username] = id
+	if k.email == "" {
+		return
+	}
+
+	if other, ok := d.emails[k.email]; ok && other != id {
+		d.emails[k.email] = ambiguous
+	} else {
+		d.emails[k.email] = id
+	}
+}
+
+// unindex removes the keys of the account with the id from the indexes.
+func (d *Datastore) unindex(id int) {
+	k, ok := d.keys[id]
+	if !ok {
+		return
+	}
+
+	delete(d.keys, id)
+	if d.uuids[k.uuid] == id {
+		delete(d.uuids, k.uuid)
+	}
+
+	if d.usernames[k.username] == id {
+		delete(d.usernames, k.username)
+	}
+
+	switch d.emails[k.email] {
+	case id:
+		delete(d.emails, k.email)
+	case ambiguous:
+		/* Index again the accounts still sharing the email. */
+		delete(d.emails, k.email)
+		for other, otherKeys := range d.keys {
+			if otherKeys.email == k.email {
+				d.index(other, otherKeys)
+			}
+		}
+	}
+}
+
+// indexAPIKeys adds the hashes of the API keys of the account to the index.
+func (d *Datastore) indexAPIKeys(acc *account.Account) {
+	for _, k := range acc.APIKeys() {
+		d.apiKeys[k.Hash()] = acc.ID()
+	}
+}
+
+// unindexAPIKeys removes the hashes of the API keys of the account stored
+// with the id from the index.
+func (d *Datastore) unindexAPIKeys(id int) {
+	acc, ok := d.accounts[id]
+	if !ok {
+		return
+	}
+
+	for _, k := range acc.APIKeys() {
+		if d.apiKeys[k.Hash()] == id {
+			delete(d.apiKeys, k.Hash())
+		}
+	}
+}
+
+// removeSession removes the session from the datastore.
+func (d *Datastore) removeSession(session string) {
+	id, ok := d.sessions[session]
+	if !ok {
+		return
+	}
+
+	delete(d.sessions, session)
+	sessions := d.owners[id]
+	for i, s := range sessions {
+		if s == session {
+			d.owners[id] = append(sessions[:i:i], sessions[i+1:]...)
+			break
+		}
+	}
+
+	if len(d.owners[id]) == 0 {
+		delete(d.owners, id)
+	}
+}
@@ -0,0 +1,257 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-plus/uuid"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+)
+
+// SchemaVersion is the version of the schema of the datastore written by this
+// radar, the one of its last migration.
+const SchemaVersion = 2
+
+// ErrSchemaNewer raised when the datastore was written by a newer radar, with
+// a schema this one doesn't know.
+var ErrSchemaNewer = i18n.NewError(&goi18n.Message{
+	ID:    "SchemaNewer",
+	Other: "The datastore schema is newer than the one of this radar",
+})
+
+// ErrSchemaOutdated raised when opening a datastore with pending migrations,
+// they are only applied on purpose.
+var ErrSchemaOutdated = i18n.NewError(&goi18n.Message{
+	ID:    "SchemaOutdated",
+	Other: "The datastore has pending migrations, back it up and run radar migrate",
+})
+
+// Migration represents a change of the schema of the datastore. It's applied
+// to the datastore decoded as a generic JSON document, so it doesn't depend on
+// how the entities are stored by later versions.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(doc map[string]interface{}) error
+}
+
+// AppliedMigration represents a migration applied to the datastore.
+type AppliedMigration struct {
+	Version int       `json:"version"`
+	Name    string    `json:"name"`
+	Applied time.Time `json:"applied"`
+}
+
+// Schema represents the version of the schema of the datastore and the
+// migrations applied to it. The datastores written before the migrations have
+// the version 0.
+type Schema struct {
+	Version    int                `json:"version"`
+	Migrations []AppliedMigration `json:"migrations,omitempty"`
+}
+
+// migrations are the changes of the schema of the datastore, the version of
+// each one being its position in the list starting at 1.
+var migrations = []Migration{
+	{Version: 1, Name: "account-uuids", Up: accountUUIDs},
+	{Version: 2, Name: "password-hashes", Up: passwordHashes},
+}
+
+// Pending returns the migrations to apply to a datastore with the schema
+// version, in order, or an error if the schema is newer than the one of this
+// radar.
+func Pending(version int) ([]Migration, error) {
+	if version > SchemaVersion {
+		return nil, errors.Wrap(ErrSchemaNewer, fmt.Sprintf("schema version %d", version))
+	}
+
+	if version < 0 {
+		version = 0
+	}
+
+	return migrations[version:], nil
+}
+
+// Migrate applies the pending migrations to the datastore file in path and
+// writes it back, returning the migrations applied. With dryRun the migrations
+// are applied in memory only, to check they succeed, and the file is left
+// untouched.
+func Migrate(path string, dryRun bool) ([]Migration, error) {
+	_, applied, err := open(path, true, dryRun)
+	return applied, err
+}
+
+// Schema returns the version of the schema of the datastore and the migrations
+// applied to it.
+func (d *Datastore) Schema() Schema {
+	return d.schema
+}
+
+// decodeDocument decodes the datastore as a generic JSON document, keeping the
+// numbers as they were written.
+func decodeDocument(data []byte) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	err := dec.Decode(&doc)
+
+	return doc, err
+}
+
+// upgrade applies to the document the migrations after the version of its
+// schema, recording them in it, and returns the migrations applied. Without
+// migrate it returns an error if there are migrations pending.
+func upgrade(doc map[string]interface{}, migrate bool, now time.Time) ([]Migration, error) {
+	var schema Schema
+	if raw, ok := doc["schema"]; ok {
+		data, err := json.Marshal(raw)
+		if err == nil {
+			err = json.Unmarshal(data, &schema)
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "Error decoding the datastore schema")
+		}
+	}
+
+	pending, err := Pending(schema.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pending) > 0 && !migrate {
+		return nil, errors.Wrap(ErrSchemaOutdated, fmt.Sprintf("schema version %d", schema.Version))
+	}
+
+	for _, m := range pending {
+		err = m.Up(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error applying the migration %d %s", m.Version, m.Name)
+		}
+
+		schema.Version = m.Version
+		schema.Migrations = append(schema.Migrations, AppliedMigration{
+			Version: m.Version,
+			Name:    m.Name,
+			Applied: now,
+		})
+	}
+
+	doc["schema"] = schema
+
+	return pending, nil
+}
+
+// accountUUIDs gives an UUID to the accounts stored before they had one, the
+// same one to the copies of a renamed account, and replaces the ids of the
+// accounts with their UUIDs as the target of the audit records.
+func accountUUIDs(doc map[string]interface{}) error {
+	accounts, _ := doc["accounts"].([]interface{})
+	uuids := make(map[string]string)
+	for _, a := range accounts {
+		acc, ok := a.(map[string]interface{})
+		if !ok {
+			return errors.New("The account is not an object")
+		}
+
+		if id, _ := acc["uuid"].(string); id != "" {
+			uuids[fmt.Sprint(acc["id"])] = id
+		}
+	}
+
+	for _, a := range accounts {
+		acc := a.(map[string]interface{})
+		if id, _ := acc["uuid"].(string); id != "" {
+			continue
+		}
+
+		id := fmt.Sprint(acc["id"])
+		if _, ok := uuids[id]; !ok {
+			publicID, err := uuid.NewRandom()
+			if err != nil {
+				return errors.Wrap(err, "Error generating the account UUID")
+			}
+
+			uuids[id] = publicID.String()
+		}
+
+		acc["uuid"] = uuids[id]
+	}
+
+	records, _ := doc["audit"].([]interface{})
+	for _, r := range records {
+		rec, ok := r.(map[string]interface{})
+		if !ok {
+			return errors.New("The audit record is not an object")
+		}
+
+		target, ok := rec["target_id"]
+		if !ok {
+			continue
+		}
+
+		id := fmt.Sprint(target)
+		if _, err := strconv.Atoi(id); err != nil {
+			continue
+		}
+
+		/* The accounts deleted before are not known anymore. */
+		delete(rec, "target_id")
+		if publicID, ok := uuids[id]; ok {
+			rec["target_id"] = publicID
+		}
+	}
+
+	return nil
+}
+
+// passwordHashes replaces the passwords stored in plain text with their bcrypt
+// hashes. The erased accounts have no password, so they are left as they are.
+func passwordHashes(doc map[string]interface{}) error {
+	accounts, _ := doc["accounts"].([]interface{})
+	for _, a := range accounts {
+		acc, ok := a.(map[string]interface{})
+		if !ok {
+			return errors.New("The account is not an object")
+		}
+
+		password, _ := acc["password"].(string)
+		if password == "" || account.IsPasswordHash(password) {
+			continue
+		}
+
+		hash, err := account.HashPassword(password)
+		if err != nil {
+			return errors.Wrapf(err, "Error migrating the password of the account %v", acc["id"])
+		}
+
+		acc["password"] = hash
+	}
+
+	return nil
+}
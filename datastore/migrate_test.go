@@ -0,0 +1,222 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+)
+
+// copyFixture copies the fixture datastore to a temporary directory, so the
+// migrations can write it back, and returns its path.
+func copyFixture(t *testing.T, dir, name string) string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Unexpected error reading the fixture %s: %s", name, err)
+	}
+
+	path := filepath.Join(dir, name)
+	err = ioutil.WriteFile(path, data, 0600)
+	if err != nil {
+		t.Fatalf("Unexpected error copying the fixture %s: %s", name, err)
+	}
+
+	return path
+}
+
+func TestMigrationsOrder(t *testing.T) {
+	for i, m := range migrations {
+		if m.Version != i+1 || m.Name == "" || m.Up == nil {
+			t.Errorf("Expected the migration %d in position %d, Got %+v", m.Version, i, m)
+		}
+	}
+
+	if len(migrations) != SchemaVersion {
+		t.Errorf("Expected the schema version %d to be the last migration, Got %d",
+			SchemaVersion, len(migrations))
+	}
+
+	pending, err := Pending(0)
+	if err != nil || len(pending) != SchemaVersion {
+		t.Errorf("Expected %d pending migrations, Got %d: %v", SchemaVersion, len(pending), err)
+	}
+
+	pending, err = Pending(SchemaVersion)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("Expected no pending migrations, Got %d: %v", len(pending), err)
+	}
+
+	_, err = Pending(SchemaVersion + 1)
+	if errors.Cause(err) != ErrSchemaNewer {
+		t.Errorf("Expected %s, Got %v", ErrSchemaNewer, err)
+	}
+
+	if New().Schema().Version != SchemaVersion {
+		t.Errorf("Expected the new datastores with the schema %d", SchemaVersion)
+	}
+}
+
+func TestMigrateFixtures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := map[string]struct {
+		fixture string
+		pending int
+		err     error
+	}{
+		"Schema 0":      {fixture: "schema-0.json", pending: SchemaVersion},
+		"Schema 1":      {fixture: "schema-1.json", pending: SchemaVersion - 1},
+		"Schema 2":      {fixture: "schema-2.json", pending: SchemaVersion - 2},
+		"Future schema": {fixture: "schema-future.json", err: ErrSchemaNewer},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := copyFixture(t, dir, tc.fixture)
+			before, _ := ioutil.ReadFile(path)
+
+			/* The pending migrations are only applied on purpose. */
+			_, err := Open(path)
+			if tc.err == nil && tc.pending > 0 && errors.Cause(err) != ErrSchemaOutdated {
+				t.Fatalf("Expected %s opening the datastore, Got %v", ErrSchemaOutdated, err)
+			}
+
+			pending, err := Migrate(path, true)
+			if errors.Cause(err) != tc.err || len(pending) != tc.pending {
+				t.Fatalf("Expected %d pending migrations and error %v, Got %d: %v",
+					tc.pending, tc.err, len(pending), err)
+			}
+
+			after, _ := ioutil.ReadFile(path)
+			if !bytes.Equal(before, after) {
+				t.Error("Expected the dry run to leave the datastore untouched")
+			}
+
+			applied, err := Migrate(path, false)
+			if errors.Cause(err) != tc.err || len(applied) != tc.pending {
+				t.Fatalf("Expected %d migrations applied and error %v, Got %d: %v",
+					tc.pending, tc.err, len(applied), err)
+			}
+
+			_, err = Open(path)
+			if errors.Cause(err) != tc.err {
+				t.Fatalf("Expected error %v opening the datastore, Got %v", tc.err, err)
+			} else if err != nil {
+				return
+			}
+
+			applied, err = Migrate(path, false)
+			if err != nil || len(applied) != 0 {
+				t.Errorf("Expected the migrations to be applied once, Got %d: %v", len(applied), err)
+			}
+
+			ds, _ := Open(path)
+			schema := ds.Schema()
+			if schema.Version != SchemaVersion || len(schema.Migrations) != SchemaVersion {
+				t.Errorf("Expected the migrations recorded up to %d, Got %+v", SchemaVersion, schema)
+			}
+		})
+	}
+}
+
+func TestMigrateAccountUUIDs(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := copyFixture(t, dir, "schema-0.json")
+	_, err = Migrate(path, false)
+	if err != nil {
+		t.Fatalf("Unexpected error migrating the datastore: %s", err)
+	}
+
+	ds, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening the datastore: %s", err)
+	}
+
+	if ds.AccountsCount() != 2 || !ds.IsAccountRegisteredByUsername(ctx, "palvarez") {
+		t.Fatalf("Expected the renamed account loaded once, Got %d accounts", ds.AccountsCount())
+	}
+
+	palvarez, _ := ds.GetAccountByID(ctx, 7)
+	jdoe, _ := ds.GetAccountByID(ctx, 9)
+	if palvarez.UUID() == "" || jdoe.UUID() == "" || palvarez.UUID() == jdoe.UUID() {
+		t.Errorf("Expected the accounts to get different UUIDs, Got %s and %s",
+			palvarez.UUID(), jdoe.UUID())
+	}
+
+	records, _ := ds.AuditRecords(ctx, audit.Filter{})
+	expected := map[int]string{1: palvarez.UUID(), 2: palvarez.UUID(), 3: "", 4: jdoe.UUID(), 5: ""}
+	for _, rec := range records {
+		if rec.TargetID != expected[rec.ID] {
+			t.Errorf("Expected the target id %q for the record %d, Got %q", expected[rec.ID],
+				rec.ID, rec.TargetID)
+		}
+	}
+}
+
+func TestMigratePasswordHashes(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "radar")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, fixture := range []string{"schema-1.json", "schema-2.json"} {
+		path := copyFixture(t, dir, fixture)
+		_, err = Migrate(path, false)
+		if err != nil {
+			t.Fatalf("Unexpected error migrating %s: %s", fixture, err)
+		}
+
+		ds, err := Open(path)
+		if err != nil {
+			t.Fatalf("Unexpected error opening %s: %s", fixture, err)
+		}
+
+		acc, err := ds.GetAccountByUsername(ctx, "palvarez")
+		if err != nil {
+			t.Fatalf("Unexpected error getting the account of %s: %s", fixture, err)
+		}
+
+		if !account.IsPasswordHash(acc.Password()) || !acc.CheckPassword("palvarez-secret") {
+			t.Errorf("Expected the password of %s hashed, Got %s", fixture, acc.Password())
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package datastore
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/metrics"
+)
+
+// Accounts required to log in with the two-factor authentication.
+const (
+	TwoFactorOptional = "optional"
+	TwoFactorAdmins   = "admins"
+)
+
+// ErrTwoFactorPolicy raised when the two-factor policy is unknown.
+var ErrTwoFactorPolicy = i18n.NewError(&goi18n.Message{
+	ID:    "PolicyTwoFactorUnknown",
+	Other: "The two-factor policy must be optional or admins",
+})
+
+// Policy represents the security settings chosen by the administrators.
+type Policy struct {
+	TwoFactor string `json:"two_factor,omitempty"`
+}
+
+// RequiresTwoFactor returns true if the account must log in with the
+// two-factor authentication.
+func (p Policy) RequiresTwoFactor(acc *account.Account) bool {
+	return p.TwoFactor == TwoFactorAdmins && acc.IsAdmin()
+}
+
+// Policy returns the security policy stored in the datastore.
+func (d *Datastore) Policy(ctx context.Context) (Policy, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if err := ctx.Err(); err != nil {
+		return Policy{}, err
+	}
+
+	return d.policy, nil
+}
+
+// SetPolicy stores the security policy in the datastore.
+func (d *Datastore) SetPolicy(ctx context.Context, p Policy) error {
+	defer metrics.ObserveDatastore("SetPolicy", time.Now())
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	switch p.TwoFactor {
+	case "":
+		p.TwoFactor = TwoFactorOptional
+	case TwoFactorOptional, TwoFactorAdmins:
+	default:
+		return ErrTwoFactorPolicy
+	}
+
+	d.policy = p
+
+	return d.save()
+}
@@ -32,8 +32,11 @@ type Member interface {
 	Roles() []role.Role
 	CurrentRole() role.Role
 	Technologies() []technology.Technology
+	IsFormer() bool
 
 	SetName(string)
+	SetFormer(bool)
+	Anonymize()
 	Equals(interface{}) bool
 	AddRole(role.Role)
 	AddTechnology(technology.Technology)
@@ -41,6 +44,9 @@ type Member interface {
 	DeleteTechnology(technology.Technology) error
 }
 
+// FormerName is the name shown instead of the name of a former member.
+const FormerName = "Former member"
+
 // New creates a new Member object.
 func New(name string) Member {
 	member := &member.Member{}
@@ -49,3 +55,13 @@ func New(name string) Member {
 
 	return member
 }
+
+// Former returns the placeholder shown instead of a former member.
+func Former() Member {
+	member := &member.Member{}
+
+	member.SetName(FormerName)
+	member.SetFormer(true)
+
+	return member
+}
@@ -28,4 +28,9 @@ func TestMember(t *testing.T) {
 	if member.Name() != "Ritho" {
 		t.Errorf("Error creating a new member, expected Ritho, got %s", member.Name())
 	}
+
+	former := Former()
+	if former.Name() != FormerName || !former.IsFormer() || member.IsFormer() {
+		t.Errorf("Expected only the placeholder to be a former member, got %s", former.Name())
+	}
 }
@@ -33,6 +33,10 @@ type Member struct {
 	name         string
 	roles        []role.Role
 	technologies []technology.Technology
+
+	/* The former members left the organization, they are kept while something
+	references them. */
+	former bool
 }
 
 // Name returns the member name.
@@ -74,6 +78,11 @@ func (m *Member) Technologies() []technology.Technology {
 	return m.technologies
 }
 
+// IsFormer returns true if the member left the organization.
+func (m *Member) IsFormer() bool {
+	return m.former
+}
+
 // SetName sets the member name.
 func (m *Member) SetName(name string) {
 	cleanName := strings.TrimSpace(name)
@@ -82,6 +91,20 @@ func (m *Member) SetName(name string) {
 	}
 }
 
+// SetFormer sets if the member left the organization.
+func (m *Member) SetFormer(former bool) {
+	m.former = former
+}
+
+// Anonymize erases the personal data of the member, its name, roles and
+// technologies, turning it into a former member.
+func (m *Member) Anonymize() {
+	m.name = ""
+	m.roles = nil
+	m.technologies = nil
+	m.former = true
+}
+
 // Equals compares two member objects to check if they're the same one.
 func (m *Member) Equals(member interface{}) bool {
 	switch member.(type) {
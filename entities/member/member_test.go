@@ -50,6 +50,23 @@ func TestMember(t *testing.T) {
 			t.Errorf("Expected %s member to be equals to %s", test.name, m.Name())
 		}
 
+		m.SetFormer(true)
+		if !m.IsFormer() {
+			t.Errorf("Expected %s to be a former member", m.Name())
+		}
+
+		m.SetFormer(false)
+		if m.IsFormer() {
+			t.Errorf("Expected %s to be a current member", m.Name())
+		}
+
+		anonymized := &Member{name: test.name}
+		anonymized.AddTechnology(technology.New("Go", "Language", 4))
+		anonymized.Anonymize()
+		if anonymized.Name() != "" || len(anonymized.Technologies()) != 0 || !anonymized.IsFormer() {
+			t.Errorf("Expected %s to be anonymized, got %s", test.name, anonymized.Name())
+		}
+
 		failMember := &Member{
 			name: test.name + "2",
 		}
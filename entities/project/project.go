@@ -38,9 +38,19 @@ func (p *Project) Name() string {
 	return p.name
 }
 
-// Members return the list of members belonging to this project.
+// Members return the list of members belonging to this project, the former
+// members of the organization are replaced by a placeholder.
 func (p *Project) Members() []member.Member {
-	return p.members
+	members := make([]member.Member, len(p.members))
+	for i, m := range p.members {
+		if m.IsFormer() {
+			m = member.Former()
+		}
+
+		members[i] = m
+	}
+
+	return members
 }
 
 // Technologies return the list of technologies used in this project.
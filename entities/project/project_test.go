@@ -87,6 +87,17 @@ func TestProjectMember(t *testing.T) {
 		if err == nil {
 			t.Errorf("Expected error deleting the member %s", deleteMember.Name())
 		}
+
+		newMember.SetFormer(true)
+		members = p.Members()
+		if len(members) != 1 || members[0].Name() != member.FormerName {
+			t.Errorf("Expected the former member to be replaced, got %s", members[0].Name())
+		}
+
+		newMember.SetFormer(false)
+		if p.Members()[0].Name() != "Ritho" {
+			t.Errorf("Expected Ritho, got %s", p.Members()[0].Name())
+		}
 	}
 }
 
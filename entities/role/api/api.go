@@ -30,6 +30,7 @@ import (
 type Role interface {
 	Title() string
 	Started() time.Time
+	Finished() time.Time
 	Experience() time.Duration
 	IsActive() bool
 	Equals(role interface{}) bool
@@ -41,6 +41,12 @@ func (r *Role) Started() time.Time {
 	return r.started
 }
 
+// Finished returns when the Role stopped being applicable, or the zero time if
+// it's still active.
+func (r *Role) Finished() time.Time {
+	return r.finished
+}
+
 // Experience returns the time the member have been doing this Role.
 func (r *Role) Experience() time.Duration {
 	if !r.finished.IsZero() {
@@ -49,6 +49,10 @@ func TestRole(t *testing.T) {
 			t.Errorf("Expected: %s, Got %s", test.expected.title, role.Title())
 		}
 
+		if !role.Finished().Equal(test.expected.finished) {
+			t.Errorf("Expected: %+v, Got %+v", test.expected.finished, role.Finished())
+		}
+
 		if role.IsActive() != test.active {
 			t.Errorf("Expected active to be %t, got %t", test.active, role.IsActive())
 		}
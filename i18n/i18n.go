@@ -0,0 +1,101 @@
+// Package i18n implements the translation of the messages shown to the users.
+package i18n
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"embed"
+	"encoding/json"
+	"io/fs"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language of the messages in the source code.
+var DefaultLanguage = language.English
+
+// locales contains the message catalogs, one file per language generated
+// with the goi18n tool (see the i18n targets of the Makefile).
+//
+//go:embed locales/active.*.json
+var locales embed.FS
+
+// bundle stores the messages of all the catalogs.
+var bundle = newBundle()
+
+// newBundle loads the message catalogs.
+func newBundle() *goi18n.Bundle {
+	b := goi18n.NewBundle(DefaultLanguage)
+	b.RegisterUnmarshalFunc("json", json.Unmarshal)
+
+	files, err := fs.Glob(locales, "locales/active.*.json")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, file := range files {
+		_, err = b.LoadMessageFileFS(locales, file)
+		if err != nil {
+			panic(errors.Wrap(err, file))
+		}
+	}
+
+	return b
+}
+
+// Languages returns the languages with a message catalog.
+func Languages() []string {
+	tags := bundle.LanguageTags()
+	languages := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		languages = append(languages, tag.String())
+	}
+
+	return languages
+}
+
+// Error is an error identified by a stable message ID, so it can be
+// translated to the language of the user.
+type Error struct {
+	msg  *goi18n.Message
+	data map[string]interface{}
+}
+
+// NewError creates and returns a new translatable error, data fills the
+// placeholders of the message.
+func NewError(msg *goi18n.Message, data ...map[string]interface{}) error {
+	e := &Error{msg: msg}
+	if len(data) > 0 {
+		e.data = data[0]
+	}
+
+	return e
+}
+
+// ID returns the message ID of the error.
+func (e *Error) ID() string {
+	return e.msg.ID
+}
+
+// Error returns the error message in the default language.
+func (e *Error) Error() string {
+	return Default.localize(e.msg, e.data)
+}
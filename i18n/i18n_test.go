@@ -0,0 +1,135 @@
+package i18n
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+)
+
+var msgTest = &goi18n.Message{
+	ID:    "AccountAlreadyRegistered",
+	Other: "User {{.Username}} already registered",
+}
+
+func TestCatalogs(t *testing.T) {
+	catalogs := make(map[string]map[string]string)
+	for _, lang := range []string{"en", "es"} {
+		data, err := locales.ReadFile("locales/active." + lang + ".json")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		catalog := make(map[string]string)
+		err = json.Unmarshal(data, &catalog)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+
+		catalogs[lang] = catalog
+	}
+
+	for id := range catalogs["en"] {
+		if catalogs["es"][id] == "" {
+			t.Errorf("Expected the message %s to be translated to es", id)
+		}
+	}
+
+	languages := Languages()
+	if len(languages) != 2 || languages[0] != "en" {
+		t.Errorf("Expected en and es languages, Got %v", languages)
+	}
+}
+
+func TestNewLocalizer(t *testing.T) {
+	testCases := map[string]string{
+		"":                      "en",
+		"es":                    "es",
+		"es-ES,es;q=0.9,en;q=0": "es",
+		"fr-FR,en;q=0.8":        "en",
+		"fr-FR,es;q=0.8":        "es",
+		"de":                    "en",
+		"invalid;;;":            "en",
+	}
+
+	for header, expected := range testCases {
+		l := NewLocalizer(header)
+		if l.Language() != expected {
+			t.Errorf("Expected %s for %q, Got %s", expected, header, l.Language())
+		}
+	}
+
+	l := NewLocalizer("fr", "es")
+	if l.Language() != "es" {
+		t.Errorf("Expected es, Got %s", l.Language())
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	es := NewLocalizer("es")
+	data := map[string]interface{}{"Username": "ritho"}
+	msg := es.Message(msgTest, data)
+	if msg != "El usuario ritho ya está registrado" {
+		t.Errorf("Expected the message in spanish, Got %s", msg)
+	}
+
+	msg = Default.Message(msgTest, data)
+	if msg != "User ritho already registered" {
+		t.Errorf("Expected the message in english, Got %s", msg)
+	}
+
+	unknown := &goi18n.Message{ID: "UnknownMessage", Other: "Unknown message"}
+	if es.Message(unknown) != "Unknown message" {
+		t.Errorf("Expected the default message, Got %s", es.Message(unknown))
+	}
+
+	err := NewError(msgTest, data)
+	if err.Error() != "User ritho already registered" {
+		t.Errorf("Expected the error in english, Got %s", err)
+	}
+
+	if err.(*Error).ID() != "AccountAlreadyRegistered" {
+		t.Errorf("Expected AccountAlreadyRegistered, Got %s", err.(*Error).ID())
+	}
+
+	wrapped := errors.Wrap(err, "context")
+	if Default.Error(wrapped) != "context: User ritho already registered" {
+		t.Errorf("Expected the error with context, Got %s", Default.Error(wrapped))
+	}
+
+	if es.Error(wrapped) != "El usuario ritho ya está registrado" {
+		t.Errorf("Expected the error in spanish, Got %s", es.Error(wrapped))
+	}
+
+	plain := errors.New("Plain error")
+	if es.Error(plain) != "Plain error" {
+		t.Errorf("Expected the error untranslated, Got %s", es.Error(plain))
+	}
+
+	if es.Translate(msgTest) != es.Message(msgTest) {
+		t.Errorf("Expected the message to be translated, Got %v", es.Translate(msgTest))
+	}
+
+	if es.Translate(plain) != "Plain error" || es.Translate(1) != 1 {
+		t.Error("Expected the values to be translated only if messages or errors")
+	}
+}
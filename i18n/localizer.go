@@ -0,0 +1,138 @@
+package i18n
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+)
+
+// Default is the localizer for the default language.
+var Default = NewLocalizer()
+
+// Localizer translates the messages to the language that best matches the
+// preferences of the user.
+type Localizer struct {
+	tag       language.Tag
+	localizer *goi18n.Localizer
+}
+
+// NewLocalizer creates and returns a new Localizer for the preferred languages,
+// in order of preference. The languages can also be values of the
+// Accept-Language header.
+func NewLocalizer(languages ...string) *Localizer {
+	preferred := make([]language.Tag, 0, len(languages))
+	for _, lang := range languages {
+		tags, _, err := language.ParseAcceptLanguage(lang)
+		if err == nil {
+			preferred = append(preferred, tags...)
+		}
+	}
+
+	tag := DefaultLanguage
+	if len(preferred) > 0 {
+		matcher := language.NewMatcher(bundle.LanguageTags())
+		_, index, confidence := matcher.Match(preferred...)
+		if confidence != language.No {
+			tag = bundle.LanguageTags()[index]
+		}
+	}
+
+	return &Localizer{
+		tag:       tag,
+		localizer: goi18n.NewLocalizer(bundle, tag.String()),
+	}
+}
+
+// localizerKey is the key of the localizer stored in a context.
+type localizerKey struct{}
+
+// WithLocalizer returns a copy of ctx carrying the localizer of the request.
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// ContextLocalizer returns the localizer carried by ctx, or the localizer for
+// the default language if there is none.
+func ContextLocalizer(ctx context.Context) *Localizer {
+	l, ok := ctx.Value(localizerKey{}).(*Localizer)
+	if !ok || l == nil {
+		return Default
+	}
+
+	return l
+}
+
+// Language returns the language used by the localizer.
+func (l *Localizer) Language() string {
+	return l.tag.String()
+}
+
+// Message returns the message translated, data fills the placeholders of the
+// message.
+func (l *Localizer) Message(msg *goi18n.Message, data ...map[string]interface{}) string {
+	if len(data) > 0 {
+		return l.localize(msg, data[0])
+	}
+
+	return l.localize(msg, nil)
+}
+
+// Error returns the error message translated. The context added wrapping the
+// error is only kept in the default language, the errors not created with
+// NewError are returned untranslated.
+func (l *Localizer) Error(err error) string {
+	e, ok := errors.Cause(err).(*Error)
+	if !ok || l.tag == DefaultLanguage {
+		return err.Error()
+	}
+
+	return l.localize(e.msg, e.data)
+}
+
+// Translate returns v translated if it's a message or an error, or v
+// otherwise.
+func (l *Localizer) Translate(v interface{}) interface{} {
+	switch value := v.(type) {
+	case *goi18n.Message:
+		return l.Message(value)
+	case error:
+		return l.Error(value)
+	}
+
+	return v
+}
+
+// localize returns the message translated with the placeholders filled with
+// data, falling back to the message in the default language.
+func (l *Localizer) localize(msg *goi18n.Message, data map[string]interface{}) string {
+	text, err := l.localizer.Localize(&goi18n.LocalizeConfig{
+		DefaultMessage: msg,
+		TemplateData:   data,
+	})
+	if err != nil && text == "" {
+		return strings.TrimSpace(msg.Other)
+	}
+
+	return text
+}
@@ -0,0 +1,234 @@
+package ldap
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/i18n"
+)
+
+// ErrNotConfigured raised when the directory is used without being configured.
+var ErrNotConfigured = i18n.NewError(&goi18n.Message{
+	ID:    "LDAPNotConfigured",
+	Other: "The directory is not configured",
+})
+
+// ErrUnavailable raised when the directory can't be reached or refuses the
+// radar credentials.
+var ErrUnavailable = i18n.NewError(&goi18n.Message{
+	ID:    "LDAPUnavailable",
+	Other: "The directory is not available, try again later",
+})
+
+// ErrUserNotFound raised when the user is not in the directory, or the
+// password is wrong.
+var ErrUserNotFound = errors.New("User not found in the directory")
+
+// DefaultUserFilter is the filter of the directory entries of the users when
+// none is configured.
+const DefaultUserFilter = "(objectClass=person)"
+
+// CheckFilter returns an error if the search filter, in its string form (RFC
+// 4515), is not valid.
+func CheckFilter(filter string) error {
+	_, err := goldap.CompileFilter(filter)
+	return errors.Wrap(err, filter)
+}
+
+// User represents a user read from the directory.
+type User struct {
+	DN       string
+	Username string
+	Name     string
+	Email    string
+	Title    string
+}
+
+// Directory represents the LDAP directory the users are read from and
+// authenticated against. The radar binds with its own account to find the
+// users, and then with the user DN to check the passwords.
+type Directory struct {
+	URL          string
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	UserFilter   string
+	TLSConfig    *tls.Config
+	Timeout      time.Duration
+
+	/* Attributes of the users data. */
+	UsernameAttr string
+	NameAttr     string
+	EmailAttr    string
+	TitleAttr    string
+}
+
+// New creates and returns a new Directory with the usual attributes of the
+// inetOrgPerson entries.
+func New(url, bindDN, bindPassword, baseDN string) *Directory {
+	return &Directory{
+		URL:          url,
+		BindDN:       bindDN,
+		BindPassword: bindPassword,
+		BaseDN:       baseDN,
+		UserFilter:   DefaultUserFilter,
+		Timeout:      10 * time.Second,
+		UsernameAttr: "uid",
+		NameAttr:     "cn",
+		EmailAttr:    "mail",
+		TitleAttr:    "title",
+	}
+}
+
+// Configured returns true if there is a directory to read the users from.
+func (d *Directory) Configured() bool {
+	return d != nil && d.URL != "" && d.BaseDN != ""
+}
+
+// Authenticate returns the user of the directory if the password is right.
+func (d *Directory) Authenticate(ctx context.Context, username, password string) (*User, error) {
+	conn, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	filter := "(&" + d.UserFilter + "(" + d.UsernameAttr + "=" + goldap.EscapeFilter(username) + "))"
+	entries, err := d.search(conn, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) != 1 {
+		return nil, ErrUserNotFound
+	}
+
+	/* The client refuses the empty passwords, as the servers take them as an
+	anonymous bind. */
+	err = conn.Bind(entries[0].DN, password)
+	if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) ||
+		goldap.IsErrorWithCode(err, goldap.ErrorEmptyPassword) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		return nil, errors.Wrap(ErrUnavailable, err.Error())
+	}
+
+	return d.user(entries[0]), nil
+}
+
+// Users returns all the users of the directory.
+func (d *Directory) Users(ctx context.Context) ([]*User, error) {
+	conn, err := d.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entries, err := d.search(conn, d.UserFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*User, 0, len(entries))
+	for _, e := range entries {
+		if u := d.user(e); u.Username != "" {
+			users = append(users, u)
+		}
+	}
+
+	return users, nil
+}
+
+// connect opens a connection to the directory bound with the radar account, if
+// any. The timeout, or the deadline of the context if it's sooner, applies to
+// the connection and to every operation.
+func (d *Directory) connect(ctx context.Context) (*goldap.Conn, error) {
+	if d.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.Timeout)
+		defer cancel()
+	}
+
+	dialer := &net.Dialer{}
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		dialer.Deadline = deadline
+	}
+
+	conn, err := goldap.DialURL(d.URL, goldap.DialWithDialer(dialer), goldap.DialWithTLSConfig(d.TLSConfig))
+	if err != nil {
+		return nil, errors.Wrap(ErrUnavailable, err.Error())
+	}
+
+	if hasDeadline {
+		conn.SetTimeout(time.Until(deadline))
+	}
+
+	/* Without a radar account the searches are done anonymously. */
+	if d.BindDN == "" {
+		return conn, nil
+	}
+
+	err = conn.Bind(d.BindDN, d.BindPassword)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(ErrUnavailable, err.Error())
+	}
+
+	return conn, nil
+}
+
+// search returns the entries under the base DN matching the filter, with the
+// attributes of the users.
+func (d *Directory) search(conn *goldap.Conn, filter string) ([]*goldap.Entry, error) {
+	req := goldap.NewSearchRequest(d.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		0, 0, false, filter, d.attributes(), nil)
+	res, err := conn.Search(req)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnavailable, err.Error())
+	}
+
+	return res.Entries, nil
+}
+
+// attributes returns the attributes of the users read from the directory.
+func (d *Directory) attributes() []string {
+	return []string{d.UsernameAttr, d.NameAttr, d.EmailAttr, d.TitleAttr}
+}
+
+// user returns the user of a directory entry. The names of the attributes are
+// compared ignoring the case, as the servers return them as they are defined.
+func (d *Directory) user(e *goldap.Entry) *User {
+	return &User{
+		DN:       e.DN,
+		Username: strings.TrimSpace(e.GetEqualFoldAttributeValue(d.UsernameAttr)),
+		Name:     strings.TrimSpace(e.GetEqualFoldAttributeValue(d.NameAttr)),
+		Email:    strings.TrimSpace(e.GetEqualFoldAttributeValue(d.EmailAttr)),
+		Title:    strings.TrimSpace(e.GetEqualFoldAttributeValue(d.TitleAttr)),
+	}
+}
@@ -0,0 +1,48 @@
+package ldap
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import "testing"
+
+func TestCheckFilter(t *testing.T) {
+	for _, filter := range []string{"(uid=ritho)", `(cn=a\2ab\29)`, "(mail=*)", "(!(uid=ritho))",
+		"(&(objectClass=person)(|(uid=a)(uid=b)))", "(cn=ab*cd*ef)"} {
+		if err := CheckFilter(filter); err != nil {
+			t.Errorf("Unexpected error checking %s: %s", filter, err)
+		}
+	}
+
+	for _, wrong := range []string{"", "uid=ritho", "(uid=ritho", "(uid)", `(uid=\2)`, "(&(uid=a)"} {
+		if err := CheckFilter(wrong); err == nil {
+			t.Errorf("Expected error checking %q", wrong)
+		}
+	}
+}
+
+func TestConfigured(t *testing.T) {
+	var d *Directory
+	if d.Configured() {
+		t.Error("Expected a nil directory not configured")
+	}
+
+	d = New("ldap://ldap.ritho.net", "", "", "ou=people,dc=ritho,dc=net")
+	if !d.Configured() || d.UserFilter != DefaultUserFilter {
+		t.Errorf("Expected the directory configured, Got %+v", d)
+	}
+}
@@ -0,0 +1,399 @@
+// Package ldaptest implements an in-process LDAP server to test the directory
+// authentication and synchronization.
+package ldaptest
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+
+	"github.com/radar-go/radar/ldap"
+)
+
+// Entries of the directory created by NewDirectory.
+const (
+	BaseDN       = "ou=people,dc=radar,dc=test"
+	BindDN       = "cn=radar,dc=radar,dc=test"
+	BindPassword = "radar-secret"
+)
+
+// Server represents the test LDAP server. The entries are kept in memory and
+// the binds are checked against their userPassword attribute.
+type Server struct {
+	URL string
+
+	ln      net.Listener
+	mu      sync.Mutex
+	entries map[string]map[string][]string
+	conns   map[net.Conn]bool
+	wg      sync.WaitGroup
+}
+
+// New creates and starts a new test LDAP server listening on a random local
+// port.
+func New() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{
+		URL:     "ldap://" + ln.Addr().String(),
+		ln:      ln,
+		entries: make(map[string]map[string][]string),
+		conns:   make(map[net.Conn]bool),
+	}
+
+	go s.serve()
+
+	return s
+}
+
+// NewDirectory creates and starts a new test LDAP server with the account of
+// the radar, to add the users under BaseDN.
+func NewDirectory() *Server {
+	s := New()
+	s.Add(BindDN, map[string]string{
+		"objectClass":  "applicationProcess",
+		"cn":           "radar",
+		"userPassword": BindPassword,
+	})
+
+	return s
+}
+
+// Directory returns the directory to access the server created by
+// NewDirectory.
+func (s *Server) Directory() *ldap.Directory {
+	return ldap.New(s.URL, BindDN, BindPassword, BaseDN)
+}
+
+// AddUser adds or replaces the entry of a person under BaseDN.
+func (s *Server) AddUser(uid, name, email, title, password string) {
+	attrs := map[string]string{
+		"objectClass":  "person",
+		"uid":          uid,
+		"cn":           name,
+		"mail":         email,
+		"userPassword": password,
+	}
+	if title != "" {
+		attrs["title"] = title
+	}
+
+	s.Add("uid="+uid+","+BaseDN, attrs)
+}
+
+// RemoveUser removes the entry of a person under BaseDN.
+func (s *Server) RemoveUser(uid string) {
+	s.Remove("uid=" + uid + "," + BaseDN)
+}
+
+// Close stops the server, closing the connections still open.
+func (s *Server) Close() {
+	s.ln.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Add adds or replaces an entry of the directory. The attributes have a
+// single value each.
+func (s *Server) Add(dn string, attrs map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := make(map[string][]string)
+	for name, value := range attrs {
+		entry[strings.ToLower(name)] = []string{value}
+	}
+
+	entry["dn"] = []string{dn}
+	s.entries[normalize(dn)] = entry
+}
+
+// Remove removes an entry of the directory.
+func (s *Server) Remove(dn string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, normalize(dn))
+}
+
+// serve accepts the connections until the server is closed.
+func (s *Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// handle answers the requests of a connection until it's unbound.
+func (s *Server) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	bound := false
+	for {
+		msg, err := ber.ReadPacket(r)
+		if err != nil || msg.Tag != ber.TagSequence || len(msg.Children) < 2 {
+			return
+		}
+
+		id, _ := msg.Children[0].Value.(int64)
+		op := msg.Children[1]
+		var responses []*ber.Packet
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			var code uint16
+			code, bound = s.bind(op.Children[1].Data.String(), op.Children[2])
+			responses = append(responses, result(goldap.ApplicationBindResponse, code))
+		case goldap.ApplicationSearchRequest:
+			if !bound {
+				responses = append(responses, result(goldap.ApplicationSearchResultDone,
+					goldap.LDAPResultInsufficientAccessRights))
+				break
+			}
+
+			responses = s.search(op)
+		case goldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = append(responses, result(op.Tag+1, goldap.LDAPResultUnwillingToPerform))
+		}
+
+		for _, res := range responses {
+			packet := ber.NewSequence("LDAP Response")
+			packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id,
+				"MessageID"))
+			packet.AppendChild(res)
+			if _, err = conn.Write(packet.Bytes()); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// bind returns the result of a simple bind, and if the connection is bound.
+func (s *Server) bind(dn string, auth *ber.Packet) (uint16, bool) {
+	if auth.ClassType != ber.ClassContext || auth.Tag != 0 {
+		return goldap.LDAPResultUnwillingToPerform, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	password := auth.Data.String()
+	entry, ok := s.entries[normalize(dn)]
+	if !ok || password == "" || len(entry["userpassword"]) == 0 ||
+		entry["userpassword"][0] != password {
+		return goldap.LDAPResultInvalidCredentials, false
+	}
+
+	return goldap.LDAPResultSuccess, true
+}
+
+// search returns the entries matching the search request, and its result.
+func (s *Server) search(op *ber.Packet) []*ber.Packet {
+	base := normalize(op.Children[0].Data.String())
+	scope, _ := op.Children[1].Value.(int64)
+	filter := op.Children[6]
+	var attrs []string
+	for _, attr := range op.Children[7].Children {
+		attrs = append(attrs, strings.ToLower(attr.Data.String()))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var responses []*ber.Packet
+	for dn, entry := range s.entries {
+		if !inScope(dn, base, int(scope)) || !match(filter, entry) {
+			continue
+		}
+
+		attributes := ber.NewSequence("Attributes")
+		for name, values := range entry {
+			if name == "dn" || name == "userpassword" || (len(attrs) > 0 && !contains(attrs, name)) {
+				continue
+			}
+
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(octetString(value))
+			}
+
+			attribute := ber.NewSequence("Attribute")
+			attribute.AppendChild(octetString(name))
+			attribute.AppendChild(set)
+			attributes.AppendChild(attribute)
+		}
+
+		res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry,
+			nil, "Search Result Entry")
+		res.AppendChild(octetString(entry["dn"][0]))
+		res.AppendChild(attributes)
+		responses = append(responses, res)
+	}
+
+	return append(responses, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess))
+}
+
+// result returns a LDAP result with the code.
+func result(tag ber.Tag, code uint16) *ber.Packet {
+	res := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	res.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code),
+		"Code"))
+	res.AppendChild(octetString(""))
+	res.AppendChild(octetString(""))
+
+	return res
+}
+
+// octetString returns the packet of an octet string.
+func octetString(value string) *ber.Packet {
+	return ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "")
+}
+
+// inScope returns true if the dn is in the scope of the search base.
+func inScope(dn, base string, scope int) bool {
+	switch scope {
+	case goldap.ScopeBaseObject:
+		return dn == base
+	case goldap.ScopeSingleLevel:
+		i := strings.Index(dn, ",")
+		return i >= 0 && dn[i+1:] == base
+	default:
+		return dn == base || strings.HasSuffix(dn, ","+base)
+	}
+}
+
+// match returns true if the entry matches the filter. The values are compared
+// ignoring the case.
+func match(filter *ber.Packet, entry map[string][]string) bool {
+	switch filter.Tag {
+	case goldap.FilterAnd:
+		for _, f := range filter.Children {
+			if !match(f, entry) {
+				return false
+			}
+		}
+
+		return true
+	case goldap.FilterOr:
+		for _, f := range filter.Children {
+			if match(f, entry) {
+				return true
+			}
+		}
+
+		return false
+	case goldap.FilterNot:
+		return len(filter.Children) == 1 && !match(filter.Children[0], entry)
+	case goldap.FilterPresent:
+		return len(entry[strings.ToLower(filter.Data.String())]) > 0
+	case goldap.FilterEqualityMatch:
+		for _, value := range entry[strings.ToLower(filter.Children[0].Data.String())] {
+			if strings.EqualFold(value, filter.Children[1].Data.String()) {
+				return true
+			}
+		}
+	case goldap.FilterSubstrings:
+		for _, value := range entry[strings.ToLower(filter.Children[0].Data.String())] {
+			if matchSubstrings(strings.ToLower(value), filter.Children[1].Children) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// matchSubstrings returns true if the value matches the substrings filter.
+func matchSubstrings(value string, parts []*ber.Packet) bool {
+	for _, part := range parts {
+		sub := strings.ToLower(part.Data.String())
+		switch part.Tag {
+		case goldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, sub) {
+				return false
+			}
+
+			value = value[len(sub):]
+		case goldap.FilterSubstringsFinal:
+			return strings.HasSuffix(value, sub)
+		default:
+			i := strings.Index(value, sub)
+			if i < 0 {
+				return false
+			}
+
+			value = value[i+len(sub):]
+		}
+	}
+
+	return true
+}
+
+// contains returns true if the list contains the value.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalize returns the dn in the form used to compare them.
+func normalize(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(part))
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,120 @@
+package ldaptest
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/ldap"
+)
+
+// newDirectory returns a test server with two users, and the directory to
+// access it.
+func newDirectory() (*Server, *ldap.Directory) {
+	s := NewDirectory()
+	s.AddUser("ritho", "Pablo Álvarez", "palvarez@ritho.net", "Developer", "ritho-secret")
+	s.AddUser("jdoe", "John Doe", "jdoe@ritho.net", "", "jdoe-secret")
+
+	return s, s.Directory()
+}
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	s, d := newDirectory()
+	defer s.Close()
+
+	user, err := d.Authenticate(ctx, "Ritho", "ritho-secret")
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	expected := ldap.User{
+		DN:       "uid=ritho," + BaseDN,
+		Username: "ritho",
+		Name:     "Pablo Álvarez",
+		Email:    "palvarez@ritho.net",
+		Title:    "Developer",
+	}
+	if *user != expected {
+		t.Errorf("Expected %+v, Got %+v", expected, user)
+	}
+
+	testCases := map[string]string{
+		"ritho":  "wrong",
+		"jdoe":   "",
+		"nobody": "ritho-secret",
+		"*":      "ritho-secret",
+		"radar":  "radar-secret",
+	}
+	for username, password := range testCases {
+		_, err = d.Authenticate(ctx, username, password)
+		if err != ldap.ErrUserNotFound {
+			t.Errorf("Expected %s for %s, Got %v", ldap.ErrUserNotFound, username, err)
+		}
+	}
+}
+
+func TestUsers(t *testing.T) {
+	s, d := newDirectory()
+	defer s.Close()
+
+	users, err := d.Users(context.Background())
+	if err != nil || len(users) != 2 {
+		t.Fatalf("Expected 2 users, Got %+v: %v", users, err)
+	}
+
+	d.UserFilter = "(&(objectClass=person)(!(uid=jdoe))(|(cn=*lvarez)(cn=pablo*)))"
+	users, err = d.Users(context.Background())
+	if err != nil || len(users) != 1 || users[0].Username != "ritho" {
+		t.Errorf("Expected only ritho, Got %+v: %v", users, err)
+	}
+
+	d.UserFilter = ldap.DefaultUserFilter
+	s.RemoveUser("jdoe")
+	users, err = d.Users(context.Background())
+	if err != nil || len(users) != 1 || users[0].Username != "ritho" {
+		t.Errorf("Expected only ritho, Got %+v: %v", users, err)
+	}
+}
+
+func TestUnavailable(t *testing.T) {
+	s, d := newDirectory()
+
+	d.BindPassword = "wrong"
+	_, err := d.Users(context.Background())
+	if errors.Cause(err) != ldap.ErrUnavailable {
+		t.Errorf("Expected %s, Got %v", ldap.ErrUnavailable, err)
+	}
+
+	d.BindDN = ""
+	_, err = d.Users(context.Background())
+	if errors.Cause(err) != ldap.ErrUnavailable {
+		t.Errorf("Expected the anonymous search refused, Got %v", err)
+	}
+
+	s.Close()
+	d.BindPassword = "radar-secret"
+	_, err = d.Authenticate(context.Background(), "ritho", "ritho-secret")
+	if errors.Cause(err) != ldap.ErrUnavailable {
+		t.Errorf("Expected %s, Got %v", ldap.ErrUnavailable, err)
+	}
+}
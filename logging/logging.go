@@ -0,0 +1,167 @@
+// Package logging implements the structured logs of Radar.
+package logging
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// RequestIDHeader is the header used to propagate the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+// Keys of the request values stored in the fasthttp context.
+const (
+	requestIDKey = "radar_request_id"
+	userKey      = "radar_user"
+)
+
+// contextKey is the type of the keys of the values stored in a context.
+type contextKey int
+
+// Keys of the request values stored in a context.
+const (
+	requestIDContextKey contextKey = iota
+	userContextKey
+	clientIPContextKey
+)
+
+// maxRequestIDLength is the maximum length of the request IDs received.
+const maxRequestIDLength = 128
+
+// Logger writes the structured logs in json format, by default to the
+// standard error.
+var Logger = slog.New(slog.NewJSONHandler(os.Stderr, nil))
+
+// SetOutput changes the writer of the logs.
+func SetOutput(w io.Writer) {
+	Logger = slog.New(slog.NewJSONHandler(w, nil))
+}
+
+// NewRequestID returns a new random request ID.
+func NewRequestID() string {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "00000000000000000000000000000000"
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// validRequestID returns true if id can be used as request ID: not empty, not
+// too long and with only printable ascii characters.
+func validRequestID(id []byte) bool {
+	if len(id) == 0 || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RequestID returns the ID of the request.
+func RequestID(ctx *fasthttp.RequestCtx) string {
+	id, _ := ctx.UserValue(requestIDKey).(string)
+	return id
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// ContextRequestID returns the request ID carried by ctx, if any.
+func ContextRequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// WithUser returns a copy of ctx carrying the user doing the request.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// ContextUser returns the user doing the request carried by ctx, if any.
+func ContextUser(ctx context.Context) string {
+	user, _ := ctx.Value(userContextKey).(string)
+	return user
+}
+
+// WithClientIP returns a copy of ctx carrying the IP address of the client
+// doing the request.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPContextKey, ip)
+}
+
+// ContextClientIP returns the IP address of the client carried by ctx, if any.
+func ContextClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPContextKey).(string)
+	return ip
+}
+
+// SetUser sets the user doing the request to be included in the access log.
+func SetUser(ctx *fasthttp.RequestCtx, user string) {
+	ctx.SetUserValue(userKey, user)
+}
+
+// Handler returns h logging every request in the access log. The request ID is
+// taken from the X-Request-ID header or generated if it's not present, and
+// returned in the response.
+func Handler(server string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		start := time.Now()
+		id := ctx.Request.Header.Peek(RequestIDHeader)
+		requestID := string(id)
+		if !validRequestID(id) {
+			requestID = NewRequestID()
+		}
+
+		ctx.SetUserValue(requestIDKey, requestID)
+		ctx.Response.Header.Set(RequestIDHeader, requestID)
+
+		h(ctx)
+
+		user, _ := ctx.UserValue(userKey).(string)
+		Logger.Info("request",
+			"server", server,
+			"request_id", requestID,
+			"method", string(ctx.Method()),
+			"path", string(ctx.Path()),
+			"status", ctx.Response.StatusCode(),
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"bytes", len(ctx.Response.Body()),
+			"user", user,
+			"remote_ip", ctx.RemoteIP().String(),
+		)
+	}
+}
@@ -0,0 +1,107 @@
+package logging
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+)
+
+func TestHandler(t *testing.T) {
+	out := &bytes.Buffer{}
+	SetOutput(out)
+
+	var requestID string
+	h := Handler("test", func(ctx *fasthttp.RequestCtx) {
+		requestID = RequestID(ctx)
+		SetUser(ctx, "ritho")
+		ctx.SetStatusCode(fasthttp.StatusCreated)
+		ctx.SetBodyString("created")
+	})
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("POST")
+	ctx.Request.SetRequestURI("/account/login")
+	ctx.Request.Header.Set(RequestIDHeader, "trace-1234")
+	h(ctx)
+
+	if requestID != "trace-1234" {
+		t.Errorf("Expected trace-1234, Got %s", requestID)
+	}
+
+	if id := string(ctx.Response.Header.Peek(RequestIDHeader)); id != "trace-1234" {
+		t.Errorf("Expected trace-1234 in the response, Got %s", id)
+	}
+
+	entry := make(map[string]interface{})
+	err := json.Unmarshal(out.Bytes(), &entry)
+	if err != nil {
+		t.Fatalf("Unexpected error parsing %s: %s", out, err)
+	}
+
+	expected := map[string]interface{}{
+		"msg":        "request",
+		"server":     "test",
+		"request_id": "trace-1234",
+		"method":     "POST",
+		"path":       "/account/login",
+		"status":     float64(201),
+		"bytes":      float64(7),
+		"user":       "ritho",
+	}
+	for key, value := range expected {
+		if entry[key] != value {
+			t.Errorf("Expected %v for %s, Got %v", value, key, entry[key])
+		}
+	}
+
+	if _, ok := entry["duration_ms"]; !ok {
+		t.Errorf("Expected the duration in %s", out)
+	}
+}
+
+func TestHandlerGeneratedID(t *testing.T) {
+	SetOutput(&bytes.Buffer{})
+	h := Handler("test", func(ctx *fasthttp.RequestCtx) {})
+
+	testCases := []string{"", "invalid id", strings.Repeat("a", maxRequestIDLength+1)}
+	for _, header := range testCases {
+		ctx := &fasthttp.RequestCtx{}
+		if header != "" {
+			ctx.Request.Header.Set(RequestIDHeader, header)
+		}
+
+		h(ctx)
+		id := string(ctx.Response.Header.Peek(RequestIDHeader))
+		if id == header || len(id) != 32 || RequestID(ctx) != id {
+			t.Errorf("Expected a generated request id for %q, Got %s", header, id)
+		}
+	}
+}
+
+func TestNewRequestID(t *testing.T) {
+	id := NewRequestID()
+	if len(id) != 32 || id == NewRequestID() {
+		t.Errorf("Expected an unique request id, Got %s", id)
+	}
+}
@@ -0,0 +1,139 @@
+// Package metrics implements the Prometheus metrics exposed by Radar.
+package metrics
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// Registry contains all the Radar metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "radar",
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by server, route, method and status.",
+	}, []string{"server", "route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "radar",
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the HTTP requests by server, route, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"server", "route", "method", "status"})
+
+	useCaseDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "radar",
+		Subsystem: "usecase",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of the use cases runs by use case.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"usecase"})
+
+	useCaseErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "radar",
+		Subsystem: "usecase",
+		Name:      "errors_total",
+		Help:      "Number of use cases runs that returned an error by use case.",
+	}, []string{"usecase"})
+
+	datastoreDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "radar",
+		Subsystem: "datastore",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of the datastore operations by operation.",
+		Buckets:   []float64{.00001, .0001, .001, .01, .1, 1},
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		useCaseDuration,
+		useCaseErrors,
+		datastoreDuration,
+	)
+}
+
+// Handler returns h instrumented to count the requests and measure their
+// latency. The server identifies the server handling the request and the route
+// the path pattern handled, to not create a serie per path.
+func Handler(server, route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		start := time.Now()
+		h(ctx)
+
+		labels := prometheus.Labels{
+			"server": server,
+			"route":  route,
+			"method": string(ctx.Method()),
+			"status": strconv.Itoa(ctx.Response.StatusCode()),
+		}
+		httpRequests.With(labels).Inc()
+		httpDuration.With(labels).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveUseCase records the duration of a use case run started at start and
+// if it failed.
+func ObserveUseCase(name string, start time.Time, err error) {
+	useCaseDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		useCaseErrors.WithLabelValues(name).Inc()
+	}
+}
+
+// ObserveDatastore records the duration of a datastore operation started at
+// start, it's meant to be deferred at the beginning of the operation.
+func ObserveDatastore(operation string, start time.Time) {
+	datastoreDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
+
+// RegisterGauge registers a gauge with the value returned by value every time
+// the metrics are collected. Registering twice the same gauge does nothing.
+func RegisterGauge(name, help string, value func() float64) {
+	err := Registry.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "radar",
+		Name:      name,
+		Help:      help,
+	}, value))
+	if _, ok := err.(prometheus.AlreadyRegisteredError); err != nil && !ok {
+		panic(err)
+	}
+}
+
+// HTTPHandler returns the handler that exposes the metrics in the Prometheus
+// format.
+func HTTPHandler() fasthttp.RequestHandler {
+	return fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,78 @@
+package metrics
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/valyala/fasthttp"
+)
+
+func TestHandler(t *testing.T) {
+	h := Handler("test", "/route/:id", func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+	})
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("GET")
+	h(ctx)
+	h(ctx)
+
+	count := testutil.ToFloat64(httpRequests.WithLabelValues("test", "/route/:id", "GET", "404"))
+	if count != 2 {
+		t.Errorf("Expected 2 requests, Got %f", count)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	ObserveUseCase("TestUseCase", time.Now(), nil)
+	ObserveUseCase("TestUseCase", time.Now(), errors.New("Use case error"))
+	if testutil.ToFloat64(useCaseErrors.WithLabelValues("TestUseCase")) != 1 {
+		t.Error("Expected one use case error")
+	}
+
+	ObserveDatastore("TestOperation", time.Now())
+	RegisterGauge("test_gauge", "Test gauge.", func() float64 { return 42 })
+	RegisterGauge("test_gauge", "Test gauge.", func() float64 { return 42 })
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod("GET")
+	ctx.Request.SetRequestURI("/metrics")
+	HTTPHandler()(ctx)
+	if ctx.Response.StatusCode() != 200 {
+		t.Errorf("Expected 200, Got %d", ctx.Response.StatusCode())
+	}
+
+	body := string(ctx.Response.Body())
+	for _, expected := range []string{
+		`radar_usecase_run_duration_seconds_count{usecase="TestUseCase"} 2`,
+		`radar_usecase_errors_total{usecase="TestUseCase"} 1`,
+		`radar_datastore_operation_duration_seconds_count{operation="TestOperation"} 1`,
+		"radar_test_gauge 42",
+		"go_goroutines",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected %s in %s", expected, body)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package oidc
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Roles of the radar the groups of the provider map to.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
+// ParseGroups returns the mapping of the groups of the provider to the radar
+// roles of a comma separated list of group=role.
+func ParseGroups(s string) (map[string]string, error) {
+	groups := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		i := strings.LastIndex(item, "=")
+		if i <= 0 {
+			return nil, errors.Errorf("Wrong group mapping %q, expected group=role", item)
+		}
+
+		group, role := strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		if role != RoleAdmin && role != RoleMember {
+			return nil, errors.Errorf("Wrong role %q of the group %s, expected %s or %s",
+				role, group, RoleAdmin, RoleMember)
+		}
+
+		groups[group] = role
+	}
+
+	return groups, nil
+}
+
+// Role returns the radar role of the user by its groups, and false if the
+// groups are mapped and none of them is allowed in. Without groups mapped the
+// users are members.
+func (p *Provider) Role(claims *Claims) (string, bool) {
+	if len(p.Groups) == 0 {
+		return RoleMember, true
+	}
+
+	role := ""
+	for _, group := range claims.Groups {
+		switch p.Groups[group] {
+		case RoleAdmin:
+			return RoleAdmin, true
+		case RoleMember:
+			role = RoleMember
+		}
+	}
+
+	return role, role != ""
+}
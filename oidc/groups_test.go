@@ -0,0 +1,69 @@
+package oidc
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import "testing"
+
+func TestParseGroups(t *testing.T) {
+	groups, err := ParseGroups(" radar-admins=admin, engineering=member,,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if len(groups) != 2 || groups["radar-admins"] != RoleAdmin ||
+		groups["engineering"] != RoleMember {
+		t.Errorf("Unexpected groups %v", groups)
+	}
+
+	for _, wrong := range []string{"radar-admins", "=admin", "radar-admins=root"} {
+		if _, err = ParseGroups(wrong); err == nil {
+			t.Errorf("Expected error parsing %q", wrong)
+		}
+	}
+}
+
+func TestRole(t *testing.T) {
+	p := New("https://sso.ritho.net", "radar", "", "")
+	testCases := []struct {
+		groups  []string
+		mapping map[string]string
+		role    string
+		allowed bool
+	}{
+		{nil, nil, RoleMember, true},
+		{[]string{"engineering"}, nil, RoleMember, true},
+		{nil, map[string]string{"engineering": RoleMember}, "", false},
+		{[]string{"sales"}, map[string]string{"engineering": RoleMember}, "", false},
+		{[]string{"engineering"}, map[string]string{"engineering": RoleMember}, RoleMember, true},
+		{
+			[]string{"engineering", "radar-admins"},
+			map[string]string{"engineering": RoleMember, "radar-admins": RoleAdmin},
+			RoleAdmin, true,
+		},
+	}
+
+	for _, tc := range testCases {
+		p.Groups = tc.mapping
+		role, allowed := p.Role(&Claims{Groups: tc.groups})
+		if role != tc.role || allowed != tc.allowed {
+			t.Errorf("Expected %q %t for %v with %v, got %q %t", tc.role, tc.allowed,
+				tc.groups, tc.mapping, role, allowed)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+// Package oidc implements the logins through an OpenID Connect identity
+// provider, with the authorization code flow protected with PKCE.
+package oidc
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/i18n"
+)
+
+// ErrNotConfigured raised when the single sign-on is used without an identity
+// provider configured.
+var ErrNotConfigured = i18n.NewError(&goi18n.Message{
+	ID:    "OIDCNotConfigured",
+	Other: "Single sign-on is not configured",
+})
+
+// ErrInvalidToken raised when the identity token given by the provider is not
+// valid, is not for the radar or has expired.
+var ErrInvalidToken = i18n.NewError(&goi18n.Message{
+	ID:    "OIDCInvalidToken",
+	Other: "The identity provider answer is not valid",
+})
+
+// DefaultScopes are the scopes asked to the provider when none is configured.
+const DefaultScopes = "openid email profile"
+
+// leeway is the clock difference allowed with the provider checking the
+// expiration of the tokens.
+const leeway = time.Minute
+
+// keysInterval is the shortest time between two fetches of the keys of the
+// provider, so the tokens with unknown keys can't make it fetch them on every
+// login.
+const keysInterval = time.Minute
+
+// minKeyBits is the size of the smallest RSA key accepted from the provider.
+const minKeyBits = 2048
+
+// Claims represents the identity of the user given by the provider.
+type Claims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	Expires       int64    `json:"exp"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	Name          string   `json:"name"`
+	Username      string   `json:"preferred_username"`
+	Groups        []string `json:"groups"`
+}
+
+// audience is the audience of a token, a string or a list of them.
+type audience []string
+
+// UnmarshalJSON decodes the audience in any of its forms.
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+
+	*a = list
+
+	return nil
+}
+
+// contains returns true if the client is in the audience.
+func (a audience) contains(client string) bool {
+	for _, aud := range a {
+		if aud == client {
+			return true
+		}
+	}
+
+	return false
+}
+
+// discovery represents the configuration published by the provider.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// jwk represents a public key published by the provider.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// Provider represents the identity provider the users log in with. Its
+// configuration is discovered from the issuer on the first login, so the
+// radar starts even if the provider is down.
+type Provider struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       string
+	Client       *http.Client
+	// Groups maps the groups of the users to the radar roles, see Role.
+	Groups map[string]string
+
+	mu      sync.Mutex
+	config  *discovery
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+	now     func() time.Time
+}
+
+// New creates and returns a new Provider for the issuer, identifying the radar
+// with the client id and secret.
+func New(issuer, clientID, clientSecret, redirectURL string) *Provider {
+	return &Provider{
+		Issuer:       strings.TrimRight(issuer, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       DefaultScopes,
+		Client:       &http.Client{Timeout: 10 * time.Second},
+		now:          time.Now,
+	}
+}
+
+// Configured returns true if there is an identity provider to log in with.
+func (p *Provider) Configured() bool {
+	return p != nil && p.Issuer != "" && p.ClientID != ""
+}
+
+// Random returns a new random value for the states, nonces and code verifiers
+// of the logins.
+func Random() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Challenge returns the PKCE code challenge of the verifier.
+func Challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthURL returns the url of the provider where the user logs in, which
+// redirects back to the radar with the code to exchange. The state and the
+// nonce tie the answer to this login, and the verifier is kept by the radar to
+// prove it started it.
+func (p *Provider) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	cfg, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.ClientID)
+	params.Set("redirect_uri", p.RedirectURL)
+	params.Set("scope", p.Scopes)
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", Challenge(verifier))
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(cfg.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return cfg.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// Exchange trades the code given by the provider for the identity of the
+// user, checking the identity token is signed by the provider, is for the
+// radar, has not expired and belongs to the login of the nonce.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	cfg, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.RedirectURL)
+	form.Set("client_id", p.ClientID)
+	form.Set("code_verifier", verifier)
+	if p.ClientSecret != "" {
+		form.Set("client_secret", p.ClientSecret)
+	}
+
+	req, err := http.NewRequest("POST", cfg.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the token request")
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	res := struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	status, err := p.do(req.WithContext(ctx), &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error exchanging the code")
+	}
+
+	if status != http.StatusOK || res.IDToken == "" {
+		return nil, errors.Wrap(ErrInvalidToken,
+			fmt.Sprintf("%d %s %s", status, res.Error, res.ErrorDescription))
+	}
+
+	return p.verify(ctx, cfg, res.IDToken, nonce)
+}
+
+// verify checks the identity token issued with the configuration and returns
+// its claims.
+func (p *Provider) verify(ctx context.Context, cfg *discovery, raw, nonce string) (*Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, errors.Wrap(ErrInvalidToken, "malformed token")
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "RS256" {
+		return nil, errors.Wrap(ErrInvalidToken, "unsupported token header")
+	}
+
+	key, err := p.key(ctx, cfg, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, "malformed signature")
+	}
+
+	sum := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], signature); err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, "wrong signature")
+	}
+
+	claims := &Claims{}
+	if err = decodeSegment(parts[1], claims); err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, "malformed claims")
+	}
+
+	switch {
+	case claims.Issuer != cfg.Issuer:
+		return nil, errors.Wrap(ErrInvalidToken, "wrong issuer")
+	case !claims.Audience.contains(p.ClientID):
+		return nil, errors.Wrap(ErrInvalidToken, "wrong audience")
+	case p.now().Add(-leeway).Unix() > claims.Expires:
+		return nil, errors.Wrap(ErrInvalidToken, "expired")
+	case claims.Nonce != nonce:
+		return nil, errors.Wrap(ErrInvalidToken, "wrong nonce")
+	case claims.Subject == "":
+		return nil, errors.Wrap(ErrInvalidToken, "missing subject")
+	}
+
+	return claims, nil
+}
+
+// discover returns the configuration of the provider, fetching it the first
+// time.
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	if !p.Configured() {
+		return nil, ErrNotConfigured
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config != nil {
+		return p.config, nil
+	}
+
+	req, err := http.NewRequest("GET", p.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the discovery request")
+	}
+
+	cfg := &discovery{}
+	status, err := p.do(req.WithContext(ctx), cfg)
+	if err != nil || status != http.StatusOK {
+		return nil, errors.Errorf("Error discovering the identity provider %s: %d %v",
+			p.Issuer, status, err)
+	}
+
+	if strings.TrimRight(cfg.Issuer, "/") != p.Issuer || cfg.AuthorizationEndpoint == "" ||
+		cfg.TokenEndpoint == "" || cfg.JWKSURI == "" {
+		return nil, errors.Errorf("Wrong configuration of the identity provider %s", p.Issuer)
+	}
+
+	p.config = cfg
+
+	return cfg, nil
+}
+
+// key returns the public key of the provider with the id, fetching the keys
+// again if it's unknown as the provider may have rotated them, at most once
+// every keysInterval.
+func (p *Provider) key(ctx context.Context, cfg *discovery, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	if p.now().Sub(p.fetched) < keysInterval {
+		return nil, errors.Wrap(ErrInvalidToken, "unknown key "+kid)
+	}
+
+	req, err := http.NewRequest("GET", cfg.JWKSURI, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating the keys request")
+	}
+
+	p.fetched = p.now()
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	status, err := p.do(req.WithContext(ctx), &set)
+	if err != nil || status != http.StatusOK {
+		return nil, errors.Errorf("Error fetching the keys of the identity provider: %d %v",
+			status, err)
+	}
+
+	p.keys = make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if key := k.publicKey(); key != nil {
+			p.keys[k.Kid] = key
+		}
+	}
+
+	key, ok := p.keys[kid]
+	if !ok {
+		return nil, errors.Wrap(ErrInvalidToken, "unknown key "+kid)
+	}
+
+	return key, nil
+}
+
+// publicKey returns the RSA public key, or nil if it's not a RSA key, it's
+// malformed or it's too weak to trust its signatures.
+func (k jwk) publicKey() *rsa.PublicKey {
+	if k.Kty != "RSA" {
+		return nil
+	}
+
+	n, errN := base64.RawURLEncoding.DecodeString(k.N)
+	e, errE := base64.RawURLEncoding.DecodeString(k.E)
+	if errN != nil || errE != nil {
+		return nil
+	}
+
+	key := &rsa.PublicKey{N: new(big.Int).SetBytes(n)}
+	exponent := new(big.Int).SetBytes(e)
+	if key.N.BitLen() < minKeyBits || exponent.BitLen() > 31 || exponent.Int64() < 3 ||
+		exponent.Bit(0) == 0 {
+		return nil
+	}
+
+	key.E = int(exponent.Int64())
+
+	return key
+}
+
+// do sends the request and decodes the json answer into v, returning the
+// status of the answer.
+func (p *Provider) do(req *http.Request, v interface{}) (int, error) {
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return res.StatusCode, err
+	}
+
+	return res.StatusCode, json.Unmarshal(body, v)
+}
+
+// decodeSegment decodes a segment of a token into v.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
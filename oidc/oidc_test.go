@@ -0,0 +1,142 @@
+package oidc
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestChallenge(t *testing.T) {
+	/* Test vector of RFC 7636, appendix B. */
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	expected := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+	if got := Challenge(verifier); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	a, err := Random()
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	b, _ := Random()
+	if len(a) < 43 || a == b {
+		t.Errorf("Unexpected random values %s and %s", a, b)
+	}
+}
+
+func TestAudience(t *testing.T) {
+	testCases := map[string]bool{
+		`"radar"`:            true,
+		`["other", "radar"]`: true,
+		`"other"`:            false,
+		`[]`:                 false,
+	}
+
+	for data, expected := range testCases {
+		var aud audience
+		if err := json.Unmarshal([]byte(data), &aud); err != nil {
+			t.Fatalf("Unexpected error decoding %s: %+v", data, err)
+		}
+
+		if aud.contains("radar") != expected {
+			t.Errorf("Expected %s to contain the client %t", data, expected)
+		}
+	}
+}
+
+func TestNotConfigured(t *testing.T) {
+	var p *Provider
+	if p.Configured() {
+		t.Error("Expected a nil provider not to be configured")
+	}
+
+	p = New("", "radar", "", "")
+	if p.Configured() {
+		t.Error("Expected a provider without issuer not to be configured")
+	}
+
+	if _, err := p.AuthURL(context.Background(), "s", "n", "v"); err != ErrNotConfigured {
+		t.Errorf("Expected %v, got %v", ErrNotConfigured, err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	strong, err := rsa.GenerateKey(rand.Reader, minKeyBits)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	encode := func(kid string, n *big.Int, e int) jwk {
+		return jwk{Kid: kid, Kty: "RSA", N: base64.RawURLEncoding.EncodeToString(n.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(e)).Bytes())}
+	}
+
+	fetches := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string][]jwk{"keys": {
+			encode("strong", strong.N, strong.E),
+			encode("weak", weak.N, weak.E),
+			encode("exponent", strong.N, 1),
+		}})
+	}))
+	defer s.Close()
+
+	now := time.Now()
+	p := New("https://sso.ritho.net", "radar", "", "")
+	p.now = func() time.Time { return now }
+	cfg := &discovery{JWKSURI: s.URL}
+	ctx := context.Background()
+	if key, err := p.key(ctx, cfg, "strong"); err != nil || key.N.Cmp(strong.N) != 0 {
+		t.Errorf("Expected the strong key, Got %v", err)
+	}
+
+	for _, kid := range []string{"weak", "exponent", "unknown", "unknown"} {
+		if _, err := p.key(ctx, cfg, kid); err == nil {
+			t.Errorf("Expected the %s key refused", kid)
+		}
+	}
+
+	if fetches != 1 {
+		t.Errorf("Expected the keys fetched once, Got %d", fetches)
+	}
+
+	now = now.Add(keysInterval)
+	p.key(ctx, cfg, "unknown")
+	if fetches != 2 {
+		t.Errorf("Expected the keys fetched again after %s, Got %d", keysInterval, fetches)
+	}
+}
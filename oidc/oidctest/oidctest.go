@@ -0,0 +1,303 @@
+// Package oidctest implements a mock OpenID Connect identity provider to test
+// the single sign-on.
+package oidctest
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/radar-go/radar/oidc"
+)
+
+// Client id and secret of the radar in the mock provider.
+const (
+	ClientID     = "radar"
+	ClientSecret = "radar-secret"
+)
+
+// keyID is the id of the key signing the tokens.
+const keyID = "test-key"
+
+// User represents the user logged in the mock provider.
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Username      string
+	Groups        []string
+}
+
+// grant represents an authorization code given by the provider.
+type grant struct {
+	user      User
+	redirect  string
+	nonce     string
+	challenge string
+}
+
+// Server represents the mock identity provider. Every authorization request
+// is approved for the configured user.
+type Server struct {
+	*httptest.Server
+
+	key    *rsa.PrivateKey
+	mu     sync.Mutex
+	user   User
+	extra  map[string]interface{}
+	codes  map[string]grant
+	issuer string
+}
+
+// New creates, starts and returns a new mock identity provider logging in the
+// user.
+func New(user User) *Server {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	s := &Server{
+		key:   key,
+		user:  user,
+		codes: make(map[string]grant),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/keys", s.keys)
+	s.Server = httptest.NewServer(mux)
+	s.issuer = s.URL
+
+	return s
+}
+
+// Provider returns a provider for the mock, redirecting to the url.
+func (s *Server) Provider(redirectURL string) *oidc.Provider {
+	p := oidc.New(s.URL, ClientID, ClientSecret, redirectURL)
+	p.Client = s.Client()
+
+	return p
+}
+
+// SetUser changes the user logged in the mock provider.
+func (s *Server) SetUser(user User) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.user = user
+}
+
+// Override changes the claims of the next identity tokens, to test the
+// answers of a misbehaving provider.
+func (s *Server) Override(claims map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.extra = claims
+}
+
+// Authorize follows the authorization url as the browser of the user, and
+// returns the code and the state the provider redirects back with.
+func (s *Server) Authorize(authURL string) (code, state string, err error) {
+	client := s.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	res, err := client.Get(authURL)
+	if err != nil {
+		return "", "", err
+	}
+	defer res.Body.Close()
+
+	location, err := res.Location()
+	if err != nil {
+		return "", "", err
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state"), nil
+}
+
+// IDToken returns an identity token signed by the provider with the claims.
+func (s *Server) IDToken(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": keyID, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := encode(header) + "." + encode(payload)
+	sum := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	if err != nil {
+		panic(err)
+	}
+
+	return signed + "." + encode(signature)
+}
+
+// discovery publishes the configuration of the provider.
+func (s *Server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.issuer,
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/keys",
+	})
+}
+
+// authorize approves the login of the user and redirects back to the client
+// with the code.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != ClientID || q.Get("response_type") != "code" ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Host == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	code := random()
+	s.mu.Lock()
+	s.codes[code] = grant{
+		user:      s.user,
+		redirect:  q.Get("redirect_uri"),
+		nonce:     q.Get("nonce"),
+		challenge: q.Get("code_challenge"),
+	}
+	s.mu.Unlock()
+
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// token exchanges a code for the identity token of the user, checking the
+// code verifier matches the challenge of the login.
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	if r.PostForm.Get("client_id") != ClientID ||
+		r.PostForm.Get("client_secret") != ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	extra := s.extra
+	s.mu.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != g.redirect ||
+		oidc.Challenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss":            s.issuer,
+		"sub":            g.user.Subject,
+		"aud":            ClientID,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.user.Email,
+		"email_verified": g.user.EmailVerified,
+	}
+	if g.user.Name != "" {
+		claims["name"] = g.user.Name
+	}
+
+	if g.user.Username != "" {
+		claims["preferred_username"] = g.user.Username
+	}
+
+	if len(g.user.Groups) > 0 {
+		claims["groups"] = g.user.Groups
+	}
+
+	for k, v := range extra {
+		claims[k] = v
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": random(),
+		"token_type":   "Bearer",
+		"id_token":     s.IDToken(claims),
+	})
+}
+
+// keys publishes the public key signing the tokens.
+func (s *Server) keys(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": keyID,
+			"n":   encode(s.key.N.Bytes()),
+			"e":   encode(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+// writeJSON writes the value as the json answer.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// random returns a new random code.
+func random() string {
+	code, err := oidc.Random()
+	if err != nil {
+		panic(err)
+	}
+
+	return code
+}
+
+// encode encodes the data as a token segment.
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
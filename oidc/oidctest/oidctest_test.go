@@ -0,0 +1,127 @@
+package oidctest
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radar-go/radar/oidc"
+)
+
+// redirectURL is the url of the radar the provider redirects back to.
+const redirectURL = "http://radar.example.com/login/oidc/callback"
+
+// login logs in through the provider, returning the identity of the user.
+func login(s *Server, p *oidc.Provider) (*oidc.Claims, error) {
+	authURL, err := p.AuthURL(context.Background(), "state", "nonce", "verifier")
+	if err != nil {
+		return nil, err
+	}
+
+	code, state, err := s.Authorize(authURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if state != "state" {
+		return nil, err
+	}
+
+	return p.Exchange(context.Background(), code, "verifier", "nonce")
+}
+
+func TestLogin(t *testing.T) {
+	s := New(User{
+		Subject:       "1234",
+		Email:         "ritho@example.com",
+		EmailVerified: true,
+		Name:          "Ritho",
+		Username:      "ritho",
+		Groups:        []string{"radar-admins"},
+	})
+	defer s.Close()
+
+	claims, err := login(s, s.Provider(redirectURL))
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	if claims.Issuer != s.URL || claims.Subject != "1234" ||
+		claims.Email != "ritho@example.com" || !claims.EmailVerified ||
+		claims.Name != "Ritho" || claims.Username != "ritho" ||
+		len(claims.Groups) != 1 || claims.Groups[0] != "radar-admins" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+}
+
+func TestLoginErrors(t *testing.T) {
+	s := New(User{Subject: "1234", Email: "ritho@example.com", EmailVerified: true})
+	defer s.Close()
+
+	testCases := map[string]map[string]interface{}{
+		"wrong issuer":    {"iss": "http://evil.example.com"},
+		"wrong audience":  {"aud": []string{"other", "another"}},
+		"expired":         {"exp": time.Now().Add(-time.Hour).Unix()},
+		"wrong nonce":     {"nonce": "other"},
+		"missing subject": {"sub": ""},
+	}
+
+	p := s.Provider(redirectURL)
+	for name, claims := range testCases {
+		s.Override(claims)
+		_, err := login(s, p)
+		if err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("Expected error %s, got %v", name, err)
+		}
+	}
+
+	s.Override(map[string]interface{}{"aud": []string{"other", ClientID}})
+	if _, err := login(s, p); err != nil {
+		t.Errorf("Unexpected error with a list of audiences: %+v", err)
+	}
+
+	s.Override(nil)
+	authURL, err := p.AuthURL(context.Background(), "state", "nonce", "verifier")
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	code, _, err := s.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %+v", err)
+	}
+
+	_, err = p.Exchange(context.Background(), code, "other verifier", "nonce")
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("Expected the wrong verifier to be refused, got %v", err)
+	}
+
+	_, err = p.Exchange(context.Background(), code, "verifier", "nonce")
+	if err == nil {
+		t.Error("Expected the code to be used once")
+	}
+
+	p.ClientSecret = "wrong"
+	if _, err = login(s, p); err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("Expected the wrong secret to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"sync"
+	"time"
+)
+
+// failures stores the failed attempts of a key.
+type failures struct {
+	count int
+	last  time.Time
+}
+
+// Backoff tracks the failed attempts by key. After some free failures every new
+// failure doubles the time the key has to wait before trying again, until the
+// key gets locked out for the maximum time.
+type Backoff struct {
+	mu        sync.Mutex
+	free      int
+	base      time.Duration
+	max       time.Duration
+	failures  map[string]*failures
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewBackoff creates and returns a new Backoff allowing free failures by key
+// before making it wait, starting with base and up to max.
+func NewBackoff(free int, base, max time.Duration) *Backoff {
+	return &Backoff{
+		free:      free,
+		base:      base,
+		max:       max,
+		failures:  make(map[string]*failures),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Wait returns the time the key has to wait before trying again, zero if it
+// can try now.
+func (b *Backoff) Wait(key string) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, ok := b.failures[key]
+	if !ok {
+		return 0
+	}
+
+	wait := f.last.Add(b.delay(f.count)).Sub(b.now())
+	if wait <= 0 && b.now().Sub(f.last) > b.max {
+		/* The key has been quiet long enough to forget its failures. */
+		delete(b.failures, key)
+	}
+
+	if wait < 0 {
+		return 0
+	}
+
+	return wait
+}
+
+// Locked returns true if the key has reached the maximum wait.
+func (b *Backoff) Locked(key string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	f, ok := b.failures[key]
+	return ok && b.delay(f.count) >= b.max && b.now().Before(f.last.Add(b.max))
+}
+
+// Fail records a failed attempt of the key.
+func (b *Backoff) Fail(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.sweep(now)
+
+	f, ok := b.failures[key]
+	if !ok {
+		f = &failures{}
+		b.failures[key] = f
+	}
+
+	f.count++
+	f.last = now
+}
+
+// Reset forgets the failed attempts of the key.
+func (b *Backoff) Reset(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.failures, key)
+}
+
+// sweep forgets the keys quiet for longer than the maximum wait, once every
+// maximum wait, so the keys failing only once don't stay forever.
+func (b *Backoff) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < b.max {
+		return
+	}
+
+	b.lastSweep = now
+	for key, f := range b.failures {
+		if now.Sub(f.last) > b.max {
+			delete(b.failures, key)
+		}
+	}
+}
+
+// delay returns the time to wait after count failures.
+func (b *Backoff) delay(count int) time.Duration {
+	if count <= b.free {
+		return 0
+	}
+
+	delay := b.base
+	for i := b.free + 1; i < count && delay < b.max; i++ {
+		delay *= 2
+	}
+
+	if delay > b.max {
+		delay = b.max
+	}
+
+	return delay
+}
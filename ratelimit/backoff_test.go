@@ -0,0 +1,68 @@
+package ratelimit
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	now := time.Now()
+	b := NewBackoff(2, time.Second, 8*time.Second)
+	b.now = func() time.Time { return now }
+
+	for i, expected := range []time.Duration{0, 0, time.Second, 2 * time.Second,
+		4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		b.Fail("ritho")
+		if wait := b.Wait("ritho"); wait != expected {
+			t.Errorf("Expected %s after %d failures, Got %s", expected, i+1, wait)
+		}
+	}
+
+	if !b.Locked("ritho") {
+		t.Error("Expected the key to be locked")
+	}
+
+	if b.Wait("other") != 0 {
+		t.Error("Expected the keys to be tracked independently")
+	}
+
+	now = now.Add(8 * time.Second)
+	if b.Wait("ritho") != 0 || b.Locked("ritho") {
+		t.Error("Expected the lockout to expire")
+	}
+
+	b.Fail("ritho")
+	if b.Wait("ritho") != 8*time.Second {
+		t.Error("Expected the failures to be remembered after the lockout")
+	}
+
+	b.Reset("ritho")
+	if b.Wait("ritho") != 0 || b.Locked("ritho") {
+		t.Error("Expected the failures to be forgotten after a reset")
+	}
+
+	b.Fail("quiet")
+	now = now.Add(9 * time.Second)
+	b.Wait("quiet")
+	if _, ok := b.failures["quiet"]; ok {
+		t.Error("Expected the failures to be forgotten after a quiet period")
+	}
+}
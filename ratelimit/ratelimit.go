@@ -0,0 +1,105 @@
+// Package ratelimit implements limits to the number of events in a period of
+// time, like the requests of a client.
+package ratelimit
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter limits the number of events by key in a sliding window of time.
+type Limiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	events    map[string][]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New creates and returns a new Limiter allowing limit events by key in every
+// window of time.
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:     limit,
+		window:    window,
+		events:    make(map[string][]time.Time),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Allow records an event for the key and returns true if the key has not
+// exceeded the limit in the window. The events not allowed are not recorded.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	events := recent(l.events[key], now.Add(-l.window))
+	if len(events) >= l.limit {
+		l.events[key] = events
+		return false
+	}
+
+	l.events[key] = append(events, now)
+
+	return true
+}
+
+// Reset forgets the events of the key.
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.events, key)
+}
+
+// sweep removes the keys without events in the window, once every window, so
+// the keys seen only once don't stay forever.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+
+	l.lastSweep = now
+	for key, events := range l.events {
+		events = recent(events, now.Add(-l.window))
+		if len(events) == 0 {
+			delete(l.events, key)
+		} else {
+			l.events[key] = events
+		}
+	}
+}
+
+// recent returns the events after since.
+func recent(events []time.Time, since time.Time) []time.Time {
+	for i, event := range events {
+		if event.After(since) {
+			return events[i:]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := New(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i, expected := range []bool{true, true, false} {
+		if l.Allow("ritho") != expected {
+			t.Errorf("Expected %t for the event %d", expected, i)
+		}
+	}
+
+	if !l.Allow("other") {
+		t.Error("Expected the keys to be limited independently")
+	}
+
+	now = now.Add(time.Minute + time.Second)
+	if !l.Allow("ritho") {
+		t.Error("Expected the events to expire after the window")
+	}
+
+	if _, ok := l.events["other"]; ok {
+		t.Error("Expected the keys without recent events to be swept")
+	}
+
+	l.Allow("ritho")
+	l.Reset("ritho")
+	if !l.Allow("ritho") {
+		t.Error("Expected the events to be forgotten after a reset")
+	}
+}
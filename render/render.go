@@ -0,0 +1,209 @@
+// Package render implements the rendering of the radar.
+package render
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/radar-go/radar"
+)
+
+// Flavors of the radar: the experience of the people, the projects done in the
+// organization and the resources to learn about the technologies.
+var Flavors = []string{"people", "projects", "resources"}
+
+// Quadrants of the radar.
+var Quadrants = []string{"Techniques", "Tools", "Platforms", "Languages & Frameworks"}
+
+// Rings of the radar, from the inner to the outer one.
+var Rings = []string{"Adopt", "Trial", "Assess", "Hold"}
+
+// quadrantTypes maps the technology types to the quadrant they belong to.
+var quadrantTypes = map[string]string{
+	"technique":                "Techniques",
+	"techniques":               "Techniques",
+	"tool":                     "Tools",
+	"tools":                    "Tools",
+	"platform":                 "Platforms",
+	"platforms":                "Platforms",
+	"language":                 "Languages & Frameworks",
+	"languages":                "Languages & Frameworks",
+	"framework":                "Languages & Frameworks",
+	"frameworks":               "Languages & Frameworks",
+	"languages & frameworks":   "Languages & Frameworks",
+	"languages and frameworks": "Languages & Frameworks",
+}
+
+// ringRadius is the outer radius of every ring in the svg.
+var ringRadius = []float64{120, 190, 250, 300}
+
+// size of the svg image.
+const size = 640
+
+// Blip represents a technology placed in the radar.
+type Blip struct {
+	Name     string `json:"name"`
+	Quadrant string `json:"quadrant"`
+	Ring     string `json:"ring"`
+}
+
+// IsFlavor returns true if name is one of the radar flavors.
+func IsFlavor(name string) bool {
+	for _, flavor := range Flavors {
+		if flavor == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Quadrant returns the quadrant for a technology type, or false if the type
+// doesn't belong to any quadrant.
+func Quadrant(techType string) (string, bool) {
+	quadrant, ok := quadrantTypes[radar.CleanString(techType)]
+	return quadrant, ok
+}
+
+// Ring returns the ring for a technology level, the levels goes from 1 (hold)
+// to 4 (adopt).
+func Ring(level int) string {
+	ring := len(Rings) - level
+	if ring < 0 {
+		ring = 0
+	} else if ring >= len(Rings) {
+		ring = len(Rings) - 1
+	}
+
+	return Rings[ring]
+}
+
+// index returns the position of value in list or -1 if it's not present.
+func index(list []string, value string) int {
+	for i, elem := range list {
+		if elem == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// SVG writes the radar with the blips to w in svg format. Every blip links to
+// the url returned by link, if any. Blips with unknown quadrant or ring are
+// ignored.
+func SVG(w io.Writer, blips []Blip, link func(Blip) string) error {
+	/* Group the blips by sector to spread them inside it. */
+	sectors := make(map[[2]int][]Blip)
+	for _, blip := range blips {
+		q := index(Quadrants, blip.Quadrant)
+		r := index(Rings, blip.Ring)
+		if q < 0 || r < 0 {
+			continue
+		}
+
+		sector := [2]int{q, r}
+		sectors[sector] = append(sectors[sector], blip)
+	}
+
+	center := size / 2.0
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img">`,
+		size, size, size, size)
+	fmt.Fprint(out, `<title>Technology radar</title>`)
+	for r := len(Rings) - 1; r >= 0; r-- {
+		fmt.Fprintf(out, `<circle cx="%g" cy="%g" r="%g" fill="#%s" stroke="#fff"/>`,
+			center, center, ringRadius[r], []string{"cfe8d6", "dde8ef", "ece6d6", "efdcdc"}[r])
+		fmt.Fprintf(out, `<text x="%g" y="%g" font-size="11" text-anchor="middle">%s</text>`,
+			center, center-ringRadius[r]+14, escape(Rings[r]))
+	}
+
+	fmt.Fprintf(out, `<line x1="0" y1="%g" x2="%d" y2="%g" stroke="#fff" stroke-width="4"/>`,
+		center, size, center)
+	fmt.Fprintf(out, `<line x1="%g" y1="0" x2="%g" y2="%d" stroke="#fff" stroke-width="4"/>`,
+		center, center, size)
+
+	for q, quadrant := range Quadrants {
+		x, y := 10.0, 20.0
+		anchor := "start"
+		if q == 0 || q == 3 {
+			x, anchor = size-10, "end"
+		}
+
+		if q >= 2 {
+			y = size - 10
+		}
+
+		fmt.Fprintf(out, `<text x="%g" y="%g" font-size="14" font-weight="bold" text-anchor="%s">%s</text>`,
+			x, y, anchor, escape(quadrant))
+
+		for r := range Rings {
+			sector := sectors[[2]int{q, r}]
+			inner := 0.0
+			if r > 0 {
+				inner = ringRadius[r-1]
+			}
+
+			for i, blip := range sector {
+				/* Quadrants go counterclockwise starting from the top right
+				one, and the blips are spread along the middle of the ring,
+				alternating the distance to the center to avoid overlaps. */
+				angle := (float64(q)*90 + float64(i+1)*90/float64(len(sector)+1)) * math.Pi / 180
+				radius := inner + (ringRadius[r]-inner)*(0.35+0.3*float64(i%2))
+				bx := center + radius*math.Cos(angle)
+				by := center - radius*math.Sin(angle)
+
+				url := ""
+				if link != nil {
+					url = link(blip)
+				}
+
+				if url != "" {
+					fmt.Fprintf(out, `<a href="%s">`, escape(url))
+				}
+
+				fmt.Fprintf(out, `<circle cx="%.1f" cy="%.1f" r="6" fill="#333"><title>%s</title></circle>`,
+					bx, by, escape(blip.Name))
+				fmt.Fprintf(out, `<text x="%.1f" y="%.1f" font-size="10">%s</text>`,
+					bx+8, by+4, escape(blip.Name))
+				if url != "" {
+					fmt.Fprint(out, `</a>`)
+				}
+			}
+		}
+	}
+
+	fmt.Fprint(out, `</svg>`)
+
+	return out.Flush()
+}
+
+// escape returns s escaped to be included in the svg.
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+
+	return b.String()
+}
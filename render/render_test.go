@@ -0,0 +1,117 @@
+package render
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestQuadrant(t *testing.T) {
+	testCases := map[string]string{
+		"Tools":                  "Tools",
+		" language ":             "Languages & Frameworks",
+		"Languages & Frameworks": "Languages & Frameworks",
+		"technique":              "Techniques",
+		"platforms":              "Platforms",
+	}
+
+	for techType, expected := range testCases {
+		quadrant, ok := Quadrant(techType)
+		if !ok || quadrant != expected {
+			t.Errorf("Expected %s for %s, Got %s", expected, techType, quadrant)
+		}
+	}
+
+	_, ok := Quadrant("unknown")
+	if ok {
+		t.Error("Expected the unknown type to not have quadrant")
+	}
+}
+
+func TestRing(t *testing.T) {
+	testCases := map[int]string{
+		-1: "Hold",
+		1:  "Hold",
+		2:  "Assess",
+		3:  "Trial",
+		4:  "Adopt",
+		10: "Adopt",
+	}
+
+	for level, expected := range testCases {
+		if Ring(level) != expected {
+			t.Errorf("Expected %s for level %d, Got %s", expected, level, Ring(level))
+		}
+	}
+}
+
+func TestIsFlavor(t *testing.T) {
+	if !IsFlavor("people") || !IsFlavor("projects") || !IsFlavor("resources") {
+		t.Error("Expected people, projects and resources to be flavors")
+	}
+
+	if IsFlavor("unknown") {
+		t.Error("Expected unknown to not be a flavor")
+	}
+}
+
+func TestSVG(t *testing.T) {
+	blips := []Blip{
+		{Name: "Go", Quadrant: "Languages & Frameworks", Ring: "Adopt"},
+		{Name: "C & C++", Quadrant: "Languages & Frameworks", Ring: "Adopt"},
+		{Name: "Docker", Quadrant: "Platforms", Ring: "Trial"},
+		{Name: "Ignored", Quadrant: "Unknown", Ring: "Trial"},
+	}
+
+	out := &bytes.Buffer{}
+	err := SVG(out, blips, func(b Blip) string {
+		return "/technology/" + b.Name
+	})
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	svg := out.String()
+	if !strings.HasPrefix(svg, "<svg") || !strings.HasSuffix(svg, "</svg>") {
+		t.Errorf("Expected a svg document, Got %s", svg)
+	}
+
+	for _, expected := range []string{`<a href="/technology/Go">`, "<title>Docker</title>",
+		"C &amp; C++", "Languages &amp; Frameworks"} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("Expected %s in %s", expected, svg)
+		}
+	}
+
+	if strings.Contains(svg, "Ignored") {
+		t.Errorf("Expected the blip with unknown quadrant to be ignored, Got %s", svg)
+	}
+
+	out.Reset()
+	err = SVG(out, blips, nil)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if strings.Contains(out.String(), "<a ") {
+		t.Errorf("Expected no links, Got %s", out)
+	}
+}
@@ -0,0 +1,508 @@
+package scim
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Filter selects the resources of a search, or the values of a multi-valued
+// attribute.
+type Filter interface {
+	Match(resource map[string]interface{}) bool
+}
+
+// and matches the resources matching both filters.
+type and struct {
+	left, right Filter
+}
+
+// Match returns true if the resource matches both filters.
+func (f *and) Match(resource map[string]interface{}) bool {
+	return f.left.Match(resource) && f.right.Match(resource)
+}
+
+// or matches the resources matching any of the filters.
+type or struct {
+	left, right Filter
+}
+
+// Match returns true if the resource matches any of the filters.
+func (f *or) Match(resource map[string]interface{}) bool {
+	return f.left.Match(resource) || f.right.Match(resource)
+}
+
+// not matches the resources not matching the filter.
+type not struct {
+	filter Filter
+}
+
+// Match returns true if the resource doesn't match the filter.
+func (f *not) Match(resource map[string]interface{}) bool {
+	return !f.filter.Match(resource)
+}
+
+// present matches the resources with a value in the attribute.
+type present struct {
+	path []string
+}
+
+// Match returns true if the attribute has a value.
+func (f *present) Match(resource map[string]interface{}) bool {
+	for _, value := range values(resource, f.path) {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			if v != "" {
+				return true
+			}
+		case []interface{}:
+			if len(v) > 0 {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+
+	return false
+}
+
+// comparison matches the resources whose attribute compares with the value.
+type comparison struct {
+	path  []string
+	op    string
+	value interface{}
+}
+
+// Match returns true if any value of the attribute compares with the value.
+func (f *comparison) Match(resource map[string]interface{}) bool {
+	if f.op == "ne" {
+		return !(&comparison{f.path, "eq", f.value}).Match(resource)
+	}
+
+	if f.value == nil {
+		return f.op == "eq" && !(&present{f.path}).Match(resource)
+	}
+
+	for _, value := range values(resource, f.path) {
+		if compare(f.op, value, f.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valuePath matches the resources with any value of a multi-valued attribute
+// matching the filter.
+type valuePath struct {
+	attr   string
+	filter Filter
+}
+
+// Match returns true if any value of the attribute matches the filter.
+func (f *valuePath) Match(resource map[string]interface{}) bool {
+	for _, elem := range elements(resource, f.attr) {
+		if f.filter.Match(elem) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseFilter returns the filter of a search, like
+// userName eq "ritho" or emails[type eq "work" and value co "@ritho.net"].
+func ParseFilter(s string) (Filter, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	f, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.done() {
+		return nil, errors.Wrapf(ErrInvalidFilter, "unexpected %s", p.peek().text)
+	}
+
+	return f, nil
+}
+
+// token kinds.
+const (
+	tokenWord = iota
+	tokenString
+	tokenSymbol
+)
+
+// token is a word, a quoted string or a parenthesis or bracket of a filter.
+type token struct {
+	kind int
+	text string
+}
+
+// tokenize splits a filter in its tokens.
+func tokenize(s string) ([]token, error) {
+	tokens := []token{}
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.IndexByte("()[]", c) >= 0:
+			tokens = append(tokens, token{tokenSymbol, s[i : i+1]})
+			i++
+		case c == '"':
+			end := quoted(s, i)
+			if end < 0 {
+				return nil, errors.Wrap(ErrInvalidFilter, "unterminated string")
+			}
+
+			var text string
+			if err := json.Unmarshal([]byte(s[i:end]), &text); err != nil {
+				return nil, errors.Wrap(ErrInvalidFilter, err.Error())
+			}
+
+			tokens = append(tokens, token{tokenString, text})
+			i = end
+		default:
+			start := i
+			for i < len(s) && strings.IndexByte(" \t()[]\"", s[i]) < 0 {
+				i++
+			}
+
+			tokens = append(tokens, token{tokenWord, s[start:i]})
+		}
+	}
+
+	return tokens, nil
+}
+
+// quoted returns the end of the quoted string starting at i, -1 if it's not
+// terminated.
+func quoted(s string, i int) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+
+	return -1
+}
+
+// parser builds the filters from their tokens, with not binding stronger than
+// and, and and binding stronger than or.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// done returns true once all the tokens are parsed.
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// peek returns the next token without consuming it.
+func (p *parser) peek() token {
+	if p.done() {
+		return token{tokenSymbol, "end of filter"}
+	}
+
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the next token.
+func (p *parser) next() token {
+	t := p.peek()
+	p.pos++
+
+	return t
+}
+
+// keyword returns true and consumes the next token if it's the word kw.
+func (p *parser) keyword(kw string) bool {
+	t := p.peek()
+	if t.kind == tokenWord && strings.EqualFold(t.text, kw) {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+// expect consumes the symbol, failing if the next token is another one.
+func (p *parser) expect(symbol string) error {
+	t := p.next()
+	if t.kind != tokenSymbol || t.text != symbol {
+		return errors.Wrapf(ErrInvalidFilter, "expected %s, got %s", symbol, t.text)
+	}
+
+	return nil
+}
+
+// or parses the filters joined by or.
+func (p *parser) or() (Filter, error) {
+	f, err := p.and()
+	for err == nil && p.keyword("or") {
+		var right Filter
+		right, err = p.and()
+		f = &or{f, right}
+	}
+
+	return f, err
+}
+
+// and parses the filters joined by and.
+func (p *parser) and() (Filter, error) {
+	f, err := p.unary()
+	for err == nil && p.keyword("and") {
+		var right Filter
+		right, err = p.unary()
+		f = &and{f, right}
+	}
+
+	return f, err
+}
+
+// unary parses a negated or grouped filter, or an attribute expression.
+func (p *parser) unary() (Filter, error) {
+	negated := p.keyword("not")
+	if t := p.peek(); negated || (t.kind == tokenSymbol && t.text == "(") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		if negated {
+			f = &not{f}
+		}
+
+		return f, nil
+	}
+
+	return p.attribute()
+}
+
+// attribute parses an attribute expression: a presence check, a comparison or
+// a filter of the values of a multi-valued attribute.
+func (p *parser) attribute() (Filter, error) {
+	t := p.next()
+	if t.kind != tokenWord {
+		return nil, errors.Wrapf(ErrInvalidFilter, "expected an attribute, got %s", t.text)
+	}
+
+	path := attrPath(t.text)
+	if next := p.peek(); next.kind == tokenSymbol && next.text == "[" {
+		p.pos++
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+
+		return &valuePath{path[0], f}, p.expect("]")
+	}
+
+	op := strings.ToLower(p.next().text)
+	switch op {
+	case "pr":
+		return &present{path}, nil
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, errors.Wrapf(ErrInvalidFilter, "unknown operator %s", op)
+	}
+
+	value, err := p.value()
+
+	return &comparison{path, op, value}, err
+}
+
+// value parses the value compared with an attribute.
+func (p *parser) value() (interface{}, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenString:
+		return t.text, nil
+	case t.kind != tokenWord:
+		return nil, errors.Wrapf(ErrInvalidFilter, "expected a value, got %s", t.text)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.ToLower(t.text)), &value); err != nil {
+		return nil, errors.Wrapf(ErrInvalidFilter, "wrong value %s", t.text)
+	}
+
+	return value, nil
+}
+
+// attrPath returns the attribute and sub-attribute of a path, without the
+// schema prefix.
+func attrPath(s string) []string {
+	if strings.HasPrefix(strings.ToLower(s), "urn:") {
+		s = s[strings.LastIndex(s, ":")+1:]
+	}
+
+	return strings.SplitN(s, ".", 2)
+}
+
+// lookup returns the key of the attribute in the resource, the attribute names
+// are case insensitive.
+func lookup(resource map[string]interface{}, attr string) (string, bool) {
+	if _, ok := resource[attr]; ok {
+		return attr, true
+	}
+
+	for key := range resource {
+		if strings.EqualFold(key, attr) {
+			return key, true
+		}
+	}
+
+	return attr, false
+}
+
+// elements returns the complex values of a multi-valued attribute.
+func elements(resource map[string]interface{}, attr string) []map[string]interface{} {
+	key, _ := lookup(resource, attr)
+	var elems []map[string]interface{}
+	switch v := resource[key].(type) {
+	case []interface{}:
+		for _, elem := range v {
+			if m, ok := elem.(map[string]interface{}); ok {
+				elems = append(elems, m)
+			}
+		}
+	case map[string]interface{}:
+		elems = append(elems, v)
+	}
+
+	return elems
+}
+
+// values returns the values of the attribute in the path. The values of the
+// multi-valued complex attributes are their value sub-attributes.
+func values(resource map[string]interface{}, path []string) []interface{} {
+	key, ok := lookup(resource, path[0])
+	if !ok {
+		return nil
+	}
+
+	sub := "value"
+	if len(path) > 1 {
+		sub = path[1]
+	}
+
+	switch v := resource[key].(type) {
+	case []interface{}, map[string]interface{}:
+		var vals []interface{}
+		for _, elem := range elements(resource, key) {
+			if subKey, ok := lookup(elem, sub); ok {
+				vals = append(vals, elem[subKey])
+			}
+		}
+
+		if arr, ok := v.([]interface{}); ok && len(path) == 1 {
+			for _, elem := range arr {
+				if _, complex := elem.(map[string]interface{}); !complex {
+					vals = append(vals, elem)
+				}
+			}
+		}
+
+		return vals
+	default:
+		if len(path) > 1 {
+			return nil
+		}
+
+		return []interface{}{v}
+	}
+}
+
+// compare returns true if the value of the attribute compares with the value
+// of the filter, the strings are compared case insensitively.
+func compare(op string, got, want interface{}) bool {
+	switch w := want.(type) {
+	case bool:
+		g, ok := got.(bool)
+		return ok && op == "eq" && g == w
+	case float64:
+		g, ok := got.(float64)
+		if !ok {
+			return false
+		}
+
+		return order(op, g == w, g > w)
+	case string:
+		g, ok := got.(string)
+		if !ok {
+			return false
+		}
+
+		g, w = strings.ToLower(g), strings.ToLower(w)
+		switch op {
+		case "co":
+			return strings.Contains(g, w)
+		case "sw":
+			return strings.HasPrefix(g, w)
+		case "ew":
+			return strings.HasSuffix(g, w)
+		}
+
+		return order(op, g == w, g > w)
+	}
+
+	return false
+}
+
+// order returns the result of the equality or ordering operator, given if the
+// values are equal or the one of the attribute is greater.
+func order(op string, equal, greater bool) bool {
+	switch op {
+	case "eq":
+		return equal
+	case "gt":
+		return greater
+	case "ge":
+		return greater || equal
+	case "lt":
+		return !greater && !equal
+	case "le":
+		return !greater
+	}
+
+	return false
+}
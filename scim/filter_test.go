@@ -0,0 +1,83 @@
+package scim
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestFilter(t *testing.T) {
+	resource := map[string]interface{}{
+		"id":       "3",
+		"userName": "Ritho",
+		"name":     map[string]interface{}{"formatted": "Pablo Álvarez"},
+		"active":   true,
+		"emails": []interface{}{
+			map[string]interface{}{"value": "palvarez@ritho.net", "type": "work"},
+			map[string]interface{}{"value": "ritho@example.com", "type": "home"},
+		},
+		"groups": []interface{}{map[string]interface{}{"value": "admin"}},
+	}
+
+	testCases := map[string]bool{
+		`userName eq "ritho"`: true,
+		`USERNAME Eq "RITHO"`: true,
+		`userName ne "ritho"`: false,
+		`urn:ietf:params:scim:schemas:core:2.0:User:userName sw "ri"`:    true,
+		`name.formatted co "álvarez"`:                                    true,
+		`name.familyName pr`:                                             false,
+		`title pr`:                                                       false,
+		`title eq null`:                                                  true,
+		`active eq true`:                                                 true,
+		`active eq false`:                                                false,
+		`emails co "example.com"`:                                        true,
+		`emails.value ew "ritho.net"`:                                    true,
+		`emails[type eq "work" and value co "example"]`:                  false,
+		`emails[type eq "home" and value co "example"]`:                  true,
+		`groups[value eq "admin"]`:                                       true,
+		`userName eq "jdoe" or groups.value eq "admin"`:                  true,
+		`not (userName eq "ritho") or active eq false`:                   false,
+		`userName eq "jdoe" or userName eq "ritho" and active eq false`:  false,
+		`(userName eq "jdoe" or userName eq "ritho") and active eq true`: true,
+		`id gt "2" and id le "3"`:                                        true,
+		`id lt "3"`:                                                      false,
+	}
+
+	for filter, expected := range testCases {
+		f, err := ParseFilter(filter)
+		if err != nil {
+			t.Errorf("Unexpected error parsing %s: %s", filter, err)
+			continue
+		}
+
+		if f.Match(resource) != expected {
+			t.Errorf("Expected %s to match %t", filter, expected)
+		}
+	}
+
+	for _, wrong := range []string{"", "userName", `userName eq`, `userName is "ritho"`,
+		`userName eq "ritho`, `(userName eq "ritho"`, `emails[type eq "work"`,
+		`userName eq "a" "b"`, `userName eq ritho`} {
+		if _, err := ParseFilter(wrong); errors.Cause(err) != ErrInvalidFilter {
+			t.Errorf("Expected invalid filter parsing %q, Got %v", wrong, err)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package scim
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Operation is a change of a patch request.
+type Operation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// patchRequest is the message of a patch request.
+type patchRequest struct {
+	Schemas    []string    `json:"schemas"`
+	Operations []Operation `json:"Operations"`
+}
+
+// ParsePatch returns the operations of the patch request in data.
+func ParsePatch(data []byte) ([]Operation, error) {
+	req := &patchRequest{}
+	if err := json.Unmarshal(data, req); err != nil {
+		return nil, errors.Wrap(ErrInvalidSyntax, err.Error())
+	}
+
+	if len(req.Operations) == 0 {
+		return nil, errors.Wrap(ErrInvalidSyntax, "no operations")
+	}
+
+	/* Some identity platforms send the operations capitalized. */
+	for i := range req.Operations {
+		op := strings.ToLower(req.Operations[i].Op)
+		if op != "add" && op != "remove" && op != "replace" {
+			return nil, errors.Wrapf(ErrInvalidSyntax, "unknown operation %s", req.Operations[i].Op)
+		}
+
+		req.Operations[i].Op = op
+	}
+
+	return req.Operations, nil
+}
+
+// Apply applies the operations to the JSON representation of a resource.
+func Apply(resource map[string]interface{}, ops []Operation) error {
+	for _, op := range ops {
+		if err := apply(resource, op.Op, op.Path, op.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// apply applies an operation to the attribute in the path, or to the
+// attributes of the value when there is no path.
+func apply(resource map[string]interface{}, op, path string, value interface{}) error {
+	if path == "" {
+		attrs, ok := value.(map[string]interface{})
+		if op == "remove" {
+			return ErrNoTarget
+		} else if !ok {
+			return errors.Wrap(ErrInvalidValue, "expected the attributes to change")
+		}
+
+		for attr, v := range attrs {
+			if err := apply(resource, op, attr, v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	attr, filter, sub, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	key, _ := lookup(resource, attr)
+	if filter != nil {
+		return applyFiltered(resource, key, filter, op, sub, value)
+	}
+
+	if sub == "" {
+		set(resource, key, op, value)
+		return nil
+	}
+
+	switch target := resource[key].(type) {
+	case nil:
+		if op != "remove" {
+			m := map[string]interface{}{}
+			set(m, sub, op, value)
+			resource[key] = m
+		}
+	case map[string]interface{}:
+		subKey, _ := lookup(target, sub)
+		set(target, subKey, op, value)
+	case []interface{}:
+		for _, elem := range elements(resource, key) {
+			subKey, _ := lookup(elem, sub)
+			set(elem, subKey, op, value)
+		}
+	default:
+		return errors.Wrap(ErrInvalidPath, path)
+	}
+
+	return nil
+}
+
+// applyFiltered applies an operation to the values of a multi-valued attribute
+// matching the filter. A value is added when none matches a filter comparing
+// the equality of one sub-attribute, as the identity platforms replace the
+// work email that way.
+func applyFiltered(resource map[string]interface{}, key string, filter Filter, op, sub string,
+	value interface{}) error {
+	arr, _ := resource[key].([]interface{})
+	kept := make([]interface{}, 0, len(arr))
+	matched := false
+	for _, elem := range arr {
+		m, ok := elem.(map[string]interface{})
+		if !ok || !filter.Match(m) {
+			kept = append(kept, elem)
+			continue
+		}
+
+		matched = true
+		switch {
+		case sub == "" && op == "remove":
+			continue
+		case sub == "":
+			attrs, ok := value.(map[string]interface{})
+			if !ok {
+				return errors.Wrap(ErrInvalidValue, key)
+			}
+
+			for attr, v := range attrs {
+				attrKey, _ := lookup(m, attr)
+				m[attrKey] = v
+			}
+		default:
+			subKey, _ := lookup(m, sub)
+			set(m, subKey, op, value)
+		}
+
+		kept = append(kept, m)
+	}
+
+	if !matched && op != "remove" {
+		c, ok := filter.(*comparison)
+		if !ok || c.op != "eq" || len(c.path) != 1 || sub == "" {
+			return errors.Wrap(ErrNoTarget, key)
+		}
+
+		kept = append(kept, map[string]interface{}{c.path[0]: c.value, sub: value})
+	}
+
+	resource[key] = kept
+
+	return nil
+}
+
+// set applies an operation to an attribute. The values added to a multi-valued
+// attribute are appended to its values.
+func set(m map[string]interface{}, key, op string, value interface{}) {
+	switch op {
+	case "remove":
+		delete(m, key)
+	case "add":
+		if arr, ok := m[key].([]interface{}); ok {
+			if values, ok := value.([]interface{}); ok {
+				m[key] = append(arr, values...)
+			} else {
+				m[key] = append(arr, value)
+			}
+
+			return
+		}
+
+		m[key] = value
+	default:
+		m[key] = value
+	}
+}
+
+// parsePath returns the attribute, the filter of its values and the
+// sub-attribute of the path of an operation, like emails[type eq "work"].value.
+func parsePath(path string) (string, Filter, string, error) {
+	start := strings.IndexByte(path, '[')
+	if start < 0 {
+		parts := attrPath(path)
+		if parts[0] == "" {
+			return "", nil, "", errors.Wrap(ErrInvalidPath, path)
+		}
+
+		if len(parts) == 1 {
+			return parts[0], nil, "", nil
+		}
+
+		return parts[0], nil, parts[1], nil
+	}
+
+	end := closing(path, start)
+	attr := attrPath(path[:start])[0]
+	if end < 0 || attr == "" {
+		return "", nil, "", errors.Wrap(ErrInvalidPath, path)
+	}
+
+	filter, err := ParseFilter(path[start+1 : end])
+	if err != nil {
+		return "", nil, "", errors.Wrap(ErrInvalidPath, err.Error())
+	}
+
+	sub := path[end+1:]
+	if sub != "" {
+		if sub[0] != '.' || len(sub) == 1 {
+			return "", nil, "", errors.Wrap(ErrInvalidPath, path)
+		}
+
+		sub = sub[1:]
+	}
+
+	return attr, filter, sub, nil
+}
+
+// closing returns the position of the bracket closing the one at start,
+// skipping the quoted strings, -1 if it's not closed.
+func closing(path string, start int) int {
+	for i := start + 1; i < len(path); i++ {
+		switch path[i] {
+		case '"':
+			end := quoted(path, i)
+			if end < 0 {
+				return -1
+			}
+
+			i = end - 1
+		case ']':
+			return i
+		}
+	}
+
+	return -1
+}
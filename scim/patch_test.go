@@ -0,0 +1,92 @@
+package scim
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestPatch(t *testing.T) {
+	resource := map[string]interface{}{
+		"userName": "ritho",
+		"active":   true,
+		"emails": []interface{}{
+			map[string]interface{}{"value": "palvarez@ritho.net", "type": "work"},
+		},
+		"members": []interface{}{
+			map[string]interface{}{"value": "1"},
+			map[string]interface{}{"value": "2"},
+		},
+	}
+
+	ops, err := ParsePatch([]byte(`{
+		"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"],
+		"Operations": [
+			{"op": "Replace", "value": {"active": false, "name.givenName": "Pablo"}},
+			{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "ritho@ritho.net"},
+			{"op": "add", "path": "emails[type eq \"home\"].value", "value": "ritho@example.com"},
+			{"op": "add", "path": "members", "value": [{"value": "3"}]},
+			{"op": "remove", "path": "members[value eq \"1\"]"},
+			{"op": "remove", "path": "members[value eq \"9\"]"},
+			{"op": "add", "path": "urn:ietf:params:scim:schemas:core:2.0:User:title", "value": "Developer"},
+			{"op": "remove", "path": "USERNAME"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	err = Apply(resource, ops)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	got, _ := json.Marshal(resource)
+	expected := `{"active":false,"emails":[{"type":"work","value":"ritho@ritho.net"},` +
+		`{"type":"home","value":"ritho@example.com"}],"members":[{"value":"2"},{"value":"3"}],` +
+		`"name":{"givenName":"Pablo"},"title":"Developer"}`
+	if string(got) != expected {
+		t.Errorf("Expected %s, Got %s", expected, got)
+	}
+
+	testCases := map[string]error{
+		`{}`: ErrInvalidSyntax,
+		`{"Operations": [{"op": "move", "path": "title"}]}`:                                 ErrInvalidSyntax,
+		`{"Operations": [{"op": "remove"}]}`:                                                ErrNoTarget,
+		`{"Operations": [{"op": "replace", "value": "ritho"}]}`:                             ErrInvalidValue,
+		`{"Operations": [{"op": "replace", "path": "emails[type eq", "value": 1}]}`:         ErrInvalidPath,
+		`{"Operations": [{"op": "replace", "path": "emails[type eq \"a\"]x", "value": 1}]}`: ErrInvalidPath,
+		`{"Operations": [{"op": "replace", "path": "emails[type pr].value", "value": 1}]}`:  ErrNoTarget,
+		`{"Operations": [{"op": "replace", "path": "active.value", "value": 1}]}`:           ErrInvalidPath,
+	}
+
+	for patch, expected := range testCases {
+		ops, err := ParsePatch([]byte(patch))
+		if err == nil {
+			err = Apply(map[string]interface{}{"active": true}, ops)
+		}
+
+		if errors.Cause(err) != expected {
+			t.Errorf("Expected %s applying %s, Got %v", expected, patch, err)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+// Package scim implements the messages of the SCIM 2.0 protocol (RFC 7643 and
+// RFC 7644) the identity platforms provision the accounts with: the resources,
+// the filters and the patch operations.
+package scim
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"github.com/pkg/errors"
+
+	"github.com/radar-go/radar/i18n"
+)
+
+// Schemas of the resources and messages.
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+)
+
+// MaxResults is the maximum number of resources returned in a page.
+const MaxResults = 100
+
+// ErrInvalidSyntax raised when the request is not a valid SCIM message.
+var ErrInvalidSyntax = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMInvalidSyntax",
+	Other: "The request is not a valid SCIM message",
+})
+
+// ErrInvalidFilter raised when the filter of a search can't be parsed.
+var ErrInvalidFilter = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMInvalidFilter",
+	Other: "The filter is not valid",
+})
+
+// ErrInvalidPath raised when the path of a patch operation can't be parsed.
+var ErrInvalidPath = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMInvalidPath",
+	Other: "The path of the patch operation is not valid",
+})
+
+// ErrNoTarget raised when the path of a patch operation matches nothing.
+var ErrNoTarget = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMNoTarget",
+	Other: "The patch operation has no target",
+})
+
+// ErrInvalidValue raised when a required attribute is missing or has a wrong
+// value.
+var ErrInvalidValue = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMInvalidValue",
+	Other: "The value of the attribute is not valid",
+})
+
+// ErrMutability raised when a read-only resource or attribute is modified.
+var ErrMutability = i18n.NewError(&goi18n.Message{
+	ID:    "SCIMMutability",
+	Other: "The attribute can't be modified",
+})
+
+// errorTypes are the SCIM error types of the errors.
+var errorTypes = map[error]string{
+	ErrInvalidSyntax: "invalidSyntax",
+	ErrInvalidFilter: "invalidFilter",
+	ErrInvalidPath:   "invalidPath",
+	ErrNoTarget:      "noTarget",
+	ErrInvalidValue:  "invalidValue",
+	ErrMutability:    "mutability",
+}
+
+// Type returns the SCIM error type of err, empty if it has none.
+func Type(err error) string {
+	return errorTypes[errors.Cause(err)]
+}
+
+// Error is the message returned when a request fails.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewError creates and returns a new error message.
+func NewError(status int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}
+}
+
+// Meta stores the metadata of a resource.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// Name stores the components of the name of an user.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email stores an email of an user.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// Ref stores a reference to another resource, like the groups of an user or
+// the members of a group.
+type Ref struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// User is the resource of an account.
+type User struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	UserName    string   `json:"userName"`
+	Name        *Name    `json:"name,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Emails      []Email  `json:"emails,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+	Password    string   `json:"password,omitempty"`
+	Groups      []Ref    `json:"groups,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ParseUser returns the user resource in data.
+func ParseUser(data []byte) (*User, error) {
+	user := &User{}
+	if err := json.Unmarshal(data, user); err != nil {
+		return nil, errors.Wrap(ErrInvalidSyntax, err.Error())
+	}
+
+	if strings.TrimSpace(user.UserName) == "" {
+		return nil, errors.Wrap(ErrInvalidValue, "userName")
+	}
+
+	return user, nil
+}
+
+// FullName returns the name of the user: the display name, the formatted name
+// or the given and family names.
+func (u *User) FullName() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+
+	if u.Name == nil {
+		return ""
+	}
+
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// Email returns the primary email of the user, or the first one if none is
+// marked as primary.
+func (u *User) Email() string {
+	for _, email := range u.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+
+	return ""
+}
+
+// Group is the resource of a role.
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	DisplayName string   `json:"displayName"`
+	Members     []Ref    `json:"members"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ParseGroup returns the group resource in data.
+func ParseGroup(data []byte) (*Group, error) {
+	group := &Group{}
+	if err := json.Unmarshal(data, group); err != nil {
+		return nil, errors.Wrap(ErrInvalidSyntax, err.Error())
+	}
+
+	return group, nil
+}
+
+// ListResponse is the message returned by the searches.
+type ListResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+// Page returns the page of the resources starting at the 1-based startIndex,
+// with count resources at most.
+func Page(resources []interface{}, startIndex, count int) *ListResponse {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	if count < 0 {
+		count = 0
+	} else if count > MaxResults {
+		count = MaxResults
+	}
+
+	page := []interface{}{}
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+
+		page = resources[startIndex-1 : end]
+	}
+
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// ServiceProviderConfig returns the features of the protocol supported.
+func ServiceProviderConfig() map[string]interface{} {
+	supported := func(s bool) map[string]interface{} {
+		return map[string]interface{}{"supported": s}
+	}
+
+	return map[string]interface{}{
+		"schemas":        []string{SchemaServiceProviderConfig},
+		"patch":          supported(true),
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": MaxResults},
+		"changePassword": supported(true),
+		"sort":           supported(false),
+		"etag":           supported(false),
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication with the bearer token configured in the radar",
+			"primary":     true,
+		}},
+	}
+}
+
+// ToMap returns the JSON representation of a resource as a map, to search or
+// patch its attributes.
+func ToMap(resource interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{})
+	err = json.Unmarshal(data, &m)
+
+	return m, err
+}
+
+// FromMap fills the resource with its JSON representation as a map.
+func FromMap(m map[string]interface{}, resource interface{}) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, resource); err != nil {
+		return errors.Wrap(ErrInvalidValue, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package scim
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestParseUser(t *testing.T) {
+	user, err := ParseUser([]byte(`{
+		"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"],
+		"userName": "ritho",
+		"name": {"givenName": "Pablo", "familyName": "Álvarez"},
+		"emails": [{"value": "ritho@example.com"}, {"value": "palvarez@ritho.net", "primary": true}]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if user.FullName() != "Pablo Álvarez" || user.Email() != "palvarez@ritho.net" ||
+		user.Active != nil {
+		t.Errorf("Unexpected user %+v", user)
+	}
+
+	for data, expected := range map[string]error{
+		`{"userName": 1}`:  ErrInvalidSyntax,
+		`{"userName": ""}`: ErrInvalidValue,
+	} {
+		if _, err := ParseUser([]byte(data)); errors.Cause(err) != expected {
+			t.Errorf("Expected %s parsing %s, Got %v", expected, data, err)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	resources := []interface{}{1, 2, 3, 4, 5}
+	testCases := []struct {
+		startIndex, count int
+		expected          []interface{}
+	}{
+		{0, 2, []interface{}{1, 2}},
+		{4, 10, []interface{}{4, 5}},
+		{6, 10, []interface{}{}},
+		{2, 0, []interface{}{}},
+	}
+
+	for _, tc := range testCases {
+		page := Page(resources, tc.startIndex, tc.count)
+		if page.TotalResults != 5 || page.ItemsPerPage != len(tc.expected) ||
+			len(page.Resources) != len(tc.expected) {
+			t.Errorf("Unexpected page %+v for %d, %d", page, tc.startIndex, tc.count)
+			continue
+		}
+
+		for i := range tc.expected {
+			if page.Resources[i] != tc.expected[i] {
+				t.Errorf("Unexpected page %+v for %d, %d", page, tc.startIndex, tc.count)
+			}
+		}
+	}
+}
+
+func TestType(t *testing.T) {
+	if Type(errors.Wrap(ErrInvalidFilter, "wrong")) != "invalidFilter" || Type(errors.New("other")) != "" {
+		t.Error("Unexpected SCIM error type")
+	}
+}
@@ -0,0 +1,132 @@
+// Package token implements the signed and expiring tokens sent to the users,
+// like the email verification links.
+package token
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+
+	"github.com/radar-go/radar/i18n"
+)
+
+// ErrInvalid raised when the token is malformed or its signature is wrong.
+var ErrInvalid = i18n.NewError(&goi18n.Message{
+	ID:    "TokenInvalid",
+	Other: "The link is not valid",
+})
+
+// ErrExpired raised when the token has expired.
+var ErrExpired = i18n.NewError(&goi18n.Message{
+	ID:    "TokenExpired",
+	Other: "The link has expired, ask for a new one",
+})
+
+// payload is the content of the tokens.
+type payload struct {
+	Purpose string `json:"p"`
+	Subject string `json:"s"`
+	Expires int64  `json:"e"`
+}
+
+// Signer signs and verifies the tokens with a secret key.
+type Signer struct {
+	key []byte
+	now func() time.Time
+}
+
+// NewSigner creates and returns a new Signer using the key, or a random key
+// if it's empty.
+func NewSigner(key string) *Signer {
+	s := &Signer{
+		key: []byte(key),
+		now: time.Now,
+	}
+
+	if len(s.key) == 0 {
+		s.key = make([]byte, 32)
+		_, err := rand.Read(s.key)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return s
+}
+
+// Sign returns a token for the subject valid for the given purpose until it
+// expires after ttl.
+func (s *Signer) Sign(purpose, subject string, ttl time.Duration) string {
+	data, _ := json.Marshal(payload{
+		Purpose: purpose,
+		Subject: subject,
+		Expires: s.now().Add(ttl).Unix(),
+	})
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + s.signature(encoded)
+}
+
+// Verify returns the subject of the token if it's valid for the purpose and
+// has not expired.
+func (s *Signer) Verify(purpose, tok string) (string, error) {
+	parts := strings.Split(tok, ".")
+	if len(parts) != 2 || !hmac.Equal([]byte(parts[1]), []byte(s.signature(parts[0]))) {
+		return "", ErrInvalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", ErrInvalid
+	}
+
+	var p payload
+	err = json.Unmarshal(data, &p)
+	if err != nil || p.Purpose != purpose {
+		return "", ErrInvalid
+	}
+
+	if s.now().Unix() > p.Expires {
+		return "", ErrExpired
+	}
+
+	return p.Subject, nil
+}
+
+// Hash returns a keyed hash of data, to bind a token to a value without
+// revealing it.
+func (s *Signer) Hash(data string) string {
+	return s.signature(data)
+}
+
+// signature returns the signature of the encoded payload.
+func (s *Signer) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
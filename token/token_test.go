@@ -0,0 +1,80 @@
+package token
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	s := NewSigner("secret")
+	tok := s.Sign("verification", "1:palvarez@ritho.net", time.Hour)
+
+	subject, err := s.Verify("verification", tok)
+	if err != nil || subject != "1:palvarez@ritho.net" {
+		t.Errorf("Expected 1:palvarez@ritho.net, Got %s: %v", subject, err)
+	}
+
+	testCases := map[string]struct {
+		purpose  string
+		tok      string
+		expected error
+	}{
+		"WrongPurpose": {"reset", tok, ErrInvalid},
+		"Malformed":    {"verification", "token", ErrInvalid},
+		"Tampered":     {"verification", "x" + tok, ErrInvalid},
+		"OtherKey": {"verification", NewSigner("other").Sign("verification", "1", time.Hour),
+			ErrInvalid},
+		"Expired": {"verification", s.Sign("verification", "1", -time.Second), ErrExpired},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Verify(tc.purpose, tc.tok)
+			if err != tc.expected {
+				t.Errorf("Expected %s, Got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestSignerExpiration(t *testing.T) {
+	s := NewSigner("")
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	tok := s.Sign("verification", "1", time.Hour)
+
+	s.now = func() time.Time { return now.Add(59 * time.Minute) }
+	_, err := s.Verify("verification", tok)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	s.now = func() time.Time { return now.Add(61 * time.Minute) }
+	_, err = s.Verify("verification", tok)
+	if err != ErrExpired {
+		t.Errorf("Expected %s, Got %v", ErrExpired, err)
+	}
+
+	if strings.Count(tok, ".") != 1 {
+		t.Errorf("Expected payload and signature in %s", tok)
+	}
+}
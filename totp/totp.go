@@ -0,0 +1,152 @@
+// Package totp implements the time-based one-time passwords of RFC 6238 used
+// by the authenticator apps, and the recovery codes to use when the app is
+// lost.
+package totp
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Parameters of the codes, the defaults of the authenticator apps.
+const (
+	Digits = 6
+	Period = 30 * time.Second
+)
+
+// skew is the number of periods before and after the current one whose codes
+// are accepted, to allow for the clocks drift and the typing time.
+const skew = 1
+
+// encoding is the base32 encoding of the secrets, without padding as the apps
+// expect them.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random secret of 160 bits encoded in base32.
+func GenerateSecret() (string, error) {
+	key := make([]byte, 20)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(key), nil
+}
+
+// Code returns the code of the secret for the period of t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decode(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return code(key, counter(t)), nil
+}
+
+// Validate checks the code against the secret around t, and returns the
+// counter of the period of the code, so the callers can refuse to use the
+// same code twice.
+func Validate(secret, c string, t time.Time) (int64, bool) {
+	key, err := decode(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	c = strings.Replace(c, " ", "", -1)
+	now := counter(t)
+	for step := now - skew; step <= now+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(code(key, step)), []byte(c)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// URI returns the otpauth uri of the secret for the account, to add it to
+// the authenticator apps typing it or scanning it as a QR code.
+func URI(issuer, account, secret string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(Digits))
+	params.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// RecoveryCodes returns n new random recovery codes.
+func RecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		buf := make([]byte, 5)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+
+		c := strings.ToLower(encoding.EncodeToString(buf))
+		codes = append(codes, c[:4]+"-"+c[4:])
+	}
+
+	return codes, nil
+}
+
+// decode returns the key of the secret, ignoring the case and the spaces
+// the users add typing it.
+func decode(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.Replace(secret, " ", "", -1))
+	return encoding.DecodeString(strings.TrimRight(secret, "="))
+}
+
+// counter returns the number of periods elapsed until t.
+func counter(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// code returns the code of the key for the counter, as defined by the HOTP
+// algorithm of RFC 4226.
+func code(key []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
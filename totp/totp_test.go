@@ -0,0 +1,109 @@
+package totp
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 secret of the test vectors of RFC 6238.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	/* The RFC vectors have 8 digits, the codes are their last 6. */
+	testCases := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range testCases {
+		c, err := Code(rfcSecret, time.Unix(unix, 0))
+		if err != nil || c != expected {
+			t.Errorf("Expected %s at %d, Got %s: %v", expected, unix, c, err)
+		}
+	}
+
+	_, err := Code("not base32!", time.Now())
+	if err == nil {
+		t.Error("Expected an error decoding the secret")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	now := time.Now()
+	c, _ := Code(strings.ToLower(secret), now)
+	step, ok := Validate(secret, c[:3]+" "+c[3:], now)
+	if !ok || step != now.Unix()/30 {
+		t.Errorf("Expected the code %s valid at step %d, Got %d %t", c, now.Unix()/30, step, ok)
+	}
+
+	_, ok = Validate(secret, c, now.Add(Period))
+	if !ok {
+		t.Error("Expected the code valid in the next period")
+	}
+
+	_, ok = Validate(secret, c, now.Add(3*Period))
+	if ok {
+		t.Error("Expected the code not valid three periods later")
+	}
+
+	_, ok = Validate(secret, "000000x", now)
+	if ok {
+		t.Error("Expected a wrong code not valid")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Radar", "ritho@radar", rfcSecret)
+	expected := "otpauth://totp/Radar:ritho@radar?algorithm=SHA1&digits=6&issuer=Radar&period=30&secret=" + rfcSecret
+	if uri != expected {
+		t.Errorf("Expected %s, Got %s", expected, uri)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := RecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range codes {
+		if len(c) != 9 || c[4] != '-' || seen[c] {
+			t.Errorf("Unexpected recovery code %s in %v", c, codes)
+		}
+
+		seen[c] = true
+	}
+
+	if len(seen) != 10 {
+		t.Errorf("Expected 10 codes, Got %d", len(seen))
+	}
+}
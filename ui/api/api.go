@@ -24,27 +24,34 @@ import (
 	"net"
 	"time"
 
-	"github.com/golang/glog"
 	"github.com/valyala/fasthttp"
 
 	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/logging"
 	"github.com/radar-go/radar/ui/api/controller"
 )
 
 // API structure to manage the Radar API.
 type API struct {
+	cfg      *config.Config
 	listener net.Listener
 }
 
-// New creates and returns a new API object.
+// New creates and returns a new API object with the default configuration.
 func New() *API {
-	return &API{}
+	return NewWithConfig(config.New())
+}
+
+// NewWithConfig creates and returns a new API object.
+func NewWithConfig(cfg *config.Config) *API {
+	return &API{
+		cfg: cfg,
+	}
 }
 
 // Start starts the Radar API.
 func (a *API) Start() error {
 	var err error
-	cfg := config.New()
 	c := controller.New()
 	server := fasthttp.Server{
 		Handler:           fasthttp.CompressHandler(c.Router.Handler),
@@ -53,12 +60,12 @@ func (a *API) Start() error {
 		ReduceMemoryUsage: true,
 	}
 
-	a.listener, err = net.Listen("tcp4", fmt.Sprint(":", cfg.APIPort))
+	a.listener, err = net.Listen("tcp4", fmt.Sprint(":", a.cfg.APIPort))
 	if err != nil {
 		return err
 	}
 
-	glog.Infof("Starting api on port %d...", cfg.APIPort)
+	logging.Logger.Info("starting api", "port", a.cfg.APIPort)
 	return server.Serve(a.listener)
 }
 
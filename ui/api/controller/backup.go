@@ -0,0 +1,96 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"time"
+
+	errWrap "github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/backup/create"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/logging"
+)
+
+// registerBackup defines the router path streaming the backup archives.
+func (c *Controller) registerBackup() {
+	c.Router.GET("/backup", handler("/backup", c.backup))
+}
+
+// backup streams a backup archive of the datastore to an administrator, that
+// sends its session or an API key with the admin scope as the bearer token.
+// The sessions are only included with the sessions=true query param.
+func (c *Controller) backup(ctx *fasthttp.RequestCtx) {
+	ctx.SetContentType("application/json; charset=utf-8")
+	loc := localizer(ctx)
+
+	uc, err := casesprovider.GetUseCase("BackupCreate")
+	if err != nil {
+		internalServerError(ctx, fmt.Sprintf("Error obtaining the use case BackupCreate: %s.", err))
+		return
+	}
+
+	params := map[string]interface{}{}
+	if token := bearerToken(ctx); token != "" {
+		params["token"] = token
+	}
+
+	if string(ctx.QueryArgs().Peek("sessions")) == "true" {
+		params["sessions"] = true
+	}
+
+	user := requestUser(ctx, params)
+	logging.SetUser(ctx, user)
+	err = uc.AddParams(params)
+	if err != nil {
+		badRequest(ctx, loc.Error(err))
+		return
+	}
+
+	res, err := uc.Run(useCaseContext(ctx, loc, user))
+	if errWrap.Cause(err) == errors.ErrTooManyRequests {
+		tooManyRequests(ctx, loc.Error(err))
+		return
+	} else if err != nil {
+		badRequest(ctx, loc.Error(err))
+		return
+	}
+
+	result, id := res.(*create.Result), logging.RequestID(ctx)
+	ctx.SetContentType("application/gzip")
+	ctx.Response.Header.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="radar-%s.json.gz"`,
+		time.Now().UTC().Format("20060102T150405Z")))
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		/* The status is already sent, the errors can only be logged and the
+		archive is left truncated. */
+		err := result.WriteArchive(w)
+		if err == nil {
+			err = w.Flush()
+		}
+
+		if err != nil {
+			logging.Logger.Error("error streaming the backup archive", "request_id", id, "error", err)
+		}
+	})
+}
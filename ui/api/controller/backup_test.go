@@ -0,0 +1,111 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/datastore/audit"
+)
+
+func TestBackup(t *testing.T) {
+	casesprovider.SetDatastore(datastore.New())
+	defer casesprovider.SetDatastore(datastore.New())
+
+	ds := casesprovider.Datastore()
+	bg := context.Background()
+	id, err := ds.AccountRegistration(bg, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ds.ActivateAccount(bg, id)
+	acc, _ := ds.GetAccountByID(bg, id)
+	now := time.Now()
+	err = acc.AddAPIKey("backup", "backup", "radar_backup", []string{account.ScopeAdmin},
+		now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if err = ds.UpdateAccountData(bg, acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name     string
+		key      string
+		code     int
+		expected string
+	}{
+		{"NoToken", "", 400, "User not logged in"},
+		{"WrongKey", "radar_wrong", 400, "Wrong or expired API key"},
+		{"NotAdmin", "radar_backup", 400, "Administration privileges required"},
+	}
+
+	for _, tc := range testCases {
+		ctx := newRequest()
+		ctx.Request.Header.SetMethod("GET")
+		ctx.Request.Header.SetRequestURI("/backup")
+		if tc.key != "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+tc.key)
+		}
+
+		c.Router.Handler(ctx)
+		if ctx.Response.StatusCode() != tc.code || !strings.Contains(string(ctx.Response.Body()), tc.expected) {
+			t.Errorf("%s: Expected %d %s, Got %d %s", tc.name, tc.code, tc.expected,
+				ctx.Response.StatusCode(), ctx.Response.Body())
+		}
+	}
+
+	ds.SetAdmin(bg, id, true)
+	ctx := newRequest()
+	ctx.Request.Header.SetMethod("GET")
+	ctx.Request.Header.SetRequestURI("/backup?sessions=true")
+	ctx.Request.Header.Set("Authorization", "Bearer radar_backup")
+	c.Router.Handler(ctx)
+	if ctx.Response.StatusCode() != 200 ||
+		string(ctx.Response.Header.ContentType()) != "application/gzip" {
+		t.Fatalf("Expected the archive, Got %d %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(ctx.Response.Body()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	data, err := ioutil.ReadAll(gz)
+	if err != nil || !strings.Contains(string(data), `"username":"ritho"`) {
+		t.Errorf("Expected the account in the archive, Got %s: %v", data, err)
+	}
+
+	records, _ := ds.AuditRecords(bg, audit.Filter{Action: "BackupCreate"})
+	if len(records) != 1 {
+		t.Errorf("Expected the backup to be audited, Got %v", records)
+	}
+}
@@ -23,10 +23,11 @@ import (
 	"fmt"
 
 	"github.com/buaazp/fasthttprouter"
-	"github.com/golang/glog"
 	"github.com/valyala/fasthttp"
 
 	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/metrics"
 )
 
 // Controller struct to manager the Radar API Controller.
@@ -44,30 +45,36 @@ func New() *Controller {
 	return c
 }
 
-// logPaths logs the requested path to the info log.
-func logPath(path []byte) {
-	glog.Infof("Request path: %s", path)
+// handler returns h recording its metrics and logging every request to the
+// access log.
+func handler(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return metrics.Handler("api", route, logging.Handler("api", h))
 }
 
 // register defines all the router paths the API implements.
 func (c *Controller) register() {
 	c.Router.HandleMethodNotAllowed = true
-	c.Router.NotFound = c.notFound
-	c.Router.MethodNotAllowed = c.methodNotAllowed
+	c.Router.NotFound = handler("not_found", c.notFound)
+	c.Router.MethodNotAllowed = handler("method_not_allowed", c.methodNotAllowed)
 	c.Router.PanicHandler = c.panic
 
-	c.Router.GET("/healthcheck", c.healthcheck)
+	c.Router.GET("/healthcheck", handler("/healthcheck", c.healthcheck))
+	c.Router.GET("/metrics", metrics.HTTPHandler())
 
 	ds := datastore.New()
 	endpoints := ds.Endpoints()
 	for key := range endpoints {
-		c.Router.POST(key, c.apiHandler)
+		c.Router.POST(key, handler(key, c.apiHandler))
 	}
+
+	c.registerSCIM()
+	c.registerBackup()
 }
 
 // panic handles when the server have a fatal error.
 func (c *Controller) panic(ctx *fasthttp.RequestCtx, from interface{}) {
-	logPath(ctx.Path())
+	logging.Logger.Error("fatal error", "request_id", logging.RequestID(ctx),
+		"path", string(ctx.Path()), "error", fmt.Sprint(from))
 	ctx.SetStatusCode(fasthttp.StatusInternalServerError)
 	ctx.SetContentType("application/json; charset=utf-8")
 	ctx.SetBodyString(fmt.Sprintf(`{"error": "API fatal error calling %s"}`,
@@ -77,7 +84,6 @@ func (c *Controller) panic(ctx *fasthttp.RequestCtx, from interface{}) {
 // methodNotAllowed handles the response when a method call is not allowed from
 // the client.
 func (c *Controller) methodNotAllowed(ctx *fasthttp.RequestCtx) {
-	logPath(ctx.Path())
 	ctx.SetStatusCode(fasthttp.StatusMethodNotAllowed)
 	ctx.SetContentType("application/json; charset=utf-8")
 	ctx.SetBodyString(fmt.Sprintf(`{"error": "Method not allowed calling %s"}`,
@@ -86,7 +92,6 @@ func (c *Controller) methodNotAllowed(ctx *fasthttp.RequestCtx) {
 
 // notFound handles the response when a path have not been found.
 func (c *Controller) notFound(ctx *fasthttp.RequestCtx) {
-	logPath(ctx.Path())
 	ctx.SetStatusCode(fasthttp.StatusNotFound)
 	ctx.SetContentType("application/json; charset=utf-8")
 	ctx.SetBodyString(fmt.Sprintf(`{"error": "Path %s not found"}`,
@@ -95,7 +100,6 @@ func (c *Controller) notFound(ctx *fasthttp.RequestCtx) {
 
 // healthcheck handler.
 func (c *Controller) healthcheck(ctx *fasthttp.RequestCtx) {
-	logPath(ctx.Path())
 	ctx.SetStatusCode(fasthttp.StatusOK)
 	ctx.SetContentType("application/json; charset=utf-8")
 	ctx.SetBodyString(`{"status": "ok"}`)
@@ -112,3 +116,9 @@ func badRequest(ctx *fasthttp.RequestCtx, msg string) {
 	ctx.SetStatusCode(fasthttp.StatusBadRequest)
 	ctx.SetBodyString(fmt.Sprintf(`{"error":"%s"}`, msg))
 }
+
+// tooManyRequests response
+func tooManyRequests(ctx *fasthttp.RequestCtx, msg string) {
+	ctx.SetStatusCode(fasthttp.StatusTooManyRequests)
+	ctx.SetBodyString(fmt.Sprintf(`{"error":"%s"}`, msg))
+}
@@ -20,15 +20,24 @@ package controller
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/valyala/fasthttp"
 )
 
+// newRequest returns a new request context initialized like the server does,
+// so it can be the parent of the context of the use cases.
+func newRequest() *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, nil, nil)
+
+	return ctx
+}
+
 func TestController(t *testing.T) {
-	logPath([]byte("/healthcheck"))
 
-	ctx := &fasthttp.RequestCtx{}
+	ctx := newRequest()
 
 	c := New()
 	c.panic(ctx, "test")
@@ -91,3 +100,54 @@ func TestController(t *testing.T) {
 			ctx.Response.Body())
 	}
 }
+
+func TestMetrics(t *testing.T) {
+	c := New()
+	for _, uri := range []string{"/healthcheck", "/unknown", "/metrics"} {
+		ctx := newRequest()
+		ctx.Request.Header.SetMethod("GET")
+		ctx.Request.SetRequestURI(uri)
+		c.Router.Handler(ctx)
+		if uri != "/metrics" {
+			continue
+		}
+
+		if ctx.Response.StatusCode() != 200 {
+			t.Errorf("Expected 200, Got %d", ctx.Response.StatusCode())
+		}
+
+		body := string(ctx.Response.Body())
+		for _, expected := range []string{
+			`radar_http_requests_total{method="GET",route="/healthcheck",server="api",status="200"}`,
+			`radar_http_requests_total{method="GET",route="not_found",server="api",status="404"}`,
+			"radar_accounts_registered",
+			"radar_sessions_active",
+		} {
+			if !strings.Contains(body, expected) {
+				t.Errorf("Expected %s in %s", expected, body)
+			}
+		}
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	c := New()
+	ctx := newRequest()
+	ctx.Request.Header.SetMethod("GET")
+	ctx.Request.SetRequestURI("/healthcheck")
+	ctx.Request.Header.Set("X-Request-ID", "healthcheck-1")
+	c.Router.Handler(ctx)
+	id := string(ctx.Response.Header.Peek("X-Request-ID"))
+	if id != "healthcheck-1" {
+		t.Errorf("Expected healthcheck-1, Got %s", id)
+	}
+
+	ctx = newRequest()
+	ctx.Request.Header.SetMethod("GET")
+	ctx.Request.SetRequestURI("/unknown")
+	c.Router.Handler(ctx)
+	id = string(ctx.Response.Header.Peek("X-Request-ID"))
+	if len(id) != 32 {
+		t.Errorf("Expected a generated request id, Got %s", id)
+	}
+}
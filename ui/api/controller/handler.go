@@ -20,20 +20,90 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	errWrap "github.com/pkg/errors"
 	"github.com/valyala/fasthttp"
 
 	"github.com/radar-go/radar/casesprovider"
 	_ "github.com/radar-go/radar/casesprovider/cases"
+	"github.com/radar-go/radar/casesprovider/errors"
 	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/logging"
 )
 
-func (c *Controller) checkRequestHeaders(ctx *fasthttp.RequestCtx) error {
+// msgJSONExpected is the error when the request is not in json format.
+var msgJSONExpected = &goi18n.Message{
+	ID:    "APIJSONExpected",
+	Other: "Expected json format for the request.",
+}
+
+// msgBodyEmpty is the error when the request has no body.
+var msgBodyEmpty = &goi18n.Message{
+	ID:    "APIBodyEmpty",
+	Other: "Unable to get the request body.",
+}
+
+// msgParamsError is the error when the request body can't be decoded.
+var msgParamsError = &goi18n.Message{
+	ID:    "APIParamsError",
+	Other: "Error obtaining the user params",
+}
+
+// msgUnknownPath is the error when the path has no use case associated.
+var msgUnknownPath = &goi18n.Message{
+	ID:    "APIUnknownPath",
+	Other: "Unknown path: {{.Path}}.",
+}
+
+// msgAddParamsError is the error when the params are not valid for the use
+// case.
+var msgAddParamsError = &goi18n.Message{
+	ID:    "APIAddParamsError",
+	Other: "Error adding the ad params: {{.Error}}.",
+}
+
+// localizer returns the localizer for the languages accepted by the client.
+func localizer(ctx *fasthttp.RequestCtx) *i18n.Localizer {
+	loc := i18n.NewLocalizer(string(ctx.Request.Header.Peek("Accept-Language")))
+	ctx.Response.Header.Set("Content-Language", loc.Language())
+
+	return loc
+}
+
+// useCaseContext returns the context to run the use cases of the request,
+// derived from it so they're canceled when the server shuts down, carrying its
+// ID, the localizer and the user doing it.
+func useCaseContext(ctx *fasthttp.RequestCtx, loc *i18n.Localizer, user string) context.Context {
+	uctx := logging.WithRequestID(ctx, logging.RequestID(ctx))
+	uctx = logging.WithUser(uctx, user)
+	uctx = logging.WithClientIP(uctx, ctx.RemoteIP().String())
+
+	return i18n.WithLocalizer(uctx, loc)
+}
+
+// requestUser returns the username of the account doing the request, obtained
+// from its session or, when logging in, from the login param.
+func requestUser(ctx *fasthttp.RequestCtx, params map[string]interface{}) string {
+	if token, ok := params["token"].(string); ok {
+		acc, err := casesprovider.Datastore().GetAccountBySession(ctx, token)
+		if err == nil {
+			return acc.Username()
+		}
+	}
+
+	login, _ := params["login"].(string)
+	return login
+}
+
+func (c *Controller) checkRequestHeaders(ctx *fasthttp.RequestCtx, loc *i18n.Localizer) error {
 	ct := ctx.Request.Header.Peek("Content-Type")
 	if !bytes.Contains(ct, []byte("application/json")) {
-		badRequest(ctx, "Expected json format for the request.")
+		badRequest(ctx, loc.Message(msgJSONExpected))
 		return fmt.Errorf("Expected json format for the request")
 	}
 
@@ -41,8 +111,6 @@ func (c *Controller) checkRequestHeaders(ctx *fasthttp.RequestCtx) error {
 }
 
 func (c *Controller) apiHandler(ctx *fasthttp.RequestCtx) {
-	logPath(ctx.Path())
-
 	if bytes.Equal(ctx.Method(), []byte("GET")) {
 		c.getHandler(ctx)
 	} else if bytes.Equal(ctx.Method(), []byte("POST")) ||
@@ -62,15 +130,16 @@ func (c *Controller) postHandler(ctx *fasthttp.RequestCtx) {
 	var uc casesprovider.UseCase
 
 	ctx.SetContentType("application/json; charset=utf-8")
+	loc := localizer(ctx)
 
-	err = c.checkRequestHeaders(ctx)
+	err = c.checkRequestHeaders(ctx, loc)
 	if err != nil {
 		return
 	}
 
 	body := ctx.PostBody()
 	if len(body) == 0 {
-		badRequest(ctx, "Unable to get the request body.")
+		badRequest(ctx, loc.Message(msgBodyEmpty))
 		return
 	}
 
@@ -79,7 +148,7 @@ func (c *Controller) postHandler(ctx *fasthttp.RequestCtx) {
 	// XXX: validate the json against an schema.
 	err = json.Unmarshal(body, &params)
 	if err != nil {
-		badRequest(ctx, "Error obtaining the user params")
+		badRequest(ctx, loc.Message(msgParamsError))
 		return
 	}
 
@@ -87,26 +156,43 @@ func (c *Controller) postHandler(ctx *fasthttp.RequestCtx) {
 	endpoints := ds.Endpoints()
 	caseName, ok := endpoints[fmt.Sprintf("%s", ctx.Path())]
 	if !ok {
-		badRequest(ctx, fmt.Sprintf("Unknown path: %s.", ctx.Path()))
+		badRequest(ctx, loc.Message(msgUnknownPath, map[string]interface{}{
+			"Path": string(ctx.Path()),
+		}))
 		return
 	}
 
+	user := requestUser(ctx, params)
+	logging.SetUser(ctx, user)
 	uc, err = casesprovider.GetUseCase(caseName)
 	if err != nil {
 		internalServerError(ctx, fmt.Sprintf("Error obtaining the use case %s: %s.",
-			uc.GetName(), err))
+			caseName, err))
 		return
 	}
 
+	/* The scripts can send their API key in the authorization header instead
+	of the token param. */
+	if _, ok := uc.GetParams()["token"]; ok && params["token"] == nil {
+		if key := bearerToken(ctx); key != "" {
+			params["token"] = key
+		}
+	}
+
 	err = uc.AddParams(params)
 	if err != nil {
-		internalServerError(ctx, fmt.Sprintf("Error adding the ad params: %s.", err))
+		internalServerError(ctx, loc.Message(msgAddParamsError, map[string]interface{}{
+			"Error": loc.Error(err),
+		}))
 		return
 	}
 
-	res, err := uc.Run()
-	if err != nil {
-		badRequest(ctx, fmt.Sprintf("%s", err))
+	res, err := uc.Run(useCaseContext(ctx, loc, user))
+	if errWrap.Cause(err) == errors.ErrTooManyRequests {
+		tooManyRequests(ctx, loc.Error(err))
+		return
+	} else if err != nil {
+		badRequest(ctx, loc.Error(err))
 		return
 	}
 
@@ -20,20 +20,32 @@ package controller
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/valyala/fasthttp"
 
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
+	"github.com/radar-go/radar/datastore"
+	"github.com/radar-go/radar/datastore/account"
 	"github.com/radar-go/radar/helper"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/logging"
 )
 
 var c *Controller = New()
 
+// uuidRe matches the UUIDs of the accounts in the responses.
+var uuidRe = regexp.MustCompile(`"id":"[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}"`)
+
 func TestAccountControllerFormatError(t *testing.T) {
-	ctx := &fasthttp.RequestCtx{}
+	ctx := newRequest()
 	ctx.Request.Header.SetRequestURI("/account/register")
 
 	c.postHandler(ctx)
@@ -48,7 +60,7 @@ func TestAccountControllerFormatError(t *testing.T) {
 }
 
 func TestAccountControllerBodyError(t *testing.T) {
-	ctx := &fasthttp.RequestCtx{}
+	ctx := newRequest()
 	ctx.Request.Header.Set("Content-Type", "application/json")
 	ctx.Request.Header.SetRequestURI("/account/register")
 
@@ -78,7 +90,7 @@ func obtainResponse(t *testing.T, ctx *fasthttp.RequestCtx) map[string]interface
 
 func TestPostHandler(t *testing.T) {
 	var token string
-	var id int
+	var id string
 	testCases := []struct {
 		name      string
 		endpoint  string
@@ -88,6 +100,7 @@ func TestPostHandler(t *testing.T) {
 		saveID    bool
 		useToken  bool
 		useID     bool
+		useCode   bool
 	}{
 		{
 			name:      "RegisterSuccess",
@@ -98,6 +111,29 @@ func TestPostHandler(t *testing.T) {
 			saveID:    true,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "LoginInactive",
+			endpoint:  "/account/login",
+			input:     `{"login": "ritho", "password": "ritho"}`,
+			code:      400,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "ActivateSuccess",
+			endpoint:  "/account/activate",
+			input:     `{"code": "code"}`,
+			code:      200,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   true,
 		},
 		{
 			name:      "LoginSuccess",
@@ -108,6 +144,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "LoginAlreadyLogin",
@@ -118,16 +155,18 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "EditSuccess",
 			endpoint:  "/account/edit",
-			input:     `{"id": 1, "name": "Pablo", "email": "i02sopop@gmail.com", "username": "i02sopop", "password": "121212", "token": "00000000-0000-0000-0000-000000000000"}`,
+			input:     `{"id": "00000000-0000-0000-0000-000000000001", "name": "Pablo", "email": "i02sopop@gmail.com", "username": "i02sopop", "password": "121212", "token": "00000000-0000-0000-0000-000000000000"}`,
 			code:      200,
 			saveToken: false,
 			saveID:    false,
 			useToken:  true,
 			useID:     true,
+			useCode:   false,
 		},
 		{
 			name:      "LogoutSuccess",
@@ -138,6 +177,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  true,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "RegisterInvalidEmailFormatError",
@@ -148,6 +188,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "RegisterUnresolvedHostError",
@@ -158,6 +199,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "RegisterUsernameShort",
@@ -168,6 +210,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "RegisterPasswordShort",
@@ -178,6 +221,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "RegisterDuplicateAccount",
@@ -188,6 +232,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "LoginUnknownParam",
@@ -198,6 +243,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "LoginAccountNotExists",
@@ -208,6 +254,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "LoginWrongPassword",
@@ -218,6 +265,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "LogoutAccountNotExists",
@@ -228,6 +276,7 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "LogoutError",
@@ -238,26 +287,29 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "EditError",
 			endpoint:  "/account/edit",
-			input:     `{"id":1, "name":"ritho", "email": "i02sopop@gmail.com", "username": "ritho", "password": "ritho", "token": "00000000-0000-0000-0000-000000000000"}`,
+			input:     `{"id": "00000000-0000-0000-0000-000000000001", "name":"ritho", "email": "i02sopop@gmail.com", "username": "ritho", "password": "ritho", "token": "00000000-0000-0000-0000-000000000000"}`,
 			code:      400,
 			saveToken: false,
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "RemoveAccountError",
 			endpoint:  "/account/remove",
-			input:     `{"id":1, "token": "00000000-0000-0000-0000-000000000000"}`,
+			input:     `{"id": "00000000-0000-0000-0000-000000000001", "token": "00000000-0000-0000-0000-000000000000"}`,
 			code:      400,
 			saveToken: false,
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "LoginSuccess",
@@ -268,16 +320,51 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "DeactivateSuccess",
 			endpoint:  "/account/deactivate",
-			input:     `{"id":1, "token": "00000000-0000-0000-0000-000000000000"}`,
+			input:     `{"id": "00000000-0000-0000-0000-000000000001", "token": "00000000-0000-0000-0000-000000000000"}`,
 			code:      200,
 			saveToken: false,
 			saveID:    false,
 			useToken:  true,
 			useID:     true,
+			useCode:   false,
+		},
+		{
+			name:      "LoginInactive",
+			endpoint:  "/account/login",
+			input:     `{"login": "i02sopop", "password": "121212"}`,
+			code:      400,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "ActivateError",
+			endpoint:  "/account/activate",
+			input:     `{"code": "abc.def"}`,
+			code:      400,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "ActivateSuccess",
+			endpoint:  "/account/activate",
+			input:     `{"code": "code"}`,
+			code:      200,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   true,
 		},
 		{
 			name:      "LoginSuccess",
@@ -288,32 +375,79 @@ func TestPostHandler(t *testing.T) {
 			saveID:    false,
 			useToken:  false,
 			useID:     false,
+			useCode:   false,
 		},
 		{
-			name:      "ActivateSuccess",
-			endpoint:  "/account/activate",
-			input:     `{"id":1, "token": "00000000-0000-0000-0000-000000000000"}`,
+			name:      "ResendSuccess",
+			endpoint:  "/account/verification/resend",
+			input:     `{"username": "unknown"}`,
 			code:      200,
 			saveToken: false,
 			saveID:    false,
-			useToken:  true,
-			useID:     true,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "PasswordResetSuccess",
+			endpoint:  "/account/password/reset",
+			input:     `{"username": "unknown"}`,
+			code:      200,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "PasswordResetConfirmError",
+			endpoint:  "/account/password/reset/confirm",
+			input:     `{"code": "abc.def", "password": "newpassword"}`,
+			code:      400,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "LoginVerifyChallengeError",
+			endpoint:  "/account/login/verify",
+			input:     `{"challenge": "abc.def", "code": "123456"}`,
+			code:      400,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
+		},
+		{
+			name:      "OIDCStartNotConfigured",
+			endpoint:  "/account/oidc/start",
+			input:     `{}`,
+			code:      400,
+			saveToken: false,
+			saveID:    false,
+			useToken:  false,
+			useID:     false,
+			useCode:   false,
 		},
 		{
 			name:      "RemoveAccountSuccess",
 			endpoint:  "/account/remove",
-			input:     `{"id":1, "token": "00000000-0000-0000-0000-000000000000"}`,
+			input:     `{"id": "00000000-0000-0000-0000-000000000001", "token": "00000000-0000-0000-0000-000000000000"}`,
 			code:      200,
 			saveToken: false,
 			saveID:    false,
 			useToken:  true,
 			useID:     true,
+			useCode:   false,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			ctx := &fasthttp.RequestCtx{}
+			ctx := newRequest()
 			ctx.Request.Header.Set("Content-Type", "application/json")
 			ctx.Request.Header.SetRequestURI(tc.endpoint)
 
@@ -323,8 +457,17 @@ func TestPostHandler(t *testing.T) {
 			}
 
 			if tc.useID {
-				var re = regexp.MustCompile(`"id"[ ]*:[ ]*[0-9]+`)
-				tc.input = re.ReplaceAllString(tc.input, fmt.Sprintf(`"id":%d`, id))
+				var re = regexp.MustCompile(`"id"[ ]*:[ ]*"[^"]*"`)
+				tc.input = re.ReplaceAllString(tc.input, fmt.Sprintf(`"id":%q`, id))
+			}
+
+			if tc.useCode {
+				acc, err := casesprovider.Datastore().GetAccountByUUID(context.Background(), id)
+				if err != nil {
+					t.Fatalf("Unexpected error getting the account: %s", err)
+				}
+
+				tc.input = fmt.Sprintf(`{"code": %q}`, verification.Code(acc))
 			}
 
 			ctx.Request.SetBody([]byte(tc.input))
@@ -333,10 +476,13 @@ func TestPostHandler(t *testing.T) {
 				t.Errorf("Expected %d, Got %d", tc.code, ctx.Response.StatusCode())
 			}
 
-			helper.SaveGoldenData(t, tc.name, ctx.Response.Body())
+			/* The UUIDs of the accounts are random, so they are replaced to
+			compare the responses. */
+			body := uuidRe.ReplaceAll(ctx.Response.Body(), []byte(`"id":"<uuid>"`))
+			helper.SaveGoldenData(t, tc.name, body)
 			expected := helper.GetGoldenData(t, tc.name)
-			if !bytes.Contains(ctx.Response.Body(), expected) {
-				t.Errorf(`Expected %s, Got %s`, expected, ctx.Response.Body())
+			if !bytes.Contains(body, expected) {
+				t.Errorf(`Expected %s, Got %s`, expected, body)
 			}
 
 			if tc.saveToken {
@@ -358,8 +504,132 @@ func TestPostHandler(t *testing.T) {
 					return
 				}
 
-				id = int(responseID.(float64))
+				id = responseID.(string)
 			}
 		})
 	}
 }
+
+func TestPostHandlerTooManyRequests(t *testing.T) {
+	for i, code := range []int{400, 400, 400, 400, 429} {
+		ctx := newRequest()
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Request.Header.SetRequestURI("/account/login")
+		ctx.Request.SetBody([]byte(`{"login": "throttled", "password": "wrong"}`))
+		c.postHandler(ctx)
+		if ctx.Response.StatusCode() != code {
+			t.Errorf("Expected %d for the attempt %d, Got %d", code, i, ctx.Response.StatusCode())
+		}
+	}
+}
+
+func TestPostHandlerAPIKey(t *testing.T) {
+	casesprovider.SetDatastore(datastore.New())
+	defer casesprovider.SetDatastore(datastore.New())
+
+	ds := casesprovider.Datastore()
+	bg := context.Background()
+	id, err := ds.AccountRegistration(bg, "ritho", "ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ds.ActivateAccount(bg, id)
+	ds.SetAdmin(bg, id, true)
+	acc, _ := ds.GetAccountByID(bg, id)
+	now := time.Now()
+	err = acc.AddAPIKey("ci", "ci", "radar_secret", []string{account.ScopeRead}, now, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if err = ds.UpdateAccountData(bg, acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := []struct {
+		name     string
+		endpoint string
+		input    string
+		key      string
+		code     int
+		expected string
+	}{
+		{"AuditListHeader", "/audit/list", `{}`, "radar_secret", 200, `"records"`},
+		{"AuditListParam", "/audit/list", `{"token": "radar_secret"}`, "", 200, `"records"`},
+		{"WrongKey", "/audit/list", `{}`, "radar_wrong", 400, "Wrong or expired API key"},
+		{"NotScoped", "/account/logout", `{"username": "ritho"}`, "radar_secret", 400,
+			"The API key is not allowed to do this operation"},
+	}
+
+	for _, tc := range testCases {
+		ctx := newRequest()
+		ctx.Request.Header.Set("Content-Type", "application/json")
+		ctx.Request.Header.SetRequestURI(tc.endpoint)
+		if tc.key != "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+tc.key)
+		}
+
+		ctx.Request.SetBodyString(tc.input)
+		c.postHandler(ctx)
+		if ctx.Response.StatusCode() != tc.code || !strings.Contains(string(ctx.Response.Body()), tc.expected) {
+			t.Errorf("%s: Expected %d %s, Got %d %s", tc.name, tc.code, tc.expected,
+				ctx.Response.StatusCode(), ctx.Response.Body())
+		}
+	}
+
+	acc, _ = ds.GetAccountByID(bg, id)
+	if acc.APIKeys()[0].LastUsed.IsZero() {
+		t.Error("Expected the last use of the key stored")
+	}
+}
+
+func TestPostHandlerLanguage(t *testing.T) {
+	ctx := newRequest()
+	ctx.Request.Header.SetRequestURI("/account/login")
+	ctx.Request.Header.Set("Accept-Language", "es-ES,es;q=0.9")
+
+	c.postHandler(ctx)
+	if ctx.Response.StatusCode() != 400 {
+		t.Errorf("Expected 400, Got %d", ctx.Response.StatusCode())
+	}
+
+	expected := `{"error":"Se esperaba una petición en formato json."}`
+	if string(ctx.Response.Body()) != expected {
+		t.Errorf("Expected %s, Got %s", expected, ctx.Response.Body())
+	}
+
+	if string(ctx.Response.Header.Peek("Content-Language")) != "es" {
+		t.Errorf("Expected es, Got %s", ctx.Response.Header.Peek("Content-Language"))
+	}
+
+	ctx = newRequest()
+	ctx.Request.Header.SetRequestURI("/account/login")
+	ctx.Request.Header.Set("Accept-Language", "es")
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Request.SetBodyString(`{"login": "unknown", "password": "ritho"}`)
+
+	c.postHandler(ctx)
+	expected = `{"error":"Usuario o contraseña incorrectos"}`
+	if string(ctx.Response.Body()) != expected {
+		t.Errorf("Expected %s, Got %s", expected, ctx.Response.Body())
+	}
+}
+
+func TestUseCaseContext(t *testing.T) {
+	ctx := newRequest()
+	ctx.SetUserValue("radar_test", "request")
+
+	uctx := useCaseContext(ctx, i18n.Default, "ritho")
+	if uctx.Value("radar_test") != "request" {
+		t.Errorf("Expected the context derived from the request, Got %v", uctx.Value("radar_test"))
+	}
+
+	if user := logging.ContextUser(uctx); user != "ritho" {
+		t.Errorf("Expected the user ritho, Got %s", user)
+	}
+
+	if uctx.Done() != ctx.Done() {
+		t.Error("Expected the context canceled with the request")
+	}
+}
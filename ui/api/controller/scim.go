@@ -0,0 +1,186 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"encoding/json"
+
+	errWrap "github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/scim/provisioning"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/datastore/account"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/scim"
+)
+
+// scimContentType is the content type of the SCIM responses.
+const scimContentType = "application/scim+json; charset=utf-8"
+
+// scimRoutes links the SCIM routes with their use case.
+var scimRoutes = []struct {
+	method, path, useCase string
+}{
+	{"GET", "/scim/v2/Users", "SCIMUserList"},
+	{"POST", "/scim/v2/Users", "SCIMUserCreate"},
+	{"GET", "/scim/v2/Users/:id", "SCIMUserGet"},
+	{"PUT", "/scim/v2/Users/:id", "SCIMUserReplace"},
+	{"PATCH", "/scim/v2/Users/:id", "SCIMUserPatch"},
+	{"DELETE", "/scim/v2/Users/:id", "SCIMUserDelete"},
+	{"GET", "/scim/v2/Groups", "SCIMGroupList"},
+	{"GET", "/scim/v2/Groups/:id", "SCIMGroupGet"},
+	{"PUT", "/scim/v2/Groups/:id", "SCIMGroupReplace"},
+	{"PATCH", "/scim/v2/Groups/:id", "SCIMGroupPatch"},
+}
+
+// scimQueryParams links the query params of the searches with the params of
+// their use cases.
+var scimQueryParams = map[string]string{
+	"filter":     "filter",
+	"startIndex": "start_index",
+	"count":      "count",
+}
+
+// registerSCIM defines the router paths of the SCIM provisioning.
+func (c *Controller) registerSCIM() {
+	c.Router.GET("/scim/v2/ServiceProviderConfig", handler("/scim/v2/ServiceProviderConfig",
+		c.scimServiceProviderConfig))
+	for _, route := range scimRoutes {
+		c.Router.Handle(route.method, route.path, handler(route.path, c.scimHandler(route.useCase)))
+	}
+}
+
+// scimServiceProviderConfig returns the features of SCIM supported.
+func (c *Controller) scimServiceProviderConfig(ctx *fasthttp.RequestCtx) {
+	body, err := json.Marshal(scim.ServiceProviderConfig())
+	if err != nil {
+		internalServerError(ctx, err.Error())
+		return
+	}
+
+	ctx.SetContentType(scimContentType)
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBody(body)
+}
+
+// scimHandler returns the handler running the SCIM use case with the bearer
+// token, the id in the path, the body and the query params of the request.
+func (c *Controller) scimHandler(useCase string) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		ctx.SetContentType(scimContentType)
+		loc := localizer(ctx)
+
+		uc, err := casesprovider.GetUseCase(useCase)
+		if err != nil {
+			scimError(ctx, loc, err)
+			return
+		}
+
+		params := map[string]interface{}{
+			"bearer":   bearerToken(ctx),
+			"resource": string(ctx.PostBody()),
+		}
+		if id, ok := ctx.UserValue("id").(string); ok {
+			params["id"] = id
+		}
+
+		for query, param := range scimQueryParams {
+			params[param] = string(ctx.QueryArgs().Peek(query))
+		}
+
+		/* Only the params of the use case with a value are passed, the rest
+		are part of other requests. */
+		for param, value := range params {
+			if _, ok := uc.GetParams()[param]; !ok || value == "" {
+				delete(params, param)
+			}
+		}
+
+		err = uc.AddParams(params)
+		if err != nil {
+			scimError(ctx, loc, err)
+			return
+		}
+
+		logging.SetUser(ctx, provisioning.Client)
+		uctx := casesprovider.WithClient(useCaseContext(ctx, loc, provisioning.Client),
+			provisioning.Client)
+		res, err := uc.Run(uctx)
+		if err != nil {
+			scimError(ctx, loc, err)
+			return
+		}
+
+		switch string(ctx.Method()) {
+		case "POST":
+			ctx.SetStatusCode(fasthttp.StatusCreated)
+		case "DELETE":
+			ctx.SetStatusCode(fasthttp.StatusNoContent)
+			return
+		default:
+			ctx.SetStatusCode(fasthttp.StatusOK)
+		}
+
+		body, err := res.Bytes()
+		if err != nil {
+			scimError(ctx, loc, err)
+			return
+		}
+
+		ctx.SetBody(body)
+	}
+}
+
+// bearerToken returns the bearer token of the Authorization header.
+func bearerToken(ctx *fasthttp.RequestCtx) string {
+	auth := ctx.Request.Header.Peek("Authorization")
+	if len(auth) < 7 || !bytes.EqualFold(auth[:7], []byte("Bearer ")) {
+		return ""
+	}
+
+	return string(bytes.TrimSpace(auth[7:]))
+}
+
+// scimError sets the SCIM error response of err.
+func scimError(ctx *fasthttp.RequestCtx, loc *i18n.Localizer, err error) {
+	status := fasthttp.StatusBadRequest
+	scimType := scim.Type(err)
+	switch errWrap.Cause(err) {
+	case provisioning.ErrUnauthorized:
+		status = fasthttp.StatusUnauthorized
+		ctx.Response.Header.Set("WWW-Authenticate", `Bearer realm="radar"`)
+	case provisioning.ErrNotConfigured:
+		status = fasthttp.StatusNotImplemented
+	case account.ErrAccountNotExists, provisioning.ErrGroupNotExists:
+		status = fasthttp.StatusNotFound
+	case account.ErrAccountExists:
+		status = fasthttp.StatusConflict
+		scimType = "uniqueness"
+	case errors.ErrTooManyRequests:
+		status = fasthttp.StatusTooManyRequests
+	}
+
+	body, _ := json.Marshal(scim.NewError(status, scimType, loc.Error(err)))
+	ctx.SetStatusCode(status)
+	ctx.SetBody(body)
+}
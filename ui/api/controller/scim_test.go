@@ -0,0 +1,121 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/datastore"
+)
+
+// scimRequest runs a SCIM request through the router and returns its status
+// and body.
+func scimRequest(method, uri, token, body string) (int, string) {
+	ctx := newRequest()
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(uri)
+	ctx.Request.Header.Set("Content-Type", "application/scim+json")
+	if token != "" {
+		ctx.Request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	ctx.Request.SetBodyString(body)
+	c.Router.Handler(ctx)
+
+	return ctx.Response.StatusCode(), string(ctx.Response.Body())
+}
+
+func TestSCIM(t *testing.T) {
+	casesprovider.SetDatastore(datastore.New())
+	defer casesprovider.SetDatastore(datastore.New())
+
+	status, body := scimRequest("GET", "/scim/v2/Users", "scim-secret", "")
+	if status != 501 || !strings.Contains(body, "The SCIM provisioning is not configured") {
+		t.Errorf("Expected the provisioning disabled, Got %d %s", status, body)
+	}
+
+	casesprovider.Config().SCIMToken = "scim-secret"
+	defer func() { casesprovider.Config().SCIMToken = "" }()
+
+	status, body = scimRequest("GET", "/scim/v2/Users", "wrong", "")
+	if status != 401 || !strings.Contains(body, `"status":"401"`) {
+		t.Errorf("Expected 401, Got %d %s", status, body)
+	}
+
+	status, body = scimRequest("POST", "/scim/v2/Users", "scim-secret",
+		`{"schemas": ["urn:ietf:params:scim:schemas:core:2.0:User"], "userName": "ritho",
+		"displayName": "Pablo Álvarez", "emails": [{"value": "palvarez@ritho.net"}]}`)
+	if status != 201 {
+		t.Fatalf("Expected 201, Got %d %s", status, body)
+	}
+
+	user := map[string]interface{}{}
+	err := json.Unmarshal([]byte(body), &user)
+	if err != nil || user["id"] == "" || user["active"] != true {
+		t.Fatalf("Unexpected user %s: %v", body, err)
+	}
+
+	status, body = scimRequest("POST", "/scim/v2/Users", "scim-secret", `{"userName": "ritho",
+		"emails": [{"value": "palvarez@ritho.net"}]}`)
+	if status != 409 || !strings.Contains(body, `"scimType":"uniqueness"`) {
+		t.Errorf("Expected 409, Got %d %s", status, body)
+	}
+
+	uri := "/scim/v2/Users/" + user["id"].(string)
+	status, body = scimRequest("PATCH", uri, "scim-secret", `{"Operations": [
+		{"op": "replace", "path": "active", "value": false}]}`)
+	if status != 200 || !strings.Contains(body, `"active":false`) {
+		t.Errorf("Expected the user deactivated, Got %d %s", status, body)
+	}
+
+	status, body = scimRequest("GET", `/scim/v2/Users?filter=userName+eq+%22ritho%22&count=1`,
+		"scim-secret", "")
+	if status != 200 || !strings.Contains(body, `"totalResults":1`) {
+		t.Errorf("Expected the user found, Got %d %s", status, body)
+	}
+
+	status, body = scimRequest("GET", `/scim/v2/Users?filter=userName+eq`, "scim-secret", "")
+	if status != 400 || !strings.Contains(body, `"scimType":"invalidFilter"`) {
+		t.Errorf("Expected 400, Got %d %s", status, body)
+	}
+
+	status, body = scimRequest("PATCH", "/scim/v2/Groups/admin", "scim-secret",
+		`{"Operations": [{"op": "add", "path": "members", "value": [{"value": "`+user["id"].(string)+`"}]}]}`)
+	if status != 200 || !strings.Contains(body, `"display":"ritho"`) {
+		t.Errorf("Expected the user in the admin group, Got %d %s", status, body)
+	}
+
+	status, _ = scimRequest("DELETE", uri, "scim-secret", "")
+	if status != 204 {
+		t.Errorf("Expected 204, Got %d", status)
+	}
+
+	status, body = scimRequest("GET", uri, "scim-secret", "")
+	if status != 404 || !strings.Contains(body, `"schemas":["urn:ietf:params:scim:api:messages:2.0:Error"]`) {
+		t.Errorf("Expected 404, Got %d %s", status, body)
+	}
+
+	status, body = scimRequest("GET", "/scim/v2/ServiceProviderConfig", "", "")
+	if status != 200 || !strings.Contains(body, `"patch":{"supported":true}`) {
+		t.Errorf("Expected the service provider configuration, Got %d %s", status, body)
+	}
+}
@@ -0,0 +1,335 @@
+// Package controller implements the handlers of the Radar web interface.
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/radar-go/radar/casesprovider"
+	_ "github.com/radar-go/radar/casesprovider/cases"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/metrics"
+	"github.com/radar-go/radar/render"
+)
+
+// Names of the cookies that keep the session of the user.
+const (
+	sessionCookie = "radar_session"
+	userCookie    = "radar_user"
+)
+
+// Names of the cookie and the form field with the token that protects the
+// forms from cross-site request forgery. The token is random per browser and
+// every form must send the same token of the cookie.
+const (
+	csrfCookie = "radar_csrf"
+	csrfField  = "csrf"
+)
+
+// Names of the cookies that keep the single sign-on in progress, binding the
+// answer of the provider to the browser that started it.
+const (
+	stateCookie    = "radar_oidc_state"
+	verifierCookie = "radar_oidc_verifier"
+)
+
+// Controller struct to manage the Radar web interface controller.
+type Controller struct {
+	Router    *fasthttprouter.Router
+	templates map[string]*template.Template
+}
+
+// page stores the data shared by every page.
+type page struct {
+	Title   string
+	Lang    string
+	User    string
+	CSRF    string
+	Flavors []string
+	Error   string
+	Message string
+	Data    interface{}
+	loc     *i18n.Localizer
+}
+
+// New creates and return a new Controller object.
+func New() *Controller {
+	c := &Controller{
+		Router:    fasthttprouter.New(),
+		templates: parseTemplates(),
+	}
+	c.register()
+
+	return c
+}
+
+// register defines all the router paths the web interface implements.
+func (c *Controller) register() {
+	c.Router.HandleMethodNotAllowed = true
+	c.Router.NotFound = handler("not_found", c.notFound)
+	c.Router.MethodNotAllowed = handler("method_not_allowed", c.methodNotAllowed)
+	c.Router.PanicHandler = c.panic
+
+	c.handle("GET", "/", c.index)
+	c.handle("GET", "/login", c.loginForm)
+	c.handle("POST", "/login", c.login)
+	c.handle("POST", "/login/verify", c.verifyLogin)
+	c.handle("GET", "/login/oidc", c.ssoLogin)
+	c.handle("GET", casesprovider.OIDCCallbackPath, c.ssoCallback)
+	c.handle("POST", "/logout", c.logout)
+	c.handle("GET", "/register", c.registerForm)
+	c.handle("POST", "/register", c.registerAccount)
+	c.handle("GET", "/account/verify", c.verify)
+	c.handle("POST", "/account/verify", c.resendVerification)
+	c.handle("GET", "/password/reset", c.forgotForm)
+	c.handle("POST", "/password/reset", c.requestReset)
+	c.handle("GET", "/password/reset/confirm", c.resetForm)
+	c.handle("POST", "/password/reset/confirm", c.confirmReset)
+	c.handle("GET", "/radar/:flavor", c.radar)
+	c.handle("GET", "/edition/:name/:file", c.edition)
+	c.handle("GET", "/technology/:name", c.technology)
+	c.handle("GET", "/member/:username", c.member)
+}
+
+// handle registers the handler h for the method and path, the forms posted
+// are checked against cross-site request forgery.
+func (c *Controller) handle(method, path string, h fasthttp.RequestHandler) {
+	if method == "POST" {
+		h = c.checkCSRF(h)
+	}
+
+	c.Router.Handle(method, path, handler(path, h))
+}
+
+// checkCSRF returns h refusing the forms that don't send the token of the CSRF
+// cookie, so other sites can't post them on behalf of the user.
+func (c *Controller) checkCSRF(h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		token := ctx.Request.Header.Cookie(csrfCookie)
+		if len(token) == 0 || subtle.ConstantTimeCompare(token, ctx.FormValue(csrfField)) != 1 {
+			c.showError(ctx, fasthttp.StatusForbidden, "WebForbiddenTitle", "WebCSRFError")
+			return
+		}
+
+		h(ctx)
+	}
+}
+
+// csrfToken returns the CSRF token of the browser, setting a new one in its
+// cookie if it doesn't have any yet.
+func csrfToken(ctx *fasthttp.RequestCtx) string {
+	if token := ctx.Request.Header.Cookie(csrfCookie); len(token) > 0 {
+		return string(token)
+	}
+
+	if token, ok := ctx.UserValue(csrfCookie).(string); ok {
+		return token
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		logging.Logger.Error("CSRF token not generated", "request_id", logging.RequestID(ctx),
+			"error", err.Error())
+		return ""
+	}
+
+	token := hex.EncodeToString(buf)
+	ctx.SetUserValue(csrfCookie, token)
+	setCookie(ctx, csrfCookie, token)
+
+	return token
+}
+
+// handler returns h recording its metrics and logging every request to the
+// access log, with the user of the session if any.
+func handler(route string, h fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return metrics.Handler("web", route, logging.Handler("web",
+		func(ctx *fasthttp.RequestCtx) {
+			logging.SetUser(ctx, sessionUser(ctx))
+			h(ctx)
+		}))
+}
+
+// sessionUser returns the username of the account of the session cookie, or an
+// empty string if the session isn't valid, so the user cookie can't be forged.
+func sessionUser(ctx *fasthttp.RequestCtx) string {
+	session := ctx.Request.Header.Cookie(sessionCookie)
+	if len(session) == 0 {
+		return ""
+	}
+
+	acc, err := casesprovider.Datastore().GetAccountBySession(ctx, string(session))
+	if err != nil {
+		return ""
+	}
+
+	return acc.Username()
+}
+
+// newPage returns the page with the title translated to the language accepted
+// by the client and the data of the request.
+func newPage(ctx *fasthttp.RequestCtx, titleID string) *page {
+	loc := i18n.NewLocalizer(string(ctx.Request.Header.Peek("Accept-Language")))
+	ctx.Response.Header.Set("Content-Language", loc.Language())
+	p := &page{
+		Lang:    loc.Language(),
+		User:    string(ctx.Request.Header.Cookie(userCookie)),
+		CSRF:    csrfToken(ctx),
+		Flavors: render.Flavors,
+		loc:     loc,
+	}
+
+	if titleID != "" {
+		p.Title = p.T(titleID)
+	}
+
+	return p
+}
+
+// T returns the text of the message id translated, data fills the placeholders
+// of the message.
+func (p *page) T(id string, data ...map[string]interface{}) string {
+	msg, ok := messages[id]
+	if !ok {
+		logging.Logger.Error("unknown message", "id", id)
+		return id
+	}
+
+	return p.loc.Message(msg, data...)
+}
+
+// SSO returns true if the users can log in with the single sign-on provider.
+func (p *page) SSO() bool {
+	return casesprovider.OIDC().Configured()
+}
+
+// Flavor returns the name of the radar flavor translated.
+func (p *page) Flavor(flavor string) string {
+	return p.T("WebFlavor" + strings.Title(flavor))
+}
+
+// TitleTag returns the title of the html document.
+func (p *page) TitleTag() string {
+	return p.T("WebTitle", map[string]interface{}{"Title": p.Title})
+}
+
+// show renders the template name with the page data as response.
+func (c *Controller) show(ctx *fasthttp.RequestCtx, status int, name string, p *page) {
+	out := &bytes.Buffer{}
+	err := c.templates[name].ExecuteTemplate(out, "layout", p)
+	if err != nil {
+		logging.Logger.Error("page not rendered", "request_id", logging.RequestID(ctx), "page", name,
+			"error", err.Error())
+		internalServerError(ctx)
+		return
+	}
+
+	ctx.SetStatusCode(status)
+	ctx.SetContentType("text/html; charset=utf-8")
+	ctx.SetBody(out.Bytes())
+}
+
+// showError renders the error page with the given status and message.
+func (c *Controller) showError(ctx *fasthttp.RequestCtx, status int, titleID, msgID string) {
+	p := newPage(ctx, titleID)
+	p.Error = p.T(msgID)
+	c.show(ctx, status, "error", p)
+}
+
+// panic handles when the server have a fatal error.
+func (c *Controller) panic(ctx *fasthttp.RequestCtx, from interface{}) {
+	logging.Logger.Error("fatal error", "request_id", logging.RequestID(ctx),
+		"path", string(ctx.Path()), "error", fmt.Sprint(from))
+	internalServerError(ctx)
+}
+
+// methodNotAllowed handles the response when a method call is not allowed from
+// the client.
+func (c *Controller) methodNotAllowed(ctx *fasthttp.RequestCtx) {
+	c.showError(ctx, fasthttp.StatusMethodNotAllowed, "WebMethodNotAllowedTitle",
+		"WebMethodNotAllowed")
+}
+
+// notFound handles the response when a path have not been found.
+func (c *Controller) notFound(ctx *fasthttp.RequestCtx) {
+	c.showError(ctx, fasthttp.StatusNotFound, "WebNotFoundTitle", "WebNotFound")
+}
+
+// internalServerError response, it doesn't use the templates as they could be
+// the cause of the error.
+func internalServerError(ctx *fasthttp.RequestCtx) {
+	ctx.SetStatusCode(fasthttp.StatusInternalServerError)
+	ctx.SetContentType("text/plain; charset=utf-8")
+	ctx.SetBodyString("Internal server error")
+}
+
+// run executes the use case name with the params, on behalf of the request,
+// and stores the result, translated by loc, in res.
+func run(ctx *fasthttp.RequestCtx, loc *i18n.Localizer, name string,
+	params map[string]interface{}, res interface{}) error {
+	uc, err := casesprovider.GetUseCase(name)
+	if err != nil {
+		return err
+	}
+
+	err = uc.AddParams(params)
+	if err != nil {
+		return err
+	}
+
+	uctx := logging.WithRequestID(ctx, logging.RequestID(ctx))
+	uctx = logging.WithUser(uctx, sessionUser(ctx))
+	uctx = logging.WithClientIP(uctx, ctx.RemoteIP().String())
+	result, err := uc.Run(i18n.WithLocalizer(uctx, loc))
+	if err != nil {
+		return err
+	}
+	data, err := result.Bytes()
+	if err != nil {
+		return err
+	}
+
+	/* Some use cases report the errors inside the result. */
+	failure := struct {
+		Error string `json:"error"`
+	}{}
+	err = json.Unmarshal(data, &failure)
+	if err != nil {
+		return err
+	}
+
+	if failure.Error != "" {
+		return errors.New(failure.Error)
+	}
+
+	return json.Unmarshal(data, res)
+}
@@ -0,0 +1,595 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/passwordreset"
+	"github.com/radar-go/radar/casesprovider/cases/account/twofactor"
+	"github.com/radar-go/radar/casesprovider/cases/account/verification"
+	"github.com/radar-go/radar/datastore"
+	technology "github.com/radar-go/radar/entities/technology/api"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/oidc/oidctest"
+	"github.com/radar-go/radar/totp"
+)
+
+// newRequest returns a new request context initialized like the server does,
+// so it can be the parent of the context of the use cases.
+func newRequest() *fasthttp.RequestCtx {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, nil, nil)
+
+	return ctx
+}
+
+// testCSRF is the CSRF token the forms are posted with in the tests.
+const testCSRF = "0123456789abcdef"
+
+// request sends a request to the web interface and returns the context with
+// the response. The forms are posted with the CSRF token of the browser.
+func request(c *Controller, method, uri, form string, cookies map[string]string) *fasthttp.RequestCtx {
+	if method == "POST" {
+		all := map[string]string{csrfCookie: testCSRF}
+		for key, value := range cookies {
+			all[key] = value
+		}
+
+		cookies = all
+		form += "&" + csrfField + "=" + testCSRF
+	}
+
+	return send(c, method, uri, form, cookies)
+}
+
+// send sends a request to the web interface as it is and returns the context
+// with the response.
+func send(c *Controller, method, uri, form string, cookies map[string]string) *fasthttp.RequestCtx {
+	ctx := newRequest()
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.Header.SetRequestURI(uri)
+	for key, value := range cookies {
+		ctx.Request.Header.SetCookie(key, value)
+	}
+
+	if form != "" {
+		ctx.Request.Header.SetContentType("application/x-www-form-urlencoded")
+		ctx.Request.SetBodyString(form)
+	}
+
+	c.Router.Handler(ctx)
+
+	return ctx
+}
+
+func TestController(t *testing.T) {
+	c := New()
+	ctx := newRequest()
+	c.panic(ctx, "test")
+	if ctx.Response.StatusCode() != 500 {
+		t.Errorf("Expected 500, Got %d", ctx.Response.StatusCode())
+	}
+
+	ctx = request(c, "GET", "/unknown", "", nil)
+	if ctx.Response.StatusCode() != 404 {
+		t.Errorf("Expected 404, Got %d", ctx.Response.StatusCode())
+	}
+
+	ctx = request(c, "DELETE", "/login", "", nil)
+	if ctx.Response.StatusCode() != 405 {
+		t.Errorf("Expected 405, Got %d", ctx.Response.StatusCode())
+	}
+
+	ctx = request(c, "GET", "/", "", nil)
+	if ctx.Response.StatusCode() != 200 {
+		t.Errorf("Expected 200, Got %d", ctx.Response.StatusCode())
+	}
+
+	body := string(ctx.Response.Body())
+	for _, expected := range []string{`<html lang="en">`, `href="/radar/people"`,
+		`href="/login"`, `href="/register"`} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected %s in %s", expected, body)
+		}
+	}
+
+	if strings.Contains(body, "<script") {
+		t.Errorf("Expected no javascript in %s", body)
+	}
+}
+
+func TestAccountPages(t *testing.T) {
+	casesprovider.SetDatastore(datastore.New())
+	defer casesprovider.SetDatastore(datastore.New())
+	c := New()
+
+	for _, uri := range []string{"/login", "/register"} {
+		ctx := request(c, "GET", uri, "", nil)
+		if ctx.Response.StatusCode() != 200 {
+			t.Errorf("Expected 200 for %s, Got %d", uri, ctx.Response.StatusCode())
+		}
+	}
+
+	ctx := request(c, "POST", "/register", "username=ritho&name=Ritho&email=palvarez%40ritho.net", nil)
+	if ctx.Response.StatusCode() != 400 {
+		t.Errorf("Expected 400, Got %d", ctx.Response.StatusCode())
+	}
+
+	if !strings.Contains(string(ctx.Response.Body()), `value="ritho"`) {
+		t.Errorf("Expected the form to keep the values, Got %s", ctx.Response.Body())
+	}
+
+	ctx = request(c, "POST", "/register",
+		"username=ritho&name=Ritho&email=palvarez%40ritho.net&password=ritho", nil)
+	if ctx.Response.StatusCode() != 303 {
+		t.Errorf("Expected 303, Got %d: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	ctx = request(c, "GET", "/account/verify?code=wrong", "", nil)
+	if ctx.Response.StatusCode() != 400 || !strings.Contains(string(ctx.Response.Body()),
+		`action="/account/verify"`) {
+		t.Errorf("Expected the resend form, Got %d: %s", ctx.Response.StatusCode(),
+			ctx.Response.Body())
+	}
+
+	ctx = request(c, "POST", "/account/verify", "username=ritho", nil)
+	if ctx.Response.StatusCode() != 200 {
+		t.Errorf("Expected 200, Got %d: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	acc, err := casesprovider.Datastore().GetAccountByUsername(context.Background(), "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctx = request(c, "GET", "/account/verify?code="+verification.Code(acc), "", nil)
+	if ctx.Response.StatusCode() != 303 {
+		t.Errorf("Expected 303, Got %d: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	acc, _ = casesprovider.Datastore().GetAccountByUsername(context.Background(), "ritho")
+	if !acc.IsActive() {
+		t.Error("Expected the account to be active")
+	}
+
+	ctx = request(c, "POST", "/login", "login=ritho&password=wrong", nil)
+	if ctx.Response.StatusCode() != 401 {
+		t.Errorf("Expected 401, Got %d", ctx.Response.StatusCode())
+	}
+
+	ctx = request(c, "POST", "/login", "login=ritho&password=ritho", nil)
+	if ctx.Response.StatusCode() != 303 {
+		t.Errorf("Expected 303, Got %d: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	cookies := make(map[string]string)
+	for _, key := range []string{sessionCookie, userCookie} {
+		cookie := fasthttp.AcquireCookie()
+		cookie.SetKey(key)
+		if !ctx.Response.Header.Cookie(cookie) {
+			t.Errorf("Expected the %s cookie to be set", key)
+		}
+
+		if !cookie.HTTPOnly() {
+			t.Errorf("Expected the %s cookie to be http only", key)
+		}
+
+		cookies[key] = string(cookie.Value())
+		fasthttp.ReleaseCookie(cookie)
+	}
+
+	ctx = request(c, "GET", "/", "", cookies)
+	if !strings.Contains(string(ctx.Response.Body()), `action="/logout"`) {
+		t.Errorf("Expected the logout form, Got %s", ctx.Response.Body())
+	}
+
+	ctx = request(c, "POST", "/logout", "", cookies)
+	if ctx.Response.StatusCode() != 303 {
+		t.Errorf("Expected 303, Got %d", ctx.Response.StatusCode())
+	}
+
+	if casesprovider.Datastore().DoesAccountHaveSessionByUsername(context.Background(), "ritho") {
+		t.Error("Expected the session to be closed")
+	}
+}
+
+func TestAccessLogUser(t *testing.T) {
+	ds := datastore.New()
+	casesprovider.SetDatastore(ds)
+	defer casesprovider.SetDatastore(datastore.New())
+	out := &bytes.Buffer{}
+	logging.SetOutput(out)
+	defer logging.SetOutput(&bytes.Buffer{})
+	c := New()
+
+	_, err := ds.AccountRegistration(context.Background(), "ritho", "Ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	token := "00000000-0000-0000-0000-000000000000"
+	err = ds.AddSession(context.Background(), token, "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := map[string]struct {
+		cookies  map[string]string
+		expected string
+	}{
+		"Session":        {map[string]string{sessionCookie: token, userCookie: "admin"}, "ritho"},
+		"ForgedUser":     {map[string]string{userCookie: "admin"}, ""},
+		"UnknownSession": {map[string]string{sessionCookie: "unknown", userCookie: "admin"}, ""},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			out.Reset()
+			request(c, "GET", "/", "", tc.cookies)
+
+			entry := make(map[string]interface{})
+			err := json.Unmarshal(out.Bytes(), &entry)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing %s: %s", out, err)
+			}
+
+			if entry["user"] != tc.expected {
+				t.Errorf("Expected the user %q in the access log, Got %v", tc.expected, entry["user"])
+			}
+		})
+	}
+}
+
+func TestPasswordResetPages(t *testing.T) {
+	ds := datastore.New()
+	casesprovider.SetDatastore(ds)
+	defer casesprovider.SetDatastore(datastore.New())
+	c := New()
+
+	id, err := ds.AccountRegistration(context.Background(), "ritho", "Ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ds.ActivateAccount(context.Background(), id)
+
+	ctx := request(c, "GET", "/login", "", nil)
+	if !strings.Contains(string(ctx.Response.Body()), `href="/password/reset"`) {
+		t.Errorf("Expected the link to reset the password, Got %s", ctx.Response.Body())
+	}
+
+	ctx = request(c, "POST", "/password/reset", "username=ritho", nil)
+	if ctx.Response.StatusCode() != 200 {
+		t.Errorf("Expected 200, Got %d: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	acc, err := ds.GetAccountByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	code := passwordreset.Code(acc)
+	ctx = request(c, "GET", "/password/reset/confirm?code="+code, "", nil)
+	if !strings.Contains(string(ctx.Response.Body()), `value="`+code+`"`) {
+		t.Errorf("Expected the code in the form, Got %s", ctx.Response.Body())
+	}
+
+	ctx = request(c, "POST", "/password/reset/confirm", "code="+code+"&password=new", nil)
+	if ctx.Response.StatusCode() != 400 {
+		t.Errorf("Expected 400, Got %d", ctx.Response.StatusCode())
+	}
+
+	ctx = request(c, "POST", "/password/reset/confirm", "code="+code+"&password=newpassword", nil)
+	if ctx.Response.StatusCode() != 303 {
+		t.Errorf("Expected 303, Got %d: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+
+	ctx = request(c, "POST", "/login", "login=ritho&password=newpassword", nil)
+	if ctx.Response.StatusCode() != 303 {
+		t.Errorf("Expected 303, Got %d: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	ds := datastore.New()
+	casesprovider.SetDatastore(ds)
+	defer casesprovider.SetDatastore(datastore.New())
+	c := New()
+
+	id, err := ds.AccountRegistration(context.Background(), "twofactor", "Ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ds.ActivateAccount(context.Background(), id)
+
+	acc, _ := ds.GetAccountByUsername(context.Background(), "twofactor")
+	secret, _ := totp.GenerateSecret()
+	acc.SetTwoFactorSecret(secret)
+	code, _ := totp.Code(secret, time.Now().Add(-totp.Period))
+	err = acc.EnableTwoFactor(code, time.Now(), nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if err = ds.UpdateAccountData(context.Background(), acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	ctx := request(c, "POST", "/login", "login=twofactor&password=ritho", nil)
+	body := string(ctx.Response.Body())
+	if ctx.Response.StatusCode() != 200 || !strings.Contains(body, `action="/login/verify"`) ||
+		len(ctx.Response.Header.PeekCookie(sessionCookie)) > 0 {
+		t.Fatalf("Expected the code form, Got %d: %s", ctx.Response.StatusCode(), body)
+	}
+
+	challenge := twofactor.Challenge(acc)
+	ctx = request(c, "POST", "/login/verify", "challenge="+challenge+"&code=000000", nil)
+	if ctx.Response.StatusCode() != 401 || !strings.Contains(string(ctx.Response.Body()), challenge) {
+		t.Errorf("Expected 401 keeping the challenge, Got %d: %s", ctx.Response.StatusCode(),
+			ctx.Response.Body())
+	}
+
+	code, _ = totp.Code(secret, time.Now())
+	ctx = request(c, "POST", "/login/verify", "challenge="+challenge+"&code="+code, nil)
+	if ctx.Response.StatusCode() != 303 || len(ctx.Response.Header.PeekCookie(sessionCookie)) == 0 {
+		t.Errorf("Expected 303 with the session, Got %d: %s", ctx.Response.StatusCode(),
+			ctx.Response.Body())
+	}
+}
+
+// cookie returns the value of the cookie set in the response.
+func cookie(ctx *fasthttp.RequestCtx, key string) string {
+	c := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(c)
+
+	c.SetKey(key)
+	ctx.Response.Header.Cookie(c)
+
+	return string(c.Value())
+}
+
+func TestCSRF(t *testing.T) {
+	casesprovider.SetDatastore(datastore.New())
+	defer casesprovider.SetDatastore(datastore.New())
+	c := New()
+
+	ctx := send(c, "GET", "/login", "", nil)
+	token := cookie(ctx, csrfCookie)
+	if len(token) != 64 {
+		t.Fatalf("Expected a new CSRF token, Got %q", token)
+	}
+
+	if !strings.Contains(string(ctx.Response.Body()), `name="csrf" type="hidden" value="`+token+`"`) {
+		t.Errorf("Expected the CSRF token in the form, Got %s", ctx.Response.Body())
+	}
+
+	ctx = send(c, "GET", "/login", "", map[string]string{csrfCookie: token})
+	if cookie(ctx, csrfCookie) != "" {
+		t.Error("Expected the CSRF token of the browser to be kept")
+	}
+
+	testCases := map[string]struct {
+		form    string
+		cookies map[string]string
+		code    int
+	}{
+		"NoToken":    {"login=ritho&password=ritho", nil, 403},
+		"NoCookie":   {"login=ritho&password=ritho&csrf=" + token, nil, 403},
+		"OtherToken": {"login=ritho&password=ritho&csrf=other", map[string]string{csrfCookie: token}, 403},
+		"Token":      {"login=ritho&password=ritho&csrf=" + token, map[string]string{csrfCookie: token}, 401},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := send(c, "POST", "/login", tc.form, tc.cookies)
+			if ctx.Response.StatusCode() != tc.code {
+				t.Errorf("Expected %d, Got %d", tc.code, ctx.Response.StatusCode())
+			}
+		})
+	}
+}
+
+func TestCookieAttributes(t *testing.T) {
+	casesprovider.SetDatastore(datastore.New())
+	defer casesprovider.SetDatastore(datastore.New())
+	c := New()
+
+	ctx := send(c, "GET", "/login", "", nil)
+	set := string(ctx.Response.Header.PeekCookie(csrfCookie))
+	if !strings.Contains(set, "SameSite=Lax") || strings.Contains(set, "secure") {
+		t.Errorf("Expected a SameSite=Lax cookie not secure over http, Got %s", set)
+	}
+
+	ctx = newRequest()
+	ctx.Request.Header.SetMethod("GET")
+	ctx.Request.Header.SetRequestURI("/login")
+	ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	c.Router.Handler(ctx)
+	set = string(ctx.Response.Header.PeekCookie(csrfCookie))
+	if !strings.Contains(set, "SameSite=Lax") || !strings.Contains(set, "secure") {
+		t.Errorf("Expected a secure SameSite=Lax cookie behind TLS, Got %s", set)
+	}
+}
+
+func TestSSOLogin(t *testing.T) {
+	ds := datastore.New()
+	casesprovider.SetDatastore(ds)
+	defer casesprovider.SetDatastore(datastore.New())
+	c := New()
+
+	ctx := request(c, "GET", "/login/oidc", "", nil)
+	if ctx.Response.StatusCode() != 404 {
+		t.Errorf("Expected 404 without single sign-on, Got %d", ctx.Response.StatusCode())
+	}
+
+	ctx = request(c, "GET", "/login", "", nil)
+	if strings.Contains(string(ctx.Response.Body()), `href="/login/oidc"`) {
+		t.Error("Expected no single sign-on link")
+	}
+
+	s := oidctest.New(oidctest.User{
+		Subject:       "1234",
+		Email:         "palvarez@ritho.net",
+		EmailVerified: true,
+		Username:      "ritho",
+	})
+	defer s.Close()
+	casesprovider.SetOIDC(s.Provider("http://localhost:10080" + casesprovider.OIDCCallbackPath))
+	defer casesprovider.SetOIDC(nil)
+
+	ctx = request(c, "GET", "/login", "", nil)
+	if !strings.Contains(string(ctx.Response.Body()), `href="/login/oidc"`) {
+		t.Error("Expected the single sign-on link")
+	}
+
+	ctx = request(c, "GET", "/login/oidc", "", nil)
+	location := string(ctx.Response.Header.Peek("Location"))
+	if ctx.Response.StatusCode() != 302 || !strings.HasPrefix(location, s.URL+"/authorize?") {
+		t.Fatalf("Expected the redirection to the provider, Got %d: %s",
+			ctx.Response.StatusCode(), location)
+	}
+
+	cookies := map[string]string{
+		stateCookie:    cookie(ctx, stateCookie),
+		verifierCookie: cookie(ctx, verifierCookie),
+	}
+	code, state, err := s.Authorize(location)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	callback := casesprovider.OIDCCallbackPath + "?code=" + code + "&state=" + state
+	ctx = request(c, "GET", callback, "", map[string]string{stateCookie: "other"})
+	if ctx.Response.StatusCode() != 400 {
+		t.Errorf("Expected 400 with the state of another browser, Got %d",
+			ctx.Response.StatusCode())
+	}
+
+	ctx = request(c, "GET", callback, "", cookies)
+	if ctx.Response.StatusCode() != 303 || cookie(ctx, sessionCookie) == "" ||
+		cookie(ctx, userCookie) != "ritho" {
+		t.Errorf("Expected 303 with the session, Got %d: %s", ctx.Response.StatusCode(),
+			ctx.Response.Body())
+	}
+
+	/* The codes are valid only once. */
+	ctx = request(c, "GET", callback, "", cookies)
+	if ctx.Response.StatusCode() != 401 {
+		t.Errorf("Expected 401 using the code again, Got %d", ctx.Response.StatusCode())
+	}
+}
+
+func TestRadarPages(t *testing.T) {
+	ds := datastore.New()
+	casesprovider.SetDatastore(ds)
+	defer casesprovider.SetDatastore(datastore.New())
+	c := New()
+
+	_, err := ds.AccountRegistration(context.Background(), "ritho", "Ritho", "palvarez@ritho.net", "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	acc, err := ds.GetAccountByUsername(context.Background(), "ritho")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	acc.AddTechnology(technology.New("C & C++", "languages", 4))
+	if err = ds.UpdateAccountData(context.Background(), acc); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	_, _, err = ds.UpdateEdition(context.Background(), "2018",
+		[]datastore.Technology{datastore.NewTechnology("Go", "Languages & Frameworks", "")},
+		[]datastore.Blip{{Technology: "Go", Ring: "Adopt"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	testCases := map[string]struct {
+		code     int
+		expected []string
+	}{
+		"/radar/people": {200, []string{"<svg", "C &amp; C++",
+			"There are no technologies in this quadrant."}},
+		"/radar/projects":     {200, []string{"<svg"}},
+		"/radar/unknown":      {404, nil},
+		"/technology/C & C++": {200, []string{`href="/member/ritho"`}},
+		"/technology/unknown": {404, nil},
+		"/member/ritho":       {200, []string{"<h1>Ritho</h1>", "/technology/C%20&amp;%20C&#43;&#43;"}},
+		"/member/unknown":     {404, nil},
+		"/edition/2018/byor.csv": {200, []string{"name,ring,quadrant,isNew,description\n",
+			"Go,adopt,languages & frameworks,FALSE,"}},
+		"/edition/2018/byor.json": {200, []string{`[{"name":"Go","ring":"adopt"`}},
+		"/edition/2018/byor.xml":  {404, nil},
+		"/edition/2019/byor.csv":  {404, nil},
+	}
+
+	for uri, tc := range testCases {
+		ctx := request(c, "GET", uri, "", nil)
+		if ctx.Response.StatusCode() != tc.code {
+			t.Errorf("Expected %d for %s, Got %d", tc.code, uri, ctx.Response.StatusCode())
+		}
+
+		body := string(ctx.Response.Body())
+		for _, expected := range tc.expected {
+			if !strings.Contains(body, expected) {
+				t.Errorf("Expected %s in %s, Got %s", expected, uri, body)
+			}
+		}
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	c := New()
+	for _, uri := range []string{"/", "/login", "/register", "/radar/people", "/unknown"} {
+		ctx := newRequest()
+		ctx.Request.Header.SetRequestURI(uri)
+		ctx.Request.Header.Set("Accept-Language", "es-ES,es;q=0.9,en;q=0.5")
+		c.Router.Handler(ctx)
+
+		body := string(ctx.Response.Body())
+		if !strings.Contains(body, `<html lang="es">`) {
+			t.Errorf("Expected the %s page in spanish, Got %s", uri, body)
+		}
+
+		if strings.Contains(body, ">Web") || strings.Contains(body, `"Web`) {
+			t.Errorf("Expected all the messages of %s to exist, Got %s", uri, body)
+		}
+	}
+
+	ctx := newRequest()
+	ctx.Request.Header.SetRequestURI("/radar/people")
+	ctx.Request.Header.Set("Accept-Language", "es")
+	c.Router.Handler(ctx)
+	if !strings.Contains(string(ctx.Response.Body()), "<h1>Radar de Personas</h1>") {
+		t.Errorf("Expected the title in spanish, Got %s", ctx.Response.Body())
+	}
+}
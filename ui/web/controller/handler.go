@@ -0,0 +1,548 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	errWrap "github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"github.com/radar-go/radar/byor"
+	"github.com/radar-go/radar/casesprovider"
+	"github.com/radar-go/radar/casesprovider/cases/account/oidcstart"
+	"github.com/radar-go/radar/casesprovider/errors"
+	"github.com/radar-go/radar/i18n"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/oidc"
+	"github.com/radar-go/radar/render"
+)
+
+// registration stores the data of the registration form.
+type registration struct {
+	Username string
+	Name     string
+	Email    string
+}
+
+// quadrant stores the blips of a quadrant of the radar.
+type quadrant struct {
+	Name  string
+	Blips []render.Blip
+}
+
+// radarView stores the data of the radar page.
+type radarView struct {
+	Flavor    string
+	SVG       template.HTML
+	Quadrants []quadrant
+}
+
+// technologyView stores the data of the technology page.
+type technologyView struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Quadrant string `json:"quadrant"`
+	Ring     string `json:"ring"`
+	Members  []struct {
+		Username string `json:"username"`
+		Name     string `json:"name"`
+		Level    int    `json:"level"`
+	} `json:"members"`
+}
+
+// memberView stores the data of the member page.
+type memberView struct {
+	Username     string `json:"username"`
+	Name         string `json:"name"`
+	Role         string `json:"role"`
+	Technologies []struct {
+		Name  string `json:"name"`
+		Type  string `json:"type"`
+		Level int    `json:"level"`
+	} `json:"technologies"`
+}
+
+// setCookie adds a cookie only readable by the server to the response.
+func setCookie(ctx *fasthttp.RequestCtx, key, value string) {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(key)
+	cookie.SetValue(value)
+	cookie.SetPath("/")
+	cookie.SetHTTPOnly(true)
+	cookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	cookie.SetSecure(secure(ctx))
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+// secure returns true if the request came through TLS, directly or behind a
+// proxy, or the web interface is published with https, so the cookies are only
+// sent back through it.
+func secure(ctx *fasthttp.RequestCtx) bool {
+	if ctx.IsTLS() || strings.EqualFold(string(ctx.Request.Header.Peek("X-Forwarded-Proto")), "https") {
+		return true
+	}
+
+	cfg := casesprovider.Config()
+	return cfg != nil && strings.HasPrefix(cfg.PublicURL, "https://")
+}
+
+// index shows the list of radars.
+func (c *Controller) index(ctx *fasthttp.RequestCtx) {
+	c.show(ctx, fasthttp.StatusOK, "index", newPage(ctx, "WebIndexTitle"))
+}
+
+// loginForm shows the login form.
+func (c *Controller) loginForm(ctx *fasthttp.RequestCtx) {
+	p := newPage(ctx, "WebLogIn")
+	if len(ctx.QueryArgs().Peek("registered")) > 0 {
+		p.Message = p.T("WebRegistered")
+	} else if len(ctx.QueryArgs().Peek("verified")) > 0 {
+		p.Message = p.T("WebVerified")
+	} else if len(ctx.QueryArgs().Peek("reset")) > 0 {
+		p.Message = p.T("WebPasswordChanged")
+	}
+
+	c.show(ctx, fasthttp.StatusOK, "login", p)
+}
+
+// login logs in the user and keeps the session in the cookies.
+func (c *Controller) login(ctx *fasthttp.RequestCtx) {
+	res := struct {
+		Username  string `json:"username"`
+		Token     string `json:"token"`
+		Challenge string `json:"challenge"`
+	}{}
+
+	p := newPage(ctx, "WebLogIn")
+	logging.SetUser(ctx, string(ctx.FormValue("login")))
+	err := run(ctx, p.loc, "AccountLogin", map[string]interface{}{
+		"login":    string(ctx.FormValue("login")),
+		"password": string(ctx.FormValue("password")),
+	}, &res)
+	if errWrap.Cause(err) == errors.ErrTooManyRequests {
+		p.Error = p.loc.Error(err)
+		c.show(ctx, fasthttp.StatusTooManyRequests, "login", p)
+		return
+	} else if err != nil {
+		logging.Logger.Info("login failed", "request_id", logging.RequestID(ctx),
+			"error", err.Error())
+		p.Error = p.T("WebLoginError")
+		c.show(ctx, fasthttp.StatusUnauthorized, "login", p)
+		return
+	}
+
+	if res.Challenge != "" {
+		p.Title = p.T("WebTwoFactorTitle")
+		p.Data = res.Challenge
+		c.show(ctx, fasthttp.StatusOK, "twofactor", p)
+		return
+	}
+
+	setCookie(ctx, sessionCookie, res.Token)
+	setCookie(ctx, userCookie, res.Username)
+	ctx.Redirect("/", fasthttp.StatusSeeOther)
+}
+
+// verifyLogin completes the login with the code of the authenticator app or a
+// recovery code, and keeps the session in the cookies.
+func (c *Controller) verifyLogin(ctx *fasthttp.RequestCtx) {
+	res := struct {
+		Username string `json:"username"`
+		Token    string `json:"token"`
+	}{}
+
+	p := newPage(ctx, "WebTwoFactorTitle")
+	challenge := string(ctx.FormValue("challenge"))
+	err := run(ctx, p.loc, "AccountLoginVerify", map[string]interface{}{
+		"challenge": challenge,
+		"code":      string(ctx.FormValue("code")),
+	}, &res)
+	if err != nil {
+		status := fasthttp.StatusUnauthorized
+		if errWrap.Cause(err) == errors.ErrTooManyRequests {
+			status = fasthttp.StatusTooManyRequests
+		}
+
+		p.Error = p.T("WebTwoFactorError", map[string]interface{}{
+			"Error": p.loc.Error(err),
+		})
+		p.Data = challenge
+		c.show(ctx, status, "twofactor", p)
+		return
+	}
+
+	setCookie(ctx, sessionCookie, res.Token)
+	setCookie(ctx, userCookie, res.Username)
+	ctx.Redirect("/", fasthttp.StatusSeeOther)
+}
+
+// ssoLogin starts the single sign-on, redirecting the user to log in the
+// identity provider.
+func (c *Controller) ssoLogin(ctx *fasthttp.RequestCtx) {
+	res := struct {
+		URL      string `json:"url"`
+		State    string `json:"state"`
+		Verifier string `json:"verifier"`
+	}{}
+
+	p := newPage(ctx, "WebLogIn")
+	err := run(ctx, p.loc, "AccountOIDCStart", map[string]interface{}{}, &res)
+	if errWrap.Cause(err) == oidc.ErrNotConfigured {
+		c.notFound(ctx)
+		return
+	} else if err != nil {
+		logging.Logger.Error("single sign-on not started", "request_id", logging.RequestID(ctx),
+			"error", err.Error())
+		p.Error = p.T("WebSSOError", map[string]interface{}{"Error": p.loc.Error(err)})
+		c.show(ctx, fasthttp.StatusBadGateway, "login", p)
+		return
+	}
+
+	setCookie(ctx, stateCookie, res.State)
+	setCookie(ctx, verifierCookie, res.Verifier)
+	ctx.Redirect(res.URL, fasthttp.StatusFound)
+}
+
+// ssoCallback completes the single sign-on with the code given by the identity
+// provider, and keeps the session in the cookies.
+func (c *Controller) ssoCallback(ctx *fasthttp.RequestCtx) {
+	res := struct {
+		Username  string `json:"username"`
+		Token     string `json:"token"`
+		Challenge string `json:"challenge"`
+	}{}
+
+	p := newPage(ctx, "WebLogIn")
+	state := string(ctx.QueryArgs().Peek("state"))
+	verifier := string(ctx.Request.Header.Cookie(verifierCookie))
+	ctx.Response.Header.DelClientCookie(stateCookie)
+	ctx.Response.Header.DelClientCookie(verifierCookie)
+
+	/* The provider answer has to come to the browser that started the login,
+	otherwise anyone could log in a victim with its own account. */
+	if state == "" || state != string(ctx.Request.Header.Cookie(stateCookie)) {
+		p.Error = p.T("WebSSOError", map[string]interface{}{
+			"Error": p.loc.Error(oidcstart.ErrState),
+		})
+		c.show(ctx, fasthttp.StatusBadRequest, "login", p)
+		return
+	}
+
+	if errCode := ctx.QueryArgs().Peek("error"); len(errCode) > 0 {
+		logging.Logger.Info("single sign-on refused by the provider",
+			"request_id", logging.RequestID(ctx), "error", string(errCode))
+		p.Error = p.T("WebSSOError", map[string]interface{}{"Error": string(errCode)})
+		c.show(ctx, fasthttp.StatusUnauthorized, "login", p)
+		return
+	}
+
+	err := run(ctx, p.loc, "AccountOIDCLogin", map[string]interface{}{
+		"code":     string(ctx.QueryArgs().Peek("code")),
+		"state":    state,
+		"verifier": verifier,
+	}, &res)
+	if err != nil {
+		logging.Logger.Info("single sign-on login failed", "request_id", logging.RequestID(ctx),
+			"error", err.Error())
+		p.Error = p.T("WebSSOError", map[string]interface{}{"Error": p.loc.Error(err)})
+		c.show(ctx, fasthttp.StatusUnauthorized, "login", p)
+		return
+	}
+
+	if res.Challenge != "" {
+		p.Title = p.T("WebTwoFactorTitle")
+		p.Data = res.Challenge
+		c.show(ctx, fasthttp.StatusOK, "twofactor", p)
+		return
+	}
+
+	setCookie(ctx, sessionCookie, res.Token)
+	setCookie(ctx, userCookie, res.Username)
+	ctx.Redirect("/", fasthttp.StatusSeeOther)
+}
+
+// logout closes the session of the user and removes the session cookies.
+func (c *Controller) logout(ctx *fasthttp.RequestCtx) {
+	res := make(map[string]interface{})
+	err := run(ctx, i18n.Default, "AccountLogout", map[string]interface{}{
+		"username": string(ctx.Request.Header.Cookie(userCookie)),
+		"token":    string(ctx.Request.Header.Cookie(sessionCookie)),
+	}, &res)
+	if err != nil {
+		logging.Logger.Info("logout failed", "request_id", logging.RequestID(ctx),
+			"error", err.Error())
+	}
+
+	ctx.Response.Header.DelClientCookie(sessionCookie)
+	ctx.Response.Header.DelClientCookie(userCookie)
+	ctx.Redirect("/", fasthttp.StatusSeeOther)
+}
+
+// registerForm shows the registration form.
+func (c *Controller) registerForm(ctx *fasthttp.RequestCtx) {
+	p := newPage(ctx, "WebRegister")
+	p.Data = registration{}
+	c.show(ctx, fasthttp.StatusOK, "register", p)
+}
+
+// registerAccount registers a new account.
+func (c *Controller) registerAccount(ctx *fasthttp.RequestCtx) {
+	data := registration{
+		Username: string(ctx.FormValue("username")),
+		Name:     string(ctx.FormValue("name")),
+		Email:    string(ctx.FormValue("email")),
+	}
+
+	p := newPage(ctx, "WebRegister")
+	res := make(map[string]interface{})
+	err := run(ctx, p.loc, "AccountRegister", map[string]interface{}{
+		"username": data.Username,
+		"name":     data.Name,
+		"email":    data.Email,
+		"password": string(ctx.FormValue("password")),
+	}, &res)
+	if err != nil {
+		p.Error = p.T("WebRegisterError", map[string]interface{}{
+			"Error": p.loc.Error(err),
+		})
+		p.Data = data
+		c.show(ctx, fasthttp.StatusBadRequest, "register", p)
+		return
+	}
+
+	ctx.Redirect("/login?registered=1", fasthttp.StatusSeeOther)
+}
+
+// verify activates the account of the code in the link sent to its email,
+// offering to send a new link if it's not valid.
+func (c *Controller) verify(ctx *fasthttp.RequestCtx) {
+	p := newPage(ctx, "WebVerifyTitle")
+	res := make(map[string]interface{})
+	err := run(ctx, p.loc, "AccountActivate", map[string]interface{}{
+		"code": string(ctx.QueryArgs().Peek("code")),
+	}, &res)
+	if err != nil {
+		p.Error = p.T("WebVerifyError", map[string]interface{}{
+			"Error": p.loc.Error(err),
+		})
+		c.show(ctx, fasthttp.StatusBadRequest, "verify", p)
+		return
+	}
+
+	ctx.Redirect("/login?verified=1", fasthttp.StatusSeeOther)
+}
+
+// resendVerification sends a new verification link to the email of the
+// account.
+func (c *Controller) resendVerification(ctx *fasthttp.RequestCtx) {
+	p := newPage(ctx, "WebVerifyTitle")
+	res := struct {
+		Result string `json:"result"`
+	}{}
+	err := run(ctx, p.loc, "AccountVerificationResend", map[string]interface{}{
+		"username": string(ctx.FormValue("username")),
+	}, &res)
+	if err != nil {
+		logging.Logger.Info("verification link not sent", "request_id", logging.RequestID(ctx),
+			"error", err.Error())
+		p.Error = p.T("WebResendError")
+		c.show(ctx, fasthttp.StatusInternalServerError, "verify", p)
+		return
+	}
+
+	p.Message = res.Result
+	c.show(ctx, fasthttp.StatusOK, "verify", p)
+}
+
+// forgotForm shows the form to ask for a link to reset the password.
+func (c *Controller) forgotForm(ctx *fasthttp.RequestCtx) {
+	c.show(ctx, fasthttp.StatusOK, "forgot", newPage(ctx, "WebResetTitle"))
+}
+
+// requestReset sends a link to reset the password to the email of the
+// account.
+func (c *Controller) requestReset(ctx *fasthttp.RequestCtx) {
+	p := newPage(ctx, "WebResetTitle")
+	res := struct {
+		Result string `json:"result"`
+	}{}
+	err := run(ctx, p.loc, "AccountPasswordResetRequest", map[string]interface{}{
+		"username": string(ctx.FormValue("username")),
+	}, &res)
+	if err != nil {
+		p.Error = p.loc.Error(err)
+		c.show(ctx, fasthttp.StatusTooManyRequests, "forgot", p)
+		return
+	}
+
+	p.Message = res.Result
+	c.show(ctx, fasthttp.StatusOK, "forgot", p)
+}
+
+// resetForm shows the form to choose a new password with the code of the link
+// sent to the email of the account.
+func (c *Controller) resetForm(ctx *fasthttp.RequestCtx) {
+	p := newPage(ctx, "WebResetTitle")
+	p.Data = string(ctx.QueryArgs().Peek("code"))
+	c.show(ctx, fasthttp.StatusOK, "reset", p)
+}
+
+// confirmReset sets the new password of the account.
+func (c *Controller) confirmReset(ctx *fasthttp.RequestCtx) {
+	p := newPage(ctx, "WebResetTitle")
+	code := string(ctx.FormValue("code"))
+	res := make(map[string]interface{})
+	err := run(ctx, p.loc, "AccountPasswordResetConfirm", map[string]interface{}{
+		"code":     code,
+		"password": string(ctx.FormValue("password")),
+	}, &res)
+	if err != nil {
+		p.Error = p.T("WebResetError", map[string]interface{}{
+			"Error": p.loc.Error(err),
+		})
+		p.Data = code
+		c.show(ctx, fasthttp.StatusBadRequest, "reset", p)
+		return
+	}
+
+	/* The reset closes all the sessions, including the one of the browser. */
+	ctx.Response.Header.DelClientCookie(sessionCookie)
+	ctx.Response.Header.DelClientCookie(userCookie)
+	ctx.Redirect("/login?reset=1", fasthttp.StatusSeeOther)
+}
+
+// radar shows the radar of a flavor.
+func (c *Controller) radar(ctx *fasthttp.RequestCtx) {
+	flavor := fmt.Sprint(ctx.UserValue("flavor"))
+	res := struct {
+		Blips []render.Blip `json:"blips"`
+	}{}
+
+	p := newPage(ctx, "")
+	err := run(ctx, p.loc, "RadarGet", map[string]interface{}{"flavor": flavor}, &res)
+	if err != nil {
+		c.notFound(ctx)
+		return
+	}
+
+	svg := &bytes.Buffer{}
+	err = render.SVG(svg, res.Blips, func(b render.Blip) string {
+		return technologyURL(b.Name)
+	})
+	if err != nil {
+		logging.Logger.Error("radar not rendered", "request_id", logging.RequestID(ctx),
+			"flavor", flavor, "error", err.Error())
+		internalServerError(ctx)
+		return
+	}
+
+	view := radarView{
+		Flavor: flavor,
+		/* The svg is generated escaping all the user provided data. */
+		SVG: template.HTML(svg.String()),
+	}
+	for _, name := range render.Quadrants {
+		q := quadrant{Name: name}
+		for _, blip := range res.Blips {
+			if blip.Quadrant == name {
+				q.Blips = append(q.Blips, blip)
+			}
+		}
+
+		view.Quadrants = append(view.Quadrants, q)
+	}
+
+	p.Title = p.T("WebRadarTitle", map[string]interface{}{"Flavor": p.Flavor(flavor)})
+	p.Data = view
+	c.show(ctx, fasthttp.StatusOK, "radar", p)
+}
+
+// edition serves a radar edition in the Build Your Own Radar format, as
+// byor.csv or byor.json, for the visualizer to load it from its url.
+func (c *Controller) edition(ctx *fasthttp.RequestCtx) {
+	format := strings.TrimPrefix(fmt.Sprint(ctx.UserValue("file")), "byor.")
+	if format != byor.CSV && format != byor.JSON {
+		c.notFound(ctx)
+		return
+	}
+
+	res := struct {
+		Data string `json:"data"`
+	}{}
+
+	p := newPage(ctx, "")
+	err := run(ctx, p.loc, "EditionExport", map[string]interface{}{
+		"edition": fmt.Sprint(ctx.UserValue("name")),
+		"format":  format,
+	}, &res)
+	if err != nil {
+		c.notFound(ctx)
+		return
+	}
+
+	ctx.SetContentType("text/csv; charset=utf-8")
+	if format == byor.JSON {
+		ctx.SetContentType("application/json")
+	}
+
+	/* The visualizer is served from other origins. */
+	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.SetBodyString(res.Data)
+}
+
+// technology shows a technology and the members that know it.
+func (c *Controller) technology(ctx *fasthttp.RequestCtx) {
+	view := technologyView{}
+	p := newPage(ctx, "")
+	err := run(ctx, p.loc, "TechnologyGet", map[string]interface{}{
+		"name": fmt.Sprint(ctx.UserValue("name")),
+	}, &view)
+	if err != nil {
+		c.notFound(ctx)
+		return
+	}
+
+	p.Title = view.Name
+	p.Data = view
+	c.show(ctx, fasthttp.StatusOK, "technology", p)
+}
+
+// member shows the profile of a member.
+func (c *Controller) member(ctx *fasthttp.RequestCtx) {
+	view := memberView{}
+	p := newPage(ctx, "")
+	err := run(ctx, p.loc, "MemberGet", map[string]interface{}{
+		"username": fmt.Sprint(ctx.UserValue("username")),
+	}, &view)
+	if err != nil {
+		c.notFound(ctx)
+		return
+	}
+
+	p.Title = view.Name
+	p.Data = view
+	c.show(ctx, fasthttp.StatusOK, "member", p)
+}
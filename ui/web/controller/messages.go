@@ -0,0 +1,92 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// messages contains the texts of the web interface by their ID.
+var messages = make(map[string]*goi18n.Message)
+
+func init() {
+	/* The type of every message is explicit so goi18n extract finds them. */
+	for _, msg := range []*goi18n.Message{
+		&goi18n.Message{ID: "WebSiteName", Other: "Radar"},
+		&goi18n.Message{ID: "WebTitle", Other: "{{.Title}} - Radar"},
+		&goi18n.Message{ID: "WebSkipToContent", Other: "Skip to content"},
+		&goi18n.Message{ID: "WebMainNavigation", Other: "Main"},
+		&goi18n.Message{ID: "WebFlavorPeople", Other: "People"},
+		&goi18n.Message{ID: "WebFlavorProjects", Other: "Projects"},
+		&goi18n.Message{ID: "WebFlavorResources", Other: "Resources"},
+		&goi18n.Message{ID: "WebLogIn", Other: "Log in"},
+		&goi18n.Message{ID: "WebLogOut", Other: "Log out"},
+		&goi18n.Message{ID: "WebRegister", Other: "Register"},
+		&goi18n.Message{ID: "WebIndexTitle", Other: "Technology radar"},
+		&goi18n.Message{ID: "WebChooseRadar", Other: "Choose a radar:"},
+		&goi18n.Message{ID: "WebLoginField", Other: "Username or email"},
+		&goi18n.Message{ID: "WebUsername", Other: "Username"},
+		&goi18n.Message{ID: "WebName", Other: "Name"},
+		&goi18n.Message{ID: "WebEmail", Other: "Email"},
+		&goi18n.Message{ID: "WebPassword", Other: "Password"},
+		&goi18n.Message{ID: "WebNoAccount", Other: "Don't have an account?"},
+		&goi18n.Message{ID: "WebRegistered", Other: "Account registered successfully, follow the link sent to your email to activate it."},
+		&goi18n.Message{ID: "WebVerified", Other: "Account activated successfully, you can log in now."},
+		&goi18n.Message{ID: "WebVerifyTitle", Other: "Account activation"},
+		&goi18n.Message{ID: "WebVerifyError", Other: "Unable to activate the account: {{.Error}}."},
+		&goi18n.Message{ID: "WebResend", Other: "Send a new link"},
+		&goi18n.Message{ID: "WebResendError", Other: "Unable to send a new link, try again later."},
+		&goi18n.Message{ID: "WebForgotPassword", Other: "Forgot your password?"},
+		&goi18n.Message{ID: "WebResetTitle", Other: "Reset the password"},
+		&goi18n.Message{ID: "WebSendResetLink", Other: "Send the reset link"},
+		&goi18n.Message{ID: "WebNewPassword", Other: "New password"},
+		&goi18n.Message{ID: "WebChangePassword", Other: "Change the password"},
+		&goi18n.Message{ID: "WebResetError", Other: "Unable to change the password: {{.Error}}."},
+		&goi18n.Message{ID: "WebPasswordChanged", Other: "Password changed successfully, log in with the new one."},
+		&goi18n.Message{ID: "WebTwoFactorTitle", Other: "Two-factor authentication"},
+		&goi18n.Message{ID: "WebTwoFactorCode", Other: "Code of the authenticator app or recovery code"},
+		&goi18n.Message{ID: "WebTwoFactorVerify", Other: "Verify"},
+		&goi18n.Message{ID: "WebTwoFactorError", Other: "Unable to log in: {{.Error}}."},
+		&goi18n.Message{ID: "WebSSOLogIn", Other: "Log in with single sign-on"},
+		&goi18n.Message{ID: "WebSSOError", Other: "Unable to log in with single sign-on: {{.Error}}."},
+		&goi18n.Message{ID: "WebLoginError", Other: "Unable to log in, check the username and the password."},
+		&goi18n.Message{ID: "WebRegisterError", Other: "Unable to register the account: {{.Error}}."},
+		&goi18n.Message{ID: "WebRadarTitle", Other: "{{.Flavor}} radar"},
+		&goi18n.Message{ID: "WebNoBlips", Other: "There are no technologies in this quadrant."},
+		&goi18n.Message{ID: "WebType", Other: "Type"},
+		&goi18n.Message{ID: "WebQuadrant", Other: "Quadrant"},
+		&goi18n.Message{ID: "WebRing", Other: "Ring"},
+		&goi18n.Message{ID: "WebMembers", Other: "Members"},
+		&goi18n.Message{ID: "WebMember", Other: "Member"},
+		&goi18n.Message{ID: "WebLevel", Other: "Level"},
+		&goi18n.Message{ID: "WebRole", Other: "Role"},
+		&goi18n.Message{ID: "WebTechnologies", Other: "Technologies"},
+		&goi18n.Message{ID: "WebTechnology", Other: "Technology"},
+		&goi18n.Message{ID: "WebNoTechnologies", Other: "The member hasn't added any technology yet."},
+		&goi18n.Message{ID: "WebNotFoundTitle", Other: "Page not found"},
+		&goi18n.Message{ID: "WebNotFound", Other: "The page doesn't exists."},
+		&goi18n.Message{ID: "WebMethodNotAllowedTitle", Other: "Method not allowed"},
+		&goi18n.Message{ID: "WebMethodNotAllowed", Other: "Method not allowed."},
+		&goi18n.Message{ID: "WebForbiddenTitle", Other: "Forbidden"},
+		&goi18n.Message{ID: "WebCSRFError", Other: "The form expired, go back, reload the page and send it again."},
+		&goi18n.Message{ID: "WebBackHome", Other: "Go back to the main page"},
+	} {
+		messages[msg.ID] = msg
+	}
+}
@@ -0,0 +1,204 @@
+package controller
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"html/template"
+	"net/url"
+)
+
+// layout is the base template of every page, the pages define the content
+// block.
+const layout = `<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.TitleTag}}</title>
+<style>
+body { font-family: sans-serif; margin: 0; color: #222; }
+a { color: #0b5394; }
+header, main, footer { padding: 0 1em; }
+header nav ul { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: 1em; }
+.skip { position: absolute; left: -1000px; }
+.skip:focus { left: 1em; }
+.error { color: #a00; font-weight: bold; }
+label { display: block; margin-top: .5em; }
+svg { max-width: 100%; height: auto; }
+</style>
+</head>
+<body>
+<a class="skip" href="#content">{{.T "WebSkipToContent"}}</a>
+<header>
+<nav aria-label="{{.T "WebMainNavigation"}}">
+<ul>
+<li><a href="/">{{.T "WebSiteName"}}</a></li>
+{{range .Flavors}}<li><a href="/radar/{{.}}">{{$.Flavor .}}</a></li>
+{{end}}{{if .User}}<li><a href="/member/{{.User}}">{{.User}}</a></li>
+<li><form method="post" action="/logout"><input name="csrf" type="hidden" value="{{.CSRF}}"><button type="submit">{{.T "WebLogOut"}}</button></form></li>
+{{else}}<li><a href="/login">{{.T "WebLogIn"}}</a></li>
+<li><a href="/register">{{.T "WebRegister"}}</a></li>
+{{end}}</ul>
+</nav>
+</header>
+<main id="content">
+<h1>{{.Title}}</h1>
+{{if .Error}}<p class="error" role="alert">{{.Error}}</p>
+{{end}}{{if .Message}}<p role="status">{{.Message}}</p>
+{{end}}{{template "content" .}}
+</main>
+</body>
+</html>
+`
+
+// pages contains the content block of every page.
+var pages = map[string]string{
+	"index": `{{define "content"}}
+<p>{{.T "WebChooseRadar"}}</p>
+<ul>
+{{range .Flavors}}<li><a href="/radar/{{.}}">{{$.Flavor .}}</a></li>
+{{end}}</ul>
+{{end}}`,
+	"login": `{{define "content"}}
+<form method="post" action="/login">
+<input name="csrf" type="hidden" value="{{.CSRF}}">
+<label for="login">{{.T "WebLoginField"}}</label>
+<input id="login" name="login" type="text" autocomplete="username" required>
+<label for="password">{{.T "WebPassword"}}</label>
+<input id="password" name="password" type="password" autocomplete="current-password" required>
+<p><button type="submit">{{.T "WebLogIn"}}</button></p>
+</form>
+{{if .SSO}}<p><a href="/login/oidc">{{.T "WebSSOLogIn"}}</a></p>
+{{end}}<p>{{.T "WebNoAccount"}} <a href="/register">{{.T "WebRegister"}}</a>.</p>
+<p><a href="/password/reset">{{.T "WebForgotPassword"}}</a></p>
+{{end}}`,
+	"twofactor": `{{define "content"}}
+<form method="post" action="/login/verify">
+<input name="csrf" type="hidden" value="{{.CSRF}}">
+<input name="challenge" type="hidden" value="{{.Data}}">
+<label for="code">{{.T "WebTwoFactorCode"}}</label>
+<input id="code" name="code" type="text" inputmode="numeric" autocomplete="one-time-code" required>
+<p><button type="submit">{{.T "WebTwoFactorVerify"}}</button></p>
+</form>
+{{end}}`,
+	"forgot": `{{define "content"}}
+<form method="post" action="/password/reset">
+<input name="csrf" type="hidden" value="{{.CSRF}}">
+<label for="username">{{.T "WebUsername"}}</label>
+<input id="username" name="username" type="text" autocomplete="username" required>
+<p><button type="submit">{{.T "WebSendResetLink"}}</button></p>
+</form>
+{{end}}`,
+	"reset": `{{define "content"}}
+<form method="post" action="/password/reset/confirm">
+<input name="csrf" type="hidden" value="{{.CSRF}}">
+<input name="code" type="hidden" value="{{.Data}}">
+<label for="password">{{.T "WebNewPassword"}}</label>
+<input id="password" name="password" type="password" autocomplete="new-password" required>
+<p><button type="submit">{{.T "WebChangePassword"}}</button></p>
+</form>
+{{end}}`,
+	"register": `{{define "content"}}
+<form method="post" action="/register">
+<input name="csrf" type="hidden" value="{{.CSRF}}">
+<label for="username">{{.T "WebUsername"}}</label>
+<input id="username" name="username" type="text" autocomplete="username" value="{{.Data.Username}}" required>
+<label for="name">{{.T "WebName"}}</label>
+<input id="name" name="name" type="text" autocomplete="name" value="{{.Data.Name}}" required>
+<label for="email">{{.T "WebEmail"}}</label>
+<input id="email" name="email" type="email" autocomplete="email" value="{{.Data.Email}}" required>
+<label for="password">{{.T "WebPassword"}}</label>
+<input id="password" name="password" type="password" autocomplete="new-password" required>
+<p><button type="submit">{{.T "WebRegister"}}</button></p>
+</form>
+{{end}}`,
+	"verify": `{{define "content"}}
+<form method="post" action="/account/verify">
+<input name="csrf" type="hidden" value="{{.CSRF}}">
+<label for="username">{{.T "WebUsername"}}</label>
+<input id="username" name="username" type="text" autocomplete="username" required>
+<p><button type="submit">{{.T "WebResend"}}</button></p>
+</form>
+{{end}}`,
+	"radar": `{{define "content"}}
+<figure>
+{{.Data.SVG}}
+<figcaption>{{.Title}}</figcaption>
+</figure>
+{{range .Data.Quadrants}}<section>
+<h2>{{.Name}}</h2>
+{{if .Blips}}<ul>
+{{range .Blips}}<li><a href="{{technology .Name}}">{{.Name}}</a> ({{.Ring}})</li>
+{{end}}</ul>
+{{else}}<p>{{$.T "WebNoBlips"}}</p>
+{{end}}</section>
+{{end}}{{end}}`,
+	"technology": `{{define "content"}}
+<dl>
+<dt>{{.T "WebType"}}</dt><dd>{{.Data.Type}}</dd>
+<dt>{{.T "WebQuadrant"}}</dt><dd>{{.Data.Quadrant}}</dd>
+<dt>{{.T "WebRing"}}</dt><dd>{{.Data.Ring}}</dd>
+</dl>
+<h2>{{.T "WebMembers"}}</h2>
+<table>
+<thead><tr><th scope="col">{{.T "WebMember"}}</th><th scope="col">{{.T "WebLevel"}}</th></tr></thead>
+<tbody>
+{{range .Data.Members}}<tr><td><a href="/member/{{.Username}}">{{.Name}}</a></td><td>{{.Level}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}`,
+	"member": `{{define "content"}}
+<dl>
+<dt>{{.T "WebUsername"}}</dt><dd>{{.Data.Username}}</dd>
+{{if .Data.Role}}<dt>{{.T "WebRole"}}</dt><dd>{{.Data.Role}}</dd>
+{{end}}</dl>
+<h2>{{.T "WebTechnologies"}}</h2>
+{{if .Data.Technologies}}<table>
+<thead><tr><th scope="col">{{.T "WebTechnology"}}</th><th scope="col">{{.T "WebType"}}</th><th scope="col">{{.T "WebLevel"}}</th></tr></thead>
+<tbody>
+{{range .Data.Technologies}}<tr><td><a href="{{technology .Name}}">{{.Name}}</a></td><td>{{.Type}}</td><td>{{.Level}}</td></tr>
+{{end}}</tbody>
+</table>
+{{else}}<p>{{.T "WebNoTechnologies"}}</p>
+{{end}}{{end}}`,
+	"error": `{{define "content"}}
+<p><a href="/">{{.T "WebBackHome"}}</a>.</p>
+{{end}}`,
+}
+
+// funcs are the functions available in the templates.
+var funcs = template.FuncMap{
+	"technology": technologyURL,
+}
+
+// parseTemplates returns the templates of every page.
+func parseTemplates() map[string]*template.Template {
+	base := template.Must(template.New("layout").Funcs(funcs).Parse(layout))
+	templates := make(map[string]*template.Template, len(pages))
+	for name, page := range pages {
+		templates[name] = template.Must(template.Must(base.Clone()).Parse(page))
+	}
+
+	return templates
+}
+
+// technologyURL returns the url of the page of the technology.
+func technologyURL(name string) string {
+	return "/technology/" + url.PathEscape(name)
+}
@@ -0,0 +1,83 @@
+// Package web contains the Radar web interface.
+package web
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/radar-go/radar/config"
+	"github.com/radar-go/radar/logging"
+	"github.com/radar-go/radar/ui/web/controller"
+)
+
+// Web structure to manage the Radar web interface.
+type Web struct {
+	cfg      *config.Config
+	listener net.Listener
+}
+
+// New creates and returns a new Web object with the default configuration.
+func New() *Web {
+	return NewWithConfig(config.New())
+}
+
+// NewWithConfig creates and returns a new Web object.
+func NewWithConfig(cfg *config.Config) *Web {
+	return &Web{
+		cfg: cfg,
+	}
+}
+
+// Start starts the Radar web interface.
+func (w *Web) Start() error {
+	var err error
+	c := controller.New()
+	server := fasthttp.Server{
+		Handler:           fasthttp.CompressHandler(c.Router.Handler),
+		ReadBufferSize:    1024 * 64,
+		WriteBufferSize:   1024 * 64,
+		ReduceMemoryUsage: true,
+	}
+
+	w.listener, err = net.Listen("tcp4", fmt.Sprint(":", w.cfg.WebPort))
+	if err != nil {
+		return err
+	}
+
+	logging.Logger.Info("starting web interface", "port", w.cfg.WebPort)
+	return server.Serve(w.listener)
+}
+
+// Stop stops the web interface.
+func (w *Web) Stop() error {
+	var err error
+
+	if w.listener != nil {
+		err = w.listener.Close()
+		time.Sleep(time.Second)
+		w.listener = nil
+	}
+
+	return err
+}
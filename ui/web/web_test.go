@@ -0,0 +1,58 @@
+package web
+
+/* Copyright (C) 2018 Radar team (see AUTHORS)
+
+   This file is part of radar.
+
+   radar is free software: you can redistribute it and/or modify
+   it under the terms of the GNU General Public License as published by
+   the Free Software Foundation, either version 3 of the License, or
+   (at your option) any later version.
+
+   radar is distributed in the hope that it will be useful,
+   but WITHOUT ANY WARRANTY; without even the implied warranty of
+   MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+   GNU General Public License for more details.
+
+   You should have received a copy of the GNU General Public License
+   along with radar. If not, see <http://www.gnu.org/licenses/>.
+*/
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWeb(t *testing.T) {
+	web := New()
+	go func() {
+		err := web.Start()
+		if err != nil {
+			t.Errorf("Unexpected error starting the web interface: %+v", err)
+		}
+	}()
+
+	time.Sleep(time.Second)
+	resp, err := http.Get("http://localhost:10080/")
+	if err != nil {
+		t.Fatalf("Unexpected error calling the web interface: %+v", err)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Errorf("Unexpected error getting the body from the response: %+v", err)
+	}
+
+	if !strings.Contains(string(body), "Technology radar") {
+		t.Errorf("Expected the index page, Got %s", body)
+	}
+
+	err = web.Stop()
+	if err != nil {
+		t.Errorf("Unexpected error stoping the web interface: %+v", err)
+	}
+}